@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	archiveCommand = &cli.Command{
+		Action:    archive,
+		Name:      "archive",
+		Usage:     "admin archive stable swaps",
+		ArgsUsage: "<days>",
+		Description: `
+move stable swaps older than days (and their results) to archive tables,
+archived swaps can still be queried by txid
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func archive(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "archive"
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	days := ctx.Args().Get(0)
+
+	log.Printf("admin archive: %v", days)
+
+	params := []string{days}
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -26,6 +26,7 @@ func initApp() {
 	app.Commands = []*cli.Command{
 		maintainCommand,
 		bigvalueCommand,
+		ratelimitCommand,
 		blacklistCommand,
 		reverifyCommand,
 		reswapCommand,
@@ -33,6 +34,10 @@ func initApp() {
 		manualCommand,
 		setnonceCommand,
 		addpairCommand,
+		statushistoryCommand,
+		rebuildstatisticsCommand,
+		archiveCommand,
+		swaptaskCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
 	}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	ratelimitCommand = &cli.Command{
+		Action:    ratelimit,
+		Name:      "ratelimit",
+		Usage:     "admin ratelimit",
+		ArgsUsage: "<passswapin|passswapout> <txid> <pairID> <bind>",
+		Description: `
+admin pass rate limited swap (bypass velocity limits)
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func ratelimit(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "ratelimit"
+	if ctx.NArg() != 4 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	txid := ctx.Args().Get(1)
+	pairID := ctx.Args().Get(2)
+	bind := ctx.Args().Get(3)
+
+	switch operation {
+	case passSwapinOp, passSwapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	log.Printf("admin ratelimit: %v %v %v %v", operation, txid, pairID, bind)
+
+	params := []string{operation, txid, pairID, bind}
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
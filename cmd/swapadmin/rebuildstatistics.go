@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	rebuildstatisticsCommand = &cli.Command{
+		Action:    rebuildstatistics,
+		Name:      "rebuildstatistics",
+		Usage:     "admin rebuild swap statistics",
+		ArgsUsage: "<pairIDs>",
+		Description: `
+rebuild all time and hourly/daily swap statistics from stable swap results,
+pairIDs can be 'all' or comma joined pairIDs
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func rebuildstatistics(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "rebuildstatistics"
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	pairIDs := ctx.Args().Get(0)
+
+	log.Printf("admin rebuildstatistics: %v", pairIDs)
+
+	params := []string{pairIDs}
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	statushistoryCommand = &cli.Command{
+		Action:    statushistory,
+		Name:      "statushistory",
+		Usage:     "query swap status history",
+		ArgsUsage: "<txid> <pairID> <bind>",
+		Description: `
+query status transitions of swap (no keystore is needed)
+`,
+		Flags: []cli.Flag{
+			utils.SwapServerFlag,
+		},
+	}
+)
+
+func statushistory(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "statushistory"
+	if ctx.NArg() != 3 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := initSwapServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := map[string]string{
+		"txid":   ctx.Args().Get(0),
+		"pairid": ctx.Args().Get(1),
+		"bind":   ctx.Args().Get(2),
+	}
+	log.Printf("query swap status history: %v %v %v", args["txid"], args["pairid"], args["bind"])
+
+	var result []*swapapi.SwapStatusTransition
+	err = client.RPCPost(&result, swapServer, "swap.GetSwapStatusHistory", args)
+	if err != nil {
+		return err
+	}
+	for _, item := range result {
+		target := "swap"
+		if item.IsResult {
+			target = "result"
+		}
+		direction := "swapout"
+		if item.IsSwapin {
+			direction = "swapin"
+		}
+		fmt.Printf("%v %v %v: %v -> %v actor=%v memo=%q\n",
+			time.Unix(item.Timestamp, 0).Format(time.RFC3339), direction, target,
+			item.OldStatusMsg, item.NewStatusMsg, item.Actor, item.Memo)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	swaptaskCommand = &cli.Command{
+		Action:    swaptask,
+		Name:      "swaptask",
+		Usage:     "admin swap task queue",
+		ArgsUsage: "<stats|list|retry|remove> [status|taskKey]",
+		Description: `
+admin swap task queue
+stats: show pending, inflight and dead count of every queue
+list [status]: list tasks of status ('pending' or 'dead', empty means all)
+retry <taskKey>: retry dead or stuck task immediately
+remove <taskKey>: remove task from queue
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func swaptask(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "swaptask"
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	operation := ctx.Args().Get(0)
+	switch operation {
+	case "stats", "list":
+	case "retry", "remove":
+		if ctx.NArg() != 2 {
+			return fmt.Errorf("operation '%v' needs task key", operation)
+		}
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin swaptask: %v", params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/urfave/cli/v2"
+)
+
+var chainsCommand = &cli.Command{
+	Action:    listChains,
+	Name:      "chains",
+	Usage:     "List registered block chains",
+	ArgsUsage: " ",
+	Description: `
+List block chains registered by linked chain packages.
+Config 'BlockChain' of chain config matches them as case insensitive prefix.
+`,
+}
+
+func listChains(_ *cli.Context) error {
+	for _, name := range tokens.GetRegisteredBlockChains() {
+		fmt.Println(name)
+	}
+	return nil
+}
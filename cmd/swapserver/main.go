@@ -32,6 +32,7 @@ func initApp() {
 	app.Commands = []*cli.Command{
 		utils.LicenseCommand,
 		utils.VersionCommand,
+		chainsCommand,
 	}
 	app.Flags = []cli.Flag{
 		utils.DataDirFlag,
@@ -66,8 +67,12 @@ func swapserver(ctx *cli.Context) error {
 
 	tokens.SetTokenPairsDir(utils.GetTokenPairsDir(ctx))
 
-	dbConfig := config.MongoDB
-	mongodb.MongoServerInit([]string{dbConfig.DBURL}, dbConfig.DBName, dbConfig.UserName, dbConfig.Password)
+	if config.LevelDB != nil {
+		mongodb.LevelDBStoreInit(config.LevelDB.GetPath())
+	} else {
+		dbConfig := config.MongoDB
+		mongodb.MongoServerInit([]string{dbConfig.DBURL}, dbConfig.DBName, dbConfig.UserName, dbConfig.Password)
+	}
 
 	worker.StartWork(true)
 	time.Sleep(100 * time.Millisecond)
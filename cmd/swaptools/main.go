@@ -26,6 +26,7 @@ func initApp() {
 	app.Commands = []*cli.Command{
 		sendBtcCommand,
 		sendLtcCommand,
+		sendDogeCommand,
 		sendEthTxCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
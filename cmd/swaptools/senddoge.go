@@ -0,0 +1,189 @@
+// nolint:dupl // keep it
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/doge"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	// nolint:lll // allow long line of example
+	sendDogeCommand = &cli.Command{
+		Action:    sendDoge,
+		Name:      "senddoge",
+		Usage:     "send doge",
+		ArgsUsage: " ",
+		Description: `
+send doge command, sign tx with WIF or private key.
+
+Example:
+
+./swaptools senddoge --gateway http://1.2.3.4:5555 --net testnet --wif ./wif.txt --from nXvMURbNET6JcCKqRGXZMcXB5k5729ut8F --to naNYk5NMCsommKs4JpKb3hhJYJBKwSrfVJ --value 100000000 --to na4K31YsECbc2vHNL3wDXMX1vKK6PT1Paz --value 200000000 --memo "test send doge" --dryrun
+`,
+		Flags: []cli.Flag{
+			utils.GatewayFlag,
+			networkFlag,
+			wifFileFlag,
+			priKeyFileFlag,
+			senderFlag,
+			receiverSliceFlag,
+			valueSliceFlag,
+			memoFlag,
+			relayFeePerKbFlag,
+			dryRunFlag,
+		},
+	}
+)
+
+type dogeTxSender struct {
+	gateway       string
+	netID         string
+	wifFile       string
+	priFile       string
+	sender        string
+	receivers     []string
+	amounts       []int64
+	memo          string
+	relayFeePerKb int64
+	dryRun        bool
+}
+
+var (
+	dogeBridge *doge.Bridge
+	dogeSender = &dogeTxSender{}
+)
+
+func (bts *dogeTxSender) initArgs(ctx *cli.Context) {
+	bts.gateway = ctx.String(utils.GatewayFlag.Name)
+	bts.netID = ctx.String(networkFlag.Name)
+	bts.wifFile = ctx.String(wifFileFlag.Name)
+	bts.priFile = ctx.String(priKeyFileFlag.Name)
+	bts.sender = ctx.String(senderFlag.Name)
+	bts.receivers = ctx.StringSlice(receiverSliceFlag.Name)
+	bts.amounts = ctx.Int64Slice(valueSliceFlag.Name)
+	bts.memo = ctx.String(memoFlag.Name)
+	bts.relayFeePerKb = ctx.Int64(relayFeePerKbFlag.Name)
+	bts.dryRun = ctx.Bool(dryRunFlag.Name)
+
+	if bts.netID == "" {
+		log.Fatal("must specify '-net' flag")
+	}
+	if bts.wifFile == "" && bts.priFile == "" {
+		log.Fatal("must specify '-wif' or '-pri' flag")
+	}
+	if bts.sender == "" {
+		log.Fatal("must specify '-from' flag")
+	}
+	if len(bts.receivers) == 0 {
+		log.Fatal("must specify '-to' flag")
+	}
+	if len(bts.amounts) == 0 {
+		log.Fatal("must specify '-value' flag")
+	}
+	if len(bts.receivers) != len(bts.amounts) {
+		log.Fatal("count of receivers and values are not equal")
+	}
+}
+
+func sendDoge(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	dogeSender.initArgs(ctx)
+
+	dogeSender.initBridge()
+
+	wifStr := dogeSender.loadWIFForAddress()
+
+	rawTx, err := dogeBridge.BuildTransaction(dogeSender.sender, dogeSender.receivers, dogeSender.amounts, dogeSender.memo, dogeSender.relayFeePerKb)
+	if err != nil {
+		log.Fatal("BuildRawTransaction error", "err", err)
+	}
+
+	signedTx, txHash, err := dogeBridge.SignTransactionWithWIF(rawTx, wifStr)
+	if err != nil {
+		log.Fatal("SignTransaction failed", "err", err)
+	}
+	log.Info("SignTransaction success", "txHash", txHash)
+
+	fmt.Println(btc.AuthoredTxToString(signedTx, true))
+
+	if !dogeSender.dryRun {
+		_, err = dogeBridge.SendTransaction(signedTx)
+		if err != nil {
+			log.Error("SendTransaction failed", "err", err)
+		}
+	} else {
+		log.Info("------------ dry run, does not sendtx -------------")
+	}
+	return nil
+}
+
+func (bts *dogeTxSender) initBridge() {
+	dogeBridge = doge.NewCrossChainBridge(true)
+	dogeBridge.ChainConfig = &tokens.ChainConfig{
+		BlockChain: doge.BlockChain,
+		NetID:      bts.netID,
+	}
+	dogeBridge.GatewayConfig = &tokens.GatewayConfig{
+		APIAddress: []string{bts.gateway},
+	}
+	dogeBridge.VerifyChainConfig()
+	dogeBridge.InitChainAPI()
+}
+
+func (bts *dogeTxSender) loadWIFForAddress() string {
+	var wifStr string
+	if bts.wifFile != "" {
+		wifdata, err := ioutil.ReadFile(bts.wifFile)
+		if err != nil {
+			log.Fatal("Read WIF file failed", "err", err)
+		}
+		wifStr = strings.TrimSpace(string(wifdata))
+	} else {
+		pridata, err := ioutil.ReadFile(bts.priFile)
+		if err != nil {
+			log.Fatal("Read private key file failed", "err", err)
+		}
+		priKey := strings.TrimSpace(string(pridata))
+		var pribs []byte
+		if common.IsHex(priKey) {
+			pribs, err = hex.DecodeString(priKey)
+			if err != nil {
+				log.Fatal("failed to decode hex private key string")
+			}
+		} else {
+			pribs, _, err = base58.CheckDecode(priKey)
+			if err != nil {
+				pribs = base58.Decode(priKey)
+			}
+		}
+		pri, _ := btcec.PrivKeyFromBytes(btcec.S256(), pribs)
+		wif, err := btcutil.NewWIF(pri, dogeBridge.GetChainParams(), true)
+		if err != nil {
+			log.Fatal("failed to parse private key")
+		}
+		wifStr = wif.String()
+	}
+	wif, err := dogeBridge.DecodeWIF(wifStr)
+	if err != nil {
+		log.Fatal("failed to decode WIF to verify", "err", err)
+	}
+	pkdata := wif.SerializePubKey()
+	pkaddr, _ := dogeBridge.NewAddressPubKeyHash(pkdata)
+	if pkaddr.EncodeAddress() != bts.sender {
+		log.Fatal("address mismatch", "decoded", pkaddr.EncodeAddress(), "from", bts.sender)
+	}
+	return wifStr
+}
@@ -11,10 +11,11 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
-	"github.com/anyswap/CrossChain-Bridge/tokens/ltc"
-	"github.com/ltcsuite/ltcd/btcec"
-	"github.com/ltcsuite/ltcutil"
-	"github.com/ltcsuite/ltcutil/base58"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/utxo"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
 	"github.com/urfave/cli/v2"
 )
 
@@ -30,7 +31,7 @@ send ltc command, sign tx with WIF or private key.
 
 Example:
 
-./swaptools sendltc --gateway http://1.2.3.4:5555 --net testnet3 --wif ./wif.txt --from maiApsjjnceZ7Cx1UMj344JRU3R8A2Say6 --to mtc4xaZgJJZpN6BdoWk7pHFho1GTUnd5aP --value 10000 --to mfwanCuht2b4Lvb5XTds4Rvzy3jZ2ZWraL --value 20000 --memo "test send ltc" --dryrun
+./swaptools sendltc --gateway http://1.2.3.4:5555 --net testnet4 --wif ./wif.txt --from maiApsjjnceZ7Cx1UMj344JRU3R8A2Say6 --to mtc4xaZgJJZpN6BdoWk7pHFho1GTUnd5aP --value 10000 --to mfwanCuht2b4Lvb5XTds4Rvzy3jZ2ZWraL --value 20000 --memo "test send ltc" --dryrun
 `,
 		Flags: []cli.Flag{
 			utils.GatewayFlag,
@@ -61,7 +62,7 @@ type ltcTxSender struct {
 }
 
 var (
-	ltcBridge *ltc.Bridge
+	ltcBridge *utxo.Bridge
 	ltcSender = &ltcTxSender{}
 )
 
@@ -116,7 +117,7 @@ func sendLtc(ctx *cli.Context) error {
 	}
 	log.Info("SignTransaction success", "txHash", txHash)
 
-	fmt.Println(ltc.AuthoredTxToString(signedTx, true))
+	fmt.Println(btc.AuthoredTxToString(signedTx, true))
 
 	if !ltcSender.dryRun {
 		_, err = ltcBridge.SendTransaction(signedTx)
@@ -130,7 +131,7 @@ func sendLtc(ctx *cli.Context) error {
 }
 
 func (bts *ltcTxSender) initBridge() {
-	ltcBridge = ltc.NewCrossChainBridge(true)
+	ltcBridge = utxo.NewCrossChainBridge("Litecoin", true)
 	ltcBridge.ChainConfig = &tokens.ChainConfig{
 		BlockChain: "Litecoin",
 		NetID:      bts.netID,
@@ -138,6 +139,8 @@ func (bts *ltcTxSender) initBridge() {
 	ltcBridge.GatewayConfig = &tokens.GatewayConfig{
 		APIAddress: []string{bts.gateway},
 	}
+	ltcBridge.VerifyChainConfig()
+	ltcBridge.InitChainAPI()
 }
 
 func (bts *ltcTxSender) loadWIFForAddress() string {
@@ -167,13 +170,13 @@ func (bts *ltcTxSender) loadWIFForAddress() string {
 			}
 		}
 		pri, _ := btcec.PrivKeyFromBytes(btcec.S256(), pribs)
-		wif, err := ltcutil.NewWIF(pri, ltcBridge.GetChainParams(), true)
+		wif, err := btcutil.NewWIF(pri, ltcBridge.GetChainParams(), true)
 		if err != nil {
 			log.Fatal("failed to parse private key")
 		}
 		wifStr = wif.String()
 	}
-	wif, err := ltcutil.DecodeWIF(wifStr)
+	wif, err := btcutil.DecodeWIF(wifStr)
 	if err != nil {
 		log.Fatal("failed to decode WIF to verify")
 	}
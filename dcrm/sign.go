@@ -28,8 +28,16 @@ var (
 	errDoSignFailed         = errors.New("do sign failed")
 	errSignWithoutPublickey = errors.New("sign without public key")
 	errGetSignResultFailed  = errors.New("get sign result failed")
+
+	signRequestHook func(keyID string, msgHash, msgContext []string)
 )
 
+// SetSignRequestHook set hook which is called when sign request is accepted
+// by dcrm node (before waiting sign result), eg. to record the keyID
+func SetSignRequestHook(hook func(keyID string, msgHash, msgContext []string)) {
+	signRequestHook = hook
+}
+
 func pingDcrmNode(nodeInfo *NodeInfo) (err error) {
 	rpcAddr := nodeInfo.dcrmRPCAddress
 	for j := 0; j < pingCount; j++ {
@@ -111,6 +119,9 @@ func doSignImpl(dcrmNode *NodeInfo, signGroupIndex int64, signPubkey string, msg
 	if err != nil {
 		return "", nil, err
 	}
+	if signRequestHook != nil {
+		signRequestHook(keyID, msgHash, msgContext)
+	}
 
 	rsvs, err = getSignResult(keyID, rpcAddr)
 	if err != nil {
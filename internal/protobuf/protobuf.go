@@ -0,0 +1,107 @@
+// Package protobuf provides minimal protobuf wire encoding and decoding,
+// which is used to build and parse txs of protobuf based chains (eg. tron, cosmos)
+// without importing their heavy protocol dependencies.
+package protobuf
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// protobuf wire types
+const (
+	WireVarint  = 0
+	WireFixed64 = 1
+	WireBytes   = 2
+	WireFixed32 = 5
+)
+
+// ErrWrongProtobuf wrong protobuf encoding
+var ErrWrongProtobuf = errors.New("wrong protobuf encoding")
+
+// AppendVarint append varint
+func AppendVarint(buf []byte, v uint64) []byte {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	return append(buf, tmp[:n]...)
+}
+
+// AppendTag append field tag
+func AppendTag(buf []byte, fieldNum, wireType uint64) []byte {
+	return AppendVarint(buf, fieldNum<<3|wireType)
+}
+
+// AppendVarintField append varint field, zero value is omitted
+func AppendVarintField(buf []byte, fieldNum, v uint64) []byte {
+	if v == 0 {
+		return buf
+	}
+	buf = AppendTag(buf, fieldNum, WireVarint)
+	return AppendVarint(buf, v)
+}
+
+// AppendBytesField append length delimited field, empty data is omitted
+func AppendBytesField(buf []byte, fieldNum uint64, data []byte) []byte {
+	if len(data) == 0 {
+		return buf
+	}
+	buf = AppendTag(buf, fieldNum, WireBytes)
+	buf = AppendVarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// AppendMessageField append embedded message, empty message is kept
+// as presence of message field is meaningful (eg. 'ModeInfo.Single').
+func AppendMessageField(buf []byte, fieldNum uint64, msg []byte) []byte {
+	buf = AppendTag(buf, fieldNum, WireBytes)
+	buf = AppendVarint(buf, uint64(len(msg)))
+	return append(buf, msg...)
+}
+
+// MarshalAny protobuf encoding of 'google.protobuf.Any'
+func MarshalAny(typeURL string, value []byte) (buf []byte) {
+	buf = AppendBytesField(buf, 1, []byte(typeURL))
+	buf = AppendBytesField(buf, 2, value)
+	return buf
+}
+
+// RangeFields iterate fields of protobuf message,
+// data is the payload of length delimited field and nil otherwise.
+func RangeFields(buf []byte, fn func(fieldNum uint64, data []byte) error) error {
+	for len(buf) > 0 {
+		tag, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return ErrWrongProtobuf
+		}
+		buf = buf[n:]
+		var data []byte
+		switch tag & 0x7 {
+		case WireVarint:
+			_, n = binary.Uvarint(buf)
+			if n <= 0 {
+				return ErrWrongProtobuf
+			}
+		case WireFixed64:
+			n = 8
+		case WireFixed32:
+			n = 4
+		case WireBytes:
+			length, m := binary.Uvarint(buf)
+			if m <= 0 || length > uint64(len(buf)-m) {
+				return ErrWrongProtobuf
+			}
+			data = buf[m : m+int(length)]
+			n = m + int(length)
+		default:
+			return ErrWrongProtobuf
+		}
+		if n > len(buf) {
+			return ErrWrongProtobuf
+		}
+		buf = buf[n:]
+		if err := fn(tag>>3, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,15 +1,21 @@
 package swapapi
 
 import (
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
+	"math/big"
 	"strings"
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/xrp"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
 	"github.com/btcsuite/btcd/txscript"
 	rpcjson "github.com/gorilla/rpc/v2/json2"
 )
@@ -18,6 +24,8 @@ var (
 	errNotBtcBridge      = newRPCError(-32096, "bridge is not btc")
 	errTokenPairNotExist = newRPCError(-32095, "token pair not exist")
 	errSwapCannotRetry   = newRPCError(-32094, "swap can not retry")
+	errNotXrpBridge      = newRPCError(-32093, "bridge is not xrp")
+	errInvalidBind       = newRPCError(-32092, "invalid bind address")
 )
 
 func newRPCError(ec rpcjson.ErrorCode, message string) error {
@@ -45,6 +53,7 @@ func GetServerInfo() (*ServerInfo, error) {
 		DestChain:           config.DestChain,
 		PairIDs:             tokens.GetAllPairIDs(),
 		Version:             params.VersionWithMeta,
+		SupportedChains:     tokens.GetRegisteredBlockChains(),
 	}, nil
 }
 
@@ -54,7 +63,54 @@ func GetTokenPairInfo(pairID string) (*tokens.TokenPairConfig, error) {
 	if pairCfg == nil {
 		return nil, errTokenPairNotExist
 	}
-	return pairCfg, nil
+	return pairCfg.Clone(), nil
+}
+
+// GetSwapVelocity api
+func GetSwapVelocity(pairID, bind string) (*SwapVelocityInfo, error) {
+	log.Debug("[api] receive GetSwapVelocity", "pairID", pairID, "bind", bind)
+	if tokens.GetTokenPairConfig(pairID) == nil {
+		return nil, errTokenPairNotExist
+	}
+	swapin, err := getSwapVelocity(true, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	swapout, err := getSwapVelocity(false, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	return &SwapVelocityInfo{
+		PairID:  pairID,
+		Bind:    bind,
+		Swapin:  swapin,
+		Swapout: swapout,
+	}, nil
+}
+
+func getSwapVelocity(isSwapin bool, pairID, bind string) (*SwapVelocity, error) {
+	usage, err := mongodb.GetSwapVelocityUsage(isSwapin, pairID, bind)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	limitStr := func(limit *big.Int) string {
+		if limit == nil {
+			return ""
+		}
+		return limit.String()
+	}
+	pairHourlyLimit, pairDailyLimit, bindDailyLimit := tokens.GetVelocityLimits(pairID, isSwapin)
+	velocity := &SwapVelocity{
+		PairHourlyUsage: usage.PairHourly.String(),
+		PairHourlyLimit: limitStr(pairHourlyLimit),
+		PairDailyUsage:  usage.PairDaily.String(),
+		PairDailyLimit:  limitStr(pairDailyLimit),
+	}
+	if bind != "" {
+		velocity.BindDailyUsage = usage.BindDaily.String()
+		velocity.BindDailyLimit = limitStr(bindDailyLimit)
+	}
+	return velocity, nil
 }
 
 // GetNonceInfo api
@@ -72,6 +128,12 @@ func GetSwapStatistics(pairID string) (*SwapStatistics, error) {
 	return mongodb.GetSwapStatistics(pairID)
 }
 
+// GetSwapStatisticsRange api
+func GetSwapStatisticsRange(pairID string, from, to int64, granularity string) ([]*SwapStatisticsBucket, error) {
+	log.Debug("[api] receive GetSwapStatisticsRange", "pairID", pairID, "from", from, "to", to, "granularity", granularity)
+	return mongodb.GetSwapStatisticsRange(pairID, from, to, granularity)
+}
+
 // GetRawSwapin api
 func GetRawSwapin(txid, pairID, bindAddr *string) (*Swap, error) {
 	return mongodb.FindSwapin(*txid, *pairID, *bindAddr)
@@ -137,25 +199,35 @@ func processHistoryLimit(limit int) int {
 }
 
 // GetSwapinHistory api
-func GetSwapinHistory(address, pairID string, offset, limit int, status string) ([]*SwapInfo, error) {
-	log.Debug("[api] receive GetSwapinHistory", "address", address, "pairID", pairID, "offset", offset, "limit", limit, "status", status)
-	limit = processHistoryLimit(limit)
-	result, err := mongodb.FindSwapinResults(address, pairID, offset, limit, status)
+func GetSwapinHistory(filter *SwapHistoryFilter) ([]*SwapInfo, error) {
+	log.Debug("[api] receive GetSwapinHistory", "filter", filter)
+	filter.Limit = processHistoryLimit(filter.Limit)
+	result, err := mongodb.FindSwapinResults(filter)
 	if err != nil {
 		return nil, err
 	}
-	return ConvertMgoSwapResultsToSwapInfos(result), nil
+	return ConvertMgoSwapResultsToSwapHistory(result), nil
 }
 
 // GetSwapoutHistory api
-func GetSwapoutHistory(address, pairID string, offset, limit int, status string) ([]*SwapInfo, error) {
-	log.Debug("[api] receive GetSwapoutHistory", "address", address, "pairID", pairID, "offset", offset, "limit", limit)
-	limit = processHistoryLimit(limit)
-	result, err := mongodb.FindSwapoutResults(address, pairID, offset, limit, status)
+func GetSwapoutHistory(filter *SwapHistoryFilter) ([]*SwapInfo, error) {
+	log.Debug("[api] receive GetSwapoutHistory", "filter", filter)
+	filter.Limit = processHistoryLimit(filter.Limit)
+	result, err := mongodb.FindSwapoutResults(filter)
 	if err != nil {
 		return nil, err
 	}
-	return ConvertMgoSwapResultsToSwapInfos(result), nil
+	return ConvertMgoSwapResultsToSwapHistory(result), nil
+}
+
+// GetSwapStatusHistory api
+func GetSwapStatusHistory(txid, pairID, bindAddr *string) ([]*SwapStatusTransition, error) {
+	log.Debug("[api] receive GetSwapStatusHistory", "txid", *txid, "pairID", *pairID, "bind", *bindAddr)
+	result, err := mongodb.GetSwapStatusHistory(*txid, *pairID, *bindAddr)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoSwapStatusHistoryToTransitions(result), nil
 }
 
 // Swapin api
@@ -187,7 +259,7 @@ func RetrySwapin(txid, pairID *string) (*PostResult, error) {
 	if !swap.Status.CanRetry() {
 		return nil, errSwapCannotRetry
 	}
-	err = mongodb.UpdateSwapinStatus(txidstr, pairIDStr, bindStr, mongodb.TxNotStable, time.Now().Unix(), "")
+	err = mongodb.UpdateSwapinStatus(txidstr, pairIDStr, bindStr, mongodb.TxNotStable, time.Now().Unix(), "", "retryswapin")
 	if err != nil {
 		return nil, err
 	}
@@ -205,7 +277,7 @@ func basicCheckSwapRegister(bridge tokens.CrossChainBridge, pairIDStr string) er
 	if tokenCfg == nil {
 		return tokens.ErrUnknownPairID
 	}
-	if tokenCfg.DisableSwap {
+	if tokenCfg.IsSwapDisabled() {
 		return tokens.ErrSwapIsClosed
 	}
 	return nil
@@ -315,6 +387,12 @@ func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddres
 				P2shAddress: p2shAddr,
 			})
 		}
+		// newly derived address has no history, import without rescan
+		if watcher, ok := btc.BridgeInstance.(btc.AddressWatcher); ok {
+			if err = watcher.WatchAddress(p2shAddr, false); err != nil {
+				return nil, newRPCInternalError(err)
+			}
+		}
 	}
 	return &tokens.P2shAddressInfo{
 		BindAddress:        bindAddress,
@@ -324,6 +402,90 @@ func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddres
 	}, nil
 }
 
+// RegisterDestinationTag api
+func RegisterDestinationTag(bindAddress string) (*tokens.DestinationTagInfo, error) {
+	if !xrp.IsXrpBridge(tokens.SrcBridge) {
+		return nil, errNotXrpBridge
+	}
+	if !tokens.DstBridge.IsValidAddress(bindAddress) {
+		return nil, errInvalidBind
+	}
+	key := normalizeBindAddress(bindAddress)
+	for _, addr := range []string{key, bindAddress} {
+		if result, _ := mongodb.FindDestinationTag(addr); result != nil {
+			return newDestinationTagInfo(result.Key, result.DestinationTag), nil
+		}
+	}
+	tag, err := allocDestinationTag(key)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return newDestinationTagInfo(key, tag), nil
+}
+
+// GetDestinationTagInfo api
+func GetDestinationTagInfo(tag uint32) (*tokens.DestinationTagInfo, error) {
+	if !xrp.IsXrpBridge(tokens.SrcBridge) {
+		return nil, errNotXrpBridge
+	}
+	bindAddress, err := mongodb.FindDestinationTagBindAddress(tag)
+	if err != nil {
+		return nil, err
+	}
+	return newDestinationTagInfo(bindAddress, tag), nil
+}
+
+// allocDestinationTag start from a tag derived from bind address,
+// and add the first unused one (tag 0 is not used).
+// destination tag is unique in database, so it is safe among servers.
+func allocDestinationTag(bindAddress string) (uint32, error) {
+	tag := binary.BigEndian.Uint32(crypto.Keccak256([]byte(bindAddress))[:4])
+	for i := 0; i < 100; i++ {
+		if tag == 0 {
+			tag++
+		}
+		err := mongodb.AddDestinationTag(&mongodb.MgoDestinationTag{
+			Key:            bindAddress,
+			DestinationTag: tag,
+		})
+		if err == nil {
+			return tag, nil
+		}
+		if !errors.Is(err, mongodb.ErrItemIsDup) {
+			return 0, err
+		}
+		// bind address is registered at the same time, otherwise tag is used
+		if result, _ := mongodb.FindDestinationTag(bindAddress); result != nil {
+			return result.DestinationTag, nil
+		}
+		tag++
+	}
+	return 0, errors.New("alloc destination tag failed")
+}
+
+// normalizeBindAddress hex address of eth like chain is case insensitive
+func normalizeBindAddress(bindAddress string) string {
+	if common.IsHexAddress(bindAddress) {
+		return strings.ToLower(bindAddress)
+	}
+	return bindAddress
+}
+
+func newDestinationTagInfo(bindAddress string, tag uint32) *tokens.DestinationTagInfo {
+	var depositAddress string
+	for _, pairID := range tokens.GetAllPairIDs() {
+		if token := tokens.GetTokenConfig(pairID, true); token != nil && token.DepositAddress != "" {
+			depositAddress = token.DepositAddress
+			break
+		}
+	}
+	return &tokens.DestinationTagInfo{
+		BindAddress:    bindAddress,
+		DepositAddress: depositAddress,
+		DestinationTag: tag,
+	}
+}
+
 // P2shSwapin api
 func P2shSwapin(txid, bindAddr *string) (*PostResult, error) {
 	log.Debug("[api] receive P2shSwapin", "txid", *txid, "bindAddress", *bindAddr)
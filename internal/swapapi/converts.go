@@ -58,6 +58,7 @@ func ConvertMgoSwapResultToSwapInfo(mr *mongodb.MgoSwapResult) *SwapInfo {
 		SwapValue:     mr.SwapValue,
 		SwapType:      mr.SwapType,
 		SwapNonce:     mr.SwapNonce,
+		SwapMemo:      mr.SwapMemo,
 		Status:        mr.Status,
 		StatusMsg:     mr.Status.String(),
 		InitTime:      mr.InitTime,
@@ -76,3 +77,31 @@ func ConvertMgoSwapResultsToSwapInfos(mrSlice []*mongodb.MgoSwapResult) []*SwapI
 	}
 	return result
 }
+
+// ConvertMgoSwapResultsToSwapHistory convert with continuation cursors
+func ConvertMgoSwapResultsToSwapHistory(mrSlice []*mongodb.MgoSwapResult) []*SwapInfo {
+	result := ConvertMgoSwapResultsToSwapInfos(mrSlice)
+	for k, v := range mrSlice {
+		result[k].Cursor = mongodb.GetHistoryCursor(v)
+	}
+	return result
+}
+
+// ConvertMgoSwapStatusHistoryToTransitions convert
+func ConvertMgoSwapStatusHistoryToTransitions(history []*mongodb.MgoSwapStatusHistory) []*SwapStatusTransition {
+	result := make([]*SwapStatusTransition, len(history))
+	for k, v := range history {
+		result[k] = &SwapStatusTransition{
+			IsSwapin:     v.IsSwapin,
+			IsResult:     v.IsResult,
+			OldStatus:    v.OldStatus,
+			OldStatusMsg: v.OldStatus.String(),
+			NewStatus:    v.NewStatus,
+			NewStatusMsg: v.NewStatus.String(),
+			Actor:        v.Actor,
+			Memo:         v.Memo,
+			Timestamp:    v.Timestamp,
+		}
+	}
+	return result
+}
@@ -14,9 +14,15 @@ type Swap = mongodb.MgoSwap
 // SwapResult type alias
 type SwapResult = mongodb.MgoSwapResult
 
+// SwapHistoryFilter type alias
+type SwapHistoryFilter = mongodb.SwapHistoryFilter
+
 // SwapStatistics type alias
 type SwapStatistics = mongodb.SwapStatistics
 
+// SwapStatisticsBucket type alias
+type SwapStatisticsBucket = mongodb.MgoSwapStatisticsBucket
+
 // LatestScanInfo type alias
 type LatestScanInfo = mongodb.MgoLatestScanInfo
 
@@ -31,6 +37,25 @@ type ServerInfo struct {
 	DestChain           *tokens.ChainConfig
 	PairIDs             []string
 	Version             string
+	SupportedChains     []string
+}
+
+// SwapVelocity swap value usage and limits in rolling windows (empty limit means unlimited)
+type SwapVelocity struct {
+	PairHourlyUsage string `json:"pairhourlyusage"`
+	PairHourlyLimit string `json:"pairhourlylimit,omitempty"`
+	PairDailyUsage  string `json:"pairdailyusage"`
+	PairDailyLimit  string `json:"pairdailylimit,omitempty"`
+	BindDailyUsage  string `json:"binddailyusage,omitempty"`
+	BindDailyLimit  string `json:"binddailylimit,omitempty"`
+}
+
+// SwapVelocityInfo swap velocity info of swapin and swapout
+type SwapVelocityInfo struct {
+	PairID  string        `json:"pairid"`
+	Bind    string        `json:"bind,omitempty"`
+	Swapin  *SwapVelocity `json:"swapin"`
+	Swapout *SwapVelocity `json:"swapout"`
 }
 
 // PostResult post result
@@ -54,6 +79,7 @@ type SwapInfo struct {
 	SwapValue     string     `json:"swapvalue"`
 	SwapType      uint32     `json:"swaptype"`
 	SwapNonce     uint64     `json:"swapnonce"`
+	SwapMemo      string     `json:"swapmemo,omitempty"` // output memo if paid by batch tx
 	Status        SwapStatus `json:"status"`
 	StatusMsg     string     `json:"statusmsg"`
 	InitTime      int64      `json:"inittime"`
@@ -61,6 +87,20 @@ type SwapInfo struct {
 	Memo          string     `json:"memo"`
 	ReplaceCount  int        `json:"replaceCount"`
 	Confirmations uint64     `json:"confirmations"`
+	Cursor        string     `json:"cursor,omitempty"` // continuation cursor in history query
+}
+
+// SwapStatusTransition swap status transition
+type SwapStatusTransition struct {
+	IsSwapin     bool       `json:"isswapin"`
+	IsResult     bool       `json:"isresult"`
+	OldStatus    SwapStatus `json:"oldstatus"`
+	OldStatusMsg string     `json:"oldstatusmsg"`
+	NewStatus    SwapStatus `json:"newstatus"`
+	NewStatusMsg string     `json:"newstatusmsg"`
+	Actor        string     `json:"actor"`
+	Memo         string     `json:"memo"`
+	Timestamp    int64      `json:"timestamp"`
 }
 
 // SwapNonceInfo swap nonce info
@@ -8,7 +8,6 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
-	"gopkg.in/mgo.v2"
 )
 
 const (
@@ -29,50 +28,49 @@ func AddToBlacklist(address, pairID string) error {
 		PairID:    strings.ToLower(pairID),
 		Timestamp: time.Now().Unix(),
 	}
-	err := collBlacklist.Insert(mb)
+	err := store.AddToBlacklist(mb)
 	if err == nil {
 		log.Info("mongodb add to black list success", "address", address, "pairID", pairID)
 	} else {
 		log.Info("mongodb add to black list failed", "address", address, "pairID", pairID, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // RemoveFromBlacklist remove from blacklist
 func RemoveFromBlacklist(address, pairID string) error {
-	err := collBlacklist.RemoveId(getBlacklistKey(address, pairID))
+	err := store.RemoveFromBlacklist(getBlacklistKey(address, pairID))
 	if err == nil {
 		log.Info("mongodb remove from black list success", "address", address, "pairID", pairID)
 	} else {
 		log.Info("mongodb remove from black list failed", "address", address, "pairID", pairID, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // QueryBlacklist query if is blacked
 func QueryBlacklist(address, pairID string) (isBlacked bool, err error) {
-	var result MgoBlackAccount
-	err = collBlacklist.FindId(getBlacklistKey(address, pairID)).One(&result)
+	_, err = store.FindBlackAccount(getBlacklistKey(address, pairID))
 	if err == nil {
 		return true, nil
 	}
-	if errors.Is(err, mgo.ErrNotFound) {
+	if errors.Is(err, ErrItemNotFound) {
 		return false, nil
 	}
 	return false, err
 }
 
 // PassSwapinBigValue pass swapin big value
-func PassSwapinBigValue(txid, pairID, bind string) error {
-	return passBigValue(txid, pairID, bind, true)
+func PassSwapinBigValue(txid, pairID, bind, actor string) error {
+	return passBigValue(txid, pairID, bind, true, actor)
 }
 
 // PassSwapoutBigValue pass swapout big value
-func PassSwapoutBigValue(txid, pairID, bind string) error {
-	return passBigValue(txid, pairID, bind, false)
+func PassSwapoutBigValue(txid, pairID, bind, actor string) error {
+	return passBigValue(txid, pairID, bind, false, actor)
 }
 
-func passBigValue(txid, pairID, bind string, isSwapin bool) error {
+func passBigValue(txid, pairID, bind string, isSwapin bool, actor string) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -87,24 +85,24 @@ func passBigValue(txid, pairID, bind string, isSwapin bool) error {
 	if res.SwapTx != "" || res.SwapHeight != 0 || len(res.OldSwapTxs) > 0 {
 		return fmt.Errorf("already swapped with swaptx %v", res.SwapTx)
 	}
-	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxEmpty, time.Now().Unix(), "")
+	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxEmpty, time.Now().Unix(), "", actor)
 	if err != nil {
 		return err
 	}
-	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "")
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "", actor)
 }
 
 // ReverifySwapin reverify swapin
-func ReverifySwapin(txid, pairID, bind string) error {
-	return reverifySwap(txid, pairID, bind, true)
+func ReverifySwapin(txid, pairID, bind, actor string) error {
+	return reverifySwap(txid, pairID, bind, true, actor)
 }
 
 // ReverifySwapout reverify swapout
-func ReverifySwapout(txid, pairID, bind string) error {
-	return reverifySwap(txid, pairID, bind, false)
+func ReverifySwapout(txid, pairID, bind, actor string) error {
+	return reverifySwap(txid, pairID, bind, false, actor)
 }
 
-func reverifySwap(txid, pairID, bind string, isSwapin bool) error {
+func reverifySwap(txid, pairID, bind string, isSwapin bool, actor string) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -112,20 +110,20 @@ func reverifySwap(txid, pairID, bind string, isSwapin bool) error {
 	if !swap.Status.CanReverify() {
 		return fmt.Errorf("swap status is %v, no need to reverify", swap.Status.String())
 	}
-	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), "")
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), "", actor)
 }
 
 // Reswapin reswapin
-func Reswapin(txid, pairID, bind string) error {
-	return reswap(txid, pairID, bind, true)
+func Reswapin(txid, pairID, bind, actor string) error {
+	return reswap(txid, pairID, bind, true, actor)
 }
 
 // Reswapout reswapout
-func Reswapout(txid, pairID, bind string) error {
-	return reswap(txid, pairID, bind, false)
+func Reswapout(txid, pairID, bind, actor string) error {
+	return reswap(txid, pairID, bind, false, actor)
 }
 
-func reswap(txid, pairID, bind string, isSwapin bool) error {
+func reswap(txid, pairID, bind string, isSwapin bool, actor string) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -143,12 +141,12 @@ func reswap(txid, pairID, bind string, isSwapin bool) error {
 	}
 
 	log.Info("[reswap] update status to TxNotSwapped to retry", "txid", txid, "pairID", pairID, "bind", bind, "swaptx", swapResult.SwapTx)
-	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, Reswapping, time.Now().Unix(), "")
+	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, Reswapping, time.Now().Unix(), "", actor)
 	if err != nil {
 		return err
 	}
 
-	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "")
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "", actor)
 }
 
 func checkCanReswap(res *MgoSwapResult, isSwapin bool) error {
@@ -216,21 +214,27 @@ func checkReswapNonce(bridge tokens.CrossChainBridge, res *MgoSwapResult) (err e
 }
 
 // ManualManageSwap manual manage swap
-func ManualManageSwap(txid, pairID, bind, memo string, isSwapin, isPass bool) error {
+func ManualManageSwap(txid, pairID, bind, memo string, isSwapin, isPass bool, actor string) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
 	}
 	if isPass {
 		if swap.Status == TxWithBigValue {
-			return passBigValue(txid, pairID, bind, isSwapin)
+			return passBigValue(txid, pairID, bind, isSwapin, actor)
+		}
+		if swap.Status == TxExceedsRateLimit {
+			return PassSwapRateLimit(isSwapin, txid, pairID, bind, actor, nil)
+		}
+		if swap.Status == TxReorged {
+			return PassSwapReorged(isSwapin, txid, pairID, bind, memo, actor)
 		}
 		if swap.Status.CanReverify() || swap.Status == ManualMakeFail {
-			return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), memo)
+			return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), memo, actor)
 		}
 	} else if swap.Status.CanManualMakeFail() {
-		_ = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, ManualMakeFail, time.Now().Unix(), memo)
-		return UpdateSwapStatus(isSwapin, txid, pairID, bind, ManualMakeFail, time.Now().Unix(), memo)
+		_ = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, ManualMakeFail, time.Now().Unix(), memo, actor)
+		return UpdateSwapStatus(isSwapin, txid, pairID, bind, ManualMakeFail, time.Now().Unix(), memo, actor)
 	}
 	return fmt.Errorf("swap status is %v, can not operate. txid=%v pairID=%v bind=%v isSwapin=%v isPass=%v", swap.Status.String(), txid, pairID, bind, isSwapin, isPass)
 }
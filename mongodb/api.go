@@ -1,390 +1,329 @@
 package mongodb
 
 import (
+	"errors"
 	"fmt"
-	"math/big"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
-	"gopkg.in/mgo.v2"
-	"gopkg.in/mgo.v2/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
-	maxCountOfResults = 5000
-	allPairs          = "all"
-	allAddresses      = "all"
+	maxCountOfResults        = 5000
+	maxCountOfReplaceResults = 5
+	allPairs                 = "all"
+	allAddresses             = "all"
 )
 
 var (
 	retryLock        sync.Mutex
 	updateResultLock sync.Mutex
+	statisticsLock   sync.Mutex
 )
 
 // --------------- swapin and swapout uniform --------------------------------
 
 // UpdateSwapStatus update swap status
-func UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	if isSwapin {
-		return updateSwapStatus(collSwapin, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	pairID = strings.ToLower(pairID)
+	if status == TxNotStable {
+		retryLock.Lock()
+		defer retryLock.Unlock()
 	}
-	return updateSwapStatus(collSwapout, txid, pairID, bind, status, timestamp, memo)
+	swap, _ := store.FindSwap(isSwapin, txid, pairID, bind)
+	if status == TxNotStable {
+		if swap == nil || !(swap.Status.CanRetry() || swap.Status.CanReverify() || swap.Status == TxReorged) {
+			return nil
+		}
+	}
+	err := store.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+	if err == nil {
+		printLog := log.Info
+		switch status {
+		case TxVerifyFailed, TxSwapFailed:
+			printLog = log.Warn
+		default:
+		}
+		printLog("mongodb update swap status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "actor", actor)
+		if swap != nil {
+			addSwapStatusHistory(isSwapin, false, txid, pairID, bind, swap.Status, status, timestamp, memo, actor)
+		}
+	} else {
+		log.Debug("mongodb update swap status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "actor", actor, "err", err)
+	}
+	return err
 }
 
 // UpdateSwapResultStatus update swap result status
-func UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	if isSwapin {
-		return updateSwapResultStatus(collSwapinResult, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	pairID = strings.ToLower(pairID)
+	swapResult, _ := store.FindSwapResult(isSwapin, txid, pairID, bind)
+	err := store.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+	if err == nil {
+		log.Info("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "actor", actor)
+		if swapResult != nil {
+			addSwapStatusHistory(isSwapin, true, txid, pairID, bind, swapResult.Status, status, timestamp, memo, actor)
+		}
+	} else {
+		log.Debug("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "actor", actor, "err", err)
+	}
+	if err == nil && swapResult != nil {
+		// count stable swap once, and revert it if it leaves the stable status
+		// (eg. reorged), it is counted again if it becomes stable again
+		wasStable := swapResult.Status == MatchTxStable
+		switch {
+		case status == MatchTxStable && !wasStable:
+			if newResult, errq := store.FindSwapResult(isSwapin, txid, pairID, bind); errq == nil {
+				_ = updateSwapStatistics(pairID, newResult.Value, newResult.SwapValue, isSwapin, timestamp, false)
+			}
+		case status != MatchTxStable && wasStable:
+			_ = updateSwapStatistics(pairID, swapResult.Value, swapResult.SwapValue, isSwapin, swapResult.Timestamp, true)
+		}
 	}
-	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo)
+	return err
 }
 
-// FindSwapResult find swap result
+// FindSwapResult find swap result (fallback to archived swap result)
 func FindSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error) {
-	if isSwapin {
-		return findSwapResult(collSwapinResult, txid, pairID, bind)
+	pairID = strings.ToLower(pairID)
+	result, err := store.FindSwapResult(isSwapin, txid, pairID, bind)
+	if errors.Is(err, ErrItemNotFound) {
+		if archived, erra := store.FindArchivedSwapResult(isSwapin, txid, pairID, bind); erra == nil {
+			return archived, nil
+		}
 	}
-	return findSwapResult(collSwapoutResult, txid, pairID, bind)
+	return result, err
 }
 
-// FindSwap find swap
+// FindSwap find swap (fallback to archived swap)
 func FindSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error) {
-	if isSwapin {
-		return findSwap(collSwapin, txid, pairID, bind)
+	pairID = strings.ToLower(pairID)
+	result, err := store.FindSwap(isSwapin, txid, pairID, bind)
+	if errors.Is(err, ErrItemNotFound) {
+		if archived, erra := store.FindArchivedSwap(isSwapin, txid, pairID, bind); erra == nil {
+			return archived, nil
+		}
 	}
-	return findSwap(collSwapout, txid, pairID, bind)
+	return result, err
+}
+
+// GetSwapKey txid + pairID + bind
+func GetSwapKey(txid, pairID, bind string) string {
+	return strings.ToLower(txid + ":" + pairID + ":" + bind)
 }
 
 // --------------- swapin --------------------------------
 
 // AddSwapin add swapin
 func AddSwapin(ms *MgoSwap) error {
-	return addSwap(collSwapin, ms)
+	return addSwap(true, ms)
 }
 
 // UpdateSwapinStatus update swapin status
-func UpdateSwapinStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapStatus(collSwapin, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapinStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return UpdateSwapStatus(true, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapin find swapin
 func FindSwapin(txid, pairID, bind string) (*MgoSwap, error) {
-	return findSwap(collSwapin, txid, pairID, bind)
+	return FindSwap(true, txid, pairID, bind)
 }
 
 // FindSwapinsWithStatus find swapin with status in the past septime
 func FindSwapinsWithStatus(status SwapStatus, septime int64) ([]*MgoSwap, error) {
-	return findSwapsWithStatus(collSwapin, status, septime)
+	return store.FindSwapsWithStatus(true, status, septime)
 }
 
 // FindSwapinsWithPairIDAndStatus find swapin with pairID and status in the past septime
 func FindSwapinsWithPairIDAndStatus(pairID string, status SwapStatus, septime int64) ([]*MgoSwap, error) {
-	return findSwapsWithPairIDAndStatus(pairID, collSwapin, status, septime)
+	return store.FindSwapsWithPairIDAndStatus(true, strings.ToLower(pairID), status, septime)
 }
 
 // GetCountOfSwapinsWithStatus get count of swapins with status
 func GetCountOfSwapinsWithStatus(pairID string, status SwapStatus) (int, error) {
-	return getCountWithStatus(collSwapin, pairID, status)
+	return store.GetCountOfSwapsWithStatus(true, strings.ToLower(pairID), status)
 }
 
 // --------------- swapout --------------------------------
 
 // AddSwapout add swapout
 func AddSwapout(ms *MgoSwap) error {
-	return addSwap(collSwapout, ms)
+	return addSwap(false, ms)
 }
 
 // UpdateSwapoutStatus update swapout status
-func UpdateSwapoutStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapStatus(collSwapout, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapoutStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return UpdateSwapStatus(false, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapout find swapout
 func FindSwapout(txid, pairID, bind string) (*MgoSwap, error) {
-	return findSwap(collSwapout, txid, pairID, bind)
+	return FindSwap(false, txid, pairID, bind)
 }
 
 // FindSwapoutsWithStatus find swapout with status
 func FindSwapoutsWithStatus(status SwapStatus, septime int64) ([]*MgoSwap, error) {
-	return findSwapsWithStatus(collSwapout, status, septime)
+	return store.FindSwapsWithStatus(false, status, septime)
 }
 
 // FindSwapoutsWithPairIDAndStatus find swapout with pairID and status in the past septime
 func FindSwapoutsWithPairIDAndStatus(pairID string, status SwapStatus, septime int64) ([]*MgoSwap, error) {
-	return findSwapsWithPairIDAndStatus(pairID, collSwapout, status, septime)
+	return store.FindSwapsWithPairIDAndStatus(false, strings.ToLower(pairID), status, septime)
 }
 
 // GetCountOfSwapoutsWithStatus get count of swapout with status
 func GetCountOfSwapoutsWithStatus(pairID string, status SwapStatus) (int, error) {
-	return getCountWithStatus(collSwapout, pairID, status)
+	return store.GetCountOfSwapsWithStatus(false, strings.ToLower(pairID), status)
 }
 
 // ------------------ swapin / swapout common ------------------------
 
-func addSwap(collection *mgo.Collection, ms *MgoSwap) error {
+func addSwap(isSwapin bool, ms *MgoSwap) error {
 	if ms.TxID == "" || ms.PairID == "" || ms.Bind == "" {
-		log.Error("mongodb add swap with wrong key", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin(collection))
+		log.Error("mongodb add swap with wrong key", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin)
 		return ErrWrongKey
 	}
 	ms.PairID = strings.ToLower(ms.PairID)
 	ms.Key = GetSwapKey(ms.TxID, ms.PairID, ms.Bind)
 	ms.InitTime = common.NowMilli()
-	err := collection.Insert(ms)
-	if err == nil {
-		log.Info("mongodb add swap", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin(collection))
-	} else {
-		log.Debug("mongodb add swap", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin(collection), "err", err)
+	if archived, _ := store.FindArchivedSwap(isSwapin, ms.TxID, ms.PairID, ms.Bind); archived != nil {
+		return ErrItemIsDup
 	}
-	return mgoError(err)
-}
-
-func updateSwapStatus(collection *mgo.Collection, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	pairID = strings.ToLower(pairID)
-	updates := bson.M{"status": status, "timestamp": timestamp}
-	if memo != "" {
-		updates["memo"] = memo
-	} else if status == TxNotSwapped || status == TxNotStable {
-		updates["memo"] = ""
-	}
-	if status == TxNotStable {
-		retryLock.Lock()
-		defer retryLock.Unlock()
-		swap, _ := findSwap(collection, txid, pairID, bind)
-		if !(swap.Status.CanRetry() || swap.Status.CanReverify()) {
-			return nil
-		}
-	}
-	err := collection.UpdateId(GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	err := store.AddSwap(isSwapin, ms)
 	if err == nil {
-		printLog := log.Info
-		switch status {
-		case TxVerifyFailed, TxSwapFailed:
-			printLog = log.Warn
-		default:
-		}
-		printLog("mongodb update swap status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin(collection))
+		log.Info("mongodb add swap", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin)
+		addInitialStatusHistory(isSwapin, false, ms.TxID, ms.PairID, ms.Bind, ms.Status, ms.Timestamp, ms.Memo)
 	} else {
-		log.Debug("mongodb update swap status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin(collection), "err", err)
+		log.Debug("mongodb add swap", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
-}
-
-// GetSwapKey txid + pairID + bind
-func GetSwapKey(txid, pairID, bind string) string {
-	return strings.ToLower(txid + ":" + pairID + ":" + bind)
-}
-
-func findSwap(collection *mgo.Collection, txid, pairID, bind string) (*MgoSwap, error) {
-	result := &MgoSwap{}
-	err := findSwapOrSwapResult(result, collection, txid, pairID, bind)
-	if err != nil {
-		return nil, err
-	}
-	return result, nil
-}
-
-func findSwapOrSwapResult(result interface{}, collection *mgo.Collection, txid, pairID, bind string) (err error) {
-	if bind != "" {
-		err = collection.FindId(GetSwapKey(txid, pairID, bind)).One(result)
-	} else {
-		qtxid := bson.M{"txid": txid}
-		qpair := bson.M{"pairid": strings.ToLower(pairID)}
-		queries := []bson.M{qtxid, qpair}
-		err = collection.Find(bson.M{"$and": queries}).One(result)
-	}
-	return mgoError(err)
-}
-
-func findSwapsWithStatus(collection *mgo.Collection, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
-	err = findSwapsOrSwapResultsWithStatus(&result, collection, status, septime)
-	return result, err
-}
-
-func findSwapsOrSwapResultsWithStatus(result interface{}, collection *mgo.Collection, status SwapStatus, septime int64) error {
-	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
-	qstatus := bson.M{"status": status}
-	queries := []bson.M{qtime, qstatus}
-	q := collection.Find(bson.M{"$and": queries}).Sort("inittime").Limit(maxCountOfResults)
-	return mgoError(q.All(result))
-}
-
-func findSwapsWithPairIDAndStatus(pairID string, collection *mgo.Collection, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
-	err = findSwapsOrSwapResultsWithPairIDAndStatus(&result, pairID, collection, status, septime)
-	return result, err
-}
-
-func findSwapsOrSwapResultsWithPairIDAndStatus(result interface{}, pairID string, collection *mgo.Collection, status SwapStatus, septime int64) error {
-	pairID = strings.ToLower(pairID)
-	qpair := bson.M{"pairid": pairID}
-	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
-	qstatus := bson.M{"status": status}
-	queries := []bson.M{qpair, qtime, qstatus}
-	q := collection.Find(bson.M{"$and": queries}).Sort("inittime").Limit(maxCountOfResults)
-	return mgoError(q.All(result))
+	return err
 }
 
 // --------------- swapin result --------------------------------
 
 // AddSwapinResult add swapin result
 func AddSwapinResult(mr *MgoSwapResult) error {
-	return addSwapResult(collSwapinResult, mr)
+	return addSwapResult(true, mr)
 }
 
 // UpdateSwapinResult update swapin result
 func UpdateSwapinResult(txid, pairID, bind string, items *SwapResultUpdateItems) error {
-	return updateSwapResult(collSwapinResult, txid, pairID, bind, items)
+	return updateSwapResult(true, txid, pairID, bind, items)
 }
 
 // UpdateSwapinResultStatus update swapin result status
-func UpdateSwapinResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapResultStatus(collSwapinResult, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapinResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return UpdateSwapResultStatus(true, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapinResult find swapin result
 func FindSwapinResult(txid, pairID, bind string) (*MgoSwapResult, error) {
-	return findSwapResult(collSwapinResult, txid, pairID, bind)
+	return FindSwapResult(true, txid, pairID, bind)
 }
 
 // FindSwapinResultsWithStatus find swapin result with status
 func FindSwapinResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
-	return findSwapResultsWithStatus(collSwapinResult, status, septime)
+	return store.FindSwapResultsWithStatus(true, status, septime)
 }
 
 // FindSwapinResults find swapin history results
-func FindSwapinResults(address, pairID string, offset, limit int, status string) ([]*MgoSwapResult, error) {
-	return findSwapResults(collSwapinResult, address, pairID, offset, limit, status)
+func FindSwapinResults(filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	return findSwapResults(true, filter)
 }
 
 // FindSwapResultsToReplace find swap results to replace
 func FindSwapResultsToReplace(status SwapStatus, septime int64, isSwapin bool) ([]*MgoSwapResult, error) {
-	qstatus := bson.M{"status": status}
-	qheight := bson.M{"swapheight": 0}
-	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
-	queries := []bson.M{qstatus, qheight, qtime}
-	var collection *mgo.Collection
-	if isSwapin {
-		collection = collSwapinResult
-	} else {
-		collection = collSwapoutResult
-	}
-	result := make([]*MgoSwapResult, 0, 20)
-	q := collection.Find(bson.M{"$and": queries}).Sort("inittime").Limit(5)
-	err := q.All(&result)
-	return result, mgoError(err)
+	return store.FindSwapResultsToReplace(isSwapin, status, septime)
 }
 
 // GetCountOfSwapinResults get count of swapin results
 func GetCountOfSwapinResults(pairID string) (int, error) {
-	return getCount(collSwapinResult, pairID)
+	return store.GetCountOfSwapResults(true, strings.ToLower(pairID))
 }
 
 // GetCountOfSwapinResultsWithStatus get count of swapin results with status
 func GetCountOfSwapinResultsWithStatus(pairID string, status SwapStatus) (int, error) {
-	return getCountWithStatus(collSwapinResult, pairID, status)
+	return store.GetCountOfSwapResultsWithStatus(true, strings.ToLower(pairID), status)
 }
 
 // --------------- swapout result --------------------------------
 
 // AddSwapoutResult add swapout result
 func AddSwapoutResult(mr *MgoSwapResult) error {
-	return addSwapResult(collSwapoutResult, mr)
+	return addSwapResult(false, mr)
 }
 
 // UpdateSwapoutResult update swapout result
 func UpdateSwapoutResult(txid, pairID, bind string, items *SwapResultUpdateItems) error {
-	return updateSwapResult(collSwapoutResult, txid, pairID, bind, items)
+	return updateSwapResult(false, txid, pairID, bind, items)
 }
 
 // UpdateSwapoutResultStatus update swapout result status
-func UpdateSwapoutResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapoutResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return UpdateSwapResultStatus(false, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapoutResult find swapout result
 func FindSwapoutResult(txid, pairID, bind string) (*MgoSwapResult, error) {
-	return findSwapResult(collSwapoutResult, txid, pairID, bind)
+	return FindSwapResult(false, txid, pairID, bind)
 }
 
 // FindSwapoutResultsWithStatus find swapout result with status
 func FindSwapoutResultsWithStatus(status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
-	return findSwapResultsWithStatus(collSwapoutResult, status, septime)
+	return store.FindSwapResultsWithStatus(false, status, septime)
 }
 
 // FindSwapoutResults find swapout history results
-func FindSwapoutResults(address, pairID string, offset, limit int, status string) ([]*MgoSwapResult, error) {
-	return findSwapResults(collSwapoutResult, address, pairID, offset, limit, status)
+func FindSwapoutResults(filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	return findSwapResults(false, filter)
 }
 
 // GetCountOfSwapoutResults get count of swapout results
 func GetCountOfSwapoutResults(pairID string) (int, error) {
-	return getCount(collSwapoutResult, pairID)
+	return store.GetCountOfSwapResults(false, strings.ToLower(pairID))
 }
 
 // GetCountOfSwapoutResultsWithStatus get count of swapout results with status
 func GetCountOfSwapoutResultsWithStatus(pairID string, status SwapStatus) (int, error) {
-	return getCountWithStatus(collSwapoutResult, pairID, status)
+	return store.GetCountOfSwapResultsWithStatus(false, strings.ToLower(pairID), status)
 }
 
 // ------------------ swapin / swapout result common ------------------------
 
-func addSwapResult(collection *mgo.Collection, ms *MgoSwapResult) error {
+func addSwapResult(isSwapin bool, ms *MgoSwapResult) error {
 	if ms.TxID == "" || ms.PairID == "" || ms.Bind == "" {
-		log.Error("mongodb add swap result with wrong key", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "isSwapin", isSwapin(collection))
+		log.Error("mongodb add swap result with wrong key", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "isSwapin", isSwapin)
 		return ErrWrongKey
 	}
 	ms.PairID = strings.ToLower(ms.PairID)
 	ms.Key = GetSwapKey(ms.TxID, ms.PairID, ms.Bind)
+	ms.ValueKey = GetValueKey(ms.Value)
 	ms.InitTime = common.NowMilli()
-	err := collection.Insert(ms)
+	if archived, _ := store.FindArchivedSwapResult(isSwapin, ms.TxID, ms.PairID, ms.Bind); archived != nil {
+		return ErrItemIsDup
+	}
+	err := store.AddSwapResult(isSwapin, ms)
 	if err == nil {
-		log.Info("mongodb add swap result", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "value", ms.Value, "isSwapin", isSwapin(collection))
+		log.Info("mongodb add swap result", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "value", ms.Value, "isSwapin", isSwapin)
+		addInitialStatusHistory(isSwapin, true, ms.TxID, ms.PairID, ms.Bind, ms.Status, ms.Timestamp, ms.Memo)
 	} else {
-		log.Debug("mongodb add swap result", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "value", ms.Value, "isSwapin", isSwapin(collection), "err", err)
+		log.Debug("mongodb add swap result", "txid", ms.TxID, "pairID", ms.PairID, "bind", ms.Bind, "swaptype", ms.SwapType, "value", ms.Value, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-func updateSwapResult(collection *mgo.Collection, txid, pairID, bind string, items *SwapResultUpdateItems) error {
+func updateSwapResult(isSwapin bool, txid, pairID, bind string, items *SwapResultUpdateItems) error {
 	pairID = strings.ToLower(pairID)
-	updates := bson.M{
-		"timestamp": items.Timestamp,
-	}
-	if items.Status != KeepStatus {
-		updates["status"] = items.Status
-	}
-	if items.SwapTx != "" {
-		updates["swaptx"] = items.SwapTx
-	}
-	if len(items.OldSwapTxs) != 0 {
-		updates["oldswaptxs"] = items.OldSwapTxs
-	}
-	if len(items.OldSwapVals) != 0 {
-		updates["oldswapvals"] = items.OldSwapVals
-	}
-	if items.SwapHeight != 0 {
-		updates["swapheight"] = items.SwapHeight
-	}
-	if items.SwapTime != 0 {
-		updates["swaptime"] = items.SwapTime
-	}
-	if items.SwapValue != "" {
-		updates["swapvalue"] = items.SwapValue
-	}
-	if items.SwapType != 0 {
-		updates["swaptype"] = items.SwapType
-	}
-	if items.Memo != "" {
-		updates["memo"] = items.Memo
-	} else if items.Status == MatchTxNotStable {
-		updates["memo"] = ""
-	}
+	var swapRes *MgoSwapResult
 	if items.SwapNonce != 0 || items.Status == MatchTxNotStable {
 		updateResultLock.Lock()
 		defer updateResultLock.Unlock()
-		swapRes, err := findSwapResult(collection, txid, pairID, bind)
+		var err error
+		swapRes, err = store.FindSwapResult(isSwapin, txid, pairID, bind)
 		if err != nil {
 			return err
 		}
@@ -396,60 +335,123 @@ func updateSwapResult(collection *mgo.Collection, txid, pairID, bind string, ite
 			log.Error("forbid update swap tx again", "old", swapRes.SwapTx, "new", items.SwapTx)
 			return ErrForbidUpdateSwapTx
 		}
-		if items.SwapNonce != 0 {
-			updates["swapnonce"] = items.SwapNonce
-		}
+	} else if items.Status != KeepStatus {
+		swapRes, _ = store.FindSwapResult(isSwapin, txid, pairID, bind)
 	}
-	err := collection.UpdateId(GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	err := store.UpdateSwapResult(isSwapin, txid, pairID, bind, items)
 	if err == nil {
-		log.Info("mongodb update swap result", "txid", txid, "pairID", pairID, "bind", bind, "updates", updates, "isSwapin", isSwapin(collection))
+		log.Info("mongodb update swap result", "txid", txid, "pairID", pairID, "bind", bind, "items", items, "isSwapin", isSwapin)
+		if swapRes != nil && items.Status != KeepStatus {
+			addSwapStatusHistory(isSwapin, true, txid, pairID, bind, swapRes.Status, items.Status, items.Timestamp, items.Memo, items.Actor)
+		}
 	} else {
-		log.Debug("mongodb update swap result", "txid", txid, "pairID", pairID, "bind", bind, "updates", updates, "isSwapin", isSwapin(collection), "err", err)
+		log.Debug("mongodb update swap result", "txid", txid, "pairID", pairID, "bind", bind, "items", items, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
-func updateSwapResultStatus(collection *mgo.Collection, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	pairID = strings.ToLower(pairID)
-	updates := bson.M{"status": status, "timestamp": timestamp}
-	if memo != "" {
-		updates["memo"] = memo
-	}
-	if status == Reswapping {
-		updates["memo"] = ""
-		updates["swaptx"] = ""
-		updates["oldswaptxs"] = nil
-		updates["swapheight"] = 0
-		updates["swaptime"] = 0
-		updates["swapnonce"] = 0
-	}
-	err := collection.UpdateId(GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
-	isSwapin := isSwapin(collection)
+// CommitSwapTx commit signed swap tx with swap result, swap status,
+// swap history and swap nonce atomically (before sending the swap tx)
+func CommitSwapTx(commit *SwapTxCommit) error {
+	items := commit.ResultItems
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+	swapRes, swap, err := checkSwapTxCommit(commit)
+	if err != nil {
+		return err
+	}
+	err = store.CommitSwapTx(commit)
 	if err == nil {
-		log.Info("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin)
+		log.Info("mongodb commit swap tx", "txid", commit.TxID, "pairID", commit.PairID, "bind", commit.Bind, "swaptx", items.SwapTx, "swapnonce", items.SwapNonce, "status", commit.SwapStatus, "isSwapin", commit.IsSwapin)
+		addSwapTxCommitStatusHistory(commit, swapRes, swap)
 	} else {
-		log.Debug("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "err", err)
+		log.Warn("mongodb commit swap tx failed", "txid", commit.TxID, "pairID", commit.PairID, "bind", commit.Bind, "swaptx", items.SwapTx, "swapnonce", items.SwapNonce, "isSwapin", commit.IsSwapin, "err", err)
 	}
-	if status == MatchTxStable {
-		if swapResult, errq := findSwapResult(collection, txid, pairID, bind); errq == nil {
-			_ = updateSwapStatistics(pairID, swapResult.Value, swapResult.SwapValue, isSwapin)
+	return err
+}
+
+// CommitBatchSwapTx commit signed batch tx with records of all the swaps
+// paid by it atomically (before sending the batch tx)
+func CommitBatchSwapTx(commits []*SwapTxCommit) error {
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+	swapResults := make([]*MgoSwapResult, len(commits))
+	swaps := make([]*MgoSwap, len(commits))
+	for i, commit := range commits {
+		swapRes, swap, err := checkSwapTxCommit(commit)
+		if err != nil {
+			return err
 		}
+		swapResults[i], swaps[i] = swapRes, swap
 	}
-	return mgoError(err)
+	err := store.CommitBatchSwapTx(commits)
+	if err != nil {
+		log.Warn("mongodb commit batch swap tx failed", "count", len(commits), "err", err)
+		return err
+	}
+	for i, commit := range commits {
+		items := commit.ResultItems
+		log.Info("mongodb commit batch swap tx", "txid", commit.TxID, "pairID", commit.PairID, "bind", commit.Bind, "swaptx", items.SwapTx, "swapmemo", items.SwapMemo, "status", commit.SwapStatus, "isSwapin", commit.IsSwapin)
+		addSwapTxCommitStatusHistory(commit, swapResults[i], swaps[i])
+	}
+	return nil
 }
 
-func findSwapResult(collection *mgo.Collection, txid, pairID, bind string) (*MgoSwapResult, error) {
-	result := &MgoSwapResult{}
-	err := findSwapOrSwapResult(result, collection, txid, pairID, bind)
+func checkSwapTxCommit(commit *SwapTxCommit) (swapRes *MgoSwapResult, swap *MgoSwap, err error) {
+	commit.PairID = strings.ToLower(commit.PairID)
+	items := commit.ResultItems
+	swapRes, err = store.FindSwapResult(commit.IsSwapin, commit.TxID, commit.PairID, commit.Bind)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return result, nil
+	if swapRes.SwapNonce != 0 {
+		log.Error("forbid update swap nonce again", "old", swapRes.SwapNonce, "new", items.SwapNonce)
+		return nil, nil, ErrForbidUpdateNonce
+	}
+	if swapRes.SwapTx != "" {
+		log.Error("forbid update swap tx again", "old", swapRes.SwapTx, "new", items.SwapTx)
+		return nil, nil, ErrForbidUpdateSwapTx
+	}
+	if commit.SwapNonce != nil {
+		oldItem, _ := FindLatestSwapNonce(commit.SwapNonce.Key)
+		if oldItem != nil && oldItem.SwapNonce >= commit.SwapNonce.SwapNonce {
+			commit.SwapNonce = nil // only increase
+		}
+	}
+	swap, _ = store.FindSwap(commit.IsSwapin, commit.TxID, commit.PairID, commit.Bind)
+	return swapRes, swap, nil
 }
 
-func findSwapResultsWithStatus(collection *mgo.Collection, status SwapStatus, septime int64) (result []*MgoSwapResult, err error) {
-	err = findSwapsOrSwapResultsWithStatus(&result, collection, status, septime)
-	return result, err
+func addSwapTxCommitStatusHistory(commit *SwapTxCommit, swapRes *MgoSwapResult, swap *MgoSwap) {
+	items := commit.ResultItems
+	if items.Status != KeepStatus {
+		addSwapStatusHistory(commit.IsSwapin, true, commit.TxID, commit.PairID, commit.Bind, swapRes.Status, items.Status, items.Timestamp, items.Memo, commit.Actor)
+	}
+	if swap != nil {
+		addSwapStatusHistory(commit.IsSwapin, false, commit.TxID, commit.PairID, commit.Bind, swap.Status, commit.SwapStatus, items.Timestamp, "", commit.Actor)
+	}
+}
+
+// NewSwapHistory new swap history item
+func NewSwapHistory(isSwapin bool, txid, bind, swaptx string) *MgoSwapHistory {
+	return &MgoSwapHistory{
+		Key:      primitive.NewObjectID(),
+		IsSwapin: isSwapin,
+		TxID:     txid,
+		Bind:     bind,
+		SwapTx:   swaptx,
+	}
+}
+
+// NewLatestSwapNonce new latest swap nonce item
+func NewLatestSwapNonce(address string, isSwapin bool, nonce uint64) *MgoLatestSwapNonce {
+	return &MgoLatestSwapNonce{
+		Key:       getSwapNonceKey(address, isSwapin),
+		Address:   strings.ToLower(address),
+		IsSwapin:  isSwapin,
+		SwapNonce: nonce,
+		Timestamp: time.Now().Unix(),
+	}
 }
 
 func getStatusesFromStr(status string) []SwapStatus {
@@ -467,126 +469,44 @@ func getStatusesFromStr(status string) []SwapStatus {
 	return result
 }
 
-func findSwapResults(collection *mgo.Collection, address, pairID string, offset, limit int, status string) ([]*MgoSwapResult, error) {
-	pairID = strings.ToLower(pairID)
-	result := make([]*MgoSwapResult, 0, 20)
-
-	var queries []bson.M
-
-	if pairID != "" && pairID != allPairs {
-		queries = append(queries, bson.M{"pairid": pairID})
-	}
-
-	if address != "" && address != allAddresses {
-		if common.IsHexAddress(address) {
-			address = strings.ToLower(address)
-		}
-		queries = append(queries, bson.M{"from": address})
-	}
-
-	filterStatuses := getStatusesFromStr(status)
-	if len(filterStatuses) > 0 {
-		if len(filterStatuses) == 1 {
-			queries = append(queries, bson.M{"status": filterStatuses[0]})
-		} else {
-			qstatus := bson.M{"status": bson.M{"$in": filterStatuses}}
-			queries = append(queries, qstatus)
-		}
-	}
-
-	var q *mgo.Query
-	switch len(queries) {
-	case 0:
-		q = collection.Find(nil)
-	case 1:
-		q = collection.Find(queries[0])
-	default:
-		q = collection.Find(bson.M{"$and": queries})
-	}
-	if limit >= 0 {
-		q = q.Skip(offset).Limit(limit)
-	} else {
-		q = q.Sort("-inittime").Skip(offset).Limit(-limit)
-	}
-	err := q.All(&result)
-	if err != nil {
-		return nil, mgoError(err)
+func findSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	if err := filter.normalize(); err != nil {
+		return nil, err
 	}
-	return result, nil
-}
-
-func getCount(collection *mgo.Collection, pairID string) (int, error) {
-	pairID = strings.ToLower(pairID)
-	return collection.Find(bson.M{"pairid": pairID}).Count()
-}
-
-func getCountWithStatus(collection *mgo.Collection, pairID string, status SwapStatus) (int, error) {
-	pairID = strings.ToLower(pairID)
-	qpair := bson.M{"pairid": pairID}
-	qstatus := bson.M{"status": status}
-	queries := []bson.M{qpair, qstatus}
-	return collection.Find(bson.M{"$and": queries}).Count()
+	return store.FindSwapResults(isSwapin, filter)
 }
 
 // ------------------ statistics ------------------------
 
-func updateSwapStatistics(pairID, value, swapValue string, isSwapin bool) error {
+// updateSwapStatistics add stable swap to statistics, or sub it if isSub
+func updateSwapStatistics(pairID, value, swapValue string, isSwapin bool, timestamp int64, isSub bool) error {
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
 	pairID = strings.ToLower(pairID)
-	curr, _ := FindSwapStatistics(pairID)
+	curr, _ := store.FindSwapStatistics(pairID)
 	if curr == nil {
 		curr = &MgoSwapStatistics{
-			Key:                pairID,
-			PairID:             pairID,
-			StableSwapinCount:  0,
-			TotalSwapinValue:   "0",
-			TotalSwapinFee:     "0",
-			StableSwapoutCount: 0,
-			TotalSwapoutValue:  "0",
-			TotalSwapoutFee:    "0",
+			Key:                  pairID,
+			PairID:               pairID,
+			SwapStatisticsValues: newSwapStatisticsValues(),
 		}
-		_ = collSwapStatistics.Insert(curr)
 	}
-
-	addVal, _ := new(big.Int).SetString(value, 0)
-	addSwapVal, _ := new(big.Int).SetString(swapValue, 0)
-	addSwapFee := new(big.Int).Sub(addVal, addSwapVal)
-
-	curVal := big.NewInt(0)
-	curFee := big.NewInt(0)
-
-	updates := bson.M{}
-	if isSwapin {
-		curVal.SetString(curr.TotalSwapinValue, 0)
-		curFee.SetString(curr.TotalSwapinFee, 0)
-		curVal.Add(curVal, addSwapVal)
-		curFee.Add(curFee, addSwapFee)
-		updates["swapincount"] = curr.StableSwapinCount + 1
-		updates["totalswapinvalue"] = curVal.String()
-		updates["totalswapinfee"] = curFee.String()
-	} else {
-		curVal.SetString(curr.TotalSwapoutValue, 0)
-		curFee.SetString(curr.TotalSwapoutFee, 0)
-		curVal.Add(curVal, addSwapVal)
-		curFee.Add(curFee, addSwapFee)
-		updates["swapoutcount"] = curr.StableSwapoutCount + 1
-		updates["totalswapoutvalue"] = curVal.String()
-		updates["totalswapoutfee"] = curFee.String()
-	}
-	err := collSwapStatistics.UpdateId(pairID, bson.M{"$set": updates})
+	curr.update(isSwapin, value, swapValue, isSub)
+	err := store.UpdateSwapStatistics(curr)
 	if err == nil {
-		log.Info("mongodb update swap statistics", "updates", updates)
+		log.Info("mongodb update swap statistics", "statistics", curr)
 	} else {
-		log.Debug("mongodb update swap statistics", "updates", updates, "err", err)
+		log.Debug("mongodb update swap statistics", "statistics", curr, "err", err)
+		return err
 	}
-	return mgoError(err)
+	updateSwapStatisticsBuckets(pairID, value, swapValue, isSwapin, timestamp, isSub)
+	return nil
 }
 
 // FindSwapStatistics find swap statistics
 func FindSwapStatistics(pairID string) (*MgoSwapStatistics, error) {
-	pairID = strings.ToLower(pairID)
-	var result MgoSwapStatistics
-	err := collSwapStatistics.FindId(pairID).One(&result)
-	return &result, mgoError(err)
+	return store.FindSwapStatistics(strings.ToLower(pairID))
 }
 
 // SwapStatistics rpc return struct
@@ -630,48 +550,62 @@ func GetSwapStatistics(pairID string) (*SwapStatistics, error) {
 
 // AddP2shAddress add p2sh address
 func AddP2shAddress(ma *MgoP2shAddress) error {
-	err := collP2shAddress.Insert(ma)
+	err := store.AddP2shAddress(ma)
 	if err == nil {
 		log.Info("mongodb add p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress)
 	} else {
 		log.Debug("mongodb add p2sh address", "key", ma.Key, "p2shaddress", ma.P2shAddress, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // FindP2shAddress find p2sh addrss through bind address
 func FindP2shAddress(key string) (*MgoP2shAddress, error) {
-	var result MgoP2shAddress
-	err := collP2shAddress.FindId(key).One(&result)
-	if err != nil {
-		return nil, mgoError(err)
-	}
-	return &result, nil
+	return store.FindP2shAddress(key)
 }
 
 // FindP2shBindAddress find bind address through p2sh address
 func FindP2shBindAddress(p2shAddress string) (string, error) {
-	var result MgoP2shAddress
-	err := collP2shAddress.Find(bson.M{"p2shaddress": p2shAddress}).One(&result)
-	if err != nil {
-		return "", mgoError(err)
-	}
-	return result.Key, nil
+	return store.FindP2shBindAddress(p2shAddress)
 }
 
 // FindP2shAddresses find p2sh address
 func FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
-	result := make([]*MgoP2shAddress, 0, limit)
-	q := collP2shAddress.Find(nil).Skip(offset).Limit(limit)
-	err := q.All(&result)
-	if err != nil {
-		return nil, mgoError(err)
+	return store.FindP2shAddresses(offset, limit)
+}
+
+// ------------------ destination tag ------------------------
+
+// AddDestinationTag add destination tag
+func AddDestinationTag(mt *MgoDestinationTag) error {
+	err := store.AddDestinationTag(mt)
+	if err == nil {
+		log.Info("mongodb add destination tag", "key", mt.Key, "destinationtag", mt.DestinationTag)
+	} else {
+		log.Debug("mongodb add destination tag", "key", mt.Key, "destinationtag", mt.DestinationTag, "err", err)
 	}
-	return result, nil
+	return err
+}
+
+// FindDestinationTag find destination tag through bind address
+func FindDestinationTag(key string) (*MgoDestinationTag, error) {
+	return store.FindDestinationTag(key)
+}
+
+// FindDestinationTagBindAddress find bind address through destination tag
+func FindDestinationTagBindAddress(tag uint32) (string, error) {
+	return store.FindDestinationTagBindAddress(tag)
 }
 
 // ------------------ latest scan info ------------------------
 
+func getLatestScanInfoKey(isSrc bool) string {
+	if isSrc {
+		return keyOfSrcLatestScanInfo
+	}
+	return keyOfDstLatestScanInfo
+}
+
 // UpdateLatestScanInfo update latest scan info
 func UpdateLatestScanInfo(isSrc bool, blockHeight uint64) error {
 	oldInfo, _ := FindLatestScanInfo(isSrc)
@@ -681,36 +615,18 @@ func UpdateLatestScanInfo(isSrc bool, blockHeight uint64) error {
 			return nil
 		}
 	}
-	var key string
-	if isSrc {
-		key = keyOfSrcLatestScanInfo
-	} else {
-		key = keyOfDstLatestScanInfo
-	}
-	updates := bson.M{
-		"blockheight": blockHeight,
-		"timestamp":   time.Now().Unix(),
-	}
-	err := collLatestScanInfo.UpdateId(key, bson.M{"$set": updates})
+	err := store.UpdateLatestScanInfo(isSrc, blockHeight, time.Now().Unix())
 	if err == nil {
-		log.Info("mongodb update lastest scan info", "isSrc", isSrc, "updates", updates)
+		log.Info("mongodb update lastest scan info", "isSrc", isSrc, "blockHeight", blockHeight)
 	} else {
-		log.Debug("mongodb update latest scan info", "isSrc", isSrc, "updates", updates, "err", err)
+		log.Debug("mongodb update latest scan info", "isSrc", isSrc, "blockHeight", blockHeight, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // FindLatestScanInfo find latest scan info
 func FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
-	var result MgoLatestScanInfo
-	var key string
-	if isSrc {
-		key = keyOfSrcLatestScanInfo
-	} else {
-		key = keyOfDstLatestScanInfo
-	}
-	err := collLatestScanInfo.FindId(key).One(&result)
-	return &result, mgoError(err)
+	return store.FindLatestScanInfo(isSrc)
 }
 
 // ------------------------ register address ------------------------------
@@ -721,23 +637,18 @@ func AddRegisteredAddress(address string) error {
 		Key:       address,
 		Timestamp: time.Now().Unix(),
 	}
-	err := collRegisteredAddress.Insert(ma)
+	err := store.AddRegisteredAddress(ma)
 	if err == nil {
 		log.Info("mongodb add register address", "key", ma.Key)
 	} else {
 		log.Debug("mongodb add register address", "key", ma.Key, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // FindRegisteredAddress find register address
 func FindRegisteredAddress(key string) (*MgoRegisteredAddress, error) {
-	var result MgoRegisteredAddress
-	err := collRegisteredAddress.FindId(key).One(&result)
-	if err != nil {
-		return nil, mgoError(err)
-	}
-	return &result, nil
+	return store.FindRegisteredAddress(key)
 }
 
 // ---------------------- latest swap nonces -----------------------------
@@ -763,57 +674,37 @@ func UpdateLatestSwapNonce(address string, isSwapin bool, nonce uint64) (err err
 	if oldItem != nil && oldItem.SwapNonce >= nonce {
 		return nil // only increase
 	}
-	if oldItem == nil {
-		ma := &MgoLatestSwapNonce{
-			Key:       key,
-			Address:   strings.ToLower(address),
-			IsSwapin:  isSwapin,
-			SwapNonce: nonce,
-			Timestamp: time.Now().Unix(),
-		}
-		err = collLatestSwapNonces.Insert(ma)
-	} else {
-		updates := bson.M{
-			"address":   strings.ToLower(address),
-			"isswapin":  isSwapin,
-			"swapnonce": nonce,
-			"timestamp": time.Now().Unix(),
-		}
-		err = collLatestSwapNonces.UpdateId(key, bson.M{"$set": updates})
-	}
+	err = store.UpdateLatestSwapNonce(NewLatestSwapNonce(address, isSwapin, nonce))
 	if err == nil {
 		log.Info("mongodb update swap nonce success", "address", address, "nonce", nonce, "isSwapin", isSwapin)
 	} else {
 		log.Warn("mongodb update swap nonce failed", "address", address, "nonce", nonce, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // FindLatestSwapNonce find
 func FindLatestSwapNonce(key string) (*MgoLatestSwapNonce, error) {
-	var result MgoLatestSwapNonce
-	err := collLatestSwapNonces.FindId(key).One(&result)
-	if err != nil {
-		return nil, mgoError(err)
-	}
-	return &result, nil
+	return store.FindLatestSwapNonce(key)
 }
 
 // LoadAllSwapNonces load
 func LoadAllSwapNonces() (swapinNonces, swapoutNonces map[string]uint64) {
 	swapinNonces = make(map[string]uint64)
 	swapoutNonces = make(map[string]uint64)
-	var result MgoLatestSwapNonce
-	iter := collLatestSwapNonces.Find(nil).Iter()
-	for iter.Next(&result) {
-		address := result.Address
+	items, err := store.FindAllLatestSwapNonces()
+	if err != nil {
+		log.Warn("load swap nonces failed", "err", err)
+	}
+	for _, item := range items {
+		address := item.Address
 		if address == "" {
 			continue
 		}
-		if result.IsSwapin {
-			swapinNonces[address] = result.SwapNonce
+		if item.IsSwapin {
+			swapinNonces[address] = item.SwapNonce
 		} else {
-			swapoutNonces[address] = result.SwapNonce
+			swapoutNonces[address] = item.SwapNonce
 		}
 	}
 	log.Info("load swap nonces finished", "swapinNonces", swapinNonces, "swapoutNonces", swapoutNonces)
@@ -822,31 +713,60 @@ func LoadAllSwapNonces() (swapinNonces, swapoutNonces map[string]uint64) {
 
 // ---------------------- swap hisitory -----------------------------
 
-// AddSwapHistory add
+// AddSwapHistory add (ignore if exist)
 func AddSwapHistory(isSwapin bool, txid, bind, swaptx string) error {
-	item := &MgoSwapHistory{
-		Key:      bson.NewObjectId(),
-		IsSwapin: isSwapin,
-		TxID:     txid,
-		Bind:     bind,
-		SwapTx:   swaptx,
+	histories, _ := GetSwapHistory(isSwapin, txid, bind)
+	for _, item := range histories {
+		if item.SwapTx == swaptx {
+			return nil
+		}
 	}
-	err := collSwapHistory.Insert(item)
+	err := store.AddSwapHistory(NewSwapHistory(isSwapin, txid, bind, swaptx))
 	if err == nil {
 		log.Info("mongodb add swap history success", "txid", txid, "bind", bind, "isSwapin", isSwapin)
 	} else {
 		log.Debug("mongodb add swap history failed", "txid", txid, "bind", bind, "isSwapin", isSwapin, "err", err)
 	}
-	return mgoError(err)
+	return err
 }
 
 // GetSwapHistory get
 func GetSwapHistory(isSwapin bool, txid, bind string) ([]*MgoSwapHistory, error) {
-	qtxid := bson.M{"txid": txid}
-	qbind := bson.M{"bind": bind}
-	qisswapin := bson.M{"isswapin": isSwapin}
-	queries := []bson.M{qtxid, qbind, qisswapin}
-	result := make([]*MgoSwapHistory, 0, 20)
-	err := collSwapHistory.Find(bson.M{"$and": queries}).All(&result)
-	return result, mgoError(err)
+	return store.GetSwapHistory(isSwapin, txid, bind)
+}
+
+// --------------- swap status history --------------------------------
+
+func addSwapStatusHistory(isSwapin, isResult bool, txid, pairID, bind string, oldStatus, newStatus SwapStatus, timestamp int64, memo, actor string) {
+	item := &MgoSwapStatusHistory{
+		Key:       primitive.NewObjectID(),
+		IsSwapin:  isSwapin,
+		IsResult:  isResult,
+		TxID:      txid,
+		PairID:    pairID,
+		Bind:      bind,
+		OldStatus: oldStatus,
+		NewStatus: newStatus,
+		Actor:     actor,
+		Memo:      memo,
+		Timestamp: timestamp,
+	}
+	err := store.AddSwapStatusHistory(item)
+	if err != nil {
+		log.Warn("mongodb add swap status history failed", "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin, "isResult", isResult, "old", oldStatus, "new", newStatus, "actor", actor, "err", err)
+	}
+}
+
+// addInitialStatusHistory record the status of new added swap or swap result,
+// the initial entry has the same old and new status.
+func addInitialStatusHistory(isSwapin, isResult bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) {
+	if timestamp == 0 {
+		timestamp = time.Now().Unix()
+	}
+	addSwapStatusHistory(isSwapin, isResult, txid, pairID, bind, status, status, timestamp, memo, "register")
+}
+
+// GetSwapStatusHistory get swap status transitions in time order
+func GetSwapStatusHistory(txid, pairID, bind string) ([]*MgoSwapStatusHistory, error) {
+	return store.FindSwapStatusHistory(txid, strings.ToLower(pairID), bind)
 }
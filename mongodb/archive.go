@@ -0,0 +1,107 @@
+package mongodb
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+var archiveLock sync.Mutex
+
+// ArchivedSwap archived swap with its result (line of export file)
+type ArchivedSwap struct {
+	Swap   *MgoSwap       `json:"swap,omitempty"`
+	Result *MgoSwapResult `json:"result"`
+}
+
+// ArchiveStableSwaps move at most `limit` stable swaps (and their results)
+// whose result timestamp is before `before` into archive tables.
+// If exportDir is not empty, the swaps are also exported to a gzip
+// compressed json lines file in it before moving.
+// Returns the count of archived swaps.
+func ArchiveStableSwaps(isSwapin bool, before int64, limit int, exportDir string) (int, error) {
+	archiveLock.Lock()
+	defer archiveLock.Unlock()
+
+	filter := &SwapHistoryFilter{
+		Status:       fmt.Sprint(uint16(MatchTxStable)),
+		MaxTimestamp: before,
+		Limit:        limit,
+	}
+	results, err := findSwapResults(isSwapin, filter)
+	if err != nil || len(results) == 0 {
+		return 0, err
+	}
+
+	archives := make([]*ArchivedSwap, 0, len(results))
+	for _, res := range results {
+		swap, _ := store.FindSwap(isSwapin, res.TxID, res.PairID, res.Bind)
+		archives = append(archives, &ArchivedSwap{Swap: swap, Result: res})
+	}
+
+	if exportDir != "" {
+		err = exportArchivedSwaps(isSwapin, exportDir, archives)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	// moving swap between tables is not allowed when rebuilding statistics
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	count := 0
+	for _, item := range archives {
+		err = store.ArchiveSwap(isSwapin, item.Swap, item.Result)
+		if err != nil {
+			log.Warn("mongodb archive swap failed", "txid", item.Result.TxID, "pairID", item.Result.PairID, "bind", item.Result.Bind, "isSwapin", isSwapin, "err", err)
+			return count, err
+		}
+		count++
+	}
+	log.Info("mongodb archive stable swaps success", "isSwapin", isSwapin, "before", before, "count", count)
+	return count, nil
+}
+
+func exportArchivedSwaps(isSwapin bool, exportDir string, archives []*ArchivedSwap) (err error) {
+	if err = os.MkdirAll(exportDir, 0700); err != nil {
+		return err
+	}
+	swapType := "swapout"
+	if isSwapin {
+		swapType = "swapin"
+	}
+	fileName := fmt.Sprintf("%v-archive-%v.jsonl.gz", swapType, time.Now().UnixNano())
+	filePath := filepath.Join(exportDir, fileName)
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(filePath)
+		}
+	}()
+
+	writer := gzip.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, item := range archives {
+		if err = encoder.Encode(item); err != nil {
+			return err
+		}
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	log.Info("mongodb export archived swaps success", "file", filePath, "count", len(archives))
+	return nil
+}
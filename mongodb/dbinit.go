@@ -1,40 +1,56 @@
 package mongodb
 
 import (
-	"fmt"
+	"context"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
 	"github.com/anyswap/CrossChain-Bridge/log"
-	"gopkg.in/mgo.v2"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+const (
+	defaultConnectTimeout     = 30 * time.Second
+	defaultQueryTimeout       = 30 * time.Second
+	defaultTransactionTimeout = 60 * time.Second
 )
 
 var (
-	database *mgo.Database
-	session  *mgo.Session
+	client   *mongo.Client
+	database *mongo.Database
 
-	dialInfo *mgo.DialInfo
-)
+	clientOpts *options.ClientOptions
+	dbName     string
 
-// HasSession has session connected
-func HasSession() bool {
-	return session != nil
-}
+	// multi-document transactions need a replica set or sharded cluster
+	isTransactionSupported bool
+)
 
 // MongoServerInit int mongodb server session
 func MongoServerInit(addrs []string, dbname, user, pass string) {
-	initDialInfo(addrs, dbname, user, pass)
+	initClientOptions(addrs, dbname, user, pass)
 	mongoConnect()
 	initCollections()
-	go checkMongoSession()
+	SetStore(&MgoStore{})
 }
 
-func initDialInfo(addrs []string, db, user, pass string) {
-	dialInfo = &mgo.DialInfo{
-		Addrs:    addrs,
-		Database: db,
-		Username: user,
-		Password: pass,
+func initClientOptions(addrs []string, db, user, pass string) {
+	dbName = db
+	clientOpts = options.Client().
+		SetHosts(addrs).
+		SetConnectTimeout(defaultConnectTimeout).
+		SetReadPreference(readpref.Primary()).
+		SetWriteConcern(writeconcern.New(writeconcern.WMajority(), writeconcern.J(true)))
+	if user != "" {
+		clientOpts.SetAuth(options.Credential{
+			AuthSource: db,
+			Username:   user,
+			Password:   pass,
+		})
 	}
 
 	utils.TopWaitGroup.Add(1)
@@ -43,77 +59,67 @@ func initDialInfo(addrs []string, db, user, pass string) {
 
 func doCleanup() {
 	defer utils.TopWaitGroup.Done()
-	if session == nil {
+	if client == nil {
 		return
 	}
-	err := session.Fsync(false)
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	err := client.Disconnect(ctx)
 	if err != nil {
-		log.Warn("[mongodb] session flush failed", "err", err)
+		log.Warn("[mongodb] client disconnect failed", "err", err)
 	} else {
-		log.Info("[mongodb] session flush success")
+		log.Info("[mongodb] client disconnect success")
 	}
-	session.Close()
-	log.Info("[mongodb] session close success")
+}
+
+func newQueryContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), defaultQueryTimeout)
 }
 
 func mongoConnect() {
-	if session != nil { // when reconnect
-		session.Close()
-	}
-	log.Info("[mongodb] connect database start.", "addrs", dialInfo.Addrs, "dbName", dialInfo.Database)
+	log.Info("[mongodb] connect database start.", "addrs", clientOpts.Hosts, "dbName", dbName)
 	var err error
 	for {
-		session, err = mgo.DialWithInfo(dialInfo)
+		client, err = mongo.NewClient(clientOpts)
+		if err == nil {
+			err = clientConnect()
+		}
 		if err == nil {
 			break
 		}
-		log.Warn("[mongodb] dial error", "err", err)
+		log.Warn("[mongodb] connect error", "err", err)
 		time.Sleep(1 * time.Second)
 	}
-	session.SetMode(mgo.Monotonic, true)
-	session.SetSafe(&mgo.Safe{FSync: true})
-	database = session.DB(dialInfo.Database)
-	deinintCollections()
-	log.Info("[mongodb] connect database finished.", "dbName", dialInfo.Database)
+	database = client.Database(dbName)
+	isTransactionSupported = checkTransactionSupported()
+	log.Info("[mongodb] connect database finished.", "dbName", dbName, "transaction", isTransactionSupported)
 }
 
-// fix 'read tcp 127.0.0.1:43502->127.0.0.1:27917: i/o timeout'
-func checkMongoSession() {
-	for {
-		time.Sleep(60 * time.Second)
-		if err := ensureMongoConnected(); err != nil {
-			log.Info("[mongodb] check session error", "err", err)
-			log.Info("[mongodb] reconnect database", "dbName", dialInfo.Database)
-			mongoConnect()
-		}
+func clientConnect() error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultConnectTimeout)
+	defer cancel()
+	err := client.Connect(ctx)
+	if err != nil {
+		return err
 	}
-}
-
-func sessionPing() (err error) {
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("recover from error %v", r)
-		}
-	}()
-	for i := 0; i < 6; i++ {
-		err = session.Ping()
-		if err == nil {
-			break
-		}
-		time.Sleep(10 * time.Second)
+	err = client.Ping(ctx, readpref.Primary())
+	if err != nil {
+		_ = client.Disconnect(ctx)
 	}
 	return err
 }
 
-func ensureMongoConnected() (err error) {
-	err = sessionPing()
+func checkTransactionSupported() bool {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	var result bson.M
+	err := database.RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result)
 	if err != nil {
-		log.Error("[mongodb] session ping error", "err", err)
-		log.Info("[mongodb] refresh session.", "dbName", dialInfo.Database)
-		session.Refresh()
-		database = session.DB(dialInfo.Database)
-		deinintCollections()
-		err = sessionPing()
+		log.Warn("[mongodb] run isMaster command failed", "err", err)
+		return false
 	}
-	return err
+	if _, exist := result["setName"]; exist {
+		return true
+	}
+	return result["msg"] == "isdbgrid"
 }
@@ -0,0 +1,175 @@
+package mongodb
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+)
+
+// SwapHistoryFilter filter of swap history query.
+// Zero valued fields are not filtered. If Cursor is set, the query
+// continues after the cursor item and Offset is ignored.
+type SwapHistoryFilter struct {
+	Address      string `json:"address"` // from address, 'all' for all
+	PairID       string `json:"pairid"`  // 'all' for all
+	Status       string `json:"status"`  // comma joined status
+	To           string `json:"to"`
+	Bind         string `json:"bind"`
+	SwapTx       string `json:"swaptx"`
+	MinInitTime  int64  `json:"mininittime"`
+	MaxInitTime  int64  `json:"maxinittime"`
+	MinTimestamp int64  `json:"mintimestamp"`
+	MaxTimestamp int64  `json:"maxtimestamp"`
+	MinValue     string `json:"minvalue"`
+	MaxValue     string `json:"maxvalue"`
+	Cursor       string `json:"cursor"`
+	Offset       int    `json:"offset"`
+	Limit        int    `json:"limit"` // negative limit means latest first
+
+	statuses    []SwapStatus
+	minValue    *big.Int
+	maxValue    *big.Int
+	minValueKey string
+	maxValueKey string
+	cursor      *historyCursor
+}
+
+// length of value key, max uint256 value has 78 decimal digits
+const valueKeyLength = 78
+
+// GetValueKey value left padded with zeros to fixed length, the order of
+// value keys is the same as the numeric order of values, so the value range
+// query can use index. Returns empty string if value is invalid.
+func GetValueKey(value string) string {
+	bigValue, err := common.GetBigIntFromStr(value)
+	if err != nil {
+		return ""
+	}
+	key, _ := getBigValueKey(bigValue)
+	return key
+}
+
+func getBigValueKey(value *big.Int) (string, error) {
+	str := value.String()
+	if value.Sign() < 0 || len(str) > valueKeyLength {
+		return "", ErrWrongValueRange
+	}
+	return strings.Repeat("0", valueKeyLength-len(str)) + str, nil
+}
+
+// historyCursor position of the last returned item.
+// history is ordered by (inittime, _id)
+type historyCursor struct {
+	InitTime int64  `json:"t"`
+	Key      string `json:"k"`
+}
+
+// GetHistoryCursor get continuation cursor of swap result
+func GetHistoryCursor(res *MgoSwapResult) string {
+	data, _ := json.Marshal(&historyCursor{InitTime: res.InitTime, Key: res.Key})
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func parseHistoryCursor(cursor string) (*historyCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrWrongCursor
+	}
+	var result historyCursor
+	if err = json.Unmarshal(data, &result); err != nil || result.Key == "" {
+		return nil, ErrWrongCursor
+	}
+	return &result, nil
+}
+
+// isAfter is swap result after cursor in the query order
+func (c *historyCursor) isAfter(res *MgoSwapResult, isLatestFirst bool) bool {
+	if res.InitTime != c.InitTime {
+		return (res.InitTime > c.InitTime) != isLatestFirst
+	}
+	if res.Key == c.Key {
+		return false
+	}
+	return (res.Key > c.Key) != isLatestFirst
+}
+
+// normalize lowercase the fields and parse the values
+func (f *SwapHistoryFilter) normalize() (err error) {
+	f.PairID = strings.ToLower(f.PairID)
+	if common.IsHexAddress(f.Address) {
+		f.Address = strings.ToLower(f.Address)
+	}
+	if common.IsHexAddress(f.To) {
+		f.To = strings.ToLower(f.To)
+	}
+	if common.IsHexAddress(f.Bind) {
+		f.Bind = strings.ToLower(f.Bind)
+	}
+	f.statuses = getStatusesFromStr(f.Status)
+	if f.MinValue != "" {
+		if f.minValue, err = common.GetBigIntFromStr(f.MinValue); err != nil {
+			return ErrWrongValueRange
+		}
+		if f.minValueKey, err = getBigValueKey(f.minValue); err != nil {
+			return err
+		}
+	}
+	if f.MaxValue != "" {
+		if f.maxValue, err = common.GetBigIntFromStr(f.MaxValue); err != nil {
+			return ErrWrongValueRange
+		}
+		if f.maxValueKey, err = getBigValueKey(f.maxValue); err != nil {
+			return err
+		}
+	}
+	if f.Cursor != "" {
+		if f.cursor, err = parseHistoryCursor(f.Cursor); err != nil {
+			return err
+		}
+		f.Offset = 0
+	}
+	return nil
+}
+
+func (f *SwapHistoryFilter) isLatestFirst() bool {
+	return f.Limit < 0
+}
+
+// match is used by stores without query language
+func (f *SwapHistoryFilter) match(res *MgoSwapResult) bool {
+	switch {
+	case f.PairID != "" && f.PairID != allPairs && res.PairID != f.PairID,
+		f.Address != "" && f.Address != allAddresses && res.From != f.Address,
+		f.To != "" && res.To != f.To,
+		f.Bind != "" && res.Bind != f.Bind,
+		f.SwapTx != "" && res.SwapTx != f.SwapTx,
+		f.MinInitTime != 0 && res.InitTime < f.MinInitTime,
+		f.MaxInitTime != 0 && res.InitTime > f.MaxInitTime,
+		f.MinTimestamp != 0 && res.Timestamp < f.MinTimestamp,
+		f.MaxTimestamp != 0 && res.Timestamp > f.MaxTimestamp:
+		return false
+	}
+	if f.minValue != nil || f.maxValue != nil {
+		value, err := common.GetBigIntFromStr(res.Value)
+		if err != nil ||
+			(f.minValue != nil && value.Cmp(f.minValue) < 0) ||
+			(f.maxValue != nil && value.Cmp(f.maxValue) > 0) {
+			return false
+		}
+	}
+	if f.cursor != nil && !f.cursor.isAfter(res, f.isLatestFirst()) {
+		return false
+	}
+	if len(f.statuses) == 0 {
+		return true
+	}
+	for _, st := range f.statuses {
+		if res.Status == st {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,32 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// AcquireLeaderLease acquire or renew leader lease of name for holder.
+// returns ErrLeaseIsHeld if the lease is held by others and not expired.
+func AcquireLeaderLease(name, holder string, leaseTime time.Duration) (*MgoLeaderLease, error) {
+	timestamp := common.NowMilli()
+	expireAt := timestamp + leaseTime.Milliseconds()
+	lease, err := store.AcquireLeaderLease(name, holder, timestamp, expireAt)
+	if err != nil {
+		log.Debug("mongodb acquire leader lease failed", "name", name, "holder", holder, "err", err)
+		return nil, err
+	}
+	return lease, nil
+}
+
+// ReleaseLeaderLease release leader lease of name if it is held by holder
+func ReleaseLeaderLease(name, holder string) error {
+	err := store.ReleaseLeaderLease(name, holder)
+	if err == nil {
+		log.Info("mongodb release leader lease success", "name", name, "holder", holder)
+	} else {
+		log.Warn("mongodb release leader lease failed", "name", name, "holder", holder, "err", err)
+	}
+	return err
+}
@@ -0,0 +1,1152 @@
+package mongodb
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/leveldb"
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// key prefixes of leveldb store tables
+const (
+	lvlSwapinPrefix            = "swapin:"
+	lvlSwapoutPrefix           = "swapout:"
+	lvlSwapinResultPrefix      = "swapinresult:"
+	lvlSwapoutResultPrefix     = "swapoutresult:"
+	lvlP2shAddressPrefix       = "p2sh:"
+	lvlP2shBindAddressPrefix   = "p2shbind:"
+	lvlDestTagPrefix           = "dtag:"
+	lvlDestTagBindPrefix       = "dtagbind:"
+	lvlSwapStatisticsPrefix    = "statistics:"
+	lvlStatisticsBucketPrefix  = "statisticsbucket:"
+	lvlLatestScanInfoPrefix    = "scaninfo:"
+	lvlRegisteredAddrPrefix    = "registered:"
+	lvlLatestSwapNoncePrefix   = "swapnonce:"
+	lvlSwapHistoryPrefix       = "swaphistory:"
+	lvlSwapStatusHistoryPrefix = "swapstatushistory:"
+	lvlBlacklistPrefix         = "blacklist:"
+	lvlArchivePrefix           = "archive:"
+	lvlLeaderLeasePrefix       = "leaderlease:"
+	lvlSwapTaskPrefix          = "swaptask:"
+	lvlSignIntentPrefix        = "signintent:"
+	lvlSwapVelocityPrefix      = "swapvelocity:"
+	lvlTxBlockHashPrefix       = "txblockhash:"
+	lvlDefaultCacheAndHandles  = 16
+)
+
+// LvlStore swap store on the embedded leveldb.
+// It has no secondary indexes, queries by status or address scan the table,
+// so it is meant for single node deployments and tests.
+type LvlStore struct {
+	db   *leveldb.Database
+	lock sync.Mutex // protect read-modify-write operations
+}
+
+var _ SwapStore = (*LvlStore)(nil)
+
+// LevelDBStoreInit open leveldb store at path and set it as the active store
+func LevelDBStoreInit(path string) {
+	s, err := NewLvlStore(path)
+	if err != nil {
+		log.Fatal("[leveldb] open swap store failed", "path", path, "err", err)
+	}
+	log.Info("[leveldb] open swap store success", "path", path)
+	SetStore(s)
+
+	utils.TopWaitGroup.Add(1)
+	go utils.WaitAndCleanup(func() {
+		defer utils.TopWaitGroup.Done()
+		if err := s.Close(); err != nil {
+			log.Warn("[leveldb] close swap store failed", "err", err)
+		} else {
+			log.Info("[leveldb] close swap store success")
+		}
+	})
+}
+
+// NewLvlStore new leveldb store
+func NewLvlStore(path string) (*LvlStore, error) {
+	db, err := leveldb.New(path, lvlDefaultCacheAndHandles, lvlDefaultCacheAndHandles, false)
+	if err != nil {
+		return nil, err
+	}
+	return &LvlStore{db: db}, nil
+}
+
+// Close close leveldb store
+func (s *LvlStore) Close() error {
+	return s.db.Close()
+}
+
+// Name name of store
+func (s *LvlStore) Name() string {
+	return "leveldb"
+}
+
+func getLvlSwapPrefix(isSwapin bool) string {
+	if isSwapin {
+		return lvlSwapinPrefix
+	}
+	return lvlSwapoutPrefix
+}
+
+func getLvlSwapResultPrefix(isSwapin bool) string {
+	if isSwapin {
+		return lvlSwapinResultPrefix
+	}
+	return lvlSwapoutResultPrefix
+}
+
+func lvlError(err error) error {
+	if err != nil {
+		if leveldb.IsNotFoundErr(err) {
+			return ErrItemNotFound
+		}
+		return newError(-32001, "lvlError: "+err.Error())
+	}
+	return nil
+}
+
+func (s *LvlStore) get(key string, result interface{}) error {
+	data, err := s.db.Get([]byte(key))
+	if err != nil {
+		return lvlError(err)
+	}
+	return lvlError(json.Unmarshal(data, result))
+}
+
+func (s *LvlStore) put(key string, item interface{}) error {
+	data, err := json.Marshal(item)
+	if err != nil {
+		return lvlError(err)
+	}
+	return lvlError(s.db.Put([]byte(key), data))
+}
+
+func (s *LvlStore) insert(key string, item interface{}) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	exist, err := s.db.Has([]byte(key))
+	if err != nil {
+		return lvlError(err)
+	}
+	if exist {
+		return ErrItemIsDup
+	}
+	return s.put(key, item)
+}
+
+// iterate call fn with every value under prefix, stop if fn returns false
+func (s *LvlStore) iterate(prefix string, fn func(value []byte) bool) error {
+	iter := s.db.NewIterator([]byte(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		if !fn(iter.Value()) {
+			break
+		}
+	}
+	return lvlError(iter.Error())
+}
+
+func (s *LvlStore) iterateKeys(prefix string, fn func(key []byte)) error {
+	iter := s.db.NewIterator([]byte(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		fn(common.CopyBytes(iter.Key()))
+	}
+	return lvlError(iter.Error())
+}
+
+func (s *LvlStore) findSwaps(isSwapin bool, match func(*MgoSwap) bool) (result []*MgoSwap, err error) {
+	err = s.iterate(getLvlSwapPrefix(isSwapin), func(value []byte) bool {
+		swap := &MgoSwap{}
+		if json.Unmarshal(value, swap) == nil && match(swap) {
+			result = append(result, swap)
+		}
+		return true
+	})
+	sort.SliceStable(result, func(i, j int) bool { return result[i].InitTime < result[j].InitTime })
+	return result, err
+}
+
+func (s *LvlStore) findSwapResults(isSwapin bool, match func(*MgoSwapResult) bool) (result []*MgoSwapResult, err error) {
+	return s.findSwapResultsWithPrefix(getLvlSwapResultPrefix(isSwapin), match)
+}
+
+func (s *LvlStore) findSwapResultsWithPrefix(prefix string, match func(*MgoSwapResult) bool) (result []*MgoSwapResult, err error) {
+	err = s.iterate(prefix, func(value []byte) bool {
+		res := &MgoSwapResult{}
+		if json.Unmarshal(value, res) == nil && match(res) {
+			result = append(result, res)
+		}
+		return true
+	})
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].InitTime != result[j].InitTime {
+			return result[i].InitTime < result[j].InitTime
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, err
+}
+
+// ------------------ swapin / swapout common ------------------------
+
+// AddSwap add swap
+func (s *LvlStore) AddSwap(isSwapin bool, ms *MgoSwap) error {
+	return s.insert(getLvlSwapPrefix(isSwapin)+ms.Key, ms)
+}
+
+// UpdateSwapStatus update swap status
+func (s *LvlStore) UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getLvlSwapPrefix(isSwapin) + GetSwapKey(txid, pairID, bind)
+	var swap MgoSwap
+	if err := s.get(key, &swap); err != nil {
+		return err
+	}
+	swap.Status = status
+	swap.Timestamp = timestamp
+	if memo != "" {
+		swap.Memo = memo
+	} else if shouldClearSwapMemo(status) {
+		swap.Memo = ""
+	}
+	return s.put(key, &swap)
+}
+
+// FindSwap find swap
+func (s *LvlStore) FindSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error) {
+	if bind != "" {
+		result := &MgoSwap{}
+		err := s.get(getLvlSwapPrefix(isSwapin)+GetSwapKey(txid, pairID, bind), result)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	swaps, err := s.findSwaps(isSwapin, func(swap *MgoSwap) bool {
+		return swap.TxID == txid && swap.PairID == pairID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(swaps) == 0 {
+		return nil, ErrItemNotFound
+	}
+	return swaps[0], nil
+}
+
+// FindSwapsWithStatus find swaps with status in the past septime
+func (s *LvlStore) FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	result, err := s.findSwaps(isSwapin, func(swap *MgoSwap) bool {
+		return swap.Status == status && swap.Timestamp >= septime
+	})
+	if len(result) > maxCountOfResults {
+		result = result[:maxCountOfResults]
+	}
+	return result, err
+}
+
+// FindSwapsWithPairIDAndStatus find swaps with pairID and status in the past septime
+func (s *LvlStore) FindSwapsWithPairIDAndStatus(isSwapin bool, pairID string, status SwapStatus, septime int64) ([]*MgoSwap, error) {
+	result, err := s.findSwaps(isSwapin, func(swap *MgoSwap) bool {
+		return swap.PairID == pairID && swap.Status == status && swap.Timestamp >= septime
+	})
+	if len(result) > maxCountOfResults {
+		result = result[:maxCountOfResults]
+	}
+	return result, err
+}
+
+// GetCountOfSwapsWithStatus get count of swaps with status
+func (s *LvlStore) GetCountOfSwapsWithStatus(isSwapin bool, pairID string, status SwapStatus) (int, error) {
+	result, err := s.findSwaps(isSwapin, func(swap *MgoSwap) bool {
+		return swap.PairID == pairID && swap.Status == status
+	})
+	return len(result), err
+}
+
+// ------------------ swapin / swapout result common ------------------------
+
+// AddSwapResult add swap result
+func (s *LvlStore) AddSwapResult(isSwapin bool, mr *MgoSwapResult) error {
+	return s.insert(getLvlSwapResultPrefix(isSwapin)+mr.Key, mr)
+}
+
+func applySwapResultUpdates(res *MgoSwapResult, items *SwapResultUpdateItems) {
+	res.Timestamp = items.Timestamp
+	if items.Status != KeepStatus {
+		res.Status = items.Status
+	}
+	if items.SwapTx != "" {
+		res.SwapTx = items.SwapTx
+	}
+	if len(items.OldSwapTxs) != 0 {
+		res.OldSwapTxs = items.OldSwapTxs
+	}
+	if len(items.OldSwapVals) != 0 {
+		res.OldSwapVals = items.OldSwapVals
+	}
+	if items.SwapHeight != 0 {
+		res.SwapHeight = items.SwapHeight
+	}
+	if items.SwapTime != 0 {
+		res.SwapTime = items.SwapTime
+	}
+	if items.SwapValue != "" {
+		res.SwapValue = items.SwapValue
+	}
+	if items.SwapType != 0 {
+		res.SwapType = items.SwapType
+	}
+	if items.SwapNonce != 0 {
+		res.SwapNonce = items.SwapNonce
+	}
+	if items.SwapMemo != "" {
+		res.SwapMemo = items.SwapMemo
+	}
+	if items.Memo != "" {
+		res.Memo = items.Memo
+	} else if items.Status == MatchTxNotStable {
+		res.Memo = ""
+	}
+}
+
+// UpdateSwapResult update swap result
+func (s *LvlStore) UpdateSwapResult(isSwapin bool, txid, pairID, bind string, items *SwapResultUpdateItems) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getLvlSwapResultPrefix(isSwapin) + GetSwapKey(txid, pairID, bind)
+	var res MgoSwapResult
+	if err := s.get(key, &res); err != nil {
+		return err
+	}
+	applySwapResultUpdates(&res, items)
+	return s.put(key, &res)
+}
+
+// UpdateSwapResultStatus update swap result status
+func (s *LvlStore) UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getLvlSwapResultPrefix(isSwapin) + GetSwapKey(txid, pairID, bind)
+	var res MgoSwapResult
+	if err := s.get(key, &res); err != nil {
+		return err
+	}
+	res.Status = status
+	res.Timestamp = timestamp
+	if memo != "" {
+		res.Memo = memo
+	}
+	if status == Reswapping {
+		res.Memo = ""
+		res.SwapTx = ""
+		res.OldSwapTxs = nil
+		res.SwapHeight = 0
+		res.SwapTime = 0
+		res.SwapNonce = 0
+	}
+	return s.put(key, &res)
+}
+
+// FindSwapResult find swap result
+func (s *LvlStore) FindSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error) {
+	if bind != "" {
+		result := &MgoSwapResult{}
+		err := s.get(getLvlSwapResultPrefix(isSwapin)+GetSwapKey(txid, pairID, bind), result)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	results, err := s.findSwapResults(isSwapin, func(res *MgoSwapResult) bool {
+		return res.TxID == txid && res.PairID == pairID
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrItemNotFound
+	}
+	return results[0], nil
+}
+
+// FindSwapResultsWithStatus find swap results with status in the past septime
+func (s *LvlStore) FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	result, err := s.findSwapResults(isSwapin, func(res *MgoSwapResult) bool {
+		return res.Status == status && res.Timestamp >= septime
+	})
+	if len(result) > maxCountOfResults {
+		result = result[:maxCountOfResults]
+	}
+	return result, err
+}
+
+// FindSwapResults find swap history results
+func (s *LvlStore) FindSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	return s.filterSwapResults(getLvlSwapResultPrefix(isSwapin), filter)
+}
+
+func (s *LvlStore) filterSwapResults(prefix string, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	result, err := s.findSwapResultsWithPrefix(prefix, filter.match)
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	if filter.isLatestFirst() {
+		limit = -limit
+		for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+			result[i], result[j] = result[j], result[i]
+		}
+	}
+	if filter.Offset >= len(result) {
+		return []*MgoSwapResult{}, nil
+	}
+	result = result[filter.Offset:]
+	if limit > 0 && limit < len(result) {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+// FindSwapResultsToReplace find swap results to replace
+func (s *LvlStore) FindSwapResultsToReplace(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	result, err := s.findSwapResults(isSwapin, func(res *MgoSwapResult) bool {
+		return res.Status == status && res.SwapHeight == 0 && res.Timestamp >= septime
+	})
+	if len(result) > maxCountOfReplaceResults {
+		result = result[:maxCountOfReplaceResults]
+	}
+	return result, err
+}
+
+// GetCountOfSwapResults get count of swap results
+func (s *LvlStore) GetCountOfSwapResults(isSwapin bool, pairID string) (int, error) {
+	result, err := s.findSwapResults(isSwapin, func(res *MgoSwapResult) bool {
+		return res.PairID == pairID
+	})
+	return len(result), err
+}
+
+// GetCountOfSwapResultsWithStatus get count of swap results with status
+func (s *LvlStore) GetCountOfSwapResultsWithStatus(isSwapin bool, pairID string, status SwapStatus) (int, error) {
+	result, err := s.findSwapResults(isSwapin, func(res *MgoSwapResult) bool {
+		return res.PairID == pairID && res.Status == status
+	})
+	return len(result), err
+}
+
+// CommitSwapTx commit swap tx records in one leveldb batch
+func (s *LvlStore) CommitSwapTx(commit *SwapTxCommit) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := s.db.NewBatch()
+	if err := s.putSwapTxCommit(batch, commit); err != nil {
+		return err
+	}
+	return lvlError(batch.Write())
+}
+
+// CommitBatchSwapTx commit records of swaps paid by one batch tx in one leveldb batch
+func (s *LvlStore) CommitBatchSwapTx(commits []*SwapTxCommit) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := s.db.NewBatch()
+	for _, commit := range commits {
+		if err := s.putSwapTxCommit(batch, commit); err != nil {
+			return err
+		}
+	}
+	return lvlError(batch.Write())
+}
+
+func (s *LvlStore) putSwapTxCommit(batch leveldb.Batch, commit *SwapTxCommit) error {
+	swapKey := GetSwapKey(commit.TxID, commit.PairID, commit.Bind)
+	swapDBKey := getLvlSwapPrefix(commit.IsSwapin) + swapKey
+	resultDBKey := getLvlSwapResultPrefix(commit.IsSwapin) + swapKey
+
+	var swap MgoSwap
+	if err := s.get(swapDBKey, &swap); err != nil {
+		return err
+	}
+	var res MgoSwapResult
+	if err := s.get(resultDBKey, &res); err != nil {
+		return err
+	}
+	applySwapResultUpdates(&res, commit.ResultItems)
+	swap.Status = commit.SwapStatus
+	swap.Timestamp = commit.ResultItems.Timestamp
+	if shouldClearSwapMemo(swap.Status) {
+		swap.Memo = ""
+	}
+
+	items := map[string]interface{}{
+		swapDBKey:   &swap,
+		resultDBKey: &res,
+	}
+	if commit.History != nil {
+		historyKey := getLvlSwapHistoryPrefix(commit.IsSwapin, commit.TxID, commit.Bind) + commit.History.Key.Hex()
+		items[historyKey] = commit.History
+	}
+	if commit.SwapNonce != nil {
+		items[lvlLatestSwapNoncePrefix+commit.SwapNonce.Key] = commit.SwapNonce
+	}
+	for key, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return lvlError(err)
+		}
+		_ = batch.Put([]byte(key), data)
+	}
+	return nil
+}
+
+// ------------------ archive ------------------------
+
+// ArchiveSwap move swap and swap result to archive tables in one batch
+func (s *LvlStore) ArchiveSwap(isSwapin bool, swap *MgoSwap, res *MgoSwapResult) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	batch := s.db.NewBatch()
+	if swap != nil {
+		data, err := json.Marshal(swap)
+		if err != nil {
+			return lvlError(err)
+		}
+		_ = batch.Put([]byte(lvlArchivePrefix+getLvlSwapPrefix(isSwapin)+swap.Key), data)
+		_ = batch.Delete([]byte(getLvlSwapPrefix(isSwapin) + swap.Key))
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return lvlError(err)
+	}
+	_ = batch.Put([]byte(lvlArchivePrefix+getLvlSwapResultPrefix(isSwapin)+res.Key), data)
+	_ = batch.Delete([]byte(getLvlSwapResultPrefix(isSwapin) + res.Key))
+	return lvlError(batch.Write())
+}
+
+// FindArchivedSwap find archived swap
+func (s *LvlStore) FindArchivedSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error) {
+	result := &MgoSwap{}
+	err := s.get(lvlArchivePrefix+getLvlSwapPrefix(isSwapin)+GetSwapKey(txid, pairID, bind), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindArchivedSwapResults find archived swap results with filter
+func (s *LvlStore) FindArchivedSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	return s.filterSwapResults(lvlArchivePrefix+getLvlSwapResultPrefix(isSwapin), filter)
+}
+
+// FindArchivedSwapResult find archived swap result
+func (s *LvlStore) FindArchivedSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error) {
+	result := &MgoSwapResult{}
+	err := s.get(lvlArchivePrefix+getLvlSwapResultPrefix(isSwapin)+GetSwapKey(txid, pairID, bind), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ------------------ statistics ------------------------
+
+// FindSwapStatistics find swap statistics
+func (s *LvlStore) FindSwapStatistics(pairID string) (*MgoSwapStatistics, error) {
+	result := &MgoSwapStatistics{}
+	err := s.get(lvlSwapStatisticsPrefix+pairID, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateSwapStatistics update swap statistics
+func (s *LvlStore) UpdateSwapStatistics(stat *MgoSwapStatistics) error {
+	return s.put(lvlSwapStatisticsPrefix+stat.Key, stat)
+}
+
+// FindSwapStatisticsBucket find swap statistics bucket
+func (s *LvlStore) FindSwapStatisticsBucket(key string) (*MgoSwapStatisticsBucket, error) {
+	result := &MgoSwapStatisticsBucket{}
+	err := s.get(lvlStatisticsBucketPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// UpdateSwapStatisticsBucket update swap statistics bucket
+func (s *LvlStore) UpdateSwapStatisticsBucket(bucket *MgoSwapStatisticsBucket) error {
+	return s.put(lvlStatisticsBucketPrefix+bucket.Key, bucket)
+}
+
+// FindSwapStatisticsBuckets find swap statistics buckets in time range [from, to]
+func (s *LvlStore) FindSwapStatisticsBuckets(pairID, granularity string, from, to int64) ([]*MgoSwapStatisticsBucket, error) {
+	result := make([]*MgoSwapStatisticsBucket, 0, 24)
+	err := s.iterate(lvlStatisticsBucketPrefix+pairID+":"+granularity+":", func(value []byte) bool {
+		bucket := &MgoSwapStatisticsBucket{}
+		if json.Unmarshal(value, bucket) == nil && bucket.PairID == pairID &&
+			bucket.StartTime >= from && bucket.StartTime <= to {
+			result = append(result, bucket)
+		}
+		return true
+	})
+	sort.Slice(result, func(i, j int) bool { return result[i].StartTime < result[j].StartTime })
+	return result, err
+}
+
+// RemoveSwapStatisticsBuckets remove all swap statistics buckets of pair
+func (s *LvlStore) RemoveSwapStatisticsBuckets(pairID string) error {
+	batch := s.db.NewBatch()
+	err := s.iterateKeys(lvlStatisticsBucketPrefix+pairID+":", func(key []byte) {
+		_ = batch.Delete(key)
+	})
+	if err != nil {
+		return err
+	}
+	return lvlError(batch.Write())
+}
+
+// ------------------ p2sh address ------------------------
+
+// AddP2shAddress add p2sh address
+func (s *LvlStore) AddP2shAddress(ma *MgoP2shAddress) error {
+	err := s.insert(lvlP2shAddressPrefix+ma.Key, ma)
+	if err != nil {
+		return err
+	}
+	return lvlError(s.db.Put([]byte(lvlP2shBindAddressPrefix+ma.P2shAddress), []byte(ma.Key)))
+}
+
+// FindP2shAddress find p2sh addrss through bind address
+func (s *LvlStore) FindP2shAddress(key string) (*MgoP2shAddress, error) {
+	result := &MgoP2shAddress{}
+	err := s.get(lvlP2shAddressPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindP2shBindAddress find bind address through p2sh address
+func (s *LvlStore) FindP2shBindAddress(p2shAddress string) (string, error) {
+	data, err := s.db.Get([]byte(lvlP2shBindAddressPrefix + p2shAddress))
+	if err != nil {
+		return "", lvlError(err)
+	}
+	return string(data), nil
+}
+
+// FindP2shAddresses find p2sh address
+func (s *LvlStore) FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	result := make([]*MgoP2shAddress, 0, limit)
+	index := 0
+	err := s.iterate(lvlP2shAddressPrefix, func(value []byte) bool {
+		if len(result) >= limit {
+			return false
+		}
+		if index >= offset {
+			item := &MgoP2shAddress{}
+			if json.Unmarshal(value, item) == nil {
+				result = append(result, item)
+			}
+		}
+		index++
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ------------------ destination tag ------------------------
+
+// AddDestinationTag add destination tag, the tag can only be used by one bind address
+func (s *LvlStore) AddDestinationTag(mt *MgoDestinationTag) error {
+	key := lvlDestTagPrefix + mt.Key
+	bindKey := lvlDestTagBindPrefix + strconv.FormatUint(uint64(mt.DestinationTag), 10)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, k := range []string{key, bindKey} {
+		exist, err := s.db.Has([]byte(k))
+		if err != nil {
+			return lvlError(err)
+		}
+		if exist {
+			return ErrItemIsDup
+		}
+	}
+	err := s.put(key, mt)
+	if err != nil {
+		return err
+	}
+	return lvlError(s.db.Put([]byte(bindKey), []byte(mt.Key)))
+}
+
+// FindDestinationTag find destination tag through bind address
+func (s *LvlStore) FindDestinationTag(key string) (*MgoDestinationTag, error) {
+	result := &MgoDestinationTag{}
+	err := s.get(lvlDestTagPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindDestinationTagBindAddress find bind address through destination tag
+func (s *LvlStore) FindDestinationTagBindAddress(tag uint32) (string, error) {
+	data, err := s.db.Get([]byte(lvlDestTagBindPrefix + strconv.FormatUint(uint64(tag), 10)))
+	if err != nil {
+		return "", lvlError(err)
+	}
+	return string(data), nil
+}
+
+// ------------------ latest scan info ------------------------
+
+// UpdateLatestScanInfo update latest scan info
+func (s *LvlStore) UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	key := getLatestScanInfoKey(isSrc)
+	info := &MgoLatestScanInfo{
+		Key:         key,
+		BlockHeight: blockHeight,
+		Timestamp:   timestamp,
+	}
+	return s.put(lvlLatestScanInfoPrefix+key, info)
+}
+
+// FindLatestScanInfo find latest scan info
+func (s *LvlStore) FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	result := &MgoLatestScanInfo{}
+	err := s.get(lvlLatestScanInfoPrefix+getLatestScanInfoKey(isSrc), result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ------------------------ register address ------------------------------
+
+// AddRegisteredAddress add register address
+func (s *LvlStore) AddRegisteredAddress(ma *MgoRegisteredAddress) error {
+	return s.insert(lvlRegisteredAddrPrefix+ma.Key, ma)
+}
+
+// FindRegisteredAddress find register address
+func (s *LvlStore) FindRegisteredAddress(key string) (*MgoRegisteredAddress, error) {
+	result := &MgoRegisteredAddress{}
+	err := s.get(lvlRegisteredAddrPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ---------------------- latest swap nonces -----------------------------
+
+// UpdateLatestSwapNonce update latest swap nonce
+func (s *LvlStore) UpdateLatestSwapNonce(item *MgoLatestSwapNonce) error {
+	return s.put(lvlLatestSwapNoncePrefix+item.Key, item)
+}
+
+// FindLatestSwapNonce find latest swap nonce
+func (s *LvlStore) FindLatestSwapNonce(key string) (*MgoLatestSwapNonce, error) {
+	result := &MgoLatestSwapNonce{}
+	err := s.get(lvlLatestSwapNoncePrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindAllLatestSwapNonces find all latest swap nonces
+func (s *LvlStore) FindAllLatestSwapNonces() ([]*MgoLatestSwapNonce, error) {
+	result := make([]*MgoLatestSwapNonce, 0, 20)
+	err := s.iterate(lvlLatestSwapNoncePrefix, func(value []byte) bool {
+		item := &MgoLatestSwapNonce{}
+		if json.Unmarshal(value, item) == nil {
+			result = append(result, item)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ---------------------- swap hisitory -----------------------------
+
+func getLvlSwapHistoryPrefix(isSwapin bool, txid, bind string) string {
+	return strings.ToLower(fmt.Sprintf("%v%v:%v:%v:", lvlSwapHistoryPrefix, isSwapin, txid, bind))
+}
+
+// AddSwapHistory add swap history
+func (s *LvlStore) AddSwapHistory(item *MgoSwapHistory) error {
+	key := getLvlSwapHistoryPrefix(item.IsSwapin, item.TxID, item.Bind) + item.Key.Hex()
+	return s.insert(key, item)
+}
+
+// GetSwapHistory get swap history
+func (s *LvlStore) GetSwapHistory(isSwapin bool, txid, bind string) ([]*MgoSwapHistory, error) {
+	result := make([]*MgoSwapHistory, 0, 20)
+	err := s.iterate(getLvlSwapHistoryPrefix(isSwapin, txid, bind), func(value []byte) bool {
+		item := &MgoSwapHistory{}
+		if json.Unmarshal(value, item) == nil && item.TxID == txid && item.Bind == bind {
+			result = append(result, item)
+		}
+		return true
+	})
+	return result, err
+}
+
+// --------------- swap status history --------------------------------
+
+func getLvlSwapStatusHistoryPrefix(txid, pairID, bind string) string {
+	return strings.ToLower(fmt.Sprintf("%v%v:%v:%v:", lvlSwapStatusHistoryPrefix, txid, pairID, bind))
+}
+
+// AddSwapStatusHistory add swap status history
+func (s *LvlStore) AddSwapStatusHistory(item *MgoSwapStatusHistory) error {
+	key := getLvlSwapStatusHistoryPrefix(item.TxID, item.PairID, item.Bind) + item.Key.Hex()
+	return s.insert(key, item)
+}
+
+// FindSwapStatusHistory find swap status history in time order
+func (s *LvlStore) FindSwapStatusHistory(txid, pairID, bind string) ([]*MgoSwapStatusHistory, error) {
+	prefix := getLvlSwapStatusHistoryPrefix(txid, pairID, bind)
+	if bind == "" {
+		prefix = strings.TrimSuffix(prefix, ":")
+	}
+	result := make([]*MgoSwapStatusHistory, 0, 20)
+	err := s.iterate(prefix, func(value []byte) bool {
+		item := &MgoSwapStatusHistory{}
+		if json.Unmarshal(value, item) == nil && item.TxID == txid && item.PairID == pairID && (bind == "" || item.Bind == bind) {
+			result = append(result, item)
+		}
+		return true
+	})
+	// items of different binds are iterated by bind order
+	sort.SliceStable(result, func(i, j int) bool { return bytes.Compare(result[i].Key[:], result[j].Key[:]) < 0 })
+	return result, err
+}
+
+// --------------- blacklist --------------------------------
+
+// AddToBlacklist add to blacklist
+func (s *LvlStore) AddToBlacklist(mb *MgoBlackAccount) error {
+	return s.insert(lvlBlacklistPrefix+mb.Key, mb)
+}
+
+// RemoveFromBlacklist remove from blacklist
+func (s *LvlStore) RemoveFromBlacklist(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	dbKey := []byte(lvlBlacklistPrefix + key)
+	exist, err := s.db.Has(dbKey)
+	if err != nil {
+		return lvlError(err)
+	}
+	if !exist {
+		return ErrItemNotFound
+	}
+	return lvlError(s.db.Delete(dbKey))
+}
+
+// FindBlackAccount find black account
+func (s *LvlStore) FindBlackAccount(key string) (*MgoBlackAccount, error) {
+	result := &MgoBlackAccount{}
+	err := s.get(lvlBlacklistPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// --------------- leader lease --------------------------------
+
+// AcquireLeaderLease renew the lease if holder holds it,
+// otherwise take over the lease if it is expired (or not exist)
+func (s *LvlStore) AcquireLeaderLease(name, holder string, timestamp, expireAt int64) (*MgoLeaderLease, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	lease := &MgoLeaderLease{Key: name}
+	err := s.get(lvlLeaderLeasePrefix+name, lease)
+	if err != nil && !errors.Is(err, ErrItemNotFound) {
+		return nil, err
+	}
+	if lease.Holder != holder {
+		if lease.ExpireAt >= timestamp {
+			return nil, ErrLeaseIsHeld
+		}
+		lease.Holder = holder
+		lease.Term++
+	}
+	lease.ExpireAt = expireAt
+	lease.Timestamp = timestamp
+	err = s.put(lvlLeaderLeasePrefix+name, lease)
+	if err != nil {
+		return nil, err
+	}
+	return lease, nil
+}
+
+// ReleaseLeaderLease release lease held by holder
+func (s *LvlStore) ReleaseLeaderLease(name, holder string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	lease := &MgoLeaderLease{}
+	err := s.get(lvlLeaderLeasePrefix+name, lease)
+	if err != nil || lease.Holder != holder {
+		return err
+	}
+	lease.ExpireAt = 0
+	return s.put(lvlLeaderLeasePrefix+name, lease)
+}
+
+// --------------- swap task queue --------------------------------
+
+// AddSwapTask add swap task
+func (s *LvlStore) AddSwapTask(task *MgoSwapTask) error {
+	return s.insert(lvlSwapTaskPrefix+task.Key, task)
+}
+
+// LeaseSwapTask lease the oldest visible pending task of queue,
+// the task is invisible to others until visibleAt
+func (s *LvlStore) LeaseSwapTask(queue string, timestamp, visibleAt int64, leaseToken string) (*MgoSwapTask, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	tasks, err := s.findSwapTasks(func(task *MgoSwapTask) bool {
+		return task.Queue == queue && task.Status == SwapTaskPending && task.VisibleAt <= timestamp
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tasks) == 0 {
+		return nil, ErrItemNotFound
+	}
+	task := tasks[0]
+	task.VisibleAt = visibleAt
+	task.Timestamp = timestamp
+	task.Attempts++
+	task.LeaseToken = leaseToken
+	err = s.put(lvlSwapTaskPrefix+task.Key, task)
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// checkSwapTaskLease check swap task exists and is still leased with leaseToken
+// (empty lease token means unconditional)
+func (s *LvlStore) checkSwapTaskLease(key, leaseToken string) error {
+	task := &MgoSwapTask{}
+	err := s.get(lvlSwapTaskPrefix+key, task)
+	if err != nil {
+		return err
+	}
+	if leaseToken != "" && task.LeaseToken != leaseToken {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// UpdateSwapTask update swap task if it is still leased with leaseToken
+func (s *LvlStore) UpdateSwapTask(task *MgoSwapTask, leaseToken string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.checkSwapTaskLease(task.Key, leaseToken)
+	if err != nil {
+		return err
+	}
+	return s.put(lvlSwapTaskPrefix+task.Key, task)
+}
+
+// RemoveSwapTask remove swap task if it is still leased with leaseToken
+func (s *LvlStore) RemoveSwapTask(key, leaseToken string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	err := s.checkSwapTaskLease(key, leaseToken)
+	if err != nil {
+		return err
+	}
+	return lvlError(s.db.Delete([]byte(lvlSwapTaskPrefix + key)))
+}
+
+// FindSwapTask find swap task
+func (s *LvlStore) FindSwapTask(key string) (*MgoSwapTask, error) {
+	result := &MgoSwapTask{}
+	err := s.get(lvlSwapTaskPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindSwapTasks find swap tasks of queue and status (empty means all)
+func (s *LvlStore) FindSwapTasks(queue, status string) ([]*MgoSwapTask, error) {
+	return s.findSwapTasks(func(task *MgoSwapTask) bool {
+		return (queue == "" || task.Queue == queue) && (status == "" || task.Status == status)
+	})
+}
+
+func (s *LvlStore) findSwapTasks(match func(*MgoSwapTask) bool) (result []*MgoSwapTask, err error) {
+	err = s.iterate(lvlSwapTaskPrefix, func(value []byte) bool {
+		task := &MgoSwapTask{}
+		if json.Unmarshal(value, task) == nil && match(task) {
+			result = append(result, task)
+		}
+		return true
+	})
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].CreateTime != result[j].CreateTime {
+			return result[i].CreateTime < result[j].CreateTime
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result, err
+}
+
+// --------------- sign intents --------------------------------
+
+// UpdateSignIntent add or update sign intent
+func (s *LvlStore) UpdateSignIntent(intent *MgoSignIntent) error {
+	return s.put(lvlSignIntentPrefix+intent.Key, intent)
+}
+
+// FindSignIntent find sign intent
+func (s *LvlStore) FindSignIntent(key string) (*MgoSignIntent, error) {
+	result := &MgoSignIntent{}
+	err := s.get(lvlSignIntentPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindSignIntentsWithStatus find sign intents with status
+func (s *LvlStore) FindSignIntentsWithStatus(status string) (result []*MgoSignIntent, err error) {
+	err = s.iterate(lvlSignIntentPrefix, func(value []byte) bool {
+		intent := &MgoSignIntent{}
+		if json.Unmarshal(value, intent) == nil && intent.Status == status {
+			result = append(result, intent)
+		}
+		return true
+	})
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].CreateTime < result[j].CreateTime
+	})
+	return result, err
+}
+
+// --------------- swap velocities --------------------------------
+
+// AddSwapVelocity add swap velocity
+func (s *LvlStore) AddSwapVelocity(item *MgoSwapVelocity) error {
+	return s.insert(lvlSwapVelocityPrefix+item.Key, item)
+}
+
+// FindSwapVelocity find swap velocity
+func (s *LvlStore) FindSwapVelocity(key string) (*MgoSwapVelocity, error) {
+	result := &MgoSwapVelocity{}
+	err := s.get(lvlSwapVelocityPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindSwapVelocities find swap velocities of pair since timestamp
+func (s *LvlStore) FindSwapVelocities(isSwapin bool, pairID string, since int64) (result []*MgoSwapVelocity, err error) {
+	err = s.iterate(lvlSwapVelocityPrefix, func(value []byte) bool {
+		item := &MgoSwapVelocity{}
+		if json.Unmarshal(value, item) == nil && item.IsSwapin == isSwapin && item.PairID == pairID && item.Timestamp >= since {
+			result = append(result, item)
+		}
+		return true
+	})
+	return result, err
+}
+
+// RemoveSwapVelocitiesBefore remove swap velocities before timestamp
+func (s *LvlStore) RemoveSwapVelocitiesBefore(timestamp int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	batch := s.db.NewBatch()
+	err := s.iterate(lvlSwapVelocityPrefix, func(value []byte) bool {
+		item := &MgoSwapVelocity{}
+		if json.Unmarshal(value, item) == nil && item.Timestamp < timestamp {
+			_ = batch.Delete([]byte(lvlSwapVelocityPrefix + item.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return lvlError(batch.Write())
+}
+
+// --------------- tx block hashes --------------------------------
+
+// UpsertTxBlockHash upsert tx block hash
+func (s *LvlStore) UpsertTxBlockHash(item *MgoTxBlockHash) error {
+	return s.put(lvlTxBlockHashPrefix+item.Key, item)
+}
+
+// FindTxBlockHash find tx block hash
+func (s *LvlStore) FindTxBlockHash(key string) (*MgoTxBlockHash, error) {
+	result := &MgoTxBlockHash{}
+	err := s.get(lvlTxBlockHashPrefix+key, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindTxBlockHashesToWatch find not reorged tx block hashes since timestamp
+func (s *LvlStore) FindTxBlockHashesToWatch(since int64) (result []*MgoTxBlockHash, err error) {
+	err = s.iterate(lvlTxBlockHashPrefix, func(value []byte) bool {
+		item := &MgoTxBlockHash{}
+		if json.Unmarshal(value, item) == nil && !item.Reorged && item.Timestamp >= since {
+			result = append(result, item)
+		}
+		return true
+	})
+	return result, err
+}
+
+// RemoveTxBlockHashesBefore remove not reorged tx block hashes before timestamp
+func (s *LvlStore) RemoveTxBlockHashesBefore(timestamp int64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	batch := s.db.NewBatch()
+	err := s.iterate(lvlTxBlockHashPrefix, func(value []byte) bool {
+		item := &MgoTxBlockHash{}
+		if json.Unmarshal(value, item) == nil && !item.Reorged && item.Timestamp < timestamp {
+			_ = batch.Delete([]byte(lvlTxBlockHashPrefix + item.Key))
+		}
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return lvlError(batch.Write())
+}
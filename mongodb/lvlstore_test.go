@@ -0,0 +1,733 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestLvlStore(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "swapstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewLvlStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := store
+	store = s
+	return func() {
+		store = old
+		_ = s.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestLvlStoreSwapLifecycle(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	txid, pairID, bind := "0xabcd", "BTC", "0x1111"
+	swap := &MgoSwap{TxID: txid, PairID: pairID, Bind: bind, Status: TxWithBigValue}
+	if err := AddSwapin(swap); err != nil {
+		t.Fatalf("add swapin failed: %v", err)
+	}
+	if err := AddSwapin(swap); !errors.Is(err, ErrItemIsDup) {
+		t.Fatalf("add duplicate swapin, want %v, have %v", ErrItemIsDup, err)
+	}
+	res := &MgoSwapResult{TxID: txid, PairID: pairID, Bind: bind, From: "0x7a1d0000000000000000000000000000000000ab", Value: "100", Status: TxWithBigValue}
+	if err := AddSwapinResult(res); err != nil {
+		t.Fatalf("add swapin result failed: %v", err)
+	}
+
+	if err := PassSwapinBigValue(txid, pairID, bind, "admin:0x01"); err != nil {
+		t.Fatalf("pass big value failed: %v", err)
+	}
+	swaps, err := FindSwapinsWithStatus(TxNotSwapped, 0)
+	if err != nil || len(swaps) != 1 || swaps[0].Key != GetSwapKey(txid, "btc", bind) {
+		t.Fatalf("find swapins with status, have %v, err %v", swaps, err)
+	}
+
+	err = UpdateSwapinResult(txid, pairID, bind, &SwapResultUpdateItems{SwapTx: "0xswap", SwapNonce: 3, SwapValue: "90", Status: MatchTxNotStable})
+	if err != nil {
+		t.Fatalf("update swapin result failed: %v", err)
+	}
+	err = UpdateSwapinResult(txid, pairID, bind, &SwapResultUpdateItems{SwapNonce: 4, Status: KeepStatus})
+	if !errors.Is(err, ErrForbidUpdateNonce) {
+		t.Fatalf("update swap nonce again, want %v, have %v", ErrForbidUpdateNonce, err)
+	}
+
+	if err = UpdateSwapinResultStatus(txid, pairID, bind, MatchTxStable, 1, "", "stable"); err != nil {
+		t.Fatalf("update swapin result status failed: %v", err)
+	}
+	stat, err := GetSwapStatistics(pairID)
+	if err != nil || stat.StableSwapinCount != 1 || stat.TotalSwapinValue != "90" || stat.TotalSwapinFee != "10" {
+		t.Fatalf("wrong swap statistics %+v, err %v", stat, err)
+	}
+
+	history, err := FindSwapinResults(&SwapHistoryFilter{Address: "0x7A1D0000000000000000000000000000000000AB", PairID: "all", Limit: -10, Status: "10"})
+	if err != nil || len(history) != 1 || history[0].SwapTx != "0xswap" {
+		t.Fatalf("find swapin results, have %v, err %v", history, err)
+	}
+
+	transitions, err := GetSwapStatusHistory(txid, pairID, bind)
+	if err != nil || len(transitions) != 6 {
+		t.Fatalf("get swap status history, have %v, err %v", len(transitions), err)
+	}
+	for i, isResult := range []bool{false, true} {
+		initial := transitions[i]
+		if initial.IsResult != isResult || initial.OldStatus != TxWithBigValue || initial.NewStatus != TxWithBigValue || initial.Actor != "register" {
+			t.Fatalf("wrong initial swap status %+v", initial)
+		}
+	}
+	if transitions[2].OldStatus != TxWithBigValue || transitions[2].Actor != "admin:0x01" {
+		t.Fatalf("wrong swap status transition %+v", transitions[2])
+	}
+	if notStable := transitions[4]; !notStable.IsResult || notStable.OldStatus != MatchTxEmpty || notStable.NewStatus != MatchTxNotStable {
+		t.Fatalf("wrong swap status transition %+v", notStable)
+	}
+	last := transitions[5]
+	if !last.IsResult || last.OldStatus != MatchTxNotStable || last.NewStatus != MatchTxStable || last.Actor != "stable" {
+		t.Fatalf("wrong swap status transition %+v", last)
+	}
+
+	noBindTransitions, err := GetSwapStatusHistory(txid, pairID, "")
+	if err != nil || len(noBindTransitions) != len(transitions) {
+		t.Fatalf("get swap status history without bind, have %v, err %v", len(noBindTransitions), err)
+	}
+	for i, item := range noBindTransitions {
+		if item.Key != transitions[i].Key {
+			t.Fatalf("wrong swap status history order without bind, index %v", i)
+		}
+	}
+}
+
+func TestLvlStoreRecords(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	if err := AddToBlacklist("0xAbc", "BTC"); err != nil {
+		t.Fatalf("add to blacklist failed: %v", err)
+	}
+	if isBlacked, err := QueryBlacklist("0xabc", "btc"); !isBlacked || err != nil {
+		t.Fatalf("query blacklist, have %v, err %v", isBlacked, err)
+	}
+	if err := RemoveFromBlacklist("0xabc", "btc"); err != nil {
+		t.Fatalf("remove from blacklist failed: %v", err)
+	}
+	if isBlacked, err := QueryBlacklist("0xabc", "btc"); isBlacked || err != nil {
+		t.Fatalf("query removed blacklist, have %v, err %v", isBlacked, err)
+	}
+
+	if err := UpdateLatestSwapinNonce("0xDcrm", 5); err != nil {
+		t.Fatalf("update swap nonce failed: %v", err)
+	}
+	_ = UpdateLatestSwapinNonce("0xDcrm", 4) // only increase
+	swapinNonces, _ := LoadAllSwapNonces()
+	if swapinNonces["0xdcrm"] != 5 {
+		t.Fatalf("wrong swapin nonces %v", swapinNonces)
+	}
+
+	if err := AddP2shAddress(&MgoP2shAddress{Key: "bind", P2shAddress: "p2sh"}); err != nil {
+		t.Fatalf("add p2sh address failed: %v", err)
+	}
+	if bind, err := FindP2shBindAddress("p2sh"); bind != "bind" || err != nil {
+		t.Fatalf("find p2sh bind address, have %v, err %v", bind, err)
+	}
+
+	if err := AddDestinationTag(&MgoDestinationTag{Key: "bind", DestinationTag: 123}); err != nil {
+		t.Fatalf("add destination tag failed: %v", err)
+	}
+	if err := AddDestinationTag(&MgoDestinationTag{Key: "other", DestinationTag: 123}); !errors.Is(err, ErrItemIsDup) {
+		t.Fatalf("add used destination tag should fail, err %v", err)
+	}
+	if bind, err := FindDestinationTagBindAddress(123); bind != "bind" || err != nil {
+		t.Fatalf("find destination tag bind address, have %v, err %v", bind, err)
+	}
+	if item, err := FindDestinationTag("bind"); err != nil || item.DestinationTag != 123 {
+		t.Fatalf("find destination tag, have %v, err %v", item, err)
+	}
+
+	if err := UpdateLatestScanInfo(true, 100); err != nil {
+		t.Fatalf("update latest scan info failed: %v", err)
+	}
+	_ = UpdateLatestScanInfo(true, 90)
+	if info, err := FindLatestScanInfo(true); err != nil || info.BlockHeight != 100 {
+		t.Fatalf("find latest scan info, have %v, err %v", info, err)
+	}
+}
+
+func TestLvlStoreCommitSwapTx(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	txid, pairID, bind := "0xbeef", "ETH", "0x2222"
+	swaptx, dcrmAddr := "0x3333", "0x7a1d0000000000000000000000000000000000cd"
+	_ = AddSwapout(&MgoSwap{TxID: txid, PairID: pairID, Bind: bind, Status: TxNotSwapped})
+	_ = AddSwapoutResult(&MgoSwapResult{TxID: txid, PairID: pairID, Bind: bind, Value: "100", Status: MatchTxEmpty})
+
+	commit := &SwapTxCommit{
+		TxID:   txid,
+		PairID: pairID,
+		Bind:   bind,
+		ResultItems: &SwapResultUpdateItems{
+			SwapTx:    swaptx,
+			SwapNonce: 5,
+			Status:    MatchTxNotStable,
+			Timestamp: 1,
+		},
+		SwapStatus: TxProcessed,
+		History:    NewSwapHistory(false, txid, bind, swaptx),
+		SwapNonce:  NewLatestSwapNonce(dcrmAddr, false, 6),
+	}
+	if err := CommitSwapTx(commit); err != nil {
+		t.Fatalf("commit swap tx failed: %v", err)
+	}
+
+	swap, _ := FindSwapout(txid, pairID, bind)
+	if swap.Status != TxProcessed {
+		t.Fatalf("swap status, want %v, have %v", TxProcessed, swap.Status)
+	}
+	res, _ := FindSwapoutResult(txid, pairID, bind)
+	if res.SwapTx != swaptx || res.SwapNonce != 5 || res.Status != MatchTxNotStable {
+		t.Fatalf("swap result not committed: %+v", res)
+	}
+	if history, _ := GetSwapHistory(false, txid, bind); len(history) != 1 {
+		t.Fatalf("swap history count, want 1, have %v", len(history))
+	}
+	if swapinNonces, swapoutNonces := LoadAllSwapNonces(); len(swapinNonces) != 0 || swapoutNonces[dcrmAddr] != 6 {
+		t.Fatalf("swap nonce, want 6, have %v", swapoutNonces[dcrmAddr])
+	}
+
+	// forbid commit again with a different swaptx
+	commit.ResultItems.SwapTx = "0x4444"
+	if err := CommitSwapTx(commit); err == nil {
+		t.Fatal("commit swap tx with different swaptx should fail")
+	}
+}
+
+func TestLvlStoreCommitBatchSwapTx(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	pairID, swaptx := "BTC", "batchtx"
+	txids, binds := []string{"0xaa01", "0xaa02"}, []string{"bind1", "bind2"}
+	commits := make([]*SwapTxCommit, 0, len(txids))
+	for i, txid := range txids {
+		_ = AddSwapout(&MgoSwap{TxID: txid, PairID: pairID, Bind: binds[i], Status: TxNotSwapped})
+		_ = AddSwapoutResult(&MgoSwapResult{TxID: txid, PairID: pairID, Bind: binds[i], Value: "100", Status: MatchTxEmpty})
+		commits = append(commits, &SwapTxCommit{
+			TxID:   txid,
+			PairID: pairID,
+			Bind:   binds[i],
+			ResultItems: &SwapResultUpdateItems{
+				SwapTx:    swaptx,
+				SwapMemo:  fmt.Sprintf("SWAPTX:%v:%v", txid, i),
+				Status:    MatchTxNotStable,
+				Timestamp: 1,
+			},
+			SwapStatus: TxProcessed,
+			History:    NewSwapHistory(false, txid, binds[i], swaptx),
+		})
+	}
+	if err := CommitBatchSwapTx(commits); err != nil {
+		t.Fatalf("commit batch swap tx failed: %v", err)
+	}
+	for i, txid := range txids {
+		res, _ := FindSwapoutResult(txid, pairID, binds[i])
+		if res.SwapTx != swaptx || res.SwapMemo != commits[i].ResultItems.SwapMemo || res.Status != MatchTxNotStable {
+			t.Fatalf("swap result not committed: %+v", res)
+		}
+		if swap, _ := FindSwapout(txid, pairID, binds[i]); swap.Status != TxProcessed {
+			t.Fatalf("swap status, want %v, have %v", TxProcessed, swap.Status)
+		}
+	}
+
+	// batch is rejected as whole if any swap is already committed
+	_ = AddSwapout(&MgoSwap{TxID: "0xaa03", PairID: pairID, Bind: "bind3", Status: TxNotSwapped})
+	_ = AddSwapoutResult(&MgoSwapResult{TxID: "0xaa03", PairID: pairID, Bind: "bind3", Value: "100", Status: MatchTxEmpty})
+	commits[0].ResultItems.SwapTx = "batchtx2"
+	commits[1] = &SwapTxCommit{
+		TxID:        "0xaa03",
+		PairID:      pairID,
+		Bind:        "bind3",
+		ResultItems: &SwapResultUpdateItems{SwapTx: "batchtx2", Status: MatchTxNotStable, Timestamp: 2},
+		SwapStatus:  TxProcessed,
+	}
+	if err := CommitBatchSwapTx(commits); err == nil {
+		t.Fatal("commit batch swap tx with committed swap should fail")
+	}
+	if res, _ := FindSwapoutResult("0xaa03", pairID, "bind3"); res.SwapTx != "" {
+		t.Fatalf("swap of rejected batch should not be committed: %+v", res)
+	}
+}
+
+func TestLvlStoreSwapHistoryFilter(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	from := "0x7a1d0000000000000000000000000000000000ef"
+	for i := 1; i <= 5; i++ {
+		res := &MgoSwapResult{
+			TxID:   fmt.Sprintf("0x%02x", i),
+			PairID: "eth",
+			From:   from,
+			Bind:   from,
+			Value:  fmt.Sprintf("%v000000000000000000", i),
+		}
+		if err := AddSwapoutResult(res); err != nil {
+			t.Fatalf("add swapout result failed: %v", err)
+		}
+	}
+
+	// page through the latest first history with cursor
+	var txids []string
+	filter := &SwapHistoryFilter{Address: from, PairID: "ETH", Limit: -2}
+	for {
+		result, err := FindSwapoutResults(filter)
+		if err != nil {
+			t.Fatalf("find swapout results failed: %v", err)
+		}
+		if len(result) == 0 {
+			break
+		}
+		for _, res := range result {
+			txids = append(txids, res.TxID)
+		}
+		filter = &SwapHistoryFilter{Address: from, PairID: "ETH", Limit: -2, Cursor: GetHistoryCursor(result[len(result)-1])}
+	}
+	if strings.Join(txids, ",") != "0x05,0x04,0x03,0x02,0x01" {
+		t.Fatalf("wrong history order %v", txids)
+	}
+
+	result, err := FindSwapoutResults(&SwapHistoryFilter{
+		PairID:      "all",
+		MinInitTime: 1,
+		MinValue:    "2000000000000000000",
+		MaxValue:    "4000000000000000000",
+	})
+	if err != nil || len(result) != 3 || result[0].TxID != "0x02" || result[2].TxID != "0x04" {
+		t.Fatalf("find swapout results with range, have %v, err %v", result, err)
+	}
+
+	_, err = FindSwapoutResults(&SwapHistoryFilter{Cursor: "wrong"})
+	if !errors.Is(err, ErrWrongCursor) {
+		t.Fatalf("query with wrong cursor, want %v, have %v", ErrWrongCursor, err)
+	}
+
+	for _, value := range []string{"-1", "1" + strings.Repeat("0", 78), "abc"} {
+		_, err = FindSwapoutResults(&SwapHistoryFilter{MinValue: value})
+		if !errors.Is(err, ErrWrongValueRange) {
+			t.Fatalf("query with wrong min value %v, want %v, have %v", value, ErrWrongValueRange, err)
+		}
+	}
+}
+
+func TestValueKey(t *testing.T) {
+	// more than 34 digits (precision of decimal128) should keep the order
+	values := []string{"0", "9", "10", "1000000000000000000", "123456789012345678901234567890123456789", "123456789012345678901234567890123456790"}
+	for i, value := range values {
+		key := GetValueKey(value)
+		if len(key) != valueKeyLength || !strings.HasSuffix(key, value) {
+			t.Fatalf("wrong value key %v of %v", key, value)
+		}
+		if i > 0 && key <= GetValueKey(values[i-1]) {
+			t.Fatalf("value key of %v should be greater than %v", value, values[i-1])
+		}
+	}
+	if key := GetValueKey("-1"); key != "" {
+		t.Fatalf("value key of negative value should be empty, have %v", key)
+	}
+}
+
+func TestLvlStoreSwapStatistics(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	pairID := "usdt"
+	day := int64(86400 * 18000)
+	for i, ts := range []int64{day + 10, day + 3700, day + 86400 + 5} {
+		txid := fmt.Sprintf("0x%02x", i)
+		_ = AddSwapinResult(&MgoSwapResult{TxID: txid, PairID: pairID, Bind: "0x01", Value: "100", SwapValue: "90"})
+		if err := UpdateSwapinResultStatus(txid, pairID, "0x01", MatchTxStable, ts, "", "stable"); err != nil {
+			t.Fatalf("update swapin result status failed: %v", err)
+		}
+	}
+
+	daily, err := GetSwapStatisticsRange(pairID, day, day+86400*2, StatisticsByDay)
+	if err != nil || len(daily) != 2 || daily[0].StableSwapinCount != 2 || daily[0].TotalSwapinFee != "20" {
+		t.Fatalf("get daily statistics, have %+v, err %v", daily, err)
+	}
+	hourly, err := GetSwapStatisticsRange(pairID, day, day+3600*3, StatisticsByHour)
+	if err != nil || len(hourly) != 2 || hourly[1].StartTime != day+3600 {
+		t.Fatalf("get hourly statistics, have %+v, err %v", hourly, err)
+	}
+	if _, err = GetSwapStatisticsRange(pairID, day, day+86400*2, "week"); err == nil {
+		t.Fatal("get statistics with unknown granularity should fail")
+	}
+
+	// stable swap is counted once, reverted when reorged, and counted again when stable again
+	_ = UpdateSwapinResultStatus("0x00", pairID, "0x01", MatchTxStable, day+20, "", "stable")
+	if err = UpdateSwapinResultStatus("0x00", pairID, "0x01", TxReorged, day+30, "", "reorg"); err != nil {
+		t.Fatalf("update swapin result status failed: %v", err)
+	}
+	stat, err := GetSwapStatistics(pairID)
+	if err != nil || stat.StableSwapinCount != 2 || stat.TotalSwapinValue != "180" || stat.TotalSwapinFee != "20" {
+		t.Fatalf("get statistics after reorg, have %+v, err %v", stat, err)
+	}
+	daily, _ = GetSwapStatisticsRange(pairID, day, day+86400*2, StatisticsByDay)
+	if len(daily) != 2 || daily[0].StableSwapinCount != 1 || daily[0].TotalSwapinValue != "90" {
+		t.Fatalf("get daily statistics after reorg, have %+v", daily)
+	}
+	_ = UpdateSwapinResultStatus("0x00", pairID, "0x01", MatchTxStable, day+86400+50, "", "stable")
+	daily, _ = GetSwapStatisticsRange(pairID, day, day+86400*2, StatisticsByDay)
+	if len(daily) != 2 || daily[0].StableSwapinCount != 1 || daily[1].StableSwapinCount != 2 {
+		t.Fatalf("get daily statistics after stable again, have %+v", daily)
+	}
+
+	// make the counters drift and rebuild
+	_ = store.UpdateSwapStatistics(&MgoSwapStatistics{Key: pairID, PairID: pairID, SwapStatisticsValues: newSwapStatisticsValues()})
+	rebuilt, err := RebuildSwapStatistics(pairID)
+	if err != nil || rebuilt.StableSwapinCount != 3 || rebuilt.TotalSwapinValue != "270" {
+		t.Fatalf("rebuild statistics, have %+v, err %v", rebuilt, err)
+	}
+	daily, _ = GetSwapStatisticsRange(pairID, day, day+86400*2, StatisticsByDay)
+	if len(daily) != 2 || daily[0].StableSwapinCount != 1 || daily[1].StableSwapinCount != 2 {
+		t.Fatalf("get daily statistics after rebuild, have %+v", daily)
+	}
+}
+
+func TestLvlStoreArchiveStableSwaps(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	pairID := "usdt"
+	for i, ts := range []int64{1000, 2000, 3000} {
+		txid := fmt.Sprintf("0x%02x", i)
+		_ = AddSwapin(&MgoSwap{TxID: txid, PairID: pairID, Bind: "0x01", Status: TxProcessed})
+		_ = AddSwapinResult(&MgoSwapResult{TxID: txid, PairID: pairID, Bind: "0x01", Value: "100", SwapValue: "90"})
+		if err := UpdateSwapinResultStatus(txid, pairID, "0x01", MatchTxStable, ts, "", "stable"); err != nil {
+			t.Fatalf("update swapin result status failed: %v", err)
+		}
+	}
+
+	exportDir, err := ioutil.TempDir("", "swaparchive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(exportDir)
+
+	count, err := ArchiveStableSwaps(true, 2500, 100, exportDir)
+	if err != nil || count != 2 {
+		t.Fatalf("archive stable swaps, have %v, err %v", count, err)
+	}
+	if files, _ := ioutil.ReadDir(exportDir); len(files) != 1 {
+		t.Fatalf("export archived swaps, want 1 file, have %v", len(files))
+	}
+
+	if _, err = store.FindSwapResult(true, "0x00", pairID, "0x01"); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("archived swap result should be removed, err %v", err)
+	}
+	if res, err := FindSwapinResult("0x00", pairID, "0x01"); err != nil || res.Status != MatchTxStable {
+		t.Fatalf("find archived swapin result, have %+v, err %v", res, err)
+	}
+	if swap, err := FindSwapin("0x01", pairID, "0x01"); err != nil || swap.Status != TxProcessed {
+		t.Fatalf("find archived swapin, have %+v, err %v", swap, err)
+	}
+	if err = AddSwapin(&MgoSwap{TxID: "0x00", PairID: pairID, Bind: "0x01"}); !errors.Is(err, ErrItemIsDup) {
+		t.Fatalf("add archived swapin, want %v, have %v", ErrItemIsDup, err)
+	}
+
+	stat, err := RebuildSwapStatistics(pairID)
+	if err != nil || stat.StableSwapinCount != 3 {
+		t.Fatalf("rebuild statistics with archived swaps, have %+v, err %v", stat, err)
+	}
+
+	if count, err = ArchiveStableSwaps(true, 2500, 100, ""); err != nil || count != 0 {
+		t.Fatalf("archive stable swaps again, have %v, err %v", count, err)
+	}
+}
+
+func TestLvlStoreLeaderLease(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	lease, err := store.AcquireLeaderLease("leader", "node1", 1000, 2000)
+	if err != nil || lease.Holder != "node1" || lease.Term != 1 {
+		t.Fatalf("acquire new lease, have %+v, err %v", lease, err)
+	}
+	if _, err = store.AcquireLeaderLease("leader", "node2", 1500, 2500); !errors.Is(err, ErrLeaseIsHeld) {
+		t.Fatalf("acquire held lease, want %v, have %v", ErrLeaseIsHeld, err)
+	}
+	lease, err = store.AcquireLeaderLease("leader", "node1", 1800, 2800)
+	if err != nil || lease.Term != 1 || lease.ExpireAt != 2800 {
+		t.Fatalf("renew lease, have %+v, err %v", lease, err)
+	}
+	lease, err = store.AcquireLeaderLease("leader", "node2", 3000, 4000)
+	if err != nil || lease.Holder != "node2" || lease.Term != 2 {
+		t.Fatalf("take over expired lease, have %+v, err %v", lease, err)
+	}
+	if err = store.ReleaseLeaderLease("leader", "node1"); err != nil {
+		t.Fatalf("release lease of others, err %v", err)
+	}
+	if _, err = store.AcquireLeaderLease("leader", "node1", 3500, 4500); !errors.Is(err, ErrLeaseIsHeld) {
+		t.Fatalf("release lease of others should not take effect, err %v", err)
+	}
+	_ = store.ReleaseLeaderLease("leader", "node2")
+	lease, err = store.AcquireLeaderLease("leader", "node1", 3500, 4500)
+	if err != nil || lease.Holder != "node1" || lease.Term != 3 {
+		t.Fatalf("acquire released lease, have %+v, err %v", lease, err)
+	}
+}
+
+func TestLvlStoreSwapTaskQueue(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	queue := "swapin:0xdcrm"
+	for _, txid := range []string{"0x01", "0x02"} {
+		if err := AddSwapTask(&MgoSwapTask{Queue: queue, IsSwapin: true, TxID: txid, PairID: "USDT", Bind: "0x03", Args: "{}"}); err != nil {
+			t.Fatalf("add swap task failed: %v", err)
+		}
+	}
+	if err := AddSwapTask(&MgoSwapTask{Queue: queue, IsSwapin: true, TxID: "0x01", PairID: "usdt", Bind: "0x03"}); !errors.Is(err, ErrItemIsDup) {
+		t.Fatalf("add duplicate swap task, want %v, have %v", ErrItemIsDup, err)
+	}
+
+	task, err := LeaseSwapTask(queue, time.Hour)
+	if err != nil || task.TxID != "0x01" || task.Attempts != 1 {
+		t.Fatalf("lease swap task, have %+v, err %v", task, err)
+	}
+	// leased task is invisible to others
+	task2, err := LeaseSwapTask(queue, time.Hour)
+	if err != nil || task2.TxID != "0x02" {
+		t.Fatalf("lease second swap task, have %+v, err %v", task2, err)
+	}
+	if _, err = LeaseSwapTask(queue, time.Hour); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("lease empty queue, want %v, have %v", ErrItemNotFound, err)
+	}
+
+	if err = AckSwapTask(task2); err != nil {
+		t.Fatalf("ack swap task failed: %v", err)
+	}
+	MaxSwapTaskAttempts = 2
+	defer func() { MaxSwapTaskAttempts = 5 }()
+	if err = NackSwapTask(task, 0, errors.New("sign failed")); err != nil {
+		t.Fatalf("nack swap task failed: %v", err)
+	}
+	task, err = LeaseSwapTask(queue, time.Hour)
+	if err != nil || task.Attempts != 2 || task.LastError != "sign failed" {
+		t.Fatalf("lease nacked swap task, have %+v, err %v", task, err)
+	}
+	_ = NackSwapTask(task, 0, errors.New("sign failed again"))
+	if _, err = LeaseSwapTask(queue, time.Hour); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("lease dead swap task, want %v, have %v", ErrItemNotFound, err)
+	}
+	stats, err := GetSwapTaskQueueStats()
+	if err != nil || len(stats) != 1 || stats[0].Dead != 1 || stats[0].Pending != 0 {
+		t.Fatalf("get swap task queue stats, have %+v, err %v", stats, err)
+	}
+
+	if err = RetrySwapTask(task.Key); err != nil {
+		t.Fatalf("retry swap task failed: %v", err)
+	}
+	task, err = LeaseSwapTask(queue, time.Hour)
+	if err != nil || task.Attempts != 1 || task.Status != SwapTaskPending {
+		t.Fatalf("lease retried swap task, have %+v, err %v", task, err)
+	}
+}
+
+func TestLvlStoreSwapTaskStaleLease(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	queue := "swapout:0xdcrm"
+	if err := AddSwapTask(&MgoSwapTask{Queue: queue, TxID: "0x01", PairID: "usdt", Bind: "0x03"}); err != nil {
+		t.Fatalf("add swap task failed: %v", err)
+	}
+	// lease expires immediately, then it is leased by another consumer
+	stale, err := LeaseSwapTask(queue, 0)
+	if err != nil {
+		t.Fatalf("lease swap task failed: %v", err)
+	}
+	task, err := LeaseSwapTask(queue, time.Hour)
+	if err != nil || task.Attempts != 2 || task.LeaseToken == stale.LeaseToken {
+		t.Fatalf("lease expired swap task, have %+v, err %v", task, err)
+	}
+
+	if err = NackSwapTask(stale, 0, errors.New("stale")); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("nack by stale lease holder, want %v, have %v", ErrItemNotFound, err)
+	}
+	if err = MarkSwapTaskDead(stale, errors.New("stale")); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("mark dead by stale lease holder, want %v, have %v", ErrItemNotFound, err)
+	}
+	if err = AckSwapTask(stale); !errors.Is(err, ErrItemNotFound) {
+		t.Fatalf("ack by stale lease holder, want %v, have %v", ErrItemNotFound, err)
+	}
+	if err = AckSwapTask(task); err != nil {
+		t.Fatalf("ack by lease holder failed: %v", err)
+	}
+}
+
+func TestLvlStoreSignIntent(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	intent := &MgoSignIntent{IsSwapin: true, TxID: "0x1111", PairID: "pair", Bind: "0xbind", Nonce: 7}
+	if err := OpenSignIntent(intent); err != nil {
+		t.Fatalf("open sign intent failed, err %v", err)
+	}
+	if err := OpenSignIntent(&MgoSignIntent{IsSwapin: true, TxID: "0x1111", PairID: "pair", Bind: "0xbind"}); !errors.Is(err, ErrSignIntentIsOpen) {
+		t.Fatalf("reopen unfinished intent, want %v, have %v", ErrSignIntentIsOpen, err)
+	}
+	if err := AddSignIntentRequest(intent.Key, "keyid1", []string{"0xhash"}); err != nil {
+		t.Fatalf("add sign request failed, err %v", err)
+	}
+	if err := UpdateSignIntentStatus(intent.Key, SignIntentSigned, "0xsigned", ""); err != nil {
+		t.Fatalf("update sign intent failed, err %v", err)
+	}
+	intents, err := FindUnfinishedSignIntents()
+	if err != nil || len(intents) != 1 {
+		t.Fatalf("find unfinished intents, have %v, err %v", len(intents), err)
+	}
+	got := intents[0]
+	if got.Status != SignIntentSigned || got.SignedTxHash != "0xsigned" || got.Nonce != 7 ||
+		len(got.KeyIDs) != 1 || got.KeyIDs[0] != "keyid1" || len(got.MsgHashes) != 1 {
+		t.Fatalf("unexpected intent %+v", got)
+	}
+	if err = UpdateSignIntentStatus(intent.Key, SignIntentFinalized, "", "done"); err != nil {
+		t.Fatalf("finalize sign intent failed, err %v", err)
+	}
+	if intents, _ = FindUnfinishedSignIntents(); len(intents) != 0 {
+		t.Fatalf("finalized intent should not be unfinished, have %v", len(intents))
+	}
+	if err = OpenSignIntent(&MgoSignIntent{IsSwapin: true, TxID: "0x1111", PairID: "pair", Bind: "0xbind"}); err != nil {
+		t.Fatalf("reopen finished intent failed, err %v", err)
+	}
+}
+
+func TestLvlStoreSwapVelocity(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	limitCheck := func(usage *SwapVelocityUsage) error {
+		if usage.PairHourly.Int64()+100 > 250 {
+			return errors.New("exceeds limit")
+		}
+		return nil
+	}
+	for i, bind := range []string{"bind1", "bind1", "bind2"} {
+		txid := fmt.Sprintf("0xcc0%d", i)
+		err := AddSwapVelocity(true, txid, "PAIR", bind, "100", "swap", limitCheck)
+		if i < 2 && err != nil {
+			t.Fatalf("add swap velocity %v failed, err %v", i, err)
+		}
+		if i == 2 && err == nil {
+			t.Fatal("add swap velocity exceeding limit should fail")
+		}
+	}
+	if err := AddSwapVelocity(true, "0xcc01", "pair", "bind1", "100", "swap", limitCheck); err != nil {
+		t.Fatalf("counted swap should pass idempotently, err %v", err)
+	}
+	usage, err := GetSwapVelocityUsage(true, "pair", "BIND1")
+	if err != nil {
+		t.Fatalf("get swap velocity usage failed, err %v", err)
+	}
+	if usage.PairHourly.Int64() != 200 || usage.PairDaily.Int64() != 200 || usage.BindDaily.Int64() != 200 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+	if usage, _ = GetSwapVelocityUsage(false, "pair", ""); usage.PairDaily.Sign() != 0 {
+		t.Fatalf("swapout usage should be zero, have %v", usage.PairDaily)
+	}
+
+	_ = AddSwapin(&MgoSwap{TxID: "0xcc02", PairID: "pair", Bind: "bind2", Status: TxExceedsRateLimit})
+	_ = AddSwapinResult(&MgoSwapResult{TxID: "0xcc02", PairID: "pair", Bind: "bind2", Value: "100"})
+	if err = PassSwapRateLimit(true, "0xcc02", "pair", "bind2", "admin", nil); err != nil {
+		t.Fatalf("pass swap rate limit failed, err %v", err)
+	}
+	swap, _ := FindSwapin("0xcc02", "pair", "bind2")
+	if swap == nil || swap.Status != TxNotSwapped {
+		t.Fatalf("passed swap should be not swapped, have %+v", swap)
+	}
+	if err = PassSwapRateLimit(true, "0xcc02", "pair", "bind2", "admin", nil); err == nil {
+		t.Fatal("pass swap not in rate limit status should fail")
+	}
+	if usage, _ = GetSwapVelocityUsage(true, "pair", ""); usage.PairDaily.Int64() != 300 {
+		t.Fatalf("passed swap should be counted, have %v", usage.PairDaily)
+	}
+
+	if err = store.RemoveSwapVelocitiesBefore(time.Now().Add(time.Minute).UnixNano() / 1e6); err != nil {
+		t.Fatalf("remove swap velocities failed, err %v", err)
+	}
+	if usage, _ = GetSwapVelocityUsage(true, "pair", ""); usage.PairDaily.Sign() != 0 {
+		t.Fatalf("removed swap velocities should not be counted, have %v", usage.PairDaily)
+	}
+}
+
+func TestLvlStoreTxBlockHash(t *testing.T) {
+	defer newTestLvlStore(t)()
+
+	pairID, bind := "pair", "0xbind"
+	for _, txid := range []string{"0xdd01", "0xdd02"} {
+		_ = AddSwapout(&MgoSwap{TxID: txid, PairID: pairID, Bind: bind, Status: TxProcessed})
+		_ = AddSwapoutResult(&MgoSwapResult{TxID: txid, PairID: pairID, Bind: bind, Value: "100", Status: MatchTxEmpty})
+		if err := AddTxBlockHash(false, txid, pairID, bind, false, txid, 100, "0xblock100"); err != nil {
+			t.Fatalf("add tx block hash failed, err %v", err)
+		}
+	}
+	_ = UpdateSwapoutResult("0xdd02", pairID, bind, &SwapResultUpdateItems{SwapTx: "0xswaptx", Status: MatchTxNotStable, Timestamp: 1})
+	if err := AddTxBlockHash(false, "0xdd02", pairID, bind, true, "0xswaptx", 200, "0xblock200"); err != nil {
+		t.Fatalf("add swap tx block hash failed, err %v", err)
+	}
+	items, err := FindTxBlockHashesToWatch(0)
+	if err != nil || len(items) != 3 {
+		t.Fatalf("find tx block hashes to watch, have %v, err %v", len(items), err)
+	}
+
+	for _, item := range items {
+		if item.IsSwapTx || item.TxID == "0xdd01" {
+			if err = MarkSwapReorged(item, "reorged"); err != nil {
+				t.Fatalf("mark swap reorged failed, err %v", err)
+			}
+		}
+	}
+	if items, _ = FindTxBlockHashesToWatch(0); len(items) != 1 || items[0].TxID != "0xdd02" || items[0].IsSwapTx {
+		t.Fatalf("reorged txs should not be watched, have %+v", items)
+	}
+	if !IsTxBlockHashRecorded(false, "0xdd02", "PAIR", bind, false) {
+		t.Fatal("watched tx block hash should be recorded")
+	}
+	if IsTxBlockHashRecorded(false, "0xdd01", pairID, bind, false) || IsTxBlockHashRecorded(false, "0xdd02", pairID, bind, true) {
+		t.Fatal("reorged tx block hash should not be counted as recorded")
+	}
+	if IsTxBlockHashRecorded(false, "0xdd03", pairID, bind, false) {
+		t.Fatal("tx block hash should not be recorded")
+	}
+	for _, txid := range []string{"0xdd01", "0xdd02"} {
+		swap, _ := FindSwapout(txid, pairID, bind)
+		res, _ := FindSwapoutResult(txid, pairID, bind)
+		if swap == nil || res == nil || swap.Status != TxReorged || res.Status != TxReorged {
+			t.Fatalf("swap %v should be reorged, have %+v %+v", txid, swap, res)
+		}
+	}
+
+	if err = PassSwapReorged(false, "0xdd01", pairID, bind, "", "admin"); err != nil {
+		t.Fatalf("pass reorged swap failed, err %v", err)
+	}
+	if err = PassSwapReorged(false, "0xdd02", pairID, bind, "", "admin"); err != nil {
+		t.Fatalf("pass reorged swap failed, err %v", err)
+	}
+	swap1, _ := FindSwapout("0xdd01", pairID, bind)
+	res1, _ := FindSwapoutResult("0xdd01", pairID, bind)
+	if swap1.Status != TxNotStable || res1.Status != MatchTxEmpty {
+		t.Fatalf("not swapped reorged swap should be reverified, have %v %v", swap1.Status, res1.Status)
+	}
+	swap2, _ := FindSwapout("0xdd02", pairID, bind)
+	res2, _ := FindSwapoutResult("0xdd02", pairID, bind)
+	if swap2.Status != TxProcessed || res2.Status != MatchTxNotStable {
+		t.Fatalf("swapped reorged swap should recheck swap tx, have %v %v", swap2.Status, res2.Status)
+	}
+
+	if err = PruneTxBlockHashes(time.Now().Add(time.Minute).UnixNano() / 1e6); err != nil {
+		t.Fatalf("prune tx block hashes failed, err %v", err)
+	}
+	if items, _ = FindTxBlockHashesToWatch(0); len(items) != 0 {
+		t.Fatalf("pruned tx block hashes should not be watched, have %v", len(items))
+	}
+	if _, err = store.FindTxBlockHash(GetTxBlockHashKey(false, "0xdd01", pairID, bind, false)); err != nil {
+		t.Fatalf("reorged tx block hash should be kept, err %v", err)
+	}
+}
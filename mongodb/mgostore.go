@@ -0,0 +1,1065 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MgoStore swap store on mongodb
+type MgoStore struct{}
+
+var _ SwapStore = (*MgoStore)(nil)
+
+// Name name of store
+func (s *MgoStore) Name() string {
+	return "mongodb"
+}
+
+func getSwapCollection(isSwapin bool) *mongo.Collection {
+	if isSwapin {
+		return collSwapin
+	}
+	return collSwapout
+}
+
+func getSwapResultCollection(isSwapin bool) *mongo.Collection {
+	if isSwapin {
+		return collSwapinResult
+	}
+	return collSwapoutResult
+}
+
+func insertOne(ctx context.Context, collection *mongo.Collection, item interface{}) error {
+	_, err := collection.InsertOne(ctx, item)
+	return mgoError(err)
+}
+
+func updateByID(ctx context.Context, collection *mongo.Collection, id interface{}, updates bson.M) error {
+	res, err := collection.UpdateByID(ctx, id, bson.M{"$set": updates})
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+func upsertByID(ctx context.Context, collection *mongo.Collection, id, item interface{}) error {
+	opts := options.Replace().SetUpsert(true)
+	_, err := collection.ReplaceOne(ctx, bson.M{"_id": id}, item, opts)
+	return mgoError(err)
+}
+
+func findByID(collection *mongo.Collection, id, result interface{}) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return mgoError(collection.FindOne(ctx, bson.M{"_id": id}).Decode(result))
+}
+
+func findAll(collection *mongo.Collection, filter, result interface{}, opts ...*options.FindOptions) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	cur, err := collection.Find(ctx, filter, opts...)
+	if err != nil {
+		return mgoError(err)
+	}
+	return mgoError(cur.All(ctx, result))
+}
+
+func countDocuments(collection *mongo.Collection, filter interface{}) (int, error) {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	count, err := collection.CountDocuments(ctx, filter)
+	return int(count), mgoError(err)
+}
+
+// ------------------ swapin / swapout common ------------------------
+
+// AddSwap add swap
+func (s *MgoStore) AddSwap(isSwapin bool, ms *MgoSwap) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, getSwapCollection(isSwapin), ms)
+}
+
+func getSwapStatusUpdates(status SwapStatus, timestamp int64, memo string) bson.M {
+	updates := bson.M{"status": status, "timestamp": timestamp}
+	if memo != "" {
+		updates["memo"] = memo
+	} else if shouldClearSwapMemo(status) {
+		updates["memo"] = ""
+	}
+	return updates
+}
+
+// UpdateSwapStatus update swap status
+func (s *MgoStore) UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	updates := getSwapStatusUpdates(status, timestamp, memo)
+	return updateByID(ctx, getSwapCollection(isSwapin), GetSwapKey(txid, pairID, bind), updates)
+}
+
+// FindSwap find swap
+func (s *MgoStore) FindSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error) {
+	result := &MgoSwap{}
+	err := findSwapOrSwapResult(result, getSwapCollection(isSwapin), txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func findSwapOrSwapResult(result interface{}, collection *mongo.Collection, txid, pairID, bind string) (err error) {
+	if bind != "" {
+		return findByID(collection, GetSwapKey(txid, pairID, bind), result)
+	}
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	qtxid := bson.M{"txid": txid}
+	qpair := bson.M{"pairid": strings.ToLower(pairID)}
+	queries := []bson.M{qtxid, qpair}
+	err = collection.FindOne(ctx, bson.M{"$and": queries}).Decode(result)
+	return mgoError(err)
+}
+
+// FindSwapsWithStatus find swaps with status in the past septime
+func (s *MgoStore) FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
+	err = findSwapsOrSwapResultsWithStatus(&result, getSwapCollection(isSwapin), status, septime)
+	return result, err
+}
+
+func findSwapsOrSwapResultsWithStatus(result interface{}, collection *mongo.Collection, status SwapStatus, septime int64) error {
+	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
+	qstatus := bson.M{"status": status}
+	queries := []bson.M{qtime, qstatus}
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}}).SetLimit(maxCountOfResults)
+	return findAll(collection, bson.M{"$and": queries}, result, opts)
+}
+
+// FindSwapsWithPairIDAndStatus find swaps with pairID and status in the past septime
+func (s *MgoStore) FindSwapsWithPairIDAndStatus(isSwapin bool, pairID string, status SwapStatus, septime int64) (result []*MgoSwap, err error) {
+	err = findSwapsOrSwapResultsWithPairIDAndStatus(&result, pairID, getSwapCollection(isSwapin), status, septime)
+	return result, err
+}
+
+func findSwapsOrSwapResultsWithPairIDAndStatus(result interface{}, pairID string, collection *mongo.Collection, status SwapStatus, septime int64) error {
+	qpair := bson.M{"pairid": pairID}
+	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
+	qstatus := bson.M{"status": status}
+	queries := []bson.M{qpair, qtime, qstatus}
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}}).SetLimit(maxCountOfResults)
+	return findAll(collection, bson.M{"$and": queries}, result, opts)
+}
+
+// GetCountOfSwapsWithStatus get count of swaps with status
+func (s *MgoStore) GetCountOfSwapsWithStatus(isSwapin bool, pairID string, status SwapStatus) (int, error) {
+	return getCountWithStatus(getSwapCollection(isSwapin), pairID, status)
+}
+
+// ------------------ swapin / swapout result common ------------------------
+
+// AddSwapResult add swap result
+func (s *MgoStore) AddSwapResult(isSwapin bool, mr *MgoSwapResult) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, getSwapResultCollection(isSwapin), mr)
+}
+
+func getSwapResultUpdates(items *SwapResultUpdateItems) bson.M {
+	updates := bson.M{
+		"timestamp": items.Timestamp,
+	}
+	if items.Status != KeepStatus {
+		updates["status"] = items.Status
+	}
+	if items.SwapTx != "" {
+		updates["swaptx"] = items.SwapTx
+	}
+	if len(items.OldSwapTxs) != 0 {
+		updates["oldswaptxs"] = items.OldSwapTxs
+	}
+	if len(items.OldSwapVals) != 0 {
+		updates["oldswapvals"] = items.OldSwapVals
+	}
+	if items.SwapHeight != 0 {
+		updates["swapheight"] = items.SwapHeight
+	}
+	if items.SwapTime != 0 {
+		updates["swaptime"] = items.SwapTime
+	}
+	if items.SwapValue != "" {
+		updates["swapvalue"] = items.SwapValue
+	}
+	if items.SwapType != 0 {
+		updates["swaptype"] = items.SwapType
+	}
+	if items.SwapNonce != 0 {
+		updates["swapnonce"] = items.SwapNonce
+	}
+	if items.SwapMemo != "" {
+		updates["swapmemo"] = items.SwapMemo
+	}
+	if items.Memo != "" {
+		updates["memo"] = items.Memo
+	} else if items.Status == MatchTxNotStable {
+		updates["memo"] = ""
+	}
+	return updates
+}
+
+// UpdateSwapResult update swap result
+func (s *MgoStore) UpdateSwapResult(isSwapin bool, txid, pairID, bind string, items *SwapResultUpdateItems) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	updates := getSwapResultUpdates(items)
+	return updateByID(ctx, getSwapResultCollection(isSwapin), GetSwapKey(txid, pairID, bind), updates)
+}
+
+// UpdateSwapResultStatus update swap result status
+func (s *MgoStore) UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+	updates := bson.M{"status": status, "timestamp": timestamp}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	if status == Reswapping {
+		updates["memo"] = ""
+		updates["swaptx"] = ""
+		updates["oldswaptxs"] = nil
+		updates["swapheight"] = 0
+		updates["swaptime"] = 0
+		updates["swapnonce"] = 0
+	}
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return updateByID(ctx, getSwapResultCollection(isSwapin), GetSwapKey(txid, pairID, bind), updates)
+}
+
+// FindSwapResult find swap result
+func (s *MgoStore) FindSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error) {
+	result := &MgoSwapResult{}
+	err := findSwapOrSwapResult(result, getSwapResultCollection(isSwapin), txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// FindSwapResultsWithStatus find swap results with status in the past septime
+func (s *MgoStore) FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) (result []*MgoSwapResult, err error) {
+	err = findSwapsOrSwapResultsWithStatus(&result, getSwapResultCollection(isSwapin), status, septime)
+	return result, err
+}
+
+// FindSwapResults find swap history results
+func (s *MgoStore) FindSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	return findSwapResultsByFilter(getSwapResultCollection(isSwapin), filter)
+}
+
+func findSwapResultsByFilter(coll *mongo.Collection, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	result := make([]*MgoSwapResult, 0, 20)
+
+	var queries []bson.M
+
+	if filter.PairID != "" && filter.PairID != allPairs {
+		queries = append(queries, bson.M{"pairid": filter.PairID})
+	}
+
+	if filter.Address != "" && filter.Address != allAddresses {
+		queries = append(queries, bson.M{"from": filter.Address})
+	}
+
+	if filter.To != "" {
+		queries = append(queries, bson.M{"to": filter.To})
+	}
+
+	if filter.Bind != "" {
+		queries = append(queries, bson.M{"bind": filter.Bind})
+	}
+
+	if filter.SwapTx != "" {
+		queries = append(queries, bson.M{"swaptx": filter.SwapTx})
+	}
+
+	if len(filter.statuses) > 0 {
+		if len(filter.statuses) == 1 {
+			queries = append(queries, bson.M{"status": filter.statuses[0]})
+		} else {
+			qstatus := bson.M{"status": bson.M{"$in": filter.statuses}}
+			queries = append(queries, qstatus)
+		}
+	}
+
+	if qrange := getRangeQuery(filter.MinInitTime, filter.MaxInitTime); qrange != nil {
+		queries = append(queries, bson.M{"inittime": qrange})
+	}
+
+	if qrange := getRangeQuery(filter.MinTimestamp, filter.MaxTimestamp); qrange != nil {
+		queries = append(queries, bson.M{"timestamp": qrange})
+	}
+
+	// value is stored as decimal string, compare the indexed zero padded value key
+	if filter.minValueKey != "" || filter.maxValueKey != "" {
+		minValueKey := filter.minValueKey
+		if minValueKey == "" {
+			minValueKey = GetValueKey("0") // exclude invalid values
+		}
+		qrange := bson.M{"$gte": minValueKey}
+		if filter.maxValueKey != "" {
+			qrange["$lte"] = filter.maxValueKey
+		}
+		queries = append(queries, bson.M{"valuekey": qrange})
+	}
+
+	sortOrder := 1
+	cmpOp := "$gt"
+	if filter.isLatestFirst() {
+		sortOrder = -1
+		cmpOp = "$lt"
+	}
+
+	if cursor := filter.cursor; cursor != nil {
+		queries = append(queries, bson.M{"$or": []bson.M{
+			{"inittime": bson.M{cmpOp: cursor.InitTime}},
+			{"inittime": cursor.InitTime, "_id": bson.M{cmpOp: cursor.Key}},
+		}})
+	}
+
+	var query bson.M
+	switch len(queries) {
+	case 0:
+		query = bson.M{}
+	case 1:
+		query = queries[0]
+	default:
+		query = bson.M{"$and": queries}
+	}
+	limit := filter.Limit
+	if limit < 0 {
+		limit = -limit
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "inittime", Value: sortOrder}, {Key: "_id", Value: sortOrder}}).
+		SetSkip(int64(filter.Offset)).
+		SetLimit(int64(limit))
+	err := findAll(coll, query, &result, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func getRangeQuery(min, max int64) bson.M {
+	if min == 0 && max == 0 {
+		return nil
+	}
+	qrange := bson.M{}
+	if min != 0 {
+		qrange["$gte"] = min
+	}
+	if max != 0 {
+		qrange["$lte"] = max
+	}
+	return qrange
+}
+
+// FindSwapResultsToReplace find swap results to replace
+func (s *MgoStore) FindSwapResultsToReplace(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error) {
+	qstatus := bson.M{"status": status}
+	qheight := bson.M{"swapheight": 0}
+	qtime := bson.M{"timestamp": bson.M{"$gte": septime}}
+	queries := []bson.M{qstatus, qheight, qtime}
+	result := make([]*MgoSwapResult, 0, 20)
+	opts := options.Find().SetSort(bson.D{{Key: "inittime", Value: 1}}).SetLimit(maxCountOfReplaceResults)
+	err := findAll(getSwapResultCollection(isSwapin), bson.M{"$and": queries}, &result, opts)
+	return result, err
+}
+
+// GetCountOfSwapResults get count of swap results
+func (s *MgoStore) GetCountOfSwapResults(isSwapin bool, pairID string) (int, error) {
+	return countDocuments(getSwapResultCollection(isSwapin), bson.M{"pairid": pairID})
+}
+
+// GetCountOfSwapResultsWithStatus get count of swap results with status
+func (s *MgoStore) GetCountOfSwapResultsWithStatus(isSwapin bool, pairID string, status SwapStatus) (int, error) {
+	return getCountWithStatus(getSwapResultCollection(isSwapin), pairID, status)
+}
+
+func getCountWithStatus(collection *mongo.Collection, pairID string, status SwapStatus) (int, error) {
+	qpair := bson.M{"pairid": pairID}
+	qstatus := bson.M{"status": status}
+	queries := []bson.M{qpair, qstatus}
+	return countDocuments(collection, bson.M{"$and": queries})
+}
+
+// CommitSwapTx commit swap tx records in one transaction.
+// If the server is standalone (no transaction support) they are written in order.
+func (s *MgoStore) CommitSwapTx(commit *SwapTxCommit) error {
+	err := runInTransaction(func(ctx context.Context) error {
+		return commitSwapTx(ctx, commit)
+	})
+	if err != nil {
+		log.Warn("[mongodb] commit swap tx transaction aborted", "txid", commit.TxID, "pairID", commit.PairID, "bind", commit.Bind, "err", err)
+	}
+	return err
+}
+
+// CommitBatchSwapTx commit records of swaps paid by one batch tx in one transaction.
+func (s *MgoStore) CommitBatchSwapTx(commits []*SwapTxCommit) error {
+	err := runInTransaction(func(ctx context.Context) error {
+		for _, commit := range commits {
+			if err := commitSwapTx(ctx, commit); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Warn("[mongodb] commit batch swap tx transaction aborted", "count", len(commits), "err", err)
+	}
+	return err
+}
+
+// runInTransaction run fn in transaction if supported, otherwise run fn directly
+func runInTransaction(fn func(ctx context.Context) error) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTransactionTimeout)
+	defer cancel()
+	if !isTransactionSupported {
+		return fn(ctx)
+	}
+	session, err := client.StartSession()
+	if err != nil {
+		return mgoError(err)
+	}
+	defer session.EndSession(ctx)
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	return mgoError(err)
+}
+
+func commitSwapTx(ctx context.Context, commit *SwapTxCommit) error {
+	key := GetSwapKey(commit.TxID, commit.PairID, commit.Bind)
+	err := updateByID(ctx, getSwapResultCollection(commit.IsSwapin), key, getSwapResultUpdates(commit.ResultItems))
+	if err != nil {
+		return err
+	}
+	err = updateByID(ctx, getSwapCollection(commit.IsSwapin), key, getSwapStatusUpdates(commit.SwapStatus, commit.ResultItems.Timestamp, ""))
+	if err != nil {
+		return err
+	}
+	if commit.History != nil {
+		err = insertOne(ctx, collSwapHistory, commit.History)
+		if err != nil {
+			return err
+		}
+	}
+	if commit.SwapNonce != nil {
+		err = upsertByID(ctx, collLatestSwapNonces, commit.SwapNonce.Key, commit.SwapNonce)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ------------------ archive ------------------------
+
+func getArchiveSwapCollection(isSwapin bool) *mongo.Collection {
+	if isSwapin {
+		return collSwapinArchive
+	}
+	return collSwapoutArchive
+}
+
+func getArchiveSwapResultCollection(isSwapin bool) *mongo.Collection {
+	if isSwapin {
+		return collSwapinResultArchive
+	}
+	return collSwapoutResultArchive
+}
+
+// ArchiveSwap move swap and swap result to archive collections
+func (s *MgoStore) ArchiveSwap(isSwapin bool, swap *MgoSwap, res *MgoSwapResult) error {
+	return runInTransaction(func(ctx context.Context) error {
+		if swap != nil {
+			err := upsertByID(ctx, getArchiveSwapCollection(isSwapin), swap.Key, swap)
+			if err != nil {
+				return err
+			}
+		}
+		err := upsertByID(ctx, getArchiveSwapResultCollection(isSwapin), res.Key, res)
+		if err != nil {
+			return err
+		}
+		if swap != nil {
+			_, err = getSwapCollection(isSwapin).DeleteOne(ctx, bson.M{"_id": swap.Key})
+			if err != nil {
+				return mgoError(err)
+			}
+		}
+		_, err = getSwapResultCollection(isSwapin).DeleteOne(ctx, bson.M{"_id": res.Key})
+		return mgoError(err)
+	})
+}
+
+// FindArchivedSwap find archived swap
+func (s *MgoStore) FindArchivedSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error) {
+	var result MgoSwap
+	err := findByID(getArchiveSwapCollection(isSwapin), GetSwapKey(txid, pairID, bind), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindArchivedSwapResults find archived swap results with filter
+func (s *MgoStore) FindArchivedSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error) {
+	return findSwapResultsByFilter(getArchiveSwapResultCollection(isSwapin), filter)
+}
+
+// FindArchivedSwapResult find archived swap result
+func (s *MgoStore) FindArchivedSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error) {
+	var result MgoSwapResult
+	err := findByID(getArchiveSwapResultCollection(isSwapin), GetSwapKey(txid, pairID, bind), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ------------------ statistics ------------------------
+
+// FindSwapStatistics find swap statistics
+func (s *MgoStore) FindSwapStatistics(pairID string) (*MgoSwapStatistics, error) {
+	var result MgoSwapStatistics
+	err := findByID(collSwapStatistics, pairID, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateSwapStatistics update swap statistics
+func (s *MgoStore) UpdateSwapStatistics(stat *MgoSwapStatistics) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return upsertByID(ctx, collSwapStatistics, stat.Key, stat)
+}
+
+// FindSwapStatisticsBucket find swap statistics bucket
+func (s *MgoStore) FindSwapStatisticsBucket(key string) (*MgoSwapStatisticsBucket, error) {
+	var result MgoSwapStatisticsBucket
+	err := findByID(collStatisticsBuckets, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// UpdateSwapStatisticsBucket update swap statistics bucket
+func (s *MgoStore) UpdateSwapStatisticsBucket(bucket *MgoSwapStatisticsBucket) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return upsertByID(ctx, collStatisticsBuckets, bucket.Key, bucket)
+}
+
+// FindSwapStatisticsBuckets find swap statistics buckets in time range [from, to]
+func (s *MgoStore) FindSwapStatisticsBuckets(pairID, granularity string, from, to int64) ([]*MgoSwapStatisticsBucket, error) {
+	qpair := bson.M{"pairid": pairID}
+	qgranularity := bson.M{"granularity": granularity}
+	qtime := bson.M{"starttime": bson.M{"$gte": from, "$lte": to}}
+	queries := []bson.M{qpair, qgranularity, qtime}
+	opts := options.Find().SetSort(bson.D{{Key: "starttime", Value: 1}})
+	result := make([]*MgoSwapStatisticsBucket, 0, 24)
+	err := findAll(collStatisticsBuckets, bson.M{"$and": queries}, &result, opts)
+	return result, err
+}
+
+// RemoveSwapStatisticsBuckets remove all swap statistics buckets of pair
+func (s *MgoStore) RemoveSwapStatisticsBuckets(pairID string) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	_, err := collStatisticsBuckets.DeleteMany(ctx, bson.M{"pairid": pairID})
+	return mgoError(err)
+}
+
+// ------------------ p2sh address ------------------------
+
+// AddP2shAddress add p2sh address
+func (s *MgoStore) AddP2shAddress(ma *MgoP2shAddress) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collP2shAddress, ma)
+}
+
+// FindP2shAddress find p2sh addrss through bind address
+func (s *MgoStore) FindP2shAddress(key string) (*MgoP2shAddress, error) {
+	var result MgoP2shAddress
+	err := findByID(collP2shAddress, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindP2shBindAddress find bind address through p2sh address
+func (s *MgoStore) FindP2shBindAddress(p2shAddress string) (string, error) {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	var result MgoP2shAddress
+	err := collP2shAddress.FindOne(ctx, bson.M{"p2shaddress": p2shAddress}).Decode(&result)
+	if err != nil {
+		return "", mgoError(err)
+	}
+	return result.Key, nil
+}
+
+// FindP2shAddresses find p2sh address
+func (s *MgoStore) FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error) {
+	result := make([]*MgoP2shAddress, 0, limit)
+	opts := options.Find().SetSkip(int64(offset)).SetLimit(int64(limit))
+	err := findAll(collP2shAddress, bson.M{}, &result, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ------------------ destination tag ------------------------
+
+// AddDestinationTag add destination tag
+func (s *MgoStore) AddDestinationTag(mt *MgoDestinationTag) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collDestinationTag, mt)
+}
+
+// FindDestinationTag find destination tag through bind address
+func (s *MgoStore) FindDestinationTag(key string) (*MgoDestinationTag, error) {
+	var result MgoDestinationTag
+	err := findByID(collDestinationTag, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindDestinationTagBindAddress find bind address through destination tag
+func (s *MgoStore) FindDestinationTagBindAddress(tag uint32) (string, error) {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	var result MgoDestinationTag
+	err := collDestinationTag.FindOne(ctx, bson.M{"destinationtag": tag}).Decode(&result)
+	if err != nil {
+		return "", mgoError(err)
+	}
+	return result.Key, nil
+}
+
+// ------------------ latest scan info ------------------------
+
+// UpdateLatestScanInfo update latest scan info
+func (s *MgoStore) UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	updates := bson.M{
+		"blockheight": blockHeight,
+		"timestamp":   timestamp,
+	}
+	opts := options.Update().SetUpsert(true)
+	_, err := collLatestScanInfo.UpdateByID(ctx, getLatestScanInfoKey(isSrc), bson.M{"$set": updates}, opts)
+	return mgoError(err)
+}
+
+// FindLatestScanInfo find latest scan info
+func (s *MgoStore) FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
+	var result MgoLatestScanInfo
+	err := findByID(collLatestScanInfo, getLatestScanInfoKey(isSrc), &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ------------------------ register address ------------------------------
+
+// AddRegisteredAddress add register address
+func (s *MgoStore) AddRegisteredAddress(ma *MgoRegisteredAddress) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collRegisteredAddress, ma)
+}
+
+// FindRegisteredAddress find register address
+func (s *MgoStore) FindRegisteredAddress(key string) (*MgoRegisteredAddress, error) {
+	var result MgoRegisteredAddress
+	err := findByID(collRegisteredAddress, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ---------------------- latest swap nonces -----------------------------
+
+// UpdateLatestSwapNonce update latest swap nonce
+func (s *MgoStore) UpdateLatestSwapNonce(item *MgoLatestSwapNonce) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return upsertByID(ctx, collLatestSwapNonces, item.Key, item)
+}
+
+// FindLatestSwapNonce find latest swap nonce
+func (s *MgoStore) FindLatestSwapNonce(key string) (*MgoLatestSwapNonce, error) {
+	var result MgoLatestSwapNonce
+	err := findByID(collLatestSwapNonces, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindAllLatestSwapNonces find all latest swap nonces
+func (s *MgoStore) FindAllLatestSwapNonces() ([]*MgoLatestSwapNonce, error) {
+	result := make([]*MgoLatestSwapNonce, 0, 20)
+	err := findAll(collLatestSwapNonces, bson.M{}, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ---------------------- swap hisitory -----------------------------
+
+// AddSwapHistory add swap history
+func (s *MgoStore) AddSwapHistory(item *MgoSwapHistory) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collSwapHistory, item)
+}
+
+// GetSwapHistory get swap history
+func (s *MgoStore) GetSwapHistory(isSwapin bool, txid, bind string) ([]*MgoSwapHistory, error) {
+	qtxid := bson.M{"txid": txid}
+	qbind := bson.M{"bind": bind}
+	qisswapin := bson.M{"isswapin": isSwapin}
+	queries := []bson.M{qtxid, qbind, qisswapin}
+	result := make([]*MgoSwapHistory, 0, 20)
+	err := findAll(collSwapHistory, bson.M{"$and": queries}, &result)
+	return result, err
+}
+
+// --------------- swap status history --------------------------------
+
+// AddSwapStatusHistory add swap status history
+func (s *MgoStore) AddSwapStatusHistory(item *MgoSwapStatusHistory) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collSwapStatusHistory, item)
+}
+
+// FindSwapStatusHistory find swap status history in time order
+func (s *MgoStore) FindSwapStatusHistory(txid, pairID, bind string) ([]*MgoSwapStatusHistory, error) {
+	qtxid := bson.M{"txid": txid}
+	qpair := bson.M{"pairid": pairID}
+	queries := []bson.M{qtxid, qpair}
+	if bind != "" {
+		queries = append(queries, bson.M{"bind": bind})
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	result := make([]*MgoSwapStatusHistory, 0, 20)
+	err := findAll(collSwapStatusHistory, bson.M{"$and": queries}, &result, opts)
+	return result, err
+}
+
+// --------------- blacklist --------------------------------
+
+// AddToBlacklist add to blacklist
+func (s *MgoStore) AddToBlacklist(mb *MgoBlackAccount) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collBlacklist, mb)
+}
+
+// RemoveFromBlacklist remove from blacklist
+func (s *MgoStore) RemoveFromBlacklist(key string) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	res, err := collBlacklist.DeleteOne(ctx, bson.M{"_id": key})
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// FindBlackAccount find black account
+func (s *MgoStore) FindBlackAccount(key string) (*MgoBlackAccount, error) {
+	var result MgoBlackAccount
+	err := findByID(collBlacklist, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// --------------- leader lease --------------------------------
+
+// AcquireLeaderLease renew the lease if holder holds it,
+// otherwise take over the lease if it is expired (or not exist)
+func (s *MgoStore) AcquireLeaderLease(name, holder string, timestamp, expireAt int64) (*MgoLeaderLease, error) {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	var result MgoLeaderLease
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := collLeaderLease.FindOneAndUpdate(ctx,
+		bson.M{"_id": name, "holder": holder},
+		bson.M{"$set": bson.M{"expireat": expireAt, "timestamp": timestamp}},
+		opts).Decode(&result)
+	if err == nil {
+		return &result, nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, mgoError(err)
+	}
+	// upsert conflicts with the existing unexpired lease of others
+	opts.SetUpsert(true)
+	err = collLeaderLease.FindOneAndUpdate(ctx,
+		bson.M{"_id": name, "expireat": bson.M{"$lt": timestamp}},
+		bson.M{
+			"$set": bson.M{"holder": holder, "expireat": expireAt, "timestamp": timestamp},
+			"$inc": bson.M{"term": 1},
+		},
+		opts).Decode(&result)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return nil, ErrLeaseIsHeld
+		}
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// ReleaseLeaderLease release lease held by holder
+func (s *MgoStore) ReleaseLeaderLease(name, holder string) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	_, err := collLeaderLease.UpdateOne(ctx,
+		bson.M{"_id": name, "holder": holder},
+		bson.M{"$set": bson.M{"expireat": 0}})
+	return mgoError(err)
+}
+
+// --------------- swap task queue --------------------------------
+
+// AddSwapTask add swap task
+func (s *MgoStore) AddSwapTask(task *MgoSwapTask) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collSwapTasks, task)
+}
+
+// LeaseSwapTask lease the oldest visible pending task of queue,
+// the task is invisible to others until visibleAt
+func (s *MgoStore) LeaseSwapTask(queue string, timestamp, visibleAt int64, leaseToken string) (*MgoSwapTask, error) {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	var result MgoSwapTask
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "createtime", Value: 1}, {Key: "_id", Value: 1}}).
+		SetReturnDocument(options.After)
+	err := collSwapTasks.FindOneAndUpdate(ctx,
+		bson.M{"queue": queue, "status": SwapTaskPending, "visibleat": bson.M{"$lte": timestamp}},
+		bson.M{
+			"$set": bson.M{"visibleat": visibleAt, "timestamp": timestamp, "leasetoken": leaseToken},
+			"$inc": bson.M{"attempts": 1},
+		},
+		opts).Decode(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// getSwapTaskQuery query swap task by key, and by lease token if not empty
+func getSwapTaskQuery(key, leaseToken string) bson.M {
+	query := bson.M{"_id": key}
+	if leaseToken != "" {
+		query["leasetoken"] = leaseToken
+	}
+	return query
+}
+
+// UpdateSwapTask update swap task if it is still leased with leaseToken
+// (empty lease token means unconditional)
+func (s *MgoStore) UpdateSwapTask(task *MgoSwapTask, leaseToken string) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	res, err := collSwapTasks.ReplaceOne(ctx, getSwapTaskQuery(task.Key, leaseToken), task)
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.MatchedCount == 0 {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// RemoveSwapTask remove swap task if it is still leased with leaseToken
+// (empty lease token means unconditional)
+func (s *MgoStore) RemoveSwapTask(key, leaseToken string) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	res, err := collSwapTasks.DeleteOne(ctx, getSwapTaskQuery(key, leaseToken))
+	if err != nil {
+		return mgoError(err)
+	}
+	if res.DeletedCount == 0 {
+		return ErrItemNotFound
+	}
+	return nil
+}
+
+// FindSwapTask find swap task
+func (s *MgoStore) FindSwapTask(key string) (*MgoSwapTask, error) {
+	var result MgoSwapTask
+	err := findByID(collSwapTasks, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindSwapTasks find swap tasks of queue and status (empty means all)
+func (s *MgoStore) FindSwapTasks(queue, status string) ([]*MgoSwapTask, error) {
+	query := bson.M{}
+	if queue != "" {
+		query["queue"] = queue
+	}
+	if status != "" {
+		query["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createtime", Value: 1}, {Key: "_id", Value: 1}})
+	result := make([]*MgoSwapTask, 0, 20)
+	err := findAll(collSwapTasks, query, &result, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// --------------- sign intents --------------------------------
+
+// UpdateSignIntent add or update sign intent
+func (s *MgoStore) UpdateSignIntent(intent *MgoSignIntent) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return upsertByID(ctx, collSignIntents, intent.Key, intent)
+}
+
+// FindSignIntent find sign intent
+func (s *MgoStore) FindSignIntent(key string) (*MgoSignIntent, error) {
+	var result MgoSignIntent
+	err := findByID(collSignIntents, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindSignIntentsWithStatus find sign intents with status
+func (s *MgoStore) FindSignIntentsWithStatus(status string) ([]*MgoSignIntent, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "createtime", Value: 1}})
+	result := make([]*MgoSignIntent, 0, 20)
+	err := findAll(collSignIntents, bson.M{"status": status}, &result, opts)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// --------------- swap velocities --------------------------------
+
+// AddSwapVelocity add swap velocity
+func (s *MgoStore) AddSwapVelocity(item *MgoSwapVelocity) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return insertOne(ctx, collSwapVelocities, item)
+}
+
+// FindSwapVelocity find swap velocity
+func (s *MgoStore) FindSwapVelocity(key string) (*MgoSwapVelocity, error) {
+	var result MgoSwapVelocity
+	err := findByID(collSwapVelocities, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindSwapVelocities find swap velocities of pair since timestamp
+func (s *MgoStore) FindSwapVelocities(isSwapin bool, pairID string, since int64) ([]*MgoSwapVelocity, error) {
+	query := bson.M{"pairid": pairID, "isswapin": isSwapin, "timestamp": bson.M{"$gte": since}}
+	result := make([]*MgoSwapVelocity, 0, 20)
+	err := findAll(collSwapVelocities, query, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RemoveSwapVelocitiesBefore remove swap velocities before timestamp
+func (s *MgoStore) RemoveSwapVelocitiesBefore(timestamp int64) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	_, err := collSwapVelocities.DeleteMany(ctx, bson.M{"timestamp": bson.M{"$lt": timestamp}})
+	return mgoError(err)
+}
+
+// --------------- tx block hashes --------------------------------
+
+// UpsertTxBlockHash upsert tx block hash
+func (s *MgoStore) UpsertTxBlockHash(item *MgoTxBlockHash) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	return upsertByID(ctx, collTxBlockHashes, item.Key, item)
+}
+
+// FindTxBlockHash find tx block hash
+func (s *MgoStore) FindTxBlockHash(key string) (*MgoTxBlockHash, error) {
+	var result MgoTxBlockHash
+	err := findByID(collTxBlockHashes, key, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FindTxBlockHashesToWatch find not reorged tx block hashes since timestamp
+func (s *MgoStore) FindTxBlockHashesToWatch(since int64) ([]*MgoTxBlockHash, error) {
+	query := bson.M{"reorged": false, "timestamp": bson.M{"$gte": since}}
+	result := make([]*MgoTxBlockHash, 0, 20)
+	err := findAll(collTxBlockHashes, query, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// RemoveTxBlockHashesBefore remove not reorged tx block hashes before timestamp
+func (s *MgoStore) RemoveTxBlockHashesBefore(timestamp int64) error {
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	_, err := collTxBlockHashes.DeleteMany(ctx, bson.M{"reorged": false, "timestamp": bson.M{"$lt": timestamp}})
+	return mgoError(err)
+}
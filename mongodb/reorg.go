@@ -0,0 +1,104 @@
+package mongodb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// GetTxBlockHashKey get key of block hash of swap source tx or swap tx
+func GetTxBlockHashKey(isSwapin bool, txid, pairID, bind string, isSwapTx bool) string {
+	key := GetSwapTaskKey(isSwapin, txid, pairID, bind)
+	if isSwapTx {
+		return key + ":swaptx"
+	}
+	return key + ":srctx"
+}
+
+// AddTxBlockHash record block hash of swap source tx or swap tx to watch reorg
+func AddTxBlockHash(isSwapin bool, txid, pairID, bind string, isSwapTx bool, txHash string, height uint64, blockHash string) error {
+	pairID = strings.ToLower(pairID)
+	item := &MgoTxBlockHash{
+		Key:       GetTxBlockHashKey(isSwapin, txid, pairID, bind, isSwapTx),
+		IsSwapin:  isSwapin,
+		TxID:      txid,
+		PairID:    pairID,
+		Bind:      bind,
+		IsSwapTx:  isSwapTx,
+		TxHash:    txHash,
+		Height:    height,
+		BlockHash: blockHash,
+		Timestamp: common.NowMilli(),
+	}
+	err := store.UpsertTxBlockHash(item)
+	if err == nil {
+		log.Debug("mongodb add tx block hash", "key", item.Key, "txhash", txHash, "height", height, "blockhash", blockHash)
+	} else {
+		log.Warn("mongodb add tx block hash failed", "key", item.Key, "txhash", txHash, "height", height, "blockhash", blockHash, "err", err)
+	}
+	return err
+}
+
+// IsTxBlockHashRecorded is block hash of swap source tx or swap tx recorded,
+// record of reorged tx is not counted as it can be replaced after passing the swap.
+func IsTxBlockHashRecorded(isSwapin bool, txid, pairID, bind string, isSwapTx bool) bool {
+	key := GetTxBlockHashKey(isSwapin, txid, strings.ToLower(pairID), bind, isSwapTx)
+	item, err := store.FindTxBlockHash(key)
+	return err == nil && !item.Reorged
+}
+
+// FindTxBlockHashesToWatch find tx block hashes recorded since timestamp (unix milliseconds)
+func FindTxBlockHashesToWatch(since int64) ([]*MgoTxBlockHash, error) {
+	return store.FindTxBlockHashesToWatch(since)
+}
+
+// PruneTxBlockHashes remove tx block hashes recorded before timestamp (unix milliseconds),
+// block hashes of reorged txs are kept for investigation
+func PruneTxBlockHashes(timestamp int64) error {
+	return store.RemoveTxBlockHashesBefore(timestamp)
+}
+
+// MarkSwapReorged move swap and swap result to status TxReorged,
+// and stop watching the reorged tx.
+func MarkSwapReorged(item *MgoTxBlockHash, memo string) error {
+	timestamp := time.Now().Unix()
+	_ = UpdateSwapResultStatus(item.IsSwapin, item.TxID, item.PairID, item.Bind, TxReorged, timestamp, memo, "reorg")
+	err := UpdateSwapStatus(item.IsSwapin, item.TxID, item.PairID, item.Bind, TxReorged, timestamp, memo, "reorg")
+	if err != nil {
+		return err
+	}
+	item.Reorged = true
+	return store.UpsertTxBlockHash(item)
+}
+
+// PassSwapReorged resume reorged swap after manual review.
+// swap not swapped yet is reverified, otherwise its swap tx is rechecked.
+func PassSwapReorged(isSwapin bool, txid, pairID, bind, memo, actor string) error {
+	swap, err := FindSwap(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	if swap.Status != TxReorged {
+		return fmt.Errorf("swap status is %v, not reorged status %v", swap.Status.String(), TxReorged.String())
+	}
+	res, err := FindSwapResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	timestamp := time.Now().Unix()
+	if res.SwapTx == "" && len(res.OldSwapTxs) == 0 {
+		err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxEmpty, timestamp, memo, actor)
+		if err != nil {
+			return err
+		}
+		return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, timestamp, memo, actor)
+	}
+	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxNotStable, timestamp, memo, actor)
+	if err != nil {
+		return err
+	}
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxProcessed, timestamp, memo, actor)
+}
@@ -0,0 +1,103 @@
+package mongodb
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+var signIntentLock sync.Mutex
+
+func isSignIntentFinished(status string) bool {
+	return status == SignIntentFinalized || status == SignIntentAborted
+}
+
+// OpenSignIntent write sign intent before signing swap tx.
+// returns ErrSignIntentIsOpen if the swap has unfinished sign intent.
+func OpenSignIntent(intent *MgoSignIntent) error {
+	signIntentLock.Lock()
+	defer signIntentLock.Unlock()
+
+	intent.PairID = strings.ToLower(intent.PairID)
+	intent.Key = GetSwapTaskKey(intent.IsSwapin, intent.TxID, intent.PairID, intent.Bind)
+	if old, _ := store.FindSignIntent(intent.Key); old != nil && !isSignIntentFinished(old.Status) {
+		log.Warn("mongodb open sign intent failed", "key", intent.Key, "oldStatus", old.Status, "err", ErrSignIntentIsOpen)
+		return ErrSignIntentIsOpen
+	}
+	intent.Status = SignIntentOpen
+	intent.CreateTime = common.NowMilli()
+	intent.Timestamp = intent.CreateTime
+	err := store.UpdateSignIntent(intent)
+	if err == nil {
+		log.Info("mongodb open sign intent", "key", intent.Key, "nonce", intent.Nonce, "utxos", intent.Utxos)
+	} else {
+		log.Warn("mongodb open sign intent failed", "key", intent.Key, "err", err)
+	}
+	return err
+}
+
+// AddSignIntentRequest record dcrm sign request of open sign intent
+func AddSignIntentRequest(key, keyID string, msgHashes []string) error {
+	signIntentLock.Lock()
+	defer signIntentLock.Unlock()
+
+	intent, err := store.FindSignIntent(key)
+	if err != nil {
+		return err
+	}
+	if intent.Status != SignIntentOpen {
+		return nil
+	}
+	intent.KeyIDs = append(intent.KeyIDs, keyID)
+	intent.MsgHashes = msgHashes
+	intent.Timestamp = common.NowMilli()
+	err = store.UpdateSignIntent(intent)
+	if err == nil {
+		log.Info("mongodb add sign intent request", "key", key, "keyID", keyID, "msgHashes", msgHashes)
+	}
+	return err
+}
+
+// UpdateSignIntentStatus update sign intent status (and signed tx hash if not empty)
+func UpdateSignIntentStatus(key, status, signedTxHash, memo string) error {
+	signIntentLock.Lock()
+	defer signIntentLock.Unlock()
+
+	intent, err := store.FindSignIntent(key)
+	if err != nil {
+		return err
+	}
+	oldStatus := intent.Status
+	intent.Status = status
+	if signedTxHash != "" {
+		intent.SignedTxHash = signedTxHash
+	}
+	intent.Memo = memo
+	intent.Timestamp = common.NowMilli()
+	err = store.UpdateSignIntent(intent)
+	if err == nil {
+		log.Info("mongodb update sign intent status", "key", key, "oldStatus", oldStatus, "status", status, "signedTxHash", intent.SignedTxHash, "memo", memo)
+	} else {
+		log.Warn("mongodb update sign intent status failed", "key", key, "status", status, "err", err)
+	}
+	return err
+}
+
+// FindSignIntent find sign intent
+func FindSignIntent(key string) (*MgoSignIntent, error) {
+	return store.FindSignIntent(key)
+}
+
+// FindUnfinishedSignIntents find sign intents which are not finalized or aborted
+func FindUnfinishedSignIntents() (result []*MgoSignIntent, err error) {
+	for _, status := range []string{SignIntentOpen, SignIntentSigned, SignIntentCommitted} {
+		intents, err := store.FindSignIntentsWithStatus(status)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, intents...)
+	}
+	return result, nil
+}
@@ -0,0 +1,214 @@
+package mongodb
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// statistics granularities
+const (
+	StatisticsByHour = "hour"
+	StatisticsByDay  = "day"
+
+	maxCountOfStatisticsBuckets = 1000
+	countOfRebuildResultsOnce   = 1000
+)
+
+var statisticsIntervals = map[string]int64{
+	StatisticsByHour: 3600,
+	StatisticsByDay:  86400,
+}
+
+func newSwapStatisticsValues() SwapStatisticsValues {
+	return SwapStatisticsValues{
+		TotalSwapinValue:  "0",
+		TotalSwapinFee:    "0",
+		TotalSwapoutValue: "0",
+		TotalSwapoutFee:   "0",
+	}
+}
+
+// add stable swap of value (the received) and swapValue (the sent),
+// fee is the difference of them
+func (v *SwapStatisticsValues) add(isSwapin bool, value, swapValue string) {
+	v.update(isSwapin, value, swapValue, false)
+}
+
+// sub revert the added stable swap (eg. it is reorged)
+func (v *SwapStatisticsValues) sub(isSwapin bool, value, swapValue string) {
+	v.update(isSwapin, value, swapValue, true)
+}
+
+func (v *SwapStatisticsValues) update(isSwapin bool, value, swapValue string, isSub bool) {
+	addVal, _ := new(big.Int).SetString(value, 0)
+	addSwapVal, _ := new(big.Int).SetString(swapValue, 0)
+	if addVal == nil || addSwapVal == nil {
+		log.Warn("mongodb update swap statistics with wrong value", "value", value, "swapValue", swapValue, "isSub", isSub)
+		return
+	}
+	addSwapFee := new(big.Int).Sub(addVal, addSwapVal)
+	addCount := 1
+	if isSub {
+		addSwapVal.Neg(addSwapVal)
+		addSwapFee.Neg(addSwapFee)
+		addCount = -1
+	}
+
+	curVal := big.NewInt(0)
+	curFee := big.NewInt(0)
+
+	if isSwapin {
+		curVal.SetString(v.TotalSwapinValue, 0)
+		curFee.SetString(v.TotalSwapinFee, 0)
+		curVal.Add(curVal, addSwapVal)
+		curFee.Add(curFee, addSwapFee)
+		v.StableSwapinCount += addCount
+		v.TotalSwapinValue = curVal.String()
+		v.TotalSwapinFee = curFee.String()
+	} else {
+		curVal.SetString(v.TotalSwapoutValue, 0)
+		curFee.SetString(v.TotalSwapoutFee, 0)
+		curVal.Add(curVal, addSwapVal)
+		curFee.Add(curFee, addSwapFee)
+		v.StableSwapoutCount += addCount
+		v.TotalSwapoutValue = curVal.String()
+		v.TotalSwapoutFee = curFee.String()
+	}
+}
+
+func getStatisticsBucketKey(pairID, granularity string, startTime int64) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", pairID, granularity, startTime))
+}
+
+func newSwapStatisticsBucket(pairID, granularity string, timestamp int64) *MgoSwapStatisticsBucket {
+	interval := statisticsIntervals[granularity]
+	startTime := timestamp - timestamp%interval
+	return &MgoSwapStatisticsBucket{
+		Key:                  getStatisticsBucketKey(pairID, granularity, startTime),
+		PairID:               pairID,
+		Granularity:          granularity,
+		StartTime:            startTime,
+		SwapStatisticsValues: newSwapStatisticsValues(),
+	}
+}
+
+// updateSwapStatisticsBuckets caller should hold the statistics lock
+func updateSwapStatisticsBuckets(pairID, value, swapValue string, isSwapin bool, timestamp int64, isSub bool) {
+	for granularity := range statisticsIntervals {
+		bucket := newSwapStatisticsBucket(pairID, granularity, timestamp)
+		if curr, _ := store.FindSwapStatisticsBucket(bucket.Key); curr != nil {
+			bucket = curr
+		}
+		bucket.update(isSwapin, value, swapValue, isSub)
+		err := store.UpdateSwapStatisticsBucket(bucket)
+		if err != nil {
+			log.Warn("mongodb update swap statistics bucket failed", "bucket", bucket, "err", err)
+		}
+	}
+}
+
+// GetSwapStatisticsRange get swap statistics buckets whose start time is in range [from, to].
+// buckets without stable swaps are omitted.
+func GetSwapStatisticsRange(pairID string, from, to int64, granularity string) ([]*MgoSwapStatisticsBucket, error) {
+	interval, exist := statisticsIntervals[granularity]
+	if !exist {
+		return nil, fmt.Errorf("unknown statistics granularity '%v'", granularity)
+	}
+	if to == 0 {
+		to = time.Now().Unix()
+	}
+	if from > to {
+		return nil, fmt.Errorf("wrong statistics time range [%v, %v]", from, to)
+	}
+	if (to-from)/interval >= maxCountOfStatisticsBuckets {
+		return nil, fmt.Errorf("statistics time range exceeds %v %vs", maxCountOfStatisticsBuckets, granularity)
+	}
+	from -= from % interval
+	return store.FindSwapStatisticsBuckets(strings.ToLower(pairID), granularity, from, to)
+}
+
+// RebuildSwapStatistics rebuild all time and bucketed statistics of pair
+// from the stable swap results (including archived), to fix the drift of incremental counters.
+func RebuildSwapStatistics(pairID string) (*MgoSwapStatistics, error) {
+	statisticsLock.Lock()
+	defer statisticsLock.Unlock()
+
+	pairID = strings.ToLower(pairID)
+	stat := &MgoSwapStatistics{
+		Key:                  pairID,
+		PairID:               pairID,
+		SwapStatisticsValues: newSwapStatisticsValues(),
+	}
+	buckets := make(map[string]*MgoSwapStatisticsBucket)
+
+	finders := []func(bool, *SwapHistoryFilter) ([]*MgoSwapResult, error){
+		store.FindSwapResults,
+		store.FindArchivedSwapResults,
+	}
+	for _, isSwapin := range []bool{true, false} {
+		for _, find := range finders {
+			err := rebuildSwapStatisticsFrom(find, isSwapin, pairID, stat, buckets)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err := store.RemoveSwapStatisticsBuckets(pairID)
+	if err != nil {
+		return nil, err
+	}
+	for _, bucket := range buckets {
+		err = store.UpdateSwapStatisticsBucket(bucket)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = store.UpdateSwapStatistics(stat)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("mongodb rebuild swap statistics success", "pairID", pairID, "buckets", len(buckets), "statistics", stat)
+	return stat, nil
+}
+
+func rebuildSwapStatisticsFrom(
+	find func(bool, *SwapHistoryFilter) ([]*MgoSwapResult, error),
+	isSwapin bool, pairID string,
+	stat *MgoSwapStatistics, buckets map[string]*MgoSwapStatisticsBucket,
+) error {
+	filter := &SwapHistoryFilter{
+		PairID: pairID,
+		Status: fmt.Sprint(uint16(MatchTxStable)),
+		Limit:  countOfRebuildResultsOnce,
+	}
+	for {
+		if err := filter.normalize(); err != nil {
+			return err
+		}
+		results, err := find(isSwapin, filter)
+		if err != nil {
+			return err
+		}
+		for _, res := range results {
+			stat.add(isSwapin, res.Value, res.SwapValue)
+			for granularity := range statisticsIntervals {
+				bucket := newSwapStatisticsBucket(pairID, granularity, res.Timestamp)
+				if curr, exist := buckets[bucket.Key]; exist {
+					bucket = curr
+				} else {
+					buckets[bucket.Key] = bucket
+				}
+				bucket.add(isSwapin, res.Value, res.SwapValue)
+			}
+		}
+		if len(results) < countOfRebuildResultsOnce {
+			return nil
+		}
+		filter.Cursor = GetHistoryCursor(results[len(results)-1])
+	}
+}
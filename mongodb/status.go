@@ -23,6 +23,11 @@ import (
 //                |- TxSenderNotRegistered ---> TxNotStable
 //                |- TxNotSwapped -> |- TxSwapFailed -> manual
 //                                   |- TxProcessed (->MatchTxNotStable)
+//                                   |- TxExceedsRateLimit ---> TxNotSwapped
+//
+// TxNotSwapped, TxProcessed -> TxReorged ---> |- TxNotStable (not swapped)
+//                                             |- TxProcessed (swapped)
+//                                             |- ManualMakeFail
 // -----------------------------------------------
 // 2. swap result status change graph
 //
@@ -31,6 +36,8 @@ import (
 // TxSenderNotRegistered ---> MatchTxEmpty
 // MatchTxEmpty          -> | MatchTxNotStable -> |- MatchTxStable
 //                                                |- MatchTxFailed -> manual
+// MatchTxEmpty, MatchTxNotStable, MatchTxStable -> TxReorged ---> |- MatchTxEmpty (not swapped)
+//                                                                 |- MatchTxNotStable (swapped)
 // -----------------------------------------------
 
 // SwapStatus swap status
@@ -56,6 +63,8 @@ const (
 	SwapInBlacklist                         // 15
 	ManualMakeFail                          // 16
 	BindAddrIsContract                      // 17
+	TxExceedsRateLimit                      // 18
+	TxReorged                               // 19
 
 	KeepStatus = 255
 	Reswapping = 256
@@ -64,7 +73,7 @@ const (
 // CanManualMakeFail can manual make fail
 func (status SwapStatus) CanManualMakeFail() bool {
 	switch status {
-	case TxNotStable, TxNotSwapped:
+	case TxNotStable, TxNotSwapped, TxExceedsRateLimit, TxReorged:
 		return true
 	default:
 		return false
@@ -147,6 +156,10 @@ func (status SwapStatus) String() string {
 		return "ManualMakeFail"
 	case BindAddrIsContract:
 		return "BindAddrIsContract"
+	case TxExceedsRateLimit:
+		return "TxExceedsRateLimit"
+	case TxReorged:
+		return "TxReorged"
 	case Reswapping:
 		return "Reswapping"
 	default:
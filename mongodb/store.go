@@ -0,0 +1,140 @@
+package mongodb
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// SwapStore storage backend of swaps, swap results and related records.
+// Package level functions keep the business rules (status checks, locks,
+// statistics) and delegate the persistence to the active store.
+type SwapStore interface {
+	Name() string
+
+	// swapin and swapout
+	AddSwap(isSwapin bool, ms *MgoSwap) error
+	UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error
+	FindSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error)
+	FindSwapsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwap, error)
+	FindSwapsWithPairIDAndStatus(isSwapin bool, pairID string, status SwapStatus, septime int64) ([]*MgoSwap, error)
+	GetCountOfSwapsWithStatus(isSwapin bool, pairID string, status SwapStatus) (int, error)
+
+	// swapin and swapout results
+	AddSwapResult(isSwapin bool, mr *MgoSwapResult) error
+	UpdateSwapResult(isSwapin bool, txid, pairID, bind string, items *SwapResultUpdateItems) error
+	UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error
+	FindSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error)
+	FindSwapResultsWithStatus(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error)
+	FindSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error)
+	FindSwapResultsToReplace(isSwapin bool, status SwapStatus, septime int64) ([]*MgoSwapResult, error)
+	GetCountOfSwapResults(isSwapin bool, pairID string) (int, error)
+	GetCountOfSwapResultsWithStatus(isSwapin bool, pairID string, status SwapStatus) (int, error)
+
+	// swap result, swap status, swap history and swap nonce commit atomically
+	CommitSwapTx(commit *SwapTxCommit) error
+	CommitBatchSwapTx(commits []*SwapTxCommit) error
+
+	// archive of stable swaps
+	ArchiveSwap(isSwapin bool, swap *MgoSwap, res *MgoSwapResult) error
+	FindArchivedSwap(isSwapin bool, txid, pairID, bind string) (*MgoSwap, error)
+	FindArchivedSwapResult(isSwapin bool, txid, pairID, bind string) (*MgoSwapResult, error)
+	FindArchivedSwapResults(isSwapin bool, filter *SwapHistoryFilter) ([]*MgoSwapResult, error)
+
+	// statistics
+	FindSwapStatistics(pairID string) (*MgoSwapStatistics, error)
+	UpdateSwapStatistics(stat *MgoSwapStatistics) error
+	FindSwapStatisticsBucket(key string) (*MgoSwapStatisticsBucket, error)
+	UpdateSwapStatisticsBucket(bucket *MgoSwapStatisticsBucket) error
+	FindSwapStatisticsBuckets(pairID, granularity string, from, to int64) ([]*MgoSwapStatisticsBucket, error)
+	RemoveSwapStatisticsBuckets(pairID string) error
+
+	// p2sh address
+	AddP2shAddress(ma *MgoP2shAddress) error
+	FindP2shAddress(key string) (*MgoP2shAddress, error)
+	FindP2shBindAddress(p2shAddress string) (string, error)
+	FindP2shAddresses(offset, limit int) ([]*MgoP2shAddress, error)
+
+	// destination tag
+	AddDestinationTag(mt *MgoDestinationTag) error
+	FindDestinationTag(key string) (*MgoDestinationTag, error)
+	FindDestinationTagBindAddress(tag uint32) (string, error)
+
+	// latest scan info
+	UpdateLatestScanInfo(isSrc bool, blockHeight uint64, timestamp int64) error
+	FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error)
+
+	// registered address
+	AddRegisteredAddress(ma *MgoRegisteredAddress) error
+	FindRegisteredAddress(key string) (*MgoRegisteredAddress, error)
+
+	// latest swap nonces
+	UpdateLatestSwapNonce(item *MgoLatestSwapNonce) error
+	FindLatestSwapNonce(key string) (*MgoLatestSwapNonce, error)
+	FindAllLatestSwapNonces() ([]*MgoLatestSwapNonce, error)
+
+	// swap history
+	AddSwapHistory(item *MgoSwapHistory) error
+	GetSwapHistory(isSwapin bool, txid, bind string) ([]*MgoSwapHistory, error)
+
+	// swap status history
+	AddSwapStatusHistory(item *MgoSwapStatusHistory) error
+	FindSwapStatusHistory(txid, pairID, bind string) ([]*MgoSwapStatusHistory, error)
+
+	// blacklist
+	AddToBlacklist(mb *MgoBlackAccount) error
+	RemoveFromBlacklist(key string) error
+	FindBlackAccount(key string) (*MgoBlackAccount, error)
+
+	// leader lease
+	AcquireLeaderLease(name, holder string, timestamp, expireAt int64) (*MgoLeaderLease, error)
+	ReleaseLeaderLease(name, holder string) error
+
+	// swap task queue
+	AddSwapTask(task *MgoSwapTask) error
+	LeaseSwapTask(queue string, timestamp, visibleAt int64, leaseToken string) (*MgoSwapTask, error)
+	UpdateSwapTask(task *MgoSwapTask, leaseToken string) error
+	RemoveSwapTask(key, leaseToken string) error
+	FindSwapTask(key string) (*MgoSwapTask, error)
+	FindSwapTasks(queue, status string) ([]*MgoSwapTask, error)
+
+	// sign intents
+	UpdateSignIntent(intent *MgoSignIntent) error
+	FindSignIntent(key string) (*MgoSignIntent, error)
+	FindSignIntentsWithStatus(status string) ([]*MgoSignIntent, error)
+
+	// swap velocities
+	AddSwapVelocity(item *MgoSwapVelocity) error
+	FindSwapVelocity(key string) (*MgoSwapVelocity, error)
+	FindSwapVelocities(isSwapin bool, pairID string, since int64) ([]*MgoSwapVelocity, error)
+	RemoveSwapVelocitiesBefore(timestamp int64) error
+
+	// tx block hashes
+	UpsertTxBlockHash(item *MgoTxBlockHash) error
+	FindTxBlockHash(key string) (*MgoTxBlockHash, error)
+	FindTxBlockHashesToWatch(since int64) ([]*MgoTxBlockHash, error)
+	RemoveTxBlockHashesBefore(timestamp int64) error
+}
+
+var store SwapStore
+
+// SetStore set the active swap store
+func SetStore(s SwapStore) {
+	if store != nil {
+		log.Warn("[store] replace swap store", "old", store.Name(), "new", s.Name())
+	}
+	store = s
+}
+
+// GetStore get the active swap store
+func GetStore() SwapStore {
+	return store
+}
+
+// HasSession has swap store initialized
+func HasSession() bool {
+	return store != nil
+}
+
+// shouldClearSwapMemo swap memo is cleared when retry or reverify
+func shouldClearSwapMemo(status SwapStatus) bool {
+	return status == TxNotSwapped || status == TxNotStable
+}
@@ -1,40 +1,57 @@
 package mongodb
 
 import (
-	"gopkg.in/mgo.v2"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 var (
-	collSwapin            *mgo.Collection
-	collSwapout           *mgo.Collection
-	collSwapinResult      *mgo.Collection
-	collSwapoutResult     *mgo.Collection
-	collP2shAddress       *mgo.Collection
-	collSwapStatistics    *mgo.Collection
-	collLatestScanInfo    *mgo.Collection
-	collRegisteredAddress *mgo.Collection
-	collBlacklist         *mgo.Collection
-	collLatestSwapNonces  *mgo.Collection
-	collSwapHistory       *mgo.Collection
-)
+	collSwapin            *mongo.Collection
+	collSwapout           *mongo.Collection
+	collSwapinResult      *mongo.Collection
+	collSwapoutResult     *mongo.Collection
+	collP2shAddress       *mongo.Collection
+	collDestinationTag    *mongo.Collection
+	collSwapStatistics    *mongo.Collection
+	collStatisticsBuckets *mongo.Collection
+	collLatestScanInfo    *mongo.Collection
+	collRegisteredAddress *mongo.Collection
+	collBlacklist         *mongo.Collection
+	collLatestSwapNonces  *mongo.Collection
+	collSwapHistory       *mongo.Collection
+	collSwapStatusHistory *mongo.Collection
 
-func isSwapin(collection *mgo.Collection) bool {
-	return collection == collSwapin || collection == collSwapinResult
-}
+	collSwapinArchive        *mongo.Collection
+	collSwapoutArchive       *mongo.Collection
+	collSwapinResultArchive  *mongo.Collection
+	collSwapoutResultArchive *mongo.Collection
+
+	collLeaderLease *mongo.Collection
+	collSwapTasks   *mongo.Collection
+	collSignIntents *mongo.Collection
+
+	collSwapVelocities *mongo.Collection
+	collTxBlockHashes  *mongo.Collection
+)
 
-// do this when reconnect to the database
-func deinintCollections() {
-	collSwapin = database.C(tbSwapins)
-	collSwapout = database.C(tbSwapouts)
-	collSwapinResult = database.C(tbSwapinResults)
-	collSwapoutResult = database.C(tbSwapoutResults)
-	collP2shAddress = database.C(tbP2shAddresses)
-	collSwapStatistics = database.C(tbSwapStatistics)
-	collLatestScanInfo = database.C(tbLatestScanInfo)
-	collRegisteredAddress = database.C(tbRegisteredAddress)
-	collBlacklist = database.C(tbBlacklist)
-	collLatestSwapNonces = database.C(tbLatestSwapNonces)
-	collSwapHistory = database.C(tbSwapHistory)
+// compound indexes of swap results used by history queries,
+// all are ended with inittime as history is ordered by (inittime, _id)
+var swapResultHistoryIndexes = [][]string{
+	{"inittime"},
+	{"pairid", "inittime"},
+	{"pairid", "from", "inittime"},
+	{"pairid", "to", "inittime"},
+	{"pairid", "bind", "inittime"},
+	{"pairid", "status", "inittime"},
+	{"from", "status", "inittime"},
+	{"swaptx", "inittime"},
+	{"timestamp", "inittime"},
+	{"valuekey", "inittime"},
+	{"pairid", "valuekey", "inittime"},
 }
 
 func initCollections() {
@@ -42,31 +59,132 @@ func initCollections() {
 	initCollection(tbSwapouts, &collSwapout, "inittime", "status")
 	initCollection(tbSwapinResults, &collSwapinResult, "from", "inittime")
 	initCollection(tbSwapoutResults, &collSwapoutResult, "from", "inittime")
+	ensureIndexes(collSwapinResult, swapResultHistoryIndexes)
+	ensureIndexes(collSwapoutResult, swapResultHistoryIndexes)
 	initCollection(tbP2shAddresses, &collP2shAddress, "p2shaddress")
+	initCollection(tbDestinationTags, &collDestinationTag)
+	ensureUniqueIndex(collDestinationTag, "destinationtag")
 	initCollection(tbSwapStatistics, &collSwapStatistics)
+	initCollection(tbStatisticsBuckets, &collStatisticsBuckets, "pairid", "granularity", "starttime")
 	initCollection(tbLatestScanInfo, &collLatestScanInfo)
 	initCollection(tbRegisteredAddress, &collRegisteredAddress)
 	initCollection(tbBlacklist, &collBlacklist)
 	initCollection(tbLatestSwapNonces, &collLatestSwapNonces, "address")
 	initCollection(tbSwapHistory, &collSwapHistory, "txid")
+	initCollection(tbSwapStatusHistory, &collSwapStatusHistory, "txid", "pairid", "bind")
+	initCollection(tbSwapinsArchive, &collSwapinArchive)
+	initCollection(tbSwapoutsArchive, &collSwapoutArchive)
+	initCollection(tbSwapinResultsArchive, &collSwapinResultArchive)
+	initCollection(tbSwapoutResultsArchive, &collSwapoutResultArchive)
+	ensureIndexes(collSwapinResultArchive, swapResultHistoryIndexes)
+	ensureIndexes(collSwapoutResultArchive, swapResultHistoryIndexes)
+	for _, coll := range []*mongo.Collection{collSwapinResult, collSwapoutResult, collSwapinResultArchive, collSwapoutResultArchive} {
+		backfillValueKeys(coll)
+	}
+	initCollection(tbLeaderLease, &collLeaderLease)
+	initCollection(tbSwapTasks, &collSwapTasks, "queue", "status", "visibleat")
+	initCollection(tbSignIntents, &collSignIntents, "status")
+	initCollection(tbSwapVelocities, &collSwapVelocities, "pairid", "isswapin", "timestamp")
+	initCollection(tbTxBlockHashes, &collTxBlockHashes, "reorged", "timestamp")
 
 	initDefaultValue()
 }
 
-func initCollection(table string, collection **mgo.Collection, indexKey ...string) {
-	*collection = database.C(table)
+// initCollection create one compound index of indexKey (like mgo EnsureIndexKey)
+func initCollection(table string, collection **mongo.Collection, indexKey ...string) {
+	*collection = database.Collection(table)
 	if len(indexKey) != 0 && indexKey[0] != "" {
-		_ = (*collection).EnsureIndexKey(indexKey...)
+		keys := bson.D{}
+		for _, key := range indexKey {
+			keys = append(keys, bson.E{Key: key, Value: 1})
+		}
+		ctx, cancel := newQueryContext()
+		defer cancel()
+		_, _ = (*collection).Indexes().CreateOne(ctx, mongo.IndexModel{Keys: keys})
+	}
+}
+
+// ensureIndexes create compound indexes (ignore if exist)
+func ensureIndexes(collection *mongo.Collection, indexes [][]string) {
+	models := make([]mongo.IndexModel, 0, len(indexes))
+	for _, indexKey := range indexes {
+		keys := bson.D{}
+		for _, key := range indexKey {
+			keys = append(keys, bson.E{Key: key, Value: 1})
+		}
+		models = append(models, mongo.IndexModel{Keys: keys})
+	}
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	_, _ = collection.Indexes().CreateMany(ctx, models)
+}
+
+// ensureUniqueIndex create unique index, and replace the same index
+// created before which is not unique (index name is the same)
+func ensureUniqueIndex(collection *mongo.Collection, indexKey ...string) {
+	keys := bson.D{}
+	for _, key := range indexKey {
+		keys = append(keys, bson.E{Key: key, Value: 1})
+	}
+	model := mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(true)}
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, model)
+	if err == nil {
+		return
+	}
+	log.Info("[mongodb] replace index with unique one", "collection", collection.Name(), "keys", indexKey, "err", err)
+	names := make([]string, 0, len(indexKey))
+	for _, key := range indexKey {
+		names = append(names, key+"_1")
+	}
+	_, _ = collection.Indexes().DropOne(ctx, strings.Join(names, "_"))
+	if _, err = collection.Indexes().CreateOne(ctx, model); err != nil {
+		log.Warn("[mongodb] create unique index failed", "collection", collection.Name(), "keys", indexKey, "err", err)
+	}
+}
+
+// backfillValueKeys set value key of swap results added before it exists
+func backfillValueKeys(collection *mongo.Collection) {
+	const batchSize = 1000
+	filter := bson.M{"valuekey": bson.M{"$exists": false}}
+	opts := options.Find().SetLimit(batchSize).SetProjection(bson.M{"value": 1})
+	count := 0
+	for {
+		var items []*MgoSwapResult
+		if err := findAll(collection, filter, &items, opts); err != nil {
+			log.Warn("[mongodb] backfill value keys failed", "collection", collection.Name(), "err", err)
+			return
+		}
+		for _, item := range items {
+			ctx, cancel := newQueryContext()
+			_, err := collection.UpdateOne(ctx, bson.M{"_id": item.Key}, bson.M{"$set": bson.M{"valuekey": GetValueKey(item.Value)}})
+			cancel()
+			if err != nil {
+				log.Warn("[mongodb] backfill value key failed", "collection", collection.Name(), "key", item.Key, "err", err)
+				return
+			}
+		}
+		count += len(items)
+		if len(items) < batchSize {
+			break
+		}
+	}
+	if count > 0 {
+		log.Info("[mongodb] backfill value keys success", "collection", collection.Name(), "count", count)
 	}
 }
 
 func initDefaultValue() {
-	_ = collLatestScanInfo.Insert(
+	ctx, cancel := newQueryContext()
+	defer cancel()
+	opts := options.InsertMany().SetOrdered(false)
+	_, _ = collLatestScanInfo.InsertMany(ctx, []interface{}{
 		&MgoLatestScanInfo{
 			Key: keyOfSrcLatestScanInfo,
 		},
 		&MgoLatestScanInfo{
 			Key: keyOfDstLatestScanInfo,
 		},
-	)
+	}, opts)
 }
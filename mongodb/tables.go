@@ -1,7 +1,7 @@
 package mongodb
 
 import (
-	"gopkg.in/mgo.v2/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
@@ -10,12 +10,27 @@ const (
 	tbSwapinResults     string = "SwapinResults"
 	tbSwapoutResults    string = "SwapoutResults"
 	tbP2shAddresses     string = "P2shAddresses"
+	tbDestinationTags   string = "DestinationTags"
 	tbSwapStatistics    string = "SwapStatistics"
+	tbStatisticsBuckets string = "SwapStatisticsBuckets"
 	tbLatestScanInfo    string = "LatestScanInfo"
 	tbRegisteredAddress string = "RegisteredAddress"
 	tbBlacklist         string = "Blacklist"
 	tbLatestSwapNonces  string = "LatestSwapNonces"
 	tbSwapHistory       string = "SwapHistory"
+	tbSwapStatusHistory string = "SwapStatusHistory"
+
+	tbSwapinsArchive        string = "SwapinsArchive"
+	tbSwapoutsArchive       string = "SwapoutsArchive"
+	tbSwapinResultsArchive  string = "SwapinResultsArchive"
+	tbSwapoutResultsArchive string = "SwapoutResultsArchive"
+
+	tbLeaderLease string = "LeaderLease"
+	tbSwapTasks   string = "SwapTasks"
+	tbSignIntents string = "SignIntents"
+
+	tbSwapVelocities string = "SwapVelocities"
+	tbTxBlockHashes  string = "TxBlockHashes"
 
 	keyOfSrcLatestScanInfo string = "srclatest"
 	keyOfDstLatestScanInfo string = "dstlatest"
@@ -47,6 +62,7 @@ type MgoSwapResult struct {
 	To          string     `bson:"to"`
 	Bind        string     `bson:"bind"`
 	Value       string     `bson:"value"`
+	ValueKey    string     `bson:"valuekey" json:"valuekey,omitempty"` // zero padded value for range query
 	SwapTx      string     `bson:"swaptx"`
 	OldSwapTxs  []string   `bson:"oldswaptxs"`
 	OldSwapVals []string   `bson:"oldswapvals"`
@@ -55,6 +71,7 @@ type MgoSwapResult struct {
 	SwapValue   string     `bson:"swapvalue"`
 	SwapType    uint32     `bson:"swaptype"`
 	SwapNonce   uint64     `bson:"swapnonce"`
+	SwapMemo    string     `bson:"swapmemo,omitempty"` // output memo if paid by batch tx
 	Status      SwapStatus `bson:"status"`
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
@@ -71,9 +88,24 @@ type SwapResultUpdateItems struct {
 	SwapValue   string
 	SwapType    uint32
 	SwapNonce   uint64
+	SwapMemo    string
 	Status      SwapStatus
 	Timestamp   int64
 	Memo        string
+	Actor       string // recorded in status history (not stored in swap result)
+}
+
+// SwapTxCommit records which are committed atomically after swap tx is signed
+type SwapTxCommit struct {
+	IsSwapin    bool
+	TxID        string
+	PairID      string
+	Bind        string
+	ResultItems *SwapResultUpdateItems
+	SwapStatus  SwapStatus
+	History     *MgoSwapHistory
+	SwapNonce   *MgoLatestSwapNonce // nil if not nonce based
+	Actor       string
 }
 
 // MgoP2shAddress key is the bind address
@@ -82,16 +114,21 @@ type MgoP2shAddress struct {
 	P2shAddress string `bson:"p2shaddress"`
 }
 
+// MgoDestinationTag key is the bind address
+// (destination tag of payment to deposit address, eg. XRP)
+type MgoDestinationTag struct {
+	Key            string `bson:"_id"`
+	DestinationTag uint32 `bson:"destinationtag"`
+}
+
 // MgoRegisteredAddress key is address (in whitelist)
 type MgoRegisteredAddress struct {
 	Key       string `bson:"_id"`
 	Timestamp int64  `bson:"timestamp"`
 }
 
-// MgoSwapStatistics swap statistics
-type MgoSwapStatistics struct {
-	Key                string `bson:"_id"` // pairid
-	PairID             string `bson:"pairid"`
+// SwapStatisticsValues stable swap count, value and fee
+type SwapStatisticsValues struct {
 	StableSwapinCount  int    `bson:"swapincount"`
 	TotalSwapinValue   string `bson:"totalswapinvalue"`
 	TotalSwapinFee     string `bson:"totalswapinfee"`
@@ -100,6 +137,22 @@ type MgoSwapStatistics struct {
 	TotalSwapoutFee    string `bson:"totalswapoutfee"`
 }
 
+// MgoSwapStatistics swap statistics
+type MgoSwapStatistics struct {
+	Key                  string `bson:"_id"` // pairid
+	PairID               string `bson:"pairid"`
+	SwapStatisticsValues `bson:",inline"`
+}
+
+// MgoSwapStatisticsBucket swap statistics in time bucket
+type MgoSwapStatisticsBucket struct {
+	Key                  string `bson:"_id"` // pairid + granularity + starttime
+	PairID               string `bson:"pairid"`
+	Granularity          string `bson:"granularity"`
+	StartTime            int64  `bson:"starttime"` // unix seconds in UTC
+	SwapStatisticsValues `bson:",inline"`
+}
+
 // MgoLatestScanInfo latest scan info
 type MgoLatestScanInfo struct {
 	Key         string `bson:"_id"`
@@ -126,9 +179,113 @@ type MgoLatestSwapNonce struct {
 
 // MgoSwapHistory swap history
 type MgoSwapHistory struct {
-	Key      bson.ObjectId `bson:"_id"`
-	IsSwapin bool          `bson:"isswapin"`
-	TxID     string        `bson:"txid"`
-	Bind     string        `bson:"bind"`
-	SwapTx   string        `bson:"swaptx"`
+	Key      primitive.ObjectID `bson:"_id"`
+	IsSwapin bool               `bson:"isswapin"`
+	TxID     string             `bson:"txid"`
+	Bind     string             `bson:"bind"`
+	SwapTx   string             `bson:"swaptx"`
+}
+
+// MgoSwapStatusHistory swap status transition history
+type MgoSwapStatusHistory struct {
+	Key       primitive.ObjectID `bson:"_id"`
+	IsSwapin  bool               `bson:"isswapin"`
+	IsResult  bool               `bson:"isresult"` // swap result status or swap status
+	TxID      string             `bson:"txid"`
+	PairID    string             `bson:"pairid"`
+	Bind      string             `bson:"bind"`
+	OldStatus SwapStatus         `bson:"oldstatus"` // same as new status in the initial entry
+	NewStatus SwapStatus         `bson:"newstatus"`
+	Actor     string             `bson:"actor"` // job name or admin address
+	Memo      string             `bson:"memo"`
+	Timestamp int64              `bson:"timestamp"`
+}
+
+// MgoLeaderLease leader lease of swap server replicas, key is lease name
+type MgoLeaderLease struct {
+	Key       string `bson:"_id"`
+	Holder    string `bson:"holder"`
+	Term      uint64 `bson:"term"`     // increased when lease is taken over
+	ExpireAt  int64  `bson:"expireat"` // unix milliseconds
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// swap task status
+const (
+	SwapTaskPending = "pending"
+	SwapTaskDead    = "dead" // exceeds max attempts, need manual handling
+)
+
+// MgoSwapTask durable swap task, key is swap type + swap key
+type MgoSwapTask struct {
+	Key        string `bson:"_id"`
+	Queue      string `bson:"queue"`
+	IsSwapin   bool   `bson:"isswapin"`
+	TxID       string `bson:"txid"`
+	PairID     string `bson:"pairid"`
+	Bind       string `bson:"bind"`
+	Args       string `bson:"args"` // json encoded build tx args
+	Status     string `bson:"status"`
+	Attempts   int    `bson:"attempts"`
+	LeaseToken string `bson:"leasetoken"` // renewed on every lease, fences ack/nack of stale lease holders
+	VisibleAt  int64  `bson:"visibleat"`  // unix milliseconds, invisible when leased or delayed
+	LastError  string `bson:"lasterror"`
+	CreateTime int64  `bson:"createtime"`
+	Timestamp  int64  `bson:"timestamp"`
+}
+
+// sign intent status
+const (
+	SignIntentOpen      = "open"      // written before signing
+	SignIntentSigned    = "signed"    // signed but not committed
+	SignIntentCommitted = "committed" // swap tx is committed, not sent
+	SignIntentFinalized = "finalized" // swap tx is sent (or recovered)
+	SignIntentAborted   = "aborted"   // signed tx (if any) is discarded
+)
+
+// MgoSignIntent write-ahead record of signing swap tx, key is swap task key
+type MgoSignIntent struct {
+	Key          string   `bson:"_id"`
+	IsSwapin     bool     `bson:"isswapin"`
+	TxID         string   `bson:"txid"`
+	PairID       string   `bson:"pairid"`
+	Bind         string   `bson:"bind"`
+	From         string   `bson:"from"`
+	Nonce        uint64   `bson:"nonce"`     // of nonce based chain
+	Utxos        []string `bson:"utxos"`     // of utxo based chain, 'txhash:index'
+	MsgHashes    []string `bson:"msghashes"` // of dcrm sign
+	KeyIDs       []string `bson:"keyids"`    // of dcrm sign requests
+	SwapValue    string   `bson:"swapvalue"`
+	SignedTxHash string   `bson:"signedtxhash"`
+	Status       string   `bson:"status"`
+	Memo         string   `bson:"memo"`
+	CreateTime   int64    `bson:"createtime"`
+	Timestamp    int64    `bson:"timestamp"`
+}
+
+// MgoSwapVelocity swap value counted in velocity limits, key is swap task key
+type MgoSwapVelocity struct {
+	Key       string `bson:"_id"`
+	IsSwapin  bool   `bson:"isswapin"`
+	PairID    string `bson:"pairid"`
+	Bind      string `bson:"bind"`
+	Value     string `bson:"value"`
+	Actor     string `bson:"actor"`     // who passed the limits check
+	Timestamp int64  `bson:"timestamp"` // unix milliseconds
+}
+
+// MgoTxBlockHash block hash of swap source tx or swap tx watched by reorg monitor,
+// key is swap task key with suffix ':srctx' or ':swaptx'
+type MgoTxBlockHash struct {
+	Key       string `bson:"_id"`
+	IsSwapin  bool   `bson:"isswapin"`
+	TxID      string `bson:"txid"`
+	PairID    string `bson:"pairid"`
+	Bind      string `bson:"bind"`
+	IsSwapTx  bool   `bson:"isswaptx"` // swap tx or swap source tx
+	TxHash    string `bson:"txhash"`
+	Height    uint64 `bson:"height"`
+	BlockHash string `bson:"blockhash"`
+	Reorged   bool   `bson:"reorged"`
+	Timestamp int64  `bson:"timestamp"` // unix milliseconds
 }
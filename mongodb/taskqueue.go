@@ -0,0 +1,164 @@
+package mongodb
+
+import (
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxSwapTaskAttempts swap task is dead if it failed this many times
+var MaxSwapTaskAttempts = 5
+
+// SwapTaskQueueStats stats of swap task queue
+type SwapTaskQueueStats struct {
+	Queue    string `json:"queue"`
+	Pending  int    `json:"pending"`  // waiting to be processed
+	Inflight int    `json:"inflight"` // being processed or delayed to retry
+	Dead     int    `json:"dead"`     // need manual handling
+}
+
+// GetSwapTaskKey swap type + txid + pairID + bind
+func GetSwapTaskKey(isSwapin bool, txid, pairID, bind string) string {
+	swapType := "swapout"
+	if isSwapin {
+		swapType = "swapin"
+	}
+	return swapType + ":" + GetSwapKey(txid, pairID, bind)
+}
+
+// AddSwapTask add pending swap task, returns ErrItemIsDup if the swap is already queued
+func AddSwapTask(task *MgoSwapTask) error {
+	task.PairID = strings.ToLower(task.PairID)
+	task.Key = GetSwapTaskKey(task.IsSwapin, task.TxID, task.PairID, task.Bind)
+	task.Status = SwapTaskPending
+	task.CreateTime = common.NowMilli()
+	task.Timestamp = task.CreateTime
+	task.VisibleAt = task.CreateTime
+	err := store.AddSwapTask(task)
+	if err == nil {
+		log.Info("mongodb add swap task", "key", task.Key, "queue", task.Queue)
+	} else {
+		log.Debug("mongodb add swap task", "key", task.Key, "queue", task.Queue, "err", err)
+	}
+	return err
+}
+
+// LeaseSwapTask lease the oldest pending task of queue, the task is
+// invisible to other consumers until visibility timeout or nack.
+// returns ErrItemNotFound if queue is empty.
+// every lease gets a new lease token, ack/nack/dead of the leased task
+// returns ErrItemNotFound if it has since been leased by others.
+func LeaseSwapTask(queue string, visibilityTimeout time.Duration) (*MgoSwapTask, error) {
+	timestamp := common.NowMilli()
+	leaseToken := primitive.NewObjectID().Hex()
+	return store.LeaseSwapTask(queue, timestamp, timestamp+visibilityTimeout.Milliseconds(), leaseToken)
+}
+
+// AckSwapTask ack the processed task (remove it from queue)
+func AckSwapTask(task *MgoSwapTask) error {
+	err := store.RemoveSwapTask(task.Key, task.LeaseToken)
+	if err == nil {
+		log.Info("mongodb ack swap task", "key", task.Key)
+	} else {
+		log.Warn("mongodb ack swap task failed", "key", task.Key, "attempts", task.Attempts, "err", err)
+	}
+	return err
+}
+
+// NackSwapTask make the failed task visible again after retryDelay,
+// or mark it dead if it exceeds max attempts
+func NackSwapTask(task *MgoSwapTask, retryDelay time.Duration, taskErr error) error {
+	if task.Attempts >= MaxSwapTaskAttempts {
+		return MarkSwapTaskDead(task, taskErr)
+	}
+	task.Timestamp = common.NowMilli()
+	task.VisibleAt = task.Timestamp + retryDelay.Milliseconds()
+	if taskErr != nil {
+		task.LastError = taskErr.Error()
+	}
+	err := store.UpdateSwapTask(task, task.LeaseToken)
+	if err == nil {
+		log.Info("mongodb nack swap task", "key", task.Key, "attempts", task.Attempts, "retryDelay", retryDelay, "taskErr", taskErr)
+	} else {
+		log.Warn("mongodb nack swap task failed", "key", task.Key, "err", err)
+	}
+	return err
+}
+
+// MarkSwapTaskDead mark task dead, it will not be processed until retried by admin
+func MarkSwapTaskDead(task *MgoSwapTask, taskErr error) error {
+	task.Status = SwapTaskDead
+	task.Timestamp = common.NowMilli()
+	if taskErr != nil {
+		task.LastError = taskErr.Error()
+	}
+	err := store.UpdateSwapTask(task, task.LeaseToken)
+	if err == nil {
+		log.Warn("mongodb swap task is dead", "key", task.Key, "attempts", task.Attempts, "lastError", task.LastError)
+	} else {
+		log.Warn("mongodb mark swap task dead failed", "key", task.Key, "err", err)
+	}
+	return err
+}
+
+// RetrySwapTask reset task to be processed immediately with attempts cleared
+func RetrySwapTask(key string) error {
+	task, err := store.FindSwapTask(key)
+	if err != nil {
+		return err
+	}
+	task.Status = SwapTaskPending
+	task.Attempts = 0
+	task.Timestamp = common.NowMilli()
+	task.VisibleAt = task.Timestamp
+	err = store.UpdateSwapTask(task, task.LeaseToken)
+	if err == nil {
+		log.Info("mongodb retry swap task", "key", key)
+	}
+	return err
+}
+
+// RemoveSwapTask remove task from queue
+func RemoveSwapTask(key string) error {
+	err := store.RemoveSwapTask(key, "")
+	if err == nil {
+		log.Info("mongodb remove swap task", "key", key)
+	}
+	return err
+}
+
+// FindSwapTasks find swap tasks of queue and status (empty means all)
+func FindSwapTasks(queue, status string) ([]*MgoSwapTask, error) {
+	return store.FindSwapTasks(strings.ToLower(queue), status)
+}
+
+// GetSwapTaskQueueStats get stats of all swap task queues
+func GetSwapTaskQueueStats() ([]*SwapTaskQueueStats, error) {
+	tasks, err := store.FindSwapTasks("", "")
+	if err != nil {
+		return nil, err
+	}
+	timestamp := common.NowMilli()
+	statsMap := make(map[string]*SwapTaskQueueStats)
+	result := make([]*SwapTaskQueueStats, 0)
+	for _, task := range tasks {
+		stats, exist := statsMap[task.Queue]
+		if !exist {
+			stats = &SwapTaskQueueStats{Queue: task.Queue}
+			statsMap[task.Queue] = stats
+			result = append(result, stats)
+		}
+		switch {
+		case task.Status == SwapTaskDead:
+			stats.Dead++
+		case task.VisibleAt > timestamp:
+			stats.Inflight++
+		default:
+			stats.Pending++
+		}
+	}
+	return result, nil
+}
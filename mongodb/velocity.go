@@ -0,0 +1,129 @@
+package mongodb
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// rolling windows of velocity limits
+const (
+	VelocityHourWindow = time.Hour
+	VelocityDayWindow  = 24 * time.Hour
+)
+
+var swapVelocityLock sync.Mutex
+
+// SwapVelocityUsage total swap value in rolling windows
+type SwapVelocityUsage struct {
+	PairHourly *big.Int
+	PairDaily  *big.Int
+	BindDaily  *big.Int // zero if no bind address specified
+}
+
+// GetSwapVelocityUsage get usage of pair and bind address (optional) in rolling windows
+func GetSwapVelocityUsage(isSwapin bool, pairID, bind string) (*SwapVelocityUsage, error) {
+	return getSwapVelocityUsage(isSwapin, strings.ToLower(pairID), bind, common.NowMilli())
+}
+
+func getSwapVelocityUsage(isSwapin bool, pairID, bind string, timestamp int64) (*SwapVelocityUsage, error) {
+	items, err := store.FindSwapVelocities(isSwapin, pairID, timestamp-VelocityDayWindow.Milliseconds())
+	if err != nil {
+		return nil, err
+	}
+	usage := &SwapVelocityUsage{
+		PairHourly: big.NewInt(0),
+		PairDaily:  big.NewInt(0),
+		BindDaily:  big.NewInt(0),
+	}
+	hourStart := timestamp - VelocityHourWindow.Milliseconds()
+	for _, item := range items {
+		value, ok := new(big.Int).SetString(item.Value, 10)
+		if !ok {
+			continue
+		}
+		usage.PairDaily.Add(usage.PairDaily, value)
+		if item.Timestamp >= hourStart {
+			usage.PairHourly.Add(usage.PairHourly, value)
+		}
+		if bind != "" && strings.EqualFold(item.Bind, bind) {
+			usage.BindDaily.Add(usage.BindDaily, value)
+		}
+	}
+	return usage, nil
+}
+
+// AddSwapVelocity count swap value in velocity limits if check (optional) passes.
+// it returns nil without check if the swap is already counted.
+func AddSwapVelocity(isSwapin bool, txid, pairID, bind, value, actor string, check func(*SwapVelocityUsage) error) error {
+	pairID = strings.ToLower(pairID)
+	key := GetSwapTaskKey(isSwapin, txid, pairID, bind)
+
+	swapVelocityLock.Lock()
+	defer swapVelocityLock.Unlock()
+
+	_, err := store.FindSwapVelocity(key)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, ErrItemNotFound) {
+		return err
+	}
+	timestamp := common.NowMilli()
+	if check != nil {
+		usage, errf := getSwapVelocityUsage(isSwapin, pairID, bind, timestamp)
+		if errf != nil {
+			return errf
+		}
+		if err = check(usage); err != nil {
+			return err
+		}
+	}
+	err = store.AddSwapVelocity(&MgoSwapVelocity{
+		Key:       key,
+		IsSwapin:  isSwapin,
+		PairID:    pairID,
+		Bind:      bind,
+		Value:     value,
+		Actor:     actor,
+		Timestamp: timestamp,
+	})
+	if err == nil {
+		log.Info("mongodb add swap velocity", "key", key, "value", value, "actor", actor)
+	} else {
+		log.Warn("mongodb add swap velocity failed", "key", key, "value", value, "actor", actor, "err", err)
+	}
+	return err
+}
+
+// PassSwapRateLimit release swap parked by exceeding velocity limits,
+// its value is counted in velocity limits if check (optional) passes.
+func PassSwapRateLimit(isSwapin bool, txid, pairID, bind, actor string, check func(*SwapVelocityUsage) error) error {
+	swap, err := FindSwap(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	if swap.Status != TxExceedsRateLimit {
+		return fmt.Errorf("swap status is %v, not rate limit status %v", swap.Status.String(), TxExceedsRateLimit.String())
+	}
+	res, err := FindSwapResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	err = AddSwapVelocity(isSwapin, txid, pairID, bind, res.Value, actor, check)
+	if err != nil {
+		return err
+	}
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "", actor)
+}
+
+// PruneSwapVelocities remove swap velocities out of the largest rolling window
+func PruneSwapVelocities() error {
+	return store.RemoveSwapVelocitiesBefore(common.NowMilli() - VelocityDayWindow.Milliseconds())
+}
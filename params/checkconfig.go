@@ -3,6 +3,8 @@ package params
 import (
 	"errors"
 	"math/big"
+	"net/url"
+	"os"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
@@ -20,12 +22,42 @@ func CheckConfig(isServer bool) (err error) {
 		return err
 	}
 	if isServer {
-		if config.MongoDB == nil {
-			return errors.New("server must config 'MongoDB'")
+		if config.MongoDB == nil && config.LevelDB == nil {
+			return errors.New("server must config 'MongoDB' or 'LevelDB'")
+		}
+		if config.LevelDB != nil {
+			err = config.LevelDB.CheckConfig()
+			if err != nil {
+				return err
+			}
 		}
 		if config.APIServer == nil {
 			return errors.New("server must config 'APIServer'")
 		}
+		if config.Archive != nil {
+			err = config.Archive.CheckConfig()
+			if err != nil {
+				return err
+			}
+		}
+		if config.HA != nil {
+			err = config.HA.CheckConfig()
+			if err != nil {
+				return err
+			}
+		}
+		if config.BatchSwapout != nil {
+			err = config.BatchSwapout.CheckConfig()
+			if err != nil {
+				return err
+			}
+		}
+		if config.ReorgMonitor != nil {
+			err = config.ReorgMonitor.CheckConfig()
+			if err != nil {
+				return err
+			}
+		}
 	} else if config.SrcChain.EnableScan || config.DestChain.EnableScan {
 		err = config.Oracle.CheckConfig()
 		if err != nil {
@@ -148,6 +180,56 @@ func (c *OracleConfig) CheckConfig() (err error) {
 	return err
 }
 
+// CheckConfig check leveldb config
+func (c *LevelDBConfig) CheckConfig() error {
+	if c.Path == "" && GetDataDir() == "" {
+		return errors.New("leveldb must config 'Path' or specify '--datadir'")
+	}
+	return nil
+}
+
+// CheckConfig check archive config
+func (c *ArchiveConfig) CheckConfig() error {
+	if c.ExportDir != "" {
+		if fi, err := os.Stat(c.ExportDir); err == nil && !fi.IsDir() {
+			return errors.New("archive 'ExportDir' is not a directory")
+		}
+	}
+	return nil
+}
+
+// CheckConfig check HA config
+func (c *HAConfig) CheckConfig() error {
+	if !c.Enable {
+		return nil
+	}
+	if GetConfig().MongoDB == nil || GetConfig().LevelDB != nil {
+		return errors.New("HA must use 'MongoDB' swap store shared by replicas")
+	}
+	if 2*c.GetRenewInterval() > c.GetLeaseTime() {
+		return errors.New("HA 'RenewIntervalSeconds' must not exceed half of 'LeaseSeconds'")
+	}
+	return nil
+}
+
+// CheckConfig check batch swapout config
+func (c *BatchSwapoutConfig) CheckConfig() error {
+	if c.MaxBatchSize < 0 {
+		return errors.New("batch swapout 'MaxBatchSize' must not be negative")
+	}
+	return nil
+}
+
+// CheckConfig check reorg monitor config
+func (c *ReorgMonitorConfig) CheckConfig() error {
+	if c.AlertWebhook != "" {
+		if _, err := url.ParseRequestURI(c.AlertWebhook); err != nil {
+			return errors.New("wrong 'AlertWebhook' in reorg monitor config")
+		}
+	}
+	return nil
+}
+
 // CheckConfig extra config
 func (c *ExtraConfig) CheckConfig() (err error) {
 	if c.MinReserveFee != "" {
@@ -2,8 +2,12 @@ package params
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/BurntSushi/toml"
 	"github.com/anyswap/CrossChain-Bridge/common"
@@ -13,6 +17,18 @@ import (
 
 const (
 	defaultAPIPort = 11556
+
+	defaultArchiveAfterDays       = 30
+	defaultArchiveIntervalMinutes = 60
+
+	defaultHALeaseSeconds         = 30
+	defaultHARenewIntervalSeconds = 10
+
+	defaultBatchSwapoutWindowSeconds = 60
+	defaultBatchSwapoutMaxBatchSize  = 20
+
+	defaultReorgCheckIntervalSeconds = 60
+	defaultReorgWatchHours           = 48
 )
 
 var (
@@ -30,9 +46,14 @@ var (
 // ServerConfig config items (decode from toml file)
 type ServerConfig struct {
 	Identifier          string
-	MustRegisterAccount bool             `toml:",omitempty" json:",omitempty"`
-	MongoDB             *MongoDBConfig   `toml:",omitempty" json:",omitempty"`
-	APIServer           *APIServerConfig `toml:",omitempty" json:",omitempty"`
+	MustRegisterAccount bool                `toml:",omitempty" json:",omitempty"`
+	MongoDB             *MongoDBConfig      `toml:",omitempty" json:",omitempty"`
+	LevelDB             *LevelDBConfig      `toml:",omitempty" json:",omitempty"`
+	APIServer           *APIServerConfig    `toml:",omitempty" json:",omitempty"`
+	Archive             *ArchiveConfig      `toml:",omitempty" json:",omitempty"`
+	HA                  *HAConfig           `toml:",omitempty" json:",omitempty"`
+	BatchSwapout        *BatchSwapoutConfig `toml:",omitempty" json:",omitempty"`
+	ReorgMonitor        *ReorgMonitorConfig `toml:",omitempty" json:",omitempty"`
 	SrcChain            *tokens.ChainConfig
 	SrcGateway          *tokens.GatewayConfig
 	DestChain           *tokens.ChainConfig
@@ -83,6 +104,182 @@ type MongoDBConfig struct {
 	Password string `json:"-"`
 }
 
+// LevelDBConfig embedded leveldb swap store config (used instead of mongodb)
+type LevelDBConfig struct {
+	Path string // defaults to 'swapdb' under datadir
+}
+
+// GetPath get leveldb swap store path
+func (c *LevelDBConfig) GetPath() string {
+	if c.Path != "" {
+		return c.Path
+	}
+	return filepath.Join(GetDataDir(), "swapdb")
+}
+
+// ArchiveConfig archive stable swaps config
+type ArchiveConfig struct {
+	Enable           bool
+	ArchiveAfterDays uint64 // archive stable swaps older than this (default 30)
+	IntervalMinutes  uint64 // archive job interval (default 60)
+	ExportDir        string // also export archived swaps as jsonl.gz files if not empty
+}
+
+// GetArchiveAfter get age of stable swaps to archive
+func (c *ArchiveConfig) GetArchiveAfter() time.Duration {
+	days := c.ArchiveAfterDays
+	if days == 0 {
+		days = defaultArchiveAfterDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetInterval get archive job interval
+func (c *ArchiveConfig) GetInterval() time.Duration {
+	minutes := c.IntervalMinutes
+	if minutes == 0 {
+		minutes = defaultArchiveIntervalMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetArchiveConfig get archive config
+func GetArchiveConfig() *ArchiveConfig {
+	return GetConfig().Archive
+}
+
+// HAConfig active/standby swap server replicas config,
+// replicas are coordinated by a leader lease stored in mongodb
+type HAConfig struct {
+	Enable               bool
+	NodeID               string // defaults to hostname:pid
+	LeaseSeconds         uint64 // default 30
+	RenewIntervalSeconds uint64 // default 10
+}
+
+// GetNodeID get node id of this replica
+func (c *HAConfig) GetNodeID() string {
+	if c.NodeID != "" {
+		return c.NodeID
+	}
+	hostname, _ := os.Hostname()
+	return fmt.Sprintf("%v:%v", hostname, os.Getpid())
+}
+
+// GetLeaseTime get leader lease time
+func (c *HAConfig) GetLeaseTime() time.Duration {
+	seconds := c.LeaseSeconds
+	if seconds == 0 {
+		seconds = defaultHALeaseSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetRenewInterval get leader lease renew interval
+func (c *HAConfig) GetRenewInterval() time.Duration {
+	seconds := c.RenewIntervalSeconds
+	if seconds == 0 {
+		seconds = defaultHARenewIntervalSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IsHAEnabled is active/standby replicas enabled
+func IsHAEnabled() bool {
+	haCfg := GetConfig().HA
+	return haCfg != nil && haCfg.Enable
+}
+
+// GetHAConfig get HA config
+func GetHAConfig() *HAConfig {
+	return GetConfig().HA
+}
+
+// BatchSwapoutConfig batch swapouts of utxo destination chains,
+// pending swapouts of a pair within a window are paid by one tx
+type BatchSwapoutConfig struct {
+	Enable        bool
+	PairIDs       []string // pairs to batch, empty means all pairs
+	WindowSeconds uint64   // default 60
+	MaxBatchSize  int      // max swapouts in one tx (default 20)
+}
+
+// IsBatchPair is swapouts of pair batched
+func (c *BatchSwapoutConfig) IsBatchPair(pairID string) bool {
+	if !c.Enable {
+		return false
+	}
+	if len(c.PairIDs) == 0 {
+		return true
+	}
+	for _, pair := range c.PairIDs {
+		if strings.EqualFold(pair, pairID) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetWindow get batch window
+func (c *BatchSwapoutConfig) GetWindow() time.Duration {
+	seconds := c.WindowSeconds
+	if seconds == 0 {
+		seconds = defaultBatchSwapoutWindowSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetMaxBatchSize get max swapouts in one tx
+func (c *BatchSwapoutConfig) GetMaxBatchSize() int {
+	if c.MaxBatchSize <= 0 {
+		return defaultBatchSwapoutMaxBatchSize
+	}
+	return c.MaxBatchSize
+}
+
+// GetBatchSwapoutConfig get batch swapout config
+func GetBatchSwapoutConfig() *BatchSwapoutConfig {
+	return GetConfig().BatchSwapout
+}
+
+// ReorgMonitorConfig watch block hashes of swap source txs and swap txs,
+// halt swaps of the pair if any of them is reorged
+type ReorgMonitorConfig struct {
+	Enable               bool
+	CheckIntervalSeconds uint64 // default 60
+	WatchHours           uint64 // watch txs recorded in these hours (default 48)
+	AlertWebhook         string `toml:",omitempty" json:",omitempty"` // post alert json to this url
+}
+
+// GetCheckInterval get check interval
+func (c *ReorgMonitorConfig) GetCheckInterval() time.Duration {
+	seconds := c.CheckIntervalSeconds
+	if seconds == 0 {
+		seconds = defaultReorgCheckIntervalSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// GetWatchTime get how long txs are watched after recorded
+func (c *ReorgMonitorConfig) GetWatchTime() time.Duration {
+	hours := c.WatchHours
+	if hours == 0 {
+		hours = defaultReorgWatchHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// IsReorgMonitorEnabled is reorg monitor enabled
+func IsReorgMonitorEnabled() bool {
+	c := GetConfig().ReorgMonitor
+	return c != nil && c.Enable
+}
+
+// GetReorgMonitorConfig get reorg monitor config
+func GetReorgMonitorConfig() *ReorgMonitorConfig {
+	return GetConfig().ReorgMonitor
+}
+
 // ExtraConfig extra config
 type ExtraConfig struct {
 	MinReserveFee string
@@ -161,7 +358,12 @@ func LoadConfig(configFile string, isServer bool) *ServerConfig {
 			config.Oracle = nil
 		} else {
 			config.MongoDB = nil
+			config.LevelDB = nil
 			config.APIServer = nil
+			config.Archive = nil
+			config.HA = nil
+			config.BatchSwapout = nil
+			config.ReorgMonitor = nil
 		}
 
 		SetConfig(config)
@@ -27,8 +27,15 @@ func InitCrossChainBridge() {
 	srcNet := srcChain.NetID
 	dstNet := dstChain.NetID
 
-	srcBridge = bridge.NewCrossChainBridge(srcID, true)
-	dstBridge = bridge.NewCrossChainBridge(dstID, false)
+	var err error
+	srcBridge, err = bridge.NewCrossChainBridge(srcID, true)
+	if err != nil {
+		log.Fatal("new source bridge failed", "err", err)
+	}
+	dstBridge, err = bridge.NewCrossChainBridge(dstID, false)
+	if err != nil {
+		log.Fatal("new dest bridge failed", "err", err)
+	}
 	log.Info("New bridge finished", "source", srcID, "sourceNet", srcNet, "dest", dstID, "destNet", dstNet)
 
 	srcBridge.SetChainAndGateway(srcChain, srcGateway)
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
@@ -59,6 +60,40 @@ func StatisticsHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// StatisticsRangeHandler handler
+func StatisticsRangeHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	vals := r.URL.Query()
+	var from, to uint64
+	var err error
+	if fromStr, exist := vals["from"]; exist {
+		from, err = common.GetUint64FromStr(fromStr[0])
+	}
+	if toStr, exist := vals["to"]; exist && err == nil {
+		to, err = common.GetUint64FromStr(toStr[0])
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	granularity := vals.Get("granularity")
+	if granularity == "" {
+		granularity = "day"
+	}
+	res, err := swapapi.GetSwapStatisticsRange(pairID, int64(from), int64(to), granularity)
+	writeResponse(w, res, err)
+}
+
+// SwapVelocityHandler handler
+func SwapVelocityHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	pairID := vars["pairid"]
+	bind := getBindParam(r)
+	res, err := swapapi.GetSwapVelocity(pairID, bind)
+	writeResponse(w, res, err)
+}
+
 func getBindParam(r *http.Request) string {
 	vals := r.URL.Query()
 	bindVals, exist := vals["bind"]
@@ -128,26 +163,45 @@ func GetSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
-type historyParams struct {
-	address string
-	pairID  string
-	offset  int
-	limit   int
-	status  string
+// GetSwapStatusHistoryHandler handler
+func GetSwapStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+	pairID := vars["pairid"]
+	bind := getBindParam(r)
+	res, err := swapapi.GetSwapStatusHistory(&txid, &pairID, &bind)
+	writeResponse(w, res, err)
 }
 
-func getHistoryParams(r *http.Request) (p *historyParams, err error) {
+func getHistoryParams(r *http.Request) (p *swapapi.SwapHistoryFilter, err error) {
 	vars := mux.Vars(r)
 	vals := r.URL.Query()
 
-	p = &historyParams{}
+	p = &swapapi.SwapHistoryFilter{}
+
+	p.Address = vars["address"]
+	p.PairID = vars["pairid"]
 
-	p.address = vars["address"]
-	p.pairID = vars["pairid"]
+	getString := func(key string) string {
+		if val, exist := vals[key]; exist {
+			return val[0]
+		}
+		return ""
+	}
+	getInt64 := func(key string, result *int64) error {
+		if val, exist := vals[key]; exist {
+			num, errf := common.GetUint64FromStr(val[0])
+			if errf != nil {
+				return errf
+			}
+			*result = int64(num)
+		}
+		return nil
+	}
 
 	offsetStr, exist := vals["offset"]
 	if exist {
-		p.offset, err = common.GetIntFromStr(offsetStr[0])
+		p.Offset, err = common.GetIntFromStr(offsetStr[0])
 		if err != nil {
 			return p, err
 		}
@@ -155,15 +209,29 @@ func getHistoryParams(r *http.Request) (p *historyParams, err error) {
 
 	limitStr, exist := vals["limit"]
 	if exist {
-		p.limit, err = common.GetIntFromStr(limitStr[0])
+		p.Limit, err = common.GetIntFromStr(limitStr[0])
 		if err != nil {
 			return p, err
 		}
 	}
 
-	statusStr, exist := vals["status"]
-	if exist {
-		p.status = statusStr[0]
+	p.Status = getString("status")
+	p.To = getString("to")
+	p.Bind = getString("bind")
+	p.SwapTx = getString("swaptx")
+	p.MinValue = getString("minvalue")
+	p.MaxValue = getString("maxvalue")
+	p.Cursor = getString("cursor")
+
+	for key, result := range map[string]*int64{
+		"mininittime":  &p.MinInitTime,
+		"maxinittime":  &p.MaxInitTime,
+		"mintimestamp": &p.MinTimestamp,
+		"maxtimestamp": &p.MaxTimestamp,
+	} {
+		if err = getInt64(key, result); err != nil {
+			return p, err
+		}
 	}
 
 	return p, nil
@@ -175,7 +243,7 @@ func SwapinHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		writeResponse(w, nil, err)
 	} else {
-		res, err := swapapi.GetSwapinHistory(p.address, p.pairID, p.offset, p.limit, p.status)
+		res, err := swapapi.GetSwapinHistory(p)
 		writeResponse(w, res, err)
 	}
 }
@@ -186,7 +254,7 @@ func SwapoutHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	if err != nil {
 		writeResponse(w, nil, err)
 	} else {
-		res, err := swapapi.GetSwapoutHistory(p.address, p.pairID, p.offset, p.limit, p.status)
+		res, err := swapapi.GetSwapoutHistory(p)
 		writeResponse(w, res, err)
 	}
 }
@@ -243,6 +311,27 @@ func GetP2shAddressInfo(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// RegisterDestinationTag handler
+func RegisterDestinationTag(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	address := vars["address"]
+	res, err := swapapi.RegisterDestinationTag(address)
+	writeResponse(w, res, err)
+}
+
+// GetDestinationTagInfo handler
+func GetDestinationTagInfo(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	tagStr := vars["tag"]
+	tag, err := strconv.ParseUint(tagStr, 10, 32)
+	if err != nil {
+		writeResponse(w, nil, err)
+		return
+	}
+	res, err := swapapi.GetDestinationTagInfo(uint32(tag))
+	writeResponse(w, res, err)
+}
+
 // RegisterAddress handler
 func RegisterAddress(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
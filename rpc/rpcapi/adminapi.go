@@ -1,9 +1,11 @@
 package rpcapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/admin"
 	"github.com/anyswap/CrossChain-Bridge/common"
@@ -39,29 +41,38 @@ func (s *RPCAPI) AdminCall(r *http.Request, rawTx, result *string) (err error) {
 	if !params.IsAdmin(sender.String()) {
 		return fmt.Errorf("sender %v is not admin", sender.String())
 	}
-	return doCall(args, result)
+	actor := "admin:" + sender.String()
+	return doCall(args, actor, result)
 }
 
-func doCall(args *admin.CallArgs, result *string) error {
+func doCall(args *admin.CallArgs, actor string, result *string) error {
 	switch args.Method {
 	case "blacklist":
 		return blacklist(args, result)
 	case "bigvalue":
-		return bigvalue(args, result)
+		return bigvalue(args, actor, result)
+	case "ratelimit":
+		return ratelimit(args, actor, result)
 	case "maintain":
 		return maintain(args, result)
 	case "reverify":
-		return reverify(args, result)
+		return reverify(args, actor, result)
 	case "reswap":
-		return reswap(args, result)
+		return reswap(args, actor, result)
 	case "replaceswap":
 		return replaceswap(args, result)
 	case "manual":
-		return manual(args, result)
+		return manual(args, actor, result)
 	case "setnonce":
 		return setnonce(args, result)
 	case "addpair":
 		return addpair(args, result)
+	case "rebuildstatistics":
+		return rebuildstatistics(args, result)
+	case "archive":
+		return archive(args, result)
+	case "swaptask":
+		return swaptask(args, result)
 	default:
 		return fmt.Errorf("unknown admin method '%v'", args.Method)
 	}
@@ -102,7 +113,7 @@ func blacklist(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func bigvalue(args *admin.CallArgs, result *string) (err error) {
+func bigvalue(args *admin.CallArgs, actor string, result *string) (err error) {
 	if len(args.Params) != 4 {
 		return fmt.Errorf("wrong number of params, have %v want 4", len(args.Params))
 	}
@@ -112,9 +123,33 @@ func bigvalue(args *admin.CallArgs, result *string) (err error) {
 	bind := args.Params[3]
 	switch operation {
 	case passSwapinOp:
-		err = mongodb.PassSwapinBigValue(txid, pairID, bind)
+		err = mongodb.PassSwapinBigValue(txid, pairID, bind, actor)
 	case passSwapoutOp:
-		err = mongodb.PassSwapoutBigValue(txid, pairID, bind)
+		err = mongodb.PassSwapoutBigValue(txid, pairID, bind, actor)
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	*result = successReuslt
+	return nil
+}
+
+// ratelimit pass rate limited swap without checking velocity limits
+func ratelimit(args *admin.CallArgs, actor string, result *string) (err error) {
+	if len(args.Params) != 4 {
+		return fmt.Errorf("wrong number of params, have %v want 4", len(args.Params))
+	}
+	operation := args.Params[0]
+	txid := args.Params[1]
+	pairID := args.Params[2]
+	bind := args.Params[3]
+	switch operation {
+	case passSwapinOp:
+		err = mongodb.PassSwapRateLimit(true, txid, pairID, bind, actor, nil)
+	case passSwapoutOp:
+		err = mongodb.PassSwapRateLimit(false, txid, pairID, bind, actor, nil)
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
@@ -172,11 +207,11 @@ func maintain(args *admin.CallArgs, result *string) (err error) {
 			continue
 		}
 		if isDeposit {
-			pairCfg.SrcToken.DisableSwap = newDisableFlag
+			pairCfg.SrcToken.SetDisableSwap(newDisableFlag)
 		}
 
 		if isWithdraw {
-			pairCfg.DestToken.DisableSwap = newDisableFlag
+			pairCfg.DestToken.SetDisableSwap(newDisableFlag)
 		}
 
 		successPairs += " " + pairID
@@ -204,16 +239,16 @@ func getOpTxAndPairID(args *admin.CallArgs) (operation, txid, pairID, bind strin
 	return operation, txid, pairID, bind, nil
 }
 
-func reverify(args *admin.CallArgs, result *string) (err error) {
+func reverify(args *admin.CallArgs, actor string, result *string) (err error) {
 	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
 	if err != nil {
 		return err
 	}
 	switch operation {
 	case swapinOp:
-		err = mongodb.ReverifySwapin(txid, pairID, bind)
+		err = mongodb.ReverifySwapin(txid, pairID, bind, actor)
 	case swapoutOp:
-		err = mongodb.ReverifySwapout(txid, pairID, bind)
+		err = mongodb.ReverifySwapout(txid, pairID, bind, actor)
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
@@ -224,7 +259,7 @@ func reverify(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func reswap(args *admin.CallArgs, result *string) (err error) {
+func reswap(args *admin.CallArgs, actor string, result *string) (err error) {
 	operation, txid, pairID, bind, err := getOpTxAndPairID(args)
 	if err != nil {
 		return err
@@ -233,9 +268,9 @@ func reswap(args *admin.CallArgs, result *string) (err error) {
 	switch operation {
 	case swapinOp:
 		isSwapin = true
-		err = mongodb.Reswapin(txid, pairID, bind)
+		err = mongodb.Reswapin(txid, pairID, bind, actor)
 	case swapoutOp:
-		err = mongodb.Reswapout(txid, pairID, bind)
+		err = mongodb.Reswapout(txid, pairID, bind, actor)
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
@@ -274,7 +309,7 @@ func replaceswap(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func manual(args *admin.CallArgs, result *string) (err error) {
+func manual(args *admin.CallArgs, actor string, result *string) (err error) {
 	if !(len(args.Params) == 4 || len(args.Params) == 5) {
 		return fmt.Errorf("wrong number of params, have %v want 4 or 5", len(args.Params))
 	}
@@ -305,7 +340,7 @@ func manual(args *admin.CallArgs, result *string) (err error) {
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
-	err = mongodb.ManualManageSwap(txid, pairID, bind, memo, isSwapin, isPass)
+	err = mongodb.ManualManageSwap(txid, pairID, bind, memo, isSwapin, isPass, actor)
 	if err != nil {
 		return err
 	}
@@ -341,6 +376,98 @@ func setnonce(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
+func rebuildstatistics(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	pairIDs := args.Params[0]
+
+	var pairIDSlice []string
+	if strings.EqualFold(pairIDs, "all") {
+		pairIDSlice = tokens.GetAllPairIDs()
+	} else {
+		pairIDSlice = strings.Split(pairIDs, ",")
+	}
+
+	var successPairs, failedPairs string
+	for _, pairID := range pairIDSlice {
+		_, err = mongodb.RebuildSwapStatistics(pairID)
+		if err != nil {
+			failedPairs += " " + pairID
+			continue
+		}
+		successPairs += " " + pairID
+	}
+
+	resultStr := "success: " + successPairs
+	if failedPairs != "" {
+		resultStr += ", failed: " + failedPairs
+	}
+
+	*result = resultStr
+	return nil
+}
+
+func archive(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) != 1 {
+		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
+	}
+	days, err := common.GetUint64FromStr(args.Params[0])
+	if err != nil || days == 0 {
+		return fmt.Errorf("wrong days value '%v'", args.Params[0])
+	}
+	before := time.Now().Add(-time.Duration(days) * 24 * time.Hour).Unix()
+	swapins, swapouts, err := worker.ArchiveStableSwaps(before)
+	if err != nil {
+		return fmt.Errorf("%w (archived swapins: %v, swapouts: %v)", err, swapins, swapouts)
+	}
+	*result = fmt.Sprintf("archived swapins: %v, swapouts: %v", swapins, swapouts)
+	return nil
+}
+
+func swaptask(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) == 0 {
+		return fmt.Errorf("wrong number of params, have 0 want at least 1")
+	}
+	operation := args.Params[0]
+	var res interface{}
+	switch operation {
+	case "stats":
+		res, err = mongodb.GetSwapTaskQueueStats()
+	case "list":
+		var status string
+		if len(args.Params) > 1 {
+			status = args.Params[1]
+		}
+		res, err = mongodb.FindSwapTasks("", status)
+	case "retry", "remove":
+		if len(args.Params) != 2 {
+			return fmt.Errorf("wrong number of params, have %v want 2", len(args.Params))
+		}
+		if operation == "retry" {
+			err = mongodb.RetrySwapTask(args.Params[1])
+		} else {
+			err = mongodb.RemoveSwapTask(args.Params[1])
+		}
+		res = successReuslt
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	if err != nil {
+		return err
+	}
+	if str, ok := res.(string); ok {
+		*result = str
+		return nil
+	}
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+	*result = string(data)
+	return nil
+}
+
 func addpair(args *admin.CallArgs, result *string) (err error) {
 	if len(args.Params) != 1 {
 		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
@@ -58,6 +58,38 @@ func (s *RPCAPI) GetSwapStatistics(r *http.Request, pairID *string, result *swap
 	return err
 }
 
+// RPCStatisticsRangeArgs args
+type RPCStatisticsRangeArgs struct {
+	PairID      string `json:"pairid"`
+	From        int64  `json:"from"`
+	To          int64  `json:"to"`
+	Granularity string `json:"granularity"`
+}
+
+// GetSwapStatisticsRange api
+func (s *RPCAPI) GetSwapStatisticsRange(r *http.Request, args *RPCStatisticsRangeArgs, result *[]*swapapi.SwapStatisticsBucket) error {
+	res, err := swapapi.GetSwapStatisticsRange(args.PairID, args.From, args.To, args.Granularity)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// RPCSwapVelocityArgs args
+type RPCSwapVelocityArgs struct {
+	PairID string `json:"pairid"`
+	Bind   string `json:"bind"`
+}
+
+// GetSwapVelocity api
+func (s *RPCAPI) GetSwapVelocity(r *http.Request, args *RPCSwapVelocityArgs, result *swapapi.SwapVelocityInfo) error {
+	res, err := swapapi.GetSwapVelocity(args.PairID, args.Bind)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // RPCTxAndPairIDArgs txid and pairID
 type RPCTxAndPairIDArgs struct {
 	TxID   string `json:"txid"`
@@ -157,17 +189,11 @@ func (s *RPCAPI) GetSwapout(r *http.Request, args *RPCTxAndPairIDArgs, result *s
 }
 
 // RPCQueryHistoryArgs args
-type RPCQueryHistoryArgs struct {
-	Address string `json:"address"`
-	PairID  string `json:"pairid"`
-	Offset  int    `json:"offset"`
-	Limit   int    `json:"limit"`
-	Status  string `json:"status"`
-}
+type RPCQueryHistoryArgs = swapapi.SwapHistoryFilter
 
 // GetSwapinHistory api
 func (s *RPCAPI) GetSwapinHistory(r *http.Request, args *RPCQueryHistoryArgs, result *[]*swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapinHistory(args.Address, args.PairID, args.Offset, args.Limit, args.Status)
+	res, err := swapapi.GetSwapinHistory(args)
 	if err == nil && res != nil {
 		*result = res
 	}
@@ -176,7 +202,20 @@ func (s *RPCAPI) GetSwapinHistory(r *http.Request, args *RPCQueryHistoryArgs, re
 
 // GetSwapoutHistory api
 func (s *RPCAPI) GetSwapoutHistory(r *http.Request, args *RPCQueryHistoryArgs, result *[]*swapapi.SwapInfo) error {
-	res, err := swapapi.GetSwapoutHistory(args.Address, args.PairID, args.Offset, args.Limit, args.Status)
+	res, err := swapapi.GetSwapoutHistory(args)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetSwapStatusHistory api
+func (s *RPCAPI) GetSwapStatusHistory(r *http.Request, args *RPCTxAndPairIDArgs, result *[]*swapapi.SwapStatusTransition) error {
+	txid, pairID, bind, err := args.getTxAndPairID()
+	if err != nil {
+		return err
+	}
+	res, err := swapapi.GetSwapStatusHistory(txid, pairID, bind)
 	if err == nil && res != nil {
 		*result = res
 	}
@@ -267,6 +306,24 @@ func (s *RPCAPI) GetP2shAddressInfo(r *http.Request, p2shAddress *string, result
 	return err
 }
 
+// RegisterDestinationTag api
+func (s *RPCAPI) RegisterDestinationTag(r *http.Request, bindAddress *string, result *tokens.DestinationTagInfo) error {
+	res, err := swapapi.RegisterDestinationTag(*bindAddress)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetDestinationTagInfo api
+func (s *RPCAPI) GetDestinationTagInfo(r *http.Request, tag *uint32, result *tokens.DestinationTagInfo) error {
+	res, err := swapapi.GetDestinationTagInfo(*tag)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
 // GetLatestScanInfo api
 func (s *RPCAPI) GetLatestScanInfo(r *http.Request, isSrc *bool, result *swapapi.LatestScanInfo) error {
 	res, err := swapapi.GetLatestScanInfo(*isSrc)
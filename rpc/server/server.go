@@ -77,6 +77,8 @@ func initRouter() *mux.Router {
 	r.HandleFunc("/nonceinfo", restapi.NonceInfoHandler).Methods("GET")
 	r.HandleFunc("/pairinfo/{pairid}", restapi.TokenPairInfoHandler).Methods("GET")
 	r.HandleFunc("/statistics/{pairid}", restapi.StatisticsHandler).Methods("GET")
+	r.HandleFunc("/statistics/{pairid}/range", restapi.StatisticsRangeHandler).Methods("GET")
+	r.HandleFunc("/velocity/{pairid}", restapi.SwapVelocityHandler).Methods("GET")
 	r.HandleFunc("/swapin/post/{pairid}/{txid}", restapi.PostSwapinHandler).Methods("POST")
 	r.HandleFunc("/swapout/post/{pairid}/{txid}", restapi.PostSwapoutHandler).Methods("POST")
 	r.HandleFunc("/swapin/p2sh/{txid}/{bind}", restapi.PostP2shSwapinHandler).Methods("POST")
@@ -87,10 +89,13 @@ func initRouter() *mux.Router {
 	r.HandleFunc("/swapout/{pairid}/{txid}/raw", restapi.GetRawSwapoutHandler).Methods("GET")
 	r.HandleFunc("/swapin/{pairid}/{txid}/rawresult", restapi.GetRawSwapinResultHandler).Methods("GET")
 	r.HandleFunc("/swapout/{pairid}/{txid}/rawresult", restapi.GetRawSwapoutResultHandler).Methods("GET")
+	r.HandleFunc("/swap/statushistory/{pairid}/{txid}", restapi.GetSwapStatusHistoryHandler).Methods("GET")
 	r.HandleFunc("/swapin/history/{pairid}/{address}", restapi.SwapinHistoryHandler).Methods("GET")
 	r.HandleFunc("/swapout/history/{pairid}/{address}", restapi.SwapoutHistoryHandler).Methods("GET")
 	r.HandleFunc("/p2sh/{address}", restapi.GetP2shAddressInfo).Methods("GET", "POST")
 	r.HandleFunc("/p2sh/bind/{address}", restapi.RegisterP2shAddress).Methods("GET", "POST")
+	r.HandleFunc("/xrp/tag/{tag}", restapi.GetDestinationTagInfo).Methods("GET", "POST")
+	r.HandleFunc("/xrp/bind/{address}", restapi.RegisterDestinationTag).Methods("GET", "POST")
 	r.HandleFunc("/registered/{address}", restapi.GetRegisteredAddress).Methods("GET", "POST")
 	r.HandleFunc("/register/{address}", restapi.RegisterAddress).Methods("GET", "POST")
 
@@ -103,6 +108,8 @@ func initRouter() *mux.Router {
 	r.HandleFunc("/nonceinfo", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/pairinfo/{pairid}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/statistics/{pairid}", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/statistics/{pairid}/range", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/velocity/{pairid}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapin/post/{pairid}/{txid}", warnHandler).Methods(methodsExcluesPost...)
 	r.HandleFunc("/swapout/post/{pairid}/{txid}", warnHandler).Methods(methodsExcluesPost...)
 	r.HandleFunc("/swapin/p2sh/{txid}/{bind}", warnHandler).Methods(methodsExcluesPost...)
@@ -113,10 +120,13 @@ func initRouter() *mux.Router {
 	r.HandleFunc("/swapout/{pairid}/{txid}/raw", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapin/{pairid}/{txid}/rawresult", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapout/{pairid}/{txid}/rawresult", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/swap/statushistory/{pairid}/{txid}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapin/history/{pairid}/{address}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapout/history/{pairid}/{address}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/p2sh/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
 	r.HandleFunc("/p2sh/bind/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
+	r.HandleFunc("/xrp/tag/{tag}", warnHandler).Methods(methodsExcluesGetAndPost...)
+	r.HandleFunc("/xrp/bind/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
 	r.HandleFunc("/registered/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
 	r.HandleFunc("/register/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
 
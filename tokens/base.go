@@ -154,6 +154,15 @@ func GetBigValueThreshold(pairID string, isSrc bool) *big.Int {
 	return token.bigValThreshhold
 }
 
+// GetVelocityLimits get rolling window velocity limits (nil means unlimited)
+func GetVelocityLimits(pairID string, isSrc bool) (pairHourly, pairDaily, bindDaily *big.Int) {
+	token := GetTokenConfig(pairID, isSrc)
+	if token == nil {
+		return nil, nil, nil
+	}
+	return token.pairHourlyLimit, token.pairDailyLimit, token.bindDailyLimit
+}
+
 // CheckSwapValue check swap value is in right range
 func CheckSwapValue(pairID string, value *big.Int, isSrc bool) bool {
 	token := GetTokenConfig(pairID, isSrc)
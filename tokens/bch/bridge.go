@@ -0,0 +1,93 @@
+// Package bch implements the bridge interfaces for bitcoin cash.
+//
+// The bridge derives from the utxo engine with bitcoin cash chain params.
+// It signs all inputs with SIGHASH_FORKID (BIP143 style sighash), and
+// accepts both cashaddr and legacy addresses. Addresses are in legacy
+// encoding internally (eg. p2sh bind addresses and records in database),
+// and are converted to cashaddr when calling the chain api.
+package bch
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/utxo"
+)
+
+const (
+	// BlockChain block chain name of bitcoin cash
+	BlockChain = "BitcoinCash"
+)
+
+// Bridge bch bridge inherit from utxo engine
+type Bridge struct {
+	*utxo.Bridge
+}
+
+func init() {
+	tokens.RegisterBridgeFactory(BlockChain, func(isSrc bool) tokens.CrossChainBridge {
+		return NewCrossChainBridge(isSrc)
+	})
+}
+
+// NewCrossChainBridge new bch bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	bridge := &Bridge{
+		Bridge: utxo.NewCrossChainBridgeWithPresets(BlockChain, presets, isSrc),
+	}
+	bridge.SetInherit(bridge)
+	btc.BridgeInstance = bridge
+	return bridge
+}
+
+// SetChainAndGateway set chain and gateway config
+func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	b.VerifyChainConfig()
+	b.InitChainAPI()
+	b.InitLatestBlockNumber()
+}
+
+// InitChainAPI init chain api which converts addresses to cashaddr
+func (b *Bridge) InitChainAPI() {
+	b.Bridge.InitChainAPI()
+	chainAPI := b.ChainAPI
+	if chainAPI == nil {
+		chainAPI = btc.NewElectrsAPI(b.Bridge.Bridge)
+	}
+	b.ChainAPI = &cashAddrAPI{b: b, ChainAPI: chainAPI}
+}
+
+// VerifyTokenConfig verify token config,
+// cashaddr of dcrm and deposit address are converted to legacy encoding.
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	for _, addr := range []*string{&tokenCfg.DcrmAddress, &tokenCfg.DepositAddress} {
+		if legacy, err := b.ToLegacyAddress(*addr); err == nil {
+			log.Info("convert cashaddr to legacy address", "symbol", tokenCfg.Symbol, "cashaddr", *addr, "legacy", legacy)
+			*addr = legacy
+		}
+	}
+	if !b.IsP2pkhAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address (not p2pkh): %v", tokenCfg.DcrmAddress)
+	}
+	return b.Bridge.VerifyTokenConfig(tokenCfg)
+}
+
+func (b *Bridge) getCashAddrPrefix() string {
+	return cashAddrPrefixes[b.GetChainParams().Name]
+}
+
+// ToLegacyAddress impl btc.AddressConverter, convert cashaddr to legacy address
+func (b *Bridge) ToLegacyAddress(addr string) (string, error) {
+	return cashAddrToLegacy(addr, b.getCashAddrPrefix(), b.GetChainParams())
+}
+
+// ToCashAddress convert address (cashaddr or legacy) to cashaddr
+func (b *Bridge) ToCashAddress(addr string) (string, error) {
+	if legacy, err := b.ToLegacyAddress(addr); err == nil {
+		addr = legacy
+	}
+	return legacyToCashAddr(addr, b.getCashAddrPrefix(), b.GetChainParams())
+}
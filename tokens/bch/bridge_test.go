@@ -0,0 +1,133 @@
+package bch
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+func newTestBridge(netID string) *Bridge {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain: BlockChain,
+		NetID:      netID,
+	}
+	b.VerifyChainConfig()
+	return b
+}
+
+func TestCashAddr(t *testing.T) {
+	b := newTestBridge("mainnet")
+	tests := []struct {
+		legacy   string
+		cashAddr string
+	}{
+		{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+		{"1KXrWXciRDZUpQwQmuM1DbwsKDLYAYsVLR", "bitcoincash:qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy"},
+		{"3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC", "bitcoincash:ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq"},
+	}
+	for _, test := range tests {
+		cashAddr, err := b.ToCashAddress(test.legacy)
+		if err != nil || cashAddr != test.cashAddr {
+			t.Fatalf("convert %v to %v, want %v, err %v", test.legacy, cashAddr, test.cashAddr, err)
+		}
+		for _, addr := range []string{test.cashAddr, test.cashAddr[len("bitcoincash:"):]} {
+			legacy, err := b.ToLegacyAddress(addr)
+			if err != nil || legacy != test.legacy {
+				t.Fatalf("convert %v to %v, want %v, err %v", addr, legacy, test.legacy, err)
+			}
+			if !b.IsValidAddress(addr) {
+				t.Fatalf("address %v should be valid", addr)
+			}
+		}
+	}
+
+	invalids := []string{
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6b", // wrong checksum
+		"bchtest:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",     // wrong prefix
+		"bitcoincash:Qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a", // mixed case
+	}
+	for _, addr := range invalids {
+		if b.IsValidAddress(addr) {
+			t.Fatalf("address %v should be invalid", addr)
+		}
+	}
+
+	testnet := newTestBridge("testnet")
+	cashAddr, err := testnet.ToCashAddress("mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn")
+	if err != nil || cashAddr[:len("bchtest:")] != "bchtest:" {
+		t.Fatalf("convert testnet address to %v, err %v", cashAddr, err)
+	}
+}
+
+func TestSignWithSigHashForkID(t *testing.T) {
+	b := newTestBridge("mainnet")
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, _ := b.NewAddressPubKeyHash(privKey.PubKey().SerializeCompressed())
+	prevScript, _ := b.GetPayToAddrScript(address.EncodeAddress())
+	prevValue := btcutil.Amount(100000)
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(90000, prevScript))
+	authoredTx := &txauthor.AuthoredTx{
+		Tx:              tx,
+		PrevScripts:     [][]byte{prevScript},
+		PrevInputValues: []btcutil.Amount{prevValue},
+		TotalInput:      prevValue,
+		ChangeIndex:     -1,
+	}
+
+	_, _, err = b.SignTransactionWithPrivateKey(authoredTx, privKey.ToECDSA())
+	if err != nil {
+		t.Fatal(err)
+	}
+	pushes, err := txscript.PushedData(tx.TxIn[0].SignatureScript)
+	if err != nil || len(pushes) != 2 {
+		t.Fatalf("wrong signature script, err %v", err)
+	}
+	sigData := pushes[0]
+	hashType := txscript.SigHashAll | 0x40
+	if sigData[len(sigData)-1] != byte(hashType) {
+		t.Fatalf("wrong sighash type %x", sigData[len(sigData)-1])
+	}
+	signature, err := btcec.ParseDERSignature(sigData[:len(sigData)-1], btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sigHash, err := txscript.CalcWitnessSigHash(prevScript, txscript.NewTxSigHashes(tx), hashType, tx, 0, int64(prevValue))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !signature.Verify(sigHash, privKey.PubKey()) {
+		t.Fatal("verify SIGHASH_FORKID signature failed")
+	}
+}
+
+func TestBindMemo(t *testing.T) {
+	for _, bind := range []string{"0x" + strings.Repeat("ab", 20), strings.Repeat("a", 90)} {
+		memo := []byte(tokens.LockMemoPrefix + bind)
+		opcode := "OP_PUSHBYTES_" + strconv.Itoa(len(memo))
+		if len(memo) > txscript.OP_DATA_75 {
+			opcode = "OP_PUSHDATA1"
+		}
+		memoScript := "OP_RETURN " + opcode + " " + hex.EncodeToString(memo)
+		got, ok := btc.GetBindAddressFromMemoScipt(memoScript)
+		if !ok || got != bind {
+			t.Fatalf("parse memo %v got %v, ok %v", memoScript, got, ok)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package bch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// cashaddr spec: https://github.com/bitcoincashorg/bitcoincash.org/blob/master/spec/cashaddr.md
+const (
+	cashAddrCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+	cashAddrChecksumLen = 8
+
+	// version byte of 160 bits hash
+	cashAddrTypeP2PKH byte = 0 << 3
+	cashAddrTypeP2SH  byte = 1 << 3
+)
+
+var errInvalidCashAddr = errors.New("invalid cashaddr")
+
+func cashAddrPolyMod(values []byte) uint64 {
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = ((c & 0x07ffffffff) << 5) ^ uint64(d)
+		if c0&0x01 != 0 {
+			c ^= 0x98f2bc8e61
+		}
+		if c0&0x02 != 0 {
+			c ^= 0x79b76d99e2
+		}
+		if c0&0x04 != 0 {
+			c ^= 0xf33e5fb3c4
+		}
+		if c0&0x08 != 0 {
+			c ^= 0xae2eabe2a8
+		}
+		if c0&0x10 != 0 {
+			c ^= 0x1e4f43e470
+		}
+	}
+	return c ^ 1
+}
+
+// cashAddrChecksumInput lower 5 bits of prefix, a zero separator and the payload
+func cashAddrChecksumInput(prefix string, payload []byte) []byte {
+	values := make([]byte, 0, len(prefix)+1+len(payload)+cashAddrChecksumLen)
+	for i := 0; i < len(prefix); i++ {
+		values = append(values, prefix[i]&0x1f)
+	}
+	values = append(values, 0)
+	return append(values, payload...)
+}
+
+// encodeCashAddr encode hash160 with version byte to cashaddr with prefix
+func encodeCashAddr(prefix string, version byte, hash []byte) (string, error) {
+	payload, err := bech32.ConvertBits(append([]byte{version}, hash...), 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	values := cashAddrChecksumInput(prefix, payload)
+	values = append(values, make([]byte, cashAddrChecksumLen)...)
+	mod := cashAddrPolyMod(values)
+	for i := 0; i < cashAddrChecksumLen; i++ {
+		payload = append(payload, byte(mod>>(5*(cashAddrChecksumLen-1-i)))&0x1f)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteByte(':')
+	for _, v := range payload {
+		sb.WriteByte(cashAddrCharset[v])
+	}
+	return sb.String(), nil
+}
+
+// decodeCashAddr decode cashaddr (prefix is optional) to version byte and hash160
+func decodeCashAddr(addr, defaultPrefix string) (version byte, hash []byte, err error) {
+	if strings.ToLower(addr) != addr && strings.ToUpper(addr) != addr {
+		return 0, nil, errInvalidCashAddr
+	}
+	addr = strings.ToLower(addr)
+	prefix := defaultPrefix
+	if pos := strings.LastIndexByte(addr, ':'); pos >= 0 {
+		prefix, addr = addr[:pos], addr[pos+1:]
+		if prefix != defaultPrefix {
+			return 0, nil, fmt.Errorf("%w: prefix %v mismatch %v", errInvalidCashAddr, prefix, defaultPrefix)
+		}
+	}
+	if len(addr) <= cashAddrChecksumLen {
+		return 0, nil, errInvalidCashAddr
+	}
+	values := make([]byte, len(addr))
+	for i := 0; i < len(addr); i++ {
+		pos := strings.IndexByte(cashAddrCharset, addr[i])
+		if pos < 0 {
+			return 0, nil, errInvalidCashAddr
+		}
+		values[i] = byte(pos)
+	}
+	if cashAddrPolyMod(cashAddrChecksumInput(prefix, values)) != 0 {
+		return 0, nil, fmt.Errorf("%w: wrong checksum", errInvalidCashAddr)
+	}
+	data, err := bech32.ConvertBits(values[:len(values)-cashAddrChecksumLen], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) != 1+20 {
+		return 0, nil, fmt.Errorf("%w: only support 160 bits hash", errInvalidCashAddr)
+	}
+	version, hash = data[0], data[1:]
+	if version != cashAddrTypeP2PKH && version != cashAddrTypeP2SH {
+		return 0, nil, fmt.Errorf("%w: unknown version byte %v", errInvalidCashAddr, version)
+	}
+	return version, hash, nil
+}
+
+// cashAddrToLegacy convert cashaddr to legacy address
+func cashAddrToLegacy(addr, prefix string, params *chaincfg.Params) (string, error) {
+	version, hash, err := decodeCashAddr(addr, prefix)
+	if err != nil {
+		return "", err
+	}
+	var address btcutil.Address
+	if version == cashAddrTypeP2SH {
+		address, err = btcutil.NewAddressScriptHashFromHash(hash, params)
+	} else {
+		address, err = btcutil.NewAddressPubKeyHash(hash, params)
+	}
+	if err != nil {
+		return "", err
+	}
+	return address.EncodeAddress(), nil
+}
+
+// legacyToCashAddr convert legacy address to cashaddr
+func legacyToCashAddr(addr, prefix string, params *chaincfg.Params) (string, error) {
+	address, err := btcutil.DecodeAddress(addr, params)
+	if err != nil {
+		return "", err
+	}
+	if !address.IsForNet(params) {
+		return "", fmt.Errorf("invalid address for net %v", params.Name)
+	}
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return encodeCashAddr(prefix, cashAddrTypeP2PKH, address.ScriptAddress())
+	case *btcutil.AddressScriptHash:
+		return encodeCashAddr(prefix, cashAddrTypeP2SH, address.ScriptAddress())
+	default:
+		return "", fmt.Errorf("unsupported address type %T", address)
+	}
+}
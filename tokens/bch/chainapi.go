@@ -0,0 +1,88 @@
+package bch
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+// cashAddrAPI wrap chain api of bitcoin cash, addresses in request
+// are converted to cashaddr, and addresses in result to legacy encoding
+type cashAddrAPI struct {
+	btc.ChainAPI
+	b *Bridge
+}
+
+func (c *cashAddrAPI) toCashAddress(addr string) string {
+	if converted, err := c.b.ToCashAddress(addr); err == nil {
+		return converted
+	}
+	return addr
+}
+
+func (c *cashAddrAPI) toLegacyVout(vout *electrs.ElectTxOut) {
+	if vout == nil || vout.ScriptpubkeyAddress == nil {
+		return
+	}
+	if legacy, err := c.b.ToLegacyAddress(*vout.ScriptpubkeyAddress); err == nil {
+		*vout.ScriptpubkeyAddress = legacy
+	}
+}
+
+func (c *cashAddrAPI) toLegacyTx(tx *electrs.ElectTx) {
+	for _, vin := range tx.Vin {
+		c.toLegacyVout(vin.Prevout)
+	}
+	for _, vout := range tx.Vout {
+		c.toLegacyVout(vout)
+	}
+}
+
+func (c *cashAddrAPI) toLegacyTxs(txs []*electrs.ElectTx) {
+	for _, tx := range txs {
+		c.toLegacyTx(tx)
+	}
+}
+
+// WatchAddress impl btc.AddressWatcher
+func (c *cashAddrAPI) WatchAddress(addr string, rescan bool) error {
+	if watcher, ok := c.ChainAPI.(btc.AddressWatcher); ok {
+		return watcher.WatchAddress(c.toCashAddress(addr), rescan)
+	}
+	return nil
+}
+
+func (c *cashAddrAPI) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	result, err := c.ChainAPI.GetTransactionByHash(txHash)
+	if err == nil {
+		c.toLegacyTx(result)
+	}
+	return result, err
+}
+
+func (c *cashAddrAPI) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	return c.ChainAPI.FindUtxos(c.toCashAddress(addr))
+}
+
+func (c *cashAddrAPI) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	results, err := c.ChainAPI.GetPoolTransactions(c.toCashAddress(addr))
+	if err == nil {
+		c.toLegacyTxs(results)
+	}
+	return results, err
+}
+
+func (c *cashAddrAPI) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	results, err := c.ChainAPI.GetTransactionHistory(c.toCashAddress(addr), lastSeenTxid)
+	if err == nil {
+		c.toLegacyTxs(results)
+	}
+	return results, err
+}
+
+func (c *cashAddrAPI) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
+	results, err := c.ChainAPI.GetBlockTransactions(blockHash, startIndex)
+	if err == nil {
+		c.toLegacyTxs(results)
+	}
+	return results, err
+}
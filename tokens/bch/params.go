@@ -0,0 +1,38 @@
+package bch
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// presets chain params of bitcoin cash, keyed by network,
+// legacy address encoding is the same as bitcoin.
+var presets = map[string]*tokens.UtxoChainConfig{
+	"mainnet": {
+		Symbol:           "BCH",
+		PairID:           "bch",
+		NetMagic:         0xe8f3e1e3,
+		PubKeyHashAddrID: 0x00,
+		ScriptHashAddrID: 0x05,
+		PrivateKeyID:     0x80,
+		HDCoinType:       145,
+		SigHashForkID:    true,
+		DisableRBF:       true,
+	},
+	"testnet": {
+		Symbol:           "BCH",
+		PairID:           "bch",
+		NetMagic:         0xf4f3e5f4,
+		PubKeyHashAddrID: 0x6f,
+		ScriptHashAddrID: 0xc4,
+		PrivateKeyID:     0xef,
+		HDCoinType:       1,
+		SigHashForkID:    true,
+		DisableRBF:       true,
+	},
+}
+
+// cashAddrPrefixes cashaddr prefix of networks
+var cashAddrPrefixes = map[string]string{
+	"mainnet": "bitcoincash",
+	"testnet": "bchtest",
+}
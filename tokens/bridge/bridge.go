@@ -2,47 +2,32 @@ package bridge
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/dcrm"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
-	"github.com/anyswap/CrossChain-Bridge/tokens/block"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
-	"github.com/anyswap/CrossChain-Bridge/tokens/colx"
-	"github.com/anyswap/CrossChain-Bridge/tokens/etc"
-	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
-	"github.com/anyswap/CrossChain-Bridge/tokens/fsn"
-	"github.com/anyswap/CrossChain-Bridge/tokens/ltc"
-	"github.com/anyswap/CrossChain-Bridge/tokens/okex"
 	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+
+	// register in-tree chains
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/bch"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/cosmos"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/doge"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/etc"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/evm"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/fsn"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/okex"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/tron"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/utxo"
+	_ "github.com/anyswap/CrossChain-Bridge/tokens/xrp"
 )
 
-// NewCrossChainBridge new bridge according to chain name
-func NewCrossChainBridge(id string, isSrc bool) tokens.CrossChainBridge {
-	blockChainIden := strings.ToUpper(id)
-	switch {
-	case strings.HasPrefix(blockChainIden, "BITCOIN"):
-		return btc.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "LITECOIN"):
-		return ltc.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "BLOCK"):
-		return block.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "ETHCLASSIC"):
-		return etc.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "ETHEREUM"):
-		return eth.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "OKEX"):
-		return okex.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "FUSION"):
-		return fsn.NewCrossChainBridge(isSrc)
-	case strings.HasPrefix(blockChainIden, "COLOSSUS") || strings.HasPrefix(blockChainIden, "COLX"):
-		return colx.NewCrossChainBridge(isSrc)
-	default:
-		log.Fatalf("Unsupported block chain %v", id)
-		return nil
-	}
+// NewCrossChainBridge new bridge according to chain name,
+// chains are registered by 'tokens.RegisterBridgeFactory' from chain packages.
+func NewCrossChainBridge(id string, isSrc bool) (tokens.CrossChainBridge, error) {
+	return tokens.NewCrossChainBridge(id, isSrc)
 }
 
 // InitCrossChainBridge init bridge
@@ -60,8 +45,15 @@ func InitCrossChainBridge(isServer bool) {
 
 	tokens.AggregateIdentifier = fmt.Sprintf("%s:%s", params.GetIdentifier(), tokens.AggregateIdentifier)
 
-	tokens.SrcBridge = NewCrossChainBridge(srcID, true)
-	tokens.DstBridge = NewCrossChainBridge(dstID, false)
+	var err error
+	tokens.SrcBridge, err = NewCrossChainBridge(srcID, true)
+	if err != nil {
+		log.Fatal("new source bridge failed", "err", err)
+	}
+	tokens.DstBridge, err = NewCrossChainBridge(dstID, false)
+	if err != nil {
+		log.Fatal("new dest bridge failed", "err", err)
+	}
 	log.Info("New bridge finished", "source", srcID, "sourceNet", srcNet, "dest", dstID, "destNet", dstNet)
 
 	if !params.IsSwapServer && params.ServerAPIAddress == "" && btc.BridgeInstance != nil {
@@ -93,17 +85,8 @@ func InitCrossChainBridge(isServer bool) {
 	tokens.IsDcrmDisabled = cfg.Dcrm.Disable
 	tokens.LoadTokenPairsConfig(true)
 
-	BlockChain := strings.ToUpper(srcChain.BlockChain)
-	switch BlockChain {
-	case "BITCOIN":
-		btc.Init(cfg.BtcExtra)
-	case "LITECOIN":
-		ltc.Init(cfg.BtcExtra)
-	case "BLOCK":
-		block.Init(cfg.BtcExtra)
-	case "COLX":
-		colx.Init(cfg.BtcExtra)
-	}
+	// bitcoin and utxo chains derived from it, no-op for other chains
+	btc.Init(cfg.BtcExtra)
 
 	dcrm.Init(cfg.Dcrm, isServer)
 
@@ -1,6 +1,7 @@
 package btc
 
 import (
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/btcsuite/btcutil"
@@ -8,6 +9,11 @@ import (
 
 // DecodeAddress decode address
 func (b *Bridge) DecodeAddress(addr string) (address btcutil.Address, err error) {
+	if converter, ok := b.Inherit.(AddressConverter); ok {
+		if legacy, errc := converter.ToLegacyAddress(addr); errc == nil {
+			addr = legacy
+		}
+	}
 	chainConfig := b.Inherit.GetChainParams()
 	address, err = btcutil.DecodeAddress(addr, chainConfig)
 	if err != nil {
@@ -30,6 +36,17 @@ func (b *Bridge) NewAddressScriptHash(redeemScript []byte) (*btcutil.AddressScri
 	return btcutil.NewAddressScriptHash(redeemScript, b.Inherit.GetChainParams())
 }
 
+// NewAddressWitnessPubKeyHash encap
+func (b *Bridge) NewAddressWitnessPubKeyHash(pkData []byte) (*btcutil.AddressWitnessPubKeyHash, error) {
+	return btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(pkData), b.Inherit.GetChainParams())
+}
+
+// NewAddressWitnessScriptHash encap
+func (b *Bridge) NewAddressWitnessScriptHash(witnessScript []byte) (*btcutil.AddressWitnessScriptHash, error) {
+	scriptHash := sha256.Sum256(witnessScript)
+	return btcutil.NewAddressWitnessScriptHash(scriptHash[:], b.Inherit.GetChainParams())
+}
+
 // IsValidAddress check address
 func (b *Bridge) IsValidAddress(addr string) bool {
 	_, err := b.DecodeAddress(addr)
@@ -56,6 +73,26 @@ func (b *Bridge) IsP2shAddress(addr string) bool {
 	return ok
 }
 
+// IsP2wpkhAddress check p2wpkh (native segwit) addrss
+func (b *Bridge) IsP2wpkhAddress(addr string) bool {
+	address, err := b.DecodeAddress(addr)
+	if err != nil {
+		return false
+	}
+	_, ok := address.(*btcutil.AddressWitnessPubKeyHash)
+	return ok
+}
+
+// IsP2wshAddress check p2wsh (native segwit) addrss
+func (b *Bridge) IsP2wshAddress(addr string) bool {
+	address, err := b.DecodeAddress(addr)
+	if err != nil {
+		return false
+	}
+	_, ok := address.(*btcutil.AddressWitnessScriptHash)
+	return ok
+}
+
 // DecodeWIF decode wif
 func DecodeWIF(wif string) (*btcutil.WIF, error) {
 	return btcutil.DecodeWIF(wif)
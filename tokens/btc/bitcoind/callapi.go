@@ -0,0 +1,311 @@
+package bitcoind
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+const (
+	// same page size as electrs `/address/{addr}/txs/chain`
+	txHistoryPageSize = 25
+
+	maxListTransactions = 10000
+	maxConfirmations    = 9999999
+)
+
+// GetLatestBlockNumberOf call getblockcount of specified api address
+func (c *Client) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	for _, cli := range c.clients {
+		if cli.Address == apiAddress {
+			number, err := cli.GetBlockCount()
+			return uint64(number), err
+		}
+	}
+	return 0, fmt.Errorf("bitcoin core api address %v not configed", apiAddress)
+}
+
+// GetLatestBlockNumber call getblockcount
+func (c *Client) GetLatestBlockNumber() (number uint64, err error) {
+	err = c.call(&number, "getblockcount")
+	return number, err
+}
+
+func (c *Client) getRawTransaction(txHash string) (*rawTxResult, error) {
+	var result rawTxResult
+	err := c.call(&result, "getrawtransaction", txHash, true)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (c *Client) getBlockHeader(blockHash string) (*blockHeaderResult, error) {
+	var result blockHeaderResult
+	err := c.call(&result, "getblockheader", blockHash, true)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTransactionByHash call getrawtransaction and fill prevouts of inputs
+func (c *Client) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	tx, err := c.getRawTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	etx := c.convertTx(tx)
+	prevTxs := make(map[string]*rawTxResult)
+	for _, vin := range etx.Vin {
+		if *vin.IsCoinbase {
+			continue
+		}
+		prevTx, exist := prevTxs[*vin.Txid]
+		if !exist {
+			prevTx, err = c.getRawTransaction(*vin.Txid)
+			if err != nil {
+				return nil, err
+			}
+			prevTxs[*vin.Txid] = prevTx
+		}
+		if int(*vin.Vout) >= len(prevTx.Vout) {
+			return nil, fmt.Errorf("prevout %v:%v not found", *vin.Txid, *vin.Vout)
+		}
+		vin.Prevout = c.convertVout(&prevTx.Vout[*vin.Vout])
+	}
+	return etx, nil
+}
+
+// GetElectTransactionStatus call getrawtransaction
+func (c *Client) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	tx, err := c.getRawTransaction(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return c.convertTxStatus(tx), nil
+}
+
+// FindUtxos call listunspent of watched address
+func (c *Client) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	err := c.WatchAddress(addr, false)
+	if err != nil {
+		return nil, err
+	}
+	var unspents []*unspentResult
+	err = c.call(&unspents, "listunspent", 0, maxConfirmations, []string{addr})
+	if err != nil {
+		return nil, err
+	}
+	latest, err := c.GetLatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+	utxos := make([]*electrs.ElectUtxo, 0, len(unspents))
+	for _, unspent := range unspents {
+		utxo := &electrs.ElectUtxo{
+			Txid:   new(string),
+			Vout:   new(uint32),
+			Value:  new(uint64),
+			Status: &electrs.ElectTxStatus{Confirmed: new(bool)},
+		}
+		*utxo.Txid = unspent.Txid
+		*utxo.Vout = unspent.Vout
+		*utxo.Value = convertAmount(unspent.Amount)
+		if unspent.Confirmations > 0 {
+			*utxo.Status.Confirmed = true
+			utxo.Status.BlockHeight = new(uint64)
+			*utxo.Status.BlockHeight = latest + 1 - unspent.Confirmations
+		}
+		utxos = append(utxos, utxo)
+	}
+	sort.Sort(electrs.SortableElectUtxoSlice(utxos))
+	return utxos, nil
+}
+
+// GetPoolTxidList call getrawmempool
+func (c *Client) GetPoolTxidList() (txids []string, err error) {
+	err = c.call(&txids, "getrawmempool")
+	return txids, err
+}
+
+// listAddressTxids list wallet txids labeled with address, from oldest to newest
+func (c *Client) listAddressTxids(addr string, confirmed bool) ([]string, error) {
+	err := c.WatchAddress(addr, false)
+	if err != nil {
+		return nil, err
+	}
+	var walletTxs []*walletTxResult
+	err = c.call(&walletTxs, "listtransactions", addr, maxListTransactions, 0, true)
+	if err != nil {
+		return nil, err
+	}
+	txids := make([]string, 0, len(walletTxs))
+	exist := make(map[string]struct{}, len(walletTxs))
+	for _, wtx := range walletTxs {
+		if (wtx.Confirmations > 0) != confirmed {
+			continue
+		}
+		if _, ok := exist[wtx.Txid]; ok {
+			continue
+		}
+		exist[wtx.Txid] = struct{}{}
+		txids = append(txids, wtx.Txid)
+	}
+	return txids, nil
+}
+
+// GetPoolTransactions list unconfirmed wallet txs of address
+func (c *Client) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	txids, err := c.listAddressTxids(addr, false)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*electrs.ElectTx, 0, len(txids))
+	for _, txid := range txids {
+		tx, err := c.GetTransactionByHash(txid)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// GetTransactionHistory list confirmed wallet txs of address,
+// newest first and after lastSeenTxid (same as electrs)
+func (c *Client) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	txids, err := c.listAddressTxids(addr, true)
+	if err != nil {
+		return nil, err
+	}
+	end := len(txids)
+	if lastSeenTxid != "" {
+		end = -1
+		for i, txid := range txids {
+			if txid == lastSeenTxid {
+				end = i
+				break
+			}
+		}
+		if end < 0 {
+			return nil, fmt.Errorf("last seen txid %v not found", lastSeenTxid)
+		}
+	}
+	txs := make([]*electrs.ElectTx, 0, txHistoryPageSize)
+	for i := end - 1; i >= 0 && len(txs) < txHistoryPageSize; i-- {
+		tx, err := c.GetTransactionByHash(txids[i])
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// GetOutspend call gettxout, only tells whether the output is spent
+func (c *Client) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	var txout *txOutResult
+	err := c.call(&txout, "gettxout", txHash, vout, true)
+	if err != nil {
+		return nil, err
+	}
+	outspend := &electrs.ElectOutspend{Spent: new(bool)}
+	*outspend.Spent = txout == nil
+	return outspend, nil
+}
+
+// PostTransaction call sendrawtransaction to all clients
+func (c *Client) PostTransaction(txHex string) (txHash string, err error) {
+	rawTx, err := json.Marshal(txHex)
+	if err != nil {
+		return "", err
+	}
+	errs := make([]error, 0, len(c.clients))
+	for _, cli := range c.clients {
+		hash, err0 := cli.RawRequest("sendrawtransaction", []json.RawMessage{rawTx})
+		if err0 != nil {
+			errs = append(errs, err0)
+			continue
+		}
+		if txHash == "" {
+			_ = json.Unmarshal(hash, &txHash)
+		}
+	}
+	if txHash != "" {
+		return txHash, nil
+	}
+	if len(errs) == 0 {
+		return "", errNoCoreClient
+	}
+	return "", fmt.Errorf("call sendrawtransaction failed: %+v", errs)
+}
+
+// GetBlockHash call getblockhash
+func (c *Client) GetBlockHash(height uint64) (hash string, err error) {
+	err = c.call(&hash, "getblockhash", height)
+	return hash, err
+}
+
+func (c *Client) getBlock(blockHash string) (*blockResult, error) {
+	var result blockResult
+	err := c.call(&result, "getblock", blockHash, 1)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlockTxids call getblock
+func (c *Client) GetBlockTxids(blockHash string) ([]string, error) {
+	block, err := c.getBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return block.Tx, nil
+}
+
+// GetBlock call getblock
+func (c *Client) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
+	block, err := c.getBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return convertBlock(block), nil
+}
+
+// GetBlockTransactions get block txs from startIndex (same page size as electrs)
+func (c *Client) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
+	block, err := c.getBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*electrs.ElectTx, 0, txHistoryPageSize)
+	for i := int(startIndex); i < len(block.Tx) && len(txs) < txHistoryPageSize; i++ {
+		tx, err := c.GetTransactionByHash(block.Tx[i])
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, tx)
+	}
+	return txs, nil
+}
+
+// EstimateFeePerKb call estimatesmartfee
+func (c *Client) EstimateFeePerKb(blocks int) (int64, error) {
+	var result estimateFeeResult
+	err := c.call(&result, "estimatesmartfee", blocks)
+	if err != nil {
+		return 0, err
+	}
+	if len(result.Errors) > 0 {
+		return 0, fmt.Errorf("estimatesmartfee error: %v", result.Errors)
+	}
+	if result.FeeRate == nil {
+		return 0, errors.New("estimatesmartfee returns no fee rate")
+	}
+	return int64(convertAmount(*result.FeeRate)), nil
+}
@@ -0,0 +1,220 @@
+// Package bitcoind implements btc chain api on bitcoin core json-rpc.
+//
+// Bitcoin core only indexes transactions of wallet addresses,
+// so the bridge addresses must be imported to a watch-only wallet,
+// and `txindex=1` is required to query transactions of other addresses.
+package bitcoind
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/rpcclient"
+)
+
+var errNoCoreClient = errors.New("no bitcoin core client")
+
+// coreClient wraps btcd rpcclient with its address
+type coreClient struct {
+	*rpcclient.Client
+	Address string
+}
+
+// Client bitcoin core json-rpc client
+type Client struct {
+	clients         []*coreClient
+	params          *chaincfg.Params
+	importTimestamp int64
+
+	watchedAddrs map[string]struct{}
+	watchLock    sync.Mutex
+}
+
+// NewClient new bitcoin core client
+func NewClient(args *tokens.BtcCoreExtraArgs, params *chaincfg.Params) (*Client, error) {
+	if args == nil || len(args.CoreAPIs) == 0 {
+		return nil, errNoCoreClient
+	}
+	c := &Client{
+		params:          params,
+		importTimestamp: args.ImportTimestamp,
+		watchedAddrs:    make(map[string]struct{}),
+	}
+	if args.ImportTimestamp >= 0 {
+		log.Warn("bitcoind import dcrm and deposit addresses with rescan, it may take a long time when importing the first time", "importTimestamp", args.ImportTimestamp)
+	}
+	for _, api := range args.CoreAPIs {
+		connCfg := &rpcclient.ConnConfig{
+			Host:         api.APIAddress,
+			User:         api.RPCUser,
+			Pass:         api.RPCPassword,
+			HTTPPostMode: true,           // Bitcoin core only supports HTTP POST mode
+			DisableTLS:   api.DisableTLS, // Bitcoin core does not provide TLS by default
+		}
+		client, err := rpcclient.New(connCfg, nil)
+		if err != nil {
+			return nil, fmt.Errorf("new bitcoin core client %v failed: %w", api.APIAddress, err)
+		}
+		c.clients = append(c.clients, &coreClient{Client: client, Address: api.APIAddress})
+	}
+	return c, nil
+}
+
+// call json-rpc on each client until success
+func (c *Client) call(result interface{}, method string, params ...interface{}) error {
+	errs := make([]error, 0, len(c.clients))
+	for _, cli := range c.clients {
+		err := cli.call(result, method, params...)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return errNoCoreClient
+	}
+	return fmt.Errorf("call %v failed: %+v", method, errs)
+}
+
+func (cli *coreClient) call(result interface{}, method string, params ...interface{}) error {
+	rawParams := make([]json.RawMessage, 0, len(params))
+	for _, param := range params {
+		data, err := json.Marshal(param)
+		if err != nil {
+			return err
+		}
+		rawParams = append(rawParams, data)
+	}
+	res, err := cli.RawRequest(method, rawParams)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return nil
+	}
+	return json.Unmarshal(res, result)
+}
+
+// WatchAddress import address to watch-only wallet of every client,
+// as queries are served by any of them. impl btc.AddressWatcher
+// addresses already in wallet are skipped, and only rescan if 'rescan' is true.
+func (c *Client) WatchAddress(addr string, rescan bool) error {
+	c.watchLock.Lock()
+	defer c.watchLock.Unlock()
+
+	timestamp := c.getImportTimestamp(rescan)
+	var errs []error
+	for _, cli := range c.clients {
+		watchKey := cli.Address + ":" + addr
+		if _, exist := c.watchedAddrs[watchKey]; exist {
+			continue
+		}
+		watched, err := isAddressInWallet(cli, addr)
+		if err == nil && !watched {
+			err = importAddress(cli, addr, timestamp)
+			if err == nil {
+				log.Info("bitcoind import address success", "client", cli.Address, "address", addr, "timestamp", timestamp)
+			}
+		}
+		if err != nil {
+			log.Warn("bitcoind import address failed", "client", cli.Address, "address", addr, "err", err)
+			errs = append(errs, err)
+			continue
+		}
+		c.watchedAddrs[watchKey] = struct{}{}
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("import address %v failed: %+v", addr, errs)
+	}
+	return nil
+}
+
+// isAddressInWallet whether address is imported to wallet (eg. by previous runs)
+func isAddressInWallet(cli *coreClient, addr string) (bool, error) {
+	var info addressInfoResult
+	err := cli.call(&info, "getaddressinfo", addr)
+	if err != nil {
+		return false, err
+	}
+	return info.IsMine || info.IsWatchOnly, nil
+}
+
+func importAddress(cli *coreClient, addr string, timestamp interface{}) error {
+	err := importDescriptor(cli, addr, timestamp)
+	if isLegacyWalletError(err) {
+		err = importMulti(cli, addr, timestamp)
+	}
+	return err
+}
+
+// getImportTimestamp rescan from the configed time,
+// 0 means rescan the whole chain, negative or no rescan means 'now'.
+func (c *Client) getImportTimestamp(rescan bool) interface{} {
+	if !rescan || c.importTimestamp < 0 {
+		return "now"
+	}
+	return c.importTimestamp
+}
+
+func importDescriptor(cli *coreClient, addr string, timestamp interface{}) error {
+	var info descriptorInfoResult
+	err := cli.call(&info, "getdescriptorinfo", fmt.Sprintf("addr(%v)", addr))
+	if err != nil {
+		return err
+	}
+	request := map[string]interface{}{
+		"desc":      fmt.Sprintf("addr(%v)#%v", addr, info.Checksum),
+		"timestamp": timestamp,
+		"label":     addr,
+	}
+	var results []importResult
+	err = cli.call(&results, "importdescriptors", []interface{}{request})
+	if err != nil {
+		return err
+	}
+	return checkImportResults(results)
+}
+
+func importMulti(cli *coreClient, addr string, timestamp interface{}) error {
+	request := map[string]interface{}{
+		"scriptPubKey": map[string]string{"address": addr},
+		"timestamp":    timestamp,
+		"label":        addr,
+		"watchonly":    true,
+	}
+	var results []importResult
+	err := cli.call(&results, "importmulti", []interface{}{request})
+	if err != nil {
+		return err
+	}
+	return checkImportResults(results)
+}
+
+func checkImportResults(results []importResult) error {
+	if len(results) != 1 {
+		return fmt.Errorf("wrong import results count %v", len(results))
+	}
+	if !results[0].Success {
+		if results[0].Error != nil {
+			return fmt.Errorf("import failed: %v", results[0].Error.Message)
+		}
+		return errors.New("import failed")
+	}
+	return nil
+}
+
+// importdescriptors is only available for descriptor wallets
+func isLegacyWalletError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "Method not found") ||
+		strings.Contains(errMsg, "descriptor wallet")
+}
@@ -0,0 +1,174 @@
+package bitcoind
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// testCoreNode fake bitcoin core wallet, records import requests
+type testCoreNode struct {
+	*httptest.Server
+	legacy bool // legacy wallet does not support importdescriptors
+	fail   bool
+
+	lock    sync.Mutex
+	imports []map[string]interface{}
+	wallet  map[string]bool // imported addresses
+}
+
+func newTestCoreNode(legacy bool) *testCoreNode {
+	node := &testCoreNode{legacy: legacy, wallet: make(map[string]bool)}
+	node.Server = httptest.NewServer(http.HandlerFunc(node.serve))
+	return node
+}
+
+func (node *testCoreNode) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID     interface{}       `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&req)
+
+	var result interface{}
+	var rpcErr interface{}
+	node.lock.Lock()
+	switch {
+	case node.fail:
+		rpcErr = map[string]interface{}{"code": -28, "message": "Loading wallet..."}
+	case req.Method == "getaddressinfo":
+		var addr string
+		_ = json.Unmarshal(req.Params[0], &addr)
+		result = map[string]interface{}{"address": addr, "ismine": false, "iswatchonly": node.wallet[addr]}
+	case req.Method == "getdescriptorinfo":
+		result = map[string]interface{}{"checksum": "t5ae7p3u"}
+	case req.Method == "importdescriptors" && node.legacy:
+		rpcErr = map[string]interface{}{"code": -4, "message": "Only descriptor wallets support this RPC call"}
+	case req.Method == "importdescriptors", req.Method == "importmulti":
+		var requests []map[string]interface{}
+		_ = json.Unmarshal(req.Params[0], &requests)
+		node.imports = append(node.imports, requests...)
+		for _, request := range requests {
+			if label, ok := request["label"].(string); ok {
+				node.wallet[label] = true
+			}
+		}
+		result = []map[string]interface{}{{"success": true}}
+	default:
+		rpcErr = map[string]interface{}{"code": -32601, "message": "Method not found"}
+	}
+	node.lock.Unlock()
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": req.ID, "result": result, "error": rpcErr})
+}
+
+func (node *testCoreNode) getImports() []map[string]interface{} {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	return node.imports
+}
+
+func (node *testCoreNode) setFail(fail bool) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	node.fail = fail
+}
+
+func newTestClient(t *testing.T, importTimestamp int64, nodes ...*testCoreNode) *Client {
+	args := &tokens.BtcCoreExtraArgs{ImportTimestamp: importTimestamp}
+	for _, node := range nodes {
+		args.CoreAPIs = append(args.CoreAPIs, tokens.BlocknetCoreAPIArgs{
+			APIAddress:  strings.TrimPrefix(node.URL, "http://"),
+			RPCUser:     "user",
+			RPCPassword: "password",
+			DisableTLS:  true,
+		})
+	}
+	client, err := NewClient(args, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func TestWatchAddressOnEveryNode(t *testing.T) {
+	descNode, legacyNode := newTestCoreNode(false), newTestCoreNode(true)
+	defer descNode.Close()
+	defer legacyNode.Close()
+
+	addr := "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"
+	client := newTestClient(t, 0, descNode, legacyNode)
+
+	legacyNode.setFail(true)
+	if err := client.WatchAddress(addr, true); err == nil {
+		t.Fatal("watch address should fail if any node fails")
+	}
+	if imports := descNode.getImports(); len(imports) != 1 || imports[0]["desc"] != "addr("+addr+")#t5ae7p3u" {
+		t.Fatalf("wrong descriptor import %v", imports)
+	}
+
+	// retry imports to the failed node only
+	legacyNode.setFail(false)
+	if err := client.WatchAddress(addr, true); err != nil {
+		t.Fatalf("watch address failed: %v", err)
+	}
+	if err := client.WatchAddress(addr, true); err != nil {
+		t.Fatalf("watch watched address failed: %v", err)
+	}
+	if imports := descNode.getImports(); len(imports) != 1 {
+		t.Fatalf("address is imported %v times to descriptor wallet", len(imports))
+	}
+	imports := legacyNode.getImports()
+	if len(imports) != 1 || imports[0]["watchonly"] != true {
+		t.Fatalf("wrong legacy import %v", imports)
+	}
+	// full rescan by default
+	if timestamp, ok := imports[0]["timestamp"].(float64); !ok || timestamp != 0 {
+		t.Fatalf("default import should rescan whole chain, have timestamp %v", imports[0]["timestamp"])
+	}
+}
+
+func TestImportTimestamp(t *testing.T) {
+	node := newTestCoreNode(false)
+	defer node.Close()
+
+	_ = newTestClient(t, 1600000000, node).WatchAddress("mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn", true)
+	_ = newTestClient(t, -1, node).WatchAddress("mkHS9ne12qx9pS9VojpwU5xtRd4T7X7ZUt", true)
+	_ = newTestClient(t, 0, node).WatchAddress("n2eMqTT929pb1RDNuqEnxdaLau1rxy3efi", false)
+	imports := node.getImports()
+	if len(imports) != 3 {
+		t.Fatalf("wrong imports count %v", len(imports))
+	}
+	if timestamp, ok := imports[0]["timestamp"].(float64); !ok || timestamp != 1600000000 {
+		t.Fatalf("wrong import timestamp %v", imports[0]["timestamp"])
+	}
+	if imports[1]["timestamp"] != "now" {
+		t.Fatalf("negative import timestamp should not rescan, have %v", imports[1]["timestamp"])
+	}
+	if imports[2]["timestamp"] != "now" {
+		t.Fatalf("import without rescan should use 'now', have %v", imports[2]["timestamp"])
+	}
+}
+
+func TestSkipAddressInWallet(t *testing.T) {
+	node := newTestCoreNode(false)
+	defer node.Close()
+
+	addr := "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"
+	if err := newTestClient(t, 0, node).WatchAddress(addr, true); err != nil {
+		t.Fatalf("watch address failed: %v", err)
+	}
+	// new client of restarted bridge does not import and rescan again
+	if err := newTestClient(t, 0, node).WatchAddress(addr, true); err != nil {
+		t.Fatalf("watch address in wallet failed: %v", err)
+	}
+	if imports := node.getImports(); len(imports) != 1 {
+		t.Fatalf("address in wallet is imported again, imports count %v", len(imports))
+	}
+}
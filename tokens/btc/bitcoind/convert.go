@@ -0,0 +1,199 @@
+package bitcoind
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// script types of bitcoin core mapping to electrs
+var scriptTypes = map[string]string{
+	"pubkey":                "p2pk",
+	"pubkeyhash":            "p2pkh",
+	"scripthash":            "p2sh",
+	"witness_v0_keyhash":    "v0_p2wpkh",
+	"witness_v0_scripthash": "v0_p2wsh",
+	"witness_v1_taproot":    "v1_p2tr",
+	"multisig":              "multisig",
+	"nulldata":              "op_return",
+}
+
+func convertAmount(value float64) uint64 {
+	amount, err := btcutil.NewAmount(value)
+	if err != nil || amount < 0 {
+		return 0
+	}
+	return uint64(amount)
+}
+
+func (c *Client) convertTx(tx *rawTxResult) *electrs.ElectTx {
+	etx := &electrs.ElectTx{
+		Txid:     new(string),
+		Version:  new(uint32),
+		Locktime: new(uint32),
+		Size:     new(uint32),
+		Weight:   new(uint32),
+		Vin:      make([]*electrs.ElectTxin, 0, len(tx.Vin)),
+		Vout:     make([]*electrs.ElectTxOut, 0, len(tx.Vout)),
+		Status:   c.convertTxStatus(tx),
+	}
+	*etx.Txid = tx.Txid
+	*etx.Version = uint32(tx.Version)
+	*etx.Locktime = tx.LockTime
+	*etx.Size = tx.Size
+	*etx.Weight = tx.Weight
+	for i := range tx.Vin {
+		etx.Vin = append(etx.Vin, convertVin(&tx.Vin[i]))
+	}
+	for i := range tx.Vout {
+		etx.Vout = append(etx.Vout, c.convertVout(&tx.Vout[i]))
+	}
+	return etx
+}
+
+// convertTxStatus confirmed means packed in block (same as electrs)
+func (c *Client) convertTxStatus(tx *rawTxResult) *electrs.ElectTxStatus {
+	status := &electrs.ElectTxStatus{
+		Confirmed: new(bool),
+	}
+	if tx.Confirmations == 0 || tx.BlockHash == "" {
+		return status
+	}
+	*status.Confirmed = true
+	status.BlockHash = new(string)
+	*status.BlockHash = tx.BlockHash
+	status.BlockTime = new(uint64)
+	*status.BlockTime = tx.BlockTime
+	if header, err := c.getBlockHeader(tx.BlockHash); err == nil {
+		status.BlockHeight = new(uint64)
+		*status.BlockHeight = header.Height
+	}
+	return status
+}
+
+func convertVin(vin *rawVin) *electrs.ElectTxin {
+	evin := &electrs.ElectTxin{
+		Txid:         new(string),
+		Vout:         new(uint32),
+		Scriptsig:    new(string),
+		ScriptsigAsm: new(string),
+		IsCoinbase:   new(bool),
+		Sequence:     new(uint32),
+	}
+	*evin.Txid = vin.Txid
+	*evin.Vout = vin.Vout
+	*evin.Sequence = vin.Sequence
+	*evin.IsCoinbase = vin.Coinbase != ""
+	if vin.ScriptSig != nil {
+		*evin.Scriptsig = vin.ScriptSig.Hex
+		*evin.ScriptsigAsm = vin.ScriptSig.Asm
+	}
+	return evin
+}
+
+func (c *Client) convertVout(vout *rawVout) *electrs.ElectTxOut {
+	spk := &vout.ScriptPubKey
+	evout := &electrs.ElectTxOut{
+		Scriptpubkey:        new(string),
+		ScriptpubkeyAsm:     new(string),
+		ScriptpubkeyType:    new(string),
+		ScriptpubkeyAddress: new(string),
+		Value:               new(uint64),
+	}
+	*evout.Scriptpubkey = spk.Hex
+	*evout.ScriptpubkeyAsm = spk.Asm
+	*evout.ScriptpubkeyType = spk.Type
+	if typ, exist := scriptTypes[spk.Type]; exist {
+		*evout.ScriptpubkeyType = typ
+	}
+	if spk.Type == "nulldata" {
+		*evout.ScriptpubkeyAsm = convertNullDataAsm(spk.Hex, spk.Asm)
+	}
+	*evout.ScriptpubkeyAddress = c.getScriptAddress(spk)
+	*evout.Value = convertAmount(vout.Value)
+	return evout
+}
+
+func (c *Client) getScriptAddress(spk *scriptPubKeyRes) string {
+	switch {
+	case spk.Address != "":
+		return spk.Address
+	case len(spk.Addresses) == 1:
+		return spk.Addresses[0]
+	case len(spk.Addresses) > 1:
+		return fmt.Sprintf("%+v", spk.Addresses)
+	}
+	script, err := hex.DecodeString(spk.Hex)
+	if err != nil {
+		return ""
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, c.params)
+	if err != nil || len(addrs) != 1 {
+		return ""
+	}
+	return addrs[0].EncodeAddress()
+}
+
+// convertNullDataAsm convert to electrs format, eg.
+// `OP_RETURN 5357...` to `OP_RETURN OP_PUSHBYTES_4 5357...`
+func convertNullDataAsm(scriptHex, asm string) string {
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return asm
+	}
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		return asm
+	}
+	var sb strings.Builder
+	sb.WriteString("OP_RETURN")
+	for _, data := range pushes {
+		switch size := len(data); {
+		case size <= txscript.OP_DATA_75:
+			sb.WriteString(" OP_PUSHBYTES_" + strconv.Itoa(size))
+		case size <= 0xff:
+			sb.WriteString(" OP_PUSHDATA1")
+		default:
+			sb.WriteString(" OP_PUSHDATA2")
+		}
+		sb.WriteString(" " + hex.EncodeToString(data))
+	}
+	return sb.String()
+}
+
+func convertBlock(blk *blockResult) *electrs.ElectBlock {
+	eblk := &electrs.ElectBlock{
+		Hash:         new(string),
+		Height:       new(uint32),
+		Version:      new(uint32),
+		Timestamp:    new(uint32),
+		TxCount:      new(uint32),
+		Size:         new(uint32),
+		Weight:       new(uint32),
+		MerkleRoot:   new(string),
+		PreviousHash: new(string),
+		Nonce:        new(uint32),
+		Bits:         new(uint32),
+		Difficulty:   new(uint64),
+	}
+	*eblk.Hash = blk.Hash
+	*eblk.Height = blk.Height
+	*eblk.Version = blk.Version
+	*eblk.Timestamp = blk.Time
+	*eblk.TxCount = blk.NTx
+	*eblk.Size = blk.Size
+	*eblk.Weight = blk.Weight
+	*eblk.MerkleRoot = blk.MerkleRoot
+	*eblk.PreviousHash = blk.PreviousHash
+	*eblk.Nonce = blk.Nonce
+	if bits, err := strconv.ParseUint(blk.Bits, 16, 32); err == nil {
+		*eblk.Bits = uint32(bits)
+	}
+	*eblk.Difficulty = uint64(blk.Difficulty)
+	return eblk
+}
@@ -0,0 +1,20 @@
+package bitcoind
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+)
+
+func TestConvertNullDataAsm(t *testing.T) {
+	memo := []byte("SWAPTO:0x1111111111111111111111111111111111111111")
+	script, err := txscript.NullDataScript(memo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "OP_RETURN OP_PUSHBYTES_49 " + hex.EncodeToString(memo)
+	if asm := convertNullDataAsm(hex.EncodeToString(script), ""); asm != want {
+		t.Fatalf("convert null data asm mismatch, have %v want %v", asm, want)
+	}
+}
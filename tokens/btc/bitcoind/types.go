@@ -0,0 +1,118 @@
+package bitcoind
+
+// rawTxResult result of `getrawtransaction txid true`
+type rawTxResult struct {
+	Txid          string    `json:"txid"`
+	Version       int32     `json:"version"`
+	Size          uint32    `json:"size"`
+	Weight        uint32    `json:"weight"`
+	LockTime      uint32    `json:"locktime"`
+	Vin           []rawVin  `json:"vin"`
+	Vout          []rawVout `json:"vout"`
+	BlockHash     string    `json:"blockhash,omitempty"`
+	Confirmations uint64    `json:"confirmations,omitempty"`
+	BlockTime     uint64    `json:"blocktime,omitempty"`
+}
+
+type rawVin struct {
+	Coinbase  string     `json:"coinbase,omitempty"`
+	Txid      string     `json:"txid,omitempty"`
+	Vout      uint32     `json:"vout"`
+	ScriptSig *scriptSig `json:"scriptSig,omitempty"`
+	Sequence  uint32     `json:"sequence"`
+}
+
+type scriptSig struct {
+	Asm string `json:"asm"`
+	Hex string `json:"hex"`
+}
+
+type rawVout struct {
+	Value        float64         `json:"value"`
+	N            uint32          `json:"n"`
+	ScriptPubKey scriptPubKeyRes `json:"scriptPubKey"`
+}
+
+// scriptPubKeyRes bitcoin core >= 22.0 returns `address`, older returns `addresses`
+type scriptPubKeyRes struct {
+	Asm       string   `json:"asm"`
+	Hex       string   `json:"hex"`
+	Type      string   `json:"type"`
+	Address   string   `json:"address,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+}
+
+// blockResult result of `getblock hash 1`
+type blockResult struct {
+	Hash         string   `json:"hash"`
+	Height       uint32   `json:"height"`
+	Version      uint32   `json:"version"`
+	MerkleRoot   string   `json:"merkleroot"`
+	Time         uint32   `json:"time"`
+	Nonce        uint32   `json:"nonce"`
+	Bits         string   `json:"bits"`
+	Difficulty   float64  `json:"difficulty"`
+	PreviousHash string   `json:"previousblockhash"`
+	Size         uint32   `json:"size"`
+	Weight       uint32   `json:"weight"`
+	NTx          uint32   `json:"nTx"`
+	Tx           []string `json:"tx"`
+}
+
+// blockHeaderResult result of `getblockheader hash true`
+type blockHeaderResult struct {
+	Hash   string `json:"hash"`
+	Height uint64 `json:"height"`
+}
+
+// unspentResult item of `listunspent` result
+type unspentResult struct {
+	Txid          string  `json:"txid"`
+	Vout          uint32  `json:"vout"`
+	Address       string  `json:"address"`
+	Amount        float64 `json:"amount"`
+	Confirmations uint64  `json:"confirmations"`
+}
+
+// walletTxResult item of `listtransactions` result
+type walletTxResult struct {
+	Address       string `json:"address"`
+	Category      string `json:"category"`
+	Txid          string `json:"txid"`
+	Confirmations int64  `json:"confirmations"`
+}
+
+// txOutResult result of `gettxout txid n true`
+type txOutResult struct {
+	BestBlock     string  `json:"bestblock"`
+	Confirmations uint64  `json:"confirmations"`
+	Value         float64 `json:"value"`
+}
+
+// estimateFeeResult result of `estimatesmartfee blocks`
+type estimateFeeResult struct {
+	FeeRate *float64 `json:"feerate,omitempty"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// descriptorInfoResult result of `getdescriptorinfo desc`
+type descriptorInfoResult struct {
+	Descriptor string `json:"descriptor"`
+	Checksum   string `json:"checksum"`
+}
+
+// addressInfoResult result of `getaddressinfo address`
+type addressInfoResult struct {
+	Address     string `json:"address"`
+	IsMine      bool   `json:"ismine"`
+	IsWatchOnly bool   `json:"iswatchonly"`
+}
+
+// importResult item of `importdescriptors` and `importmulti` result
+type importResult struct {
+	Success bool `json:"success"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
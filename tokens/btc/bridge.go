@@ -7,6 +7,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/bitcoind"
 )
 
 const (
@@ -21,7 +22,16 @@ var PairID = "btc"
 // Bridge btc bridge
 type Bridge struct {
 	*tokens.CrossChainBridgeBase
-	Inherit Inheritable
+	Inherit  Inheritable
+	ChainAPI ChainAPI // use electrs if nil
+}
+
+func init() {
+	tokens.RegisterBridgeFactory("Bitcoin", newBridgeFactory)
+}
+
+func newBridgeFactory(isSrc bool) tokens.CrossChainBridge {
+	return NewCrossChainBridge(isSrc)
 }
 
 // NewCrossChainBridge new btc bridge
@@ -44,9 +54,23 @@ func (b *Bridge) SetInherit(inherit Inheritable) {
 func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
 	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
 	b.VerifyChainConfig()
+	b.initChainAPI()
 	b.InitLatestBlockNumber()
 }
 
+func (b *Bridge) initChainAPI() {
+	extras := b.GatewayConfig.Extras
+	if extras == nil || extras.BtcCoreExtra == nil {
+		return
+	}
+	client, err := bitcoind.NewClient(extras.BtcCoreExtra, b.Inherit.GetChainParams())
+	if err != nil {
+		log.Fatal("init bitcoin core client failed", "err", err)
+	}
+	b.ChainAPI = client
+	log.Info("use bitcoin core json-rpc as btc gateway")
+}
+
 // VerifyChainConfig verify chain config
 func (b *Bridge) VerifyChainConfig() {
 	chainCfg := b.ChainConfig
@@ -62,8 +86,8 @@ func (b *Bridge) VerifyChainConfig() {
 
 // VerifyTokenConfig verify token config
 func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
-	if !b.IsP2pkhAddress(tokenCfg.DcrmAddress) {
-		return fmt.Errorf("invalid dcrm address (not p2pkh): %v", tokenCfg.DcrmAddress)
+	if !b.IsP2pkhAddress(tokenCfg.DcrmAddress) && !b.IsP2wpkhAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address (not p2pkh or p2wpkh): %v", tokenCfg.DcrmAddress)
 	}
 	if !b.IsValidAddress(tokenCfg.DepositAddress) {
 		return fmt.Errorf("invalid deposit address: %v", tokenCfg.DepositAddress)
@@ -14,11 +14,20 @@ import (
 	"github.com/btcsuite/btcutil"
 )
 
+// sigHashForkID sighash flag of bitcoin cash (fork id is 0)
+const sigHashForkID txscript.SigHashType = 0x40
+
 // Inheritable interface
 type Inheritable interface {
 	GetChainParams() *chaincfg.Params
 }
 
+// AddressConverter is implemented by inherited bridge of chain with its own
+// address encoding (eg. bitcoin cash cashaddr), addresses are decoded in legacy encoding
+type AddressConverter interface {
+	ToLegacyAddress(addr string) (string, error)
+}
+
 type btcAmountType = btcutil.Amount
 type wireTxInType = wire.TxIn
 type wireTxOutType = wire.TxOut
@@ -31,10 +40,6 @@ func isValidValue(value btcAmountType) bool {
 	return value > 0 && value <= btcutil.MaxSatoshi
 }
 
-func newAmount(value float64) (btcAmountType, error) {
-	return btcutil.NewAmount(value)
-}
-
 // GetChainParams get chain config (net params)
 func (b *Bridge) GetChainParams() *chaincfg.Params {
 	networkID := strings.ToLower(b.ChainConfig.NetID)
@@ -79,6 +84,33 @@ func (b *Bridge) IsPayToScriptHash(sigScript []byte) bool {
 	return txscript.IsPayToScriptHash(sigScript)
 }
 
+// IsPayToWitnessPubKeyHash is p2wpkh
+func (b *Bridge) IsPayToWitnessPubKeyHash(pkScript []byte) bool {
+	return txscript.IsPayToWitnessPubKeyHash(pkScript)
+}
+
+// IsPayToWitnessScriptHash is p2wsh
+func (b *Bridge) IsPayToWitnessScriptHash(pkScript []byte) bool {
+	return txscript.IsPayToWitnessScriptHash(pkScript)
+}
+
+// NewTxSigHashes new BIP143 sighash midstate of tx
+func (b *Bridge) NewTxSigHashes(tx *wire.MsgTx) *txscript.TxSigHashes {
+	return txscript.NewTxSigHashes(tx)
+}
+
+func getSigHashType() txscript.SigHashType {
+	if cfgSigHashForkID {
+		return txscript.SigHashAll | sigHashForkID
+	}
+	return txscript.SigHashAll
+}
+
+// CalcWitnessSignatureHash calc BIP143 sig hash of segwit input (or any input if SIGHASH_FORKID)
+func (b *Bridge) CalcWitnessSignatureHash(script []byte, sigHashes *txscript.TxSigHashes, tx *wire.MsgTx, i int, amount int64) (sigHash []byte, err error) {
+	return txscript.CalcWitnessSigHash(script, sigHashes, getSigHashType(), tx, i, amount)
+}
+
 // CalcSignatureHash calc sig hash
 func (b *Bridge) CalcSignatureHash(sigScript []byte, tx *wire.MsgTx, i int) (sigHash []byte, err error) {
 	return txscript.CalcSignatureHash(sigScript, txscript.SigHashAll, tx, i)
@@ -87,7 +119,7 @@ func (b *Bridge) CalcSignatureHash(sigScript []byte, tx *wire.MsgTx, i int) (sig
 // SerializeSignature serialize signature
 func (b *Bridge) SerializeSignature(r, s *big.Int) []byte {
 	sign := &btcec.Signature{R: r, S: s}
-	return append(sign.Serialize(), byte(txscript.SigHashAll))
+	return append(sign.Serialize(), byte(getSigHashType()))
 }
 
 // GetSigScript get script
@@ -112,6 +144,28 @@ func (b *Bridge) GetSigScript(sigScripts [][]byte, prevScript, signData, cPkData
 	return sigScript, err
 }
 
+// GetWitness get witness of segwit input
+func (b *Bridge) GetWitness(sigScripts [][]byte, prevScript, signData, cPkData []byte, i int) (witness wire.TxWitness, err error) {
+	scriptClass := txscript.GetScriptClass(prevScript)
+	switch scriptClass {
+	case txscript.WitnessV0PubKeyHashTy:
+		witness = wire.TxWitness{signData, cPkData}
+	case txscript.WitnessV0ScriptHashTy:
+		if sigScripts == nil {
+			err = fmt.Errorf("call MakeSignedTransaction spend p2wsh without witness scripts")
+		} else {
+			witnessScript := sigScripts[i]
+			err = b.VerifyRedeemScript(prevScript, witnessScript)
+			if err == nil {
+				witness = wire.TxWitness{signData, cPkData, witnessScript}
+			}
+		}
+	default:
+		err = fmt.Errorf("unsupport to spend '%v' output with witness", scriptClass.String())
+	}
+	return witness, err
+}
+
 // SerializePublicKey serialize ecdsa public key
 func (b *Bridge) SerializePublicKey(ecPub *ecdsa.PublicKey, compressed bool) []byte {
 	if compressed {
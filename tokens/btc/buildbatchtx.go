@@ -0,0 +1,109 @@
+package btc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+// BuildBatchRawTransaction build raw tx paying multiple swapouts of a pair,
+// the swapouts are paid by outputs in the order of args.Batch
+func (b *Bridge) BuildBatchRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	pairID := args.PairID
+	token := b.GetTokenConfig(pairID)
+	if token == nil {
+		return nil, fmt.Errorf("swap pair '%v' is not configed", pairID)
+	}
+	if args.SwapType != tokens.SwapoutType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	if len(args.Batch) == 0 {
+		return nil, errors.New("no swaps in batch")
+	}
+
+	from := token.DcrmAddress
+	changeAddress := token.DcrmAddress
+
+	var extra *tokens.BtcExtraArgs
+	if args.Extra == nil || args.Extra.BtcExtra == nil {
+		extra = &tokens.BtcExtraArgs{}
+		args.Extra = &tokens.AllExtras{BtcExtra: extra}
+	} else {
+		extra = args.Extra.BtcExtra
+	}
+
+	var relayFeePerKb btcAmountType
+	if extra.RelayFeePerKb != nil {
+		relayFeePerKb = btcAmountType(*extra.RelayFeePerKb)
+	} else {
+		relayFee, errf := b.getRelayFeePerKb()
+		if errf != nil {
+			return nil, errf
+		}
+		extra.RelayFeePerKb = &relayFee
+		relayFeePerKb = btcAmountType(relayFee)
+	}
+
+	var txOuts []*wireTxOutType
+	for _, item := range args.Batch {
+		if !strings.EqualFold(item.PairID, pairID) || item.SwapType != tokens.SwapoutType {
+			return nil, fmt.Errorf("batch swap %v mismatch, pairID %v, swapType %v", item.SwapID, item.PairID, item.SwapType.String())
+		}
+		amount := tokens.CalcSwappedValue(pairID, item.OriginValue, false)
+		if amount.Sign() <= 0 {
+			return nil, fmt.Errorf("batch swap %v with zero swap value", item.SwapID)
+		}
+		item.SwapValue = amount
+		item.OutputIndex = len(txOuts)
+		item.Memo = fmt.Sprintf("%v%v:%v", tokens.UnlockMemoPrefix, item.SwapID, item.OutputIndex)
+		err = b.addPayToAddrOutput(&txOuts, item.Bind, amount.Int64())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// standard tx has only one null data output, memo the batch id in it
+	err = b.addMemoOutput(&txOuts, tokens.UnlockMemoPrefix+args.SwapID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputSource := func(target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
+		if len(extra.PreviousOutPoints) != 0 {
+			return b.getUtxos(from, target, extra.PreviousOutPoints, nil)
+		}
+		return b.selectUtxos(from, target, relayFeePerKb)
+	}
+
+	changeSource := func() ([]byte, error) {
+		return b.GetPayToAddrScript(changeAddress)
+	}
+
+	authoredTx, err := b.NewUnsignedTransaction(txOuts, relayFeePerKb, inputSource, changeSource, false)
+	if err != nil {
+		return nil, err
+	}
+
+	updateExtraInfo(extra, authoredTx.Tx.TxIn)
+
+	args.Identifier = params.GetIdentifier()
+
+	return authoredTx, nil
+}
+
+// VerifyBatchRawTransaction verify every swap of args.Batch is paid by
+// the output of raw tx with its OutputIndex and SwapValue
+func (b *Bridge) VerifyBatchRawTransaction(rawTx interface{}, args *tokens.BuildTxArgs) error {
+	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(args.Batch) == 0 {
+		return errors.New("no swaps in batch")
+	}
+	return b.verifyBatchTransactionWithArgs(authoredTx, args)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/coinselect"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 	"github.com/btcsuite/btcwallet/wallet/txrules"
@@ -103,6 +104,17 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		relayFeePerKb = btcAmountType(relayFee)
 	}
 
+	if extra.ParentTx != "" {
+		return b.buildCPFPTransaction(from, memo, extra, relayFeePerKb)
+	}
+	var replaceTx *electrs.ElectTx
+	if extra.ReplaceTx != "" {
+		replaceTx, err = b.verifyReplaceTx(extra, memo)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	txOuts, err := b.getTxOutputs(to, amount, memo)
 	if err != nil {
 		return nil, err
@@ -110,9 +122,9 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 
 	inputSource := func(target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
 		if len(extra.PreviousOutPoints) != 0 {
-			return b.getUtxos(from, target, extra.PreviousOutPoints)
+			return b.getUtxos(from, target, extra.PreviousOutPoints, replaceTx)
 		}
-		return b.selectUtxos(from, target)
+		return b.selectUtxos(from, target, relayFeePerKb)
 	}
 
 	changeSource := func() ([]byte, error) {
@@ -124,6 +136,9 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
+	// signal replaceability so that stuck swap tx can be bumped by RBF
+	setReplaceableSequence(authoredTx.Tx)
+
 	updateExtraInfo(extra, authoredTx.Tx.TxIn)
 
 	if args.SwapType != tokens.NoSwapType {
@@ -168,7 +183,7 @@ func (b *Bridge) BuildTransaction(from string, receivers []string, amounts []int
 	}
 
 	inputSource := func(target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
-		return b.selectUtxos(from, target)
+		return b.selectUtxos(from, target, btcAmountType(relayFeePerKb))
 	}
 
 	changeSource := func() ([]byte, error) {
@@ -200,6 +215,10 @@ func (b *Bridge) addPayToAddrOutput(txOuts *[]*wireTxOutType, to string, amount
 	if amount <= 0 {
 		return nil
 	}
+	// chains with fixed dust limit (eg. dogecoin) do not relay dust outputs
+	if cfgDustLimit > 0 && amount < cfgDustLimit {
+		return fmt.Errorf("output amount %v to %v is below dust limit %v", amount, to, cfgDustLimit)
+	}
 	pkscript, err := b.GetPayToAddrScript(to)
 	if err != nil {
 		return err
@@ -253,78 +272,109 @@ func (b *Bridge) getOutspendWithRetry(point *tokens.BtcOutPoint) (outspend *elec
 	return outspend, err
 }
 
-func (b *Bridge) selectUtxos(from string, target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
+func (b *Bridge) selectUtxos(from string, target, relayFeePerKb btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
 	p2pkhScript, err := b.GetPayToAddrScript(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
+	fromType := b.getScriptPubkeyType(from)
 
 	utxos, err := b.findUxtosWithRetry(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
 
-	var (
-		tx      *electrs.ElectTx
-		success bool
-	)
-
+	candidates := make([]*coinselect.Utxo, 0, len(utxos))
 	for _, utxo := range utxos {
 		value := btcAmountType(*utxo.Value)
 		if !isValidValue(value) {
 			continue
 		}
-		tx, err = b.getTransactionByHashWithRetry(*utxo.Txid)
-		if err != nil {
-			continue
-		}
-		if *utxo.Vout >= uint32(len(tx.Vout)) {
-			continue
+		candidates = append(candidates, &coinselect.Utxo{
+			Txid:      *utxo.Txid,
+			Vout:      *utxo.Vout,
+			Value:     int64(value),
+			Confirmed: utxo.Status != nil && utxo.Status.Confirmed != nil && *utxo.Status.Confirmed,
+		})
+	}
+
+	isValid := func(utxo *coinselect.Utxo) bool {
+		tx, errf := b.getTransactionByHashWithRetry(utxo.Txid)
+		if errf != nil {
+			return false
 		}
-		output := tx.Vout[*utxo.Vout]
-		if *output.ScriptpubkeyType != p2pkhType {
-			continue
+		if utxo.Vout >= uint32(len(tx.Vout)) {
+			return false
 		}
-		if output.ScriptpubkeyAddress == nil || *output.ScriptpubkeyAddress != from {
-			continue
+		output := tx.Vout[utxo.Vout]
+		if *output.ScriptpubkeyType != fromType {
+			return false
 		}
+		return output.ScriptpubkeyAddress != nil && *output.ScriptpubkeyAddress == from
+	}
+
+	selectParams := b.getSelectParams(p2pkhScript, target, relayFeePerKb)
+	selection, err := coinselect.Select(cfgUtxoSelectStrategy, candidates, selectParams, isValid)
+	if err != nil {
+		return 0, nil, nil, nil, fmt.Errorf("not enough balance, target %v, %w", target, err)
+	}
 
-		txIn, errf := b.NewTxIn(*utxo.Txid, *utxo.Vout, p2pkhScript)
+	for _, utxo := range selection.Utxos {
+		txIn, errf := b.NewTxIn(utxo.Txid, utxo.Vout, p2pkhScript)
 		if errf != nil {
-			continue
+			return 0, nil, nil, nil, errf
 		}
-
+		value := btcAmountType(utxo.Value)
 		total += value
 		inputs = append(inputs, txIn)
 		inputValues = append(inputValues, value)
 		scripts = append(scripts, p2pkhScript)
-
-		if total >= target {
-			success = true
-			break
-		}
-	}
-
-	if !success {
-		err = fmt.Errorf("not enough balance, total %v < target %v", total, target)
-		return 0, nil, nil, nil, err
 	}
 
+	log.Debug("select utxos", "from", from, "strategy", cfgUtxoSelectStrategy, "count", len(inputs), "total", total, "target", target, "waste", selection.Waste, "changeless", selection.Changeless)
 	return total, inputs, inputValues, scripts, nil
 }
 
-func (b *Bridge) getUtxos(from string, target btcAmountType, prevOutPoints []*tokens.BtcOutPoint) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
+// getSelectParams the target of input source contains fee of one p2pkh input,
+// exclude it as coin selection counts fee of each selected input.
+func (b *Bridge) getSelectParams(pkScript []byte, target, relayFeePerKb btcAmountType) *coinselect.Params {
+	inputSize := txsizes.RedeemP2PKHInputSize
+	changeSize := txsizes.P2PKHOutputSize
+	if b.IsPayToWitnessPubKeyHash(pkScript) {
+		inputSize = txsizes.RedeemP2WPKHInputSize +
+			(txsizes.RedeemP2WPKHInputWitnessWeight+witnessScaleFactor-1)/witnessScaleFactor
+		changeSize = txsizes.P2WPKHOutputSize
+	}
+	inputFee := txrules.FeeForSerializeSize(relayFeePerKb, inputSize)
+	longTermInputFee := txrules.FeeForSerializeSize(btcAmountType(cfgLongTermFeePerKb), inputSize)
+	costOfChange := txrules.FeeForSerializeSize(relayFeePerKb, changeSize) + longTermInputFee
+	// change less than dust threshold is dropped to fee
+	maxChangelessExcess := getDustThreshold(len(pkScript)) - 1
+	if maxChangelessExcess > costOfChange {
+		maxChangelessExcess = costOfChange
+	}
+	return &coinselect.Params{
+		Target:              int64(target - txrules.FeeForSerializeSize(relayFeePerKb, txsizes.RedeemP2PKHInputSize)),
+		InputFee:            int64(inputFee),
+		LongTermInputFee:    int64(longTermInputFee),
+		CostOfChange:        int64(costOfChange),
+		MaxChangelessExcess: int64(maxChangelessExcess),
+	}
+}
+
+func (b *Bridge) getUtxos(from string, target btcAmountType, prevOutPoints []*tokens.BtcOutPoint, replaceTx *electrs.ElectTx) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
 	p2pkhScript, err := b.GetPayToAddrScript(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
+	fromType := b.getScriptPubkeyType(from)
 
 	for _, point := range prevOutPoints {
 		outspend, errf := b.getOutspendWithRetry(point)
 		if errf != nil {
 			return 0, nil, nil, nil, errf
 		}
-		if *outspend.Spent {
+		if *outspend.Spent && !isSpentByReplaceTx(outspend, point, replaceTx) {
 			if outspend.Status != nil && outspend.Status.BlockHeight != nil {
 				spentHeight := *outspend.Status.BlockHeight
 				err = fmt.Errorf("out point (%v, %v) is spent at %v", point.Hash, point.Index, spentHeight)
@@ -342,8 +392,8 @@ func (b *Bridge) getUtxos(from string, target btcAmountType, prevOutPoints []*to
 			return 0, nil, nil, nil, err
 		}
 		output := tx.Vout[point.Index]
-		if *output.ScriptpubkeyType != p2pkhType {
-			err = fmt.Errorf("out point (%v, %v) script pubkey type %v is not p2pkh", point.Hash, point.Index, *output.ScriptpubkeyType)
+		if *output.ScriptpubkeyType != fromType {
+			err = fmt.Errorf("out point (%v, %v) script pubkey type %v is not %v", point.Hash, point.Index, *output.ScriptpubkeyType, fromType)
 			return 0, nil, nil, nil, err
 		}
 		if output.ScriptpubkeyAddress == nil || *output.ScriptpubkeyAddress != from {
@@ -426,7 +476,7 @@ func (b *Bridge) NewUnsignedTransaction(outputs []*wireTxOutType, relayFeePerKb
 			//	return nil, errors.New("fee estimation requires change " +
 			//		"scripts no larger than P2WPKH output scripts")
 			//}
-			threshold := txrules.GetDustThreshold(len(changeScript), txrules.DefaultRelayFeePerKb)
+			threshold := getDustThreshold(len(changeScript))
 			if changeAmount < threshold {
 				log.Debug("get rid of dust change", "amount", changeAmount, "threshold", threshold, "scriptsize", len(changeScript))
 			} else {
@@ -448,15 +498,23 @@ func (b *Bridge) NewUnsignedTransaction(outputs []*wireTxOutType, relayFeePerKb
 	}
 }
 
-func (b *Bridge) estimateSize(scripts [][]byte, txOuts []*wireTxOutType, addChangeOutput, isAggregate bool) int {
-	if !isAggregate {
-		return txsizes.EstimateSerializeSize(len(scripts), txOuts, addChangeOutput)
+func getDustThreshold(scriptSize int) btcAmountType {
+	if cfgDustLimit > 0 {
+		return btcAmountType(cfgDustLimit)
 	}
+	return txrules.GetDustThreshold(scriptSize, txrules.DefaultRelayFeePerKb)
+}
 
-	var p2sh, p2pkh int
+// estimateSize estimate virtual size of signed tx, segwit inputs are discounted
+func (b *Bridge) estimateSize(scripts [][]byte, txOuts []*wireTxOutType, addChangeOutput, isAggregate bool) int {
+	var p2sh, p2pkh, p2wpkh, p2wsh int
 	for _, pkScript := range scripts {
 		switch {
-		case b.IsPayToScriptHash(pkScript):
+		case b.IsPayToWitnessPubKeyHash(pkScript):
+			p2wpkh++
+		case b.IsPayToWitnessScriptHash(pkScript):
+			p2wsh++
+		case isAggregate && b.IsPayToScriptHash(pkScript):
 			p2sh++
 		default:
 			p2pkh++
@@ -467,6 +525,14 @@ func (b *Bridge) estimateSize(scripts [][]byte, txOuts []*wireTxOutType, addChan
 	if p2sh > 0 {
 		size += p2sh * redeemAggregateP2SHInputSize
 	}
+	if witnessInputs := p2wpkh + p2wsh; witnessInputs > 0 {
+		size += witnessInputs * txsizes.RedeemP2WPKHInputSize
+		// 2 weight units for segwit marker and flag, count of witness items is per input
+		witnessWeight := 2 +
+			p2wpkh*txsizes.RedeemP2WPKHInputWitnessWeight +
+			p2wsh*redeemP2WSHInputWitnessWeight
+		size += (witnessWeight + witnessScaleFactor - 1) / witnessScaleFactor
+	}
 
 	return size
 }
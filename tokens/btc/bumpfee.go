@@ -0,0 +1,351 @@
+package btc
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+)
+
+const (
+	// opt-in replace-by-fee (BIP125) if any input sequence is less than this
+	maxReplaceableSequence = wire.MaxTxInSequenceNum - 1
+	rbfSequence            = wire.MaxTxInSequenceNum - 2
+
+	minBumpFeePercent        = int64(10)
+	incrementalRelayFeePerKb = int64(1000) // default `-incrementalrelayfee` of bitcoin core
+)
+
+var (
+	errTxAlreadyConfirmed = errors.New("tx is already confirmed")
+	errTxHasDescendant    = errors.New("tx change output is spent by unconfirmed descendant")
+	errTxWithoutChange    = errors.New("tx has no change output")
+	errTxOfOtherSwap      = errors.New("tx is not of the same swap")
+	errTxMissingPrevout   = errors.New("tx is missing prevout info")
+	errBumpFeeTooHigh     = errors.New("bumped relay fee exceeds max relay fee")
+	errRBFDisabled        = errors.New("replace-by-fee is disabled")
+)
+
+// GetFeeBumpExtra impl tokens.FeeBumper
+func (b *Bridge) GetFeeBumpExtra(pairID, swapTx, cpfpTx string) (*tokens.BtcExtraArgs, error) {
+	token := b.GetTokenConfig(pairID)
+	if token == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	tx, err := b.getUnconfirmedTx(swapTx)
+	if err != nil {
+		return nil, err
+	}
+	if cpfpTx != "" {
+		if cfgDisableRBF {
+			return nil, errRBFDisabled
+		}
+		return b.getReplaceCPFPExtra(tx, cpfpTx)
+	}
+	// bumping fee of descendant pulls the ancestors, and replacing
+	// the ancestor would evict the descendant (maybe another swap tx)
+	changeIndex := getOutputIndexOf(tx, token.DcrmAddress)
+	if changeIndex >= 0 {
+		outspend, errf := b.getOutspendWithRetry(&tokens.BtcOutPoint{Hash: swapTx, Index: uint32(changeIndex)})
+		if errf != nil {
+			return nil, errf
+		}
+		if *outspend.Spent {
+			return nil, errTxHasDescendant
+		}
+	}
+	fee, vsize, err := getTxFeeAndVsize(tx)
+	if err != nil {
+		return nil, err
+	}
+	relayFeePerKb, err := b.getBumpedRelayFeePerKb(fee, vsize)
+	if err != nil {
+		return nil, err
+	}
+	extra := &tokens.BtcExtraArgs{RelayFeePerKb: &relayFeePerKb}
+	if isReplaceableTx(tx) {
+		extra.ReplaceTx = swapTx
+		extra.PreviousOutPoints = getPreviousOutPoints(tx)
+		return extra, nil
+	}
+	if changeIndex < 0 {
+		return nil, errTxWithoutChange
+	}
+	extra.ParentTx = swapTx
+	return extra, nil
+}
+
+// getReplaceCPFPExtra replace the previous child with higher package fee
+func (b *Bridge) getReplaceCPFPExtra(parent *electrs.ElectTx, cpfpTx string) (*tokens.BtcExtraArgs, error) {
+	child, err := b.getUnconfirmedTx(cpfpTx)
+	if err != nil {
+		return nil, err
+	}
+	parentFee, parentSize, err := getTxFeeAndVsize(parent)
+	if err != nil {
+		return nil, err
+	}
+	childFee, childSize, err := getTxFeeAndVsize(child)
+	if err != nil {
+		return nil, err
+	}
+	relayFeePerKb, err := b.getBumpedRelayFeePerKb(parentFee+childFee, parentSize+childSize)
+	if err != nil {
+		return nil, err
+	}
+	return &tokens.BtcExtraArgs{
+		RelayFeePerKb: &relayFeePerKb,
+		ParentTx:      *parent.Txid,
+		ReplaceTx:     cpfpTx,
+	}, nil
+}
+
+// getBumpedRelayFeePerKb bump at least minBumpFeePercent and incremental relay fee
+func (b *Bridge) getBumpedRelayFeePerKb(fee, vsize int64) (int64, error) {
+	oldFeePerKb := fee * 1000 / vsize
+	feePerKb := oldFeePerKb*(100+minBumpFeePercent)/100 + incrementalRelayFeePerKb
+	if estimateFee, err := b.getRelayFeePerKb(); err == nil && estimateFee > feePerKb {
+		feePerKb = estimateFee
+	}
+	if feePerKb > cfgMaxRelayFeePerKb {
+		return 0, fmt.Errorf("%w, %v > %v", errBumpFeeTooHigh, feePerKb, cfgMaxRelayFeePerKb)
+	}
+	return feePerKb, nil
+}
+
+// verifyReplaceTx the replaced tx must be an unconfirmed tx of the same swap,
+// and spends exactly the previous outpoints so that only one of them can be on chain
+func (b *Bridge) verifyReplaceTx(extra *tokens.BtcExtraArgs, memo string) (*electrs.ElectTx, error) {
+	tx, err := b.getUnconfirmedTx(extra.ReplaceTx)
+	if err != nil {
+		return nil, err
+	}
+	if !b.hasMemoOutput(tx, memo) {
+		return nil, errTxOfOtherSwap
+	}
+	if len(tx.Vin) != len(extra.PreviousOutPoints) {
+		return nil, fmt.Errorf("replace tx inputs count mismatch, %v != %v", len(tx.Vin), len(extra.PreviousOutPoints))
+	}
+	for i, point := range extra.PreviousOutPoints {
+		txin := tx.Vin[i]
+		if !isSpendingOutPoint(txin, point) {
+			return nil, fmt.Errorf("replace tx input %v mismatch, (%v, %v) != (%v, %v)", i, *txin.Txid, *txin.Vout, point.Hash, point.Index)
+		}
+	}
+	return tx, nil
+}
+
+// buildCPFPTransaction spend the change output of unconfirmed swap tx back to itself,
+// with fee so that the package of parent and child reaches relay fee rate
+func (b *Bridge) buildCPFPTransaction(from, memo string, extra *tokens.BtcExtraArgs, relayFeePerKb btcAmountType) (*txauthor.AuthoredTx, error) {
+	parent, err := b.getUnconfirmedTx(extra.ParentTx)
+	if err != nil {
+		return nil, err
+	}
+	if !b.hasMemoOutput(parent, memo) {
+		return nil, errTxOfOtherSwap
+	}
+	changeIndex := getOutputIndexOf(parent, from)
+	if changeIndex < 0 {
+		return nil, errTxWithoutChange
+	}
+	point := &tokens.BtcOutPoint{Hash: extra.ParentTx, Index: uint32(changeIndex)}
+	var (
+		replaceTx  *electrs.ElectTx
+		replaceFee int64
+	)
+	if extra.ReplaceTx != "" {
+		replaceTx, replaceFee, err = b.verifyReplaceCPFPTx(extra.ReplaceTx, point)
+		if err != nil {
+			return nil, err
+		}
+	}
+	outspend, err := b.getOutspendWithRetry(point)
+	if err != nil {
+		return nil, err
+	}
+	if *outspend.Spent && !isSpentByReplaceTx(outspend, point, replaceTx) {
+		return nil, errTxHasDescendant
+	}
+	parentFee, parentSize, err := getTxFeeAndVsize(parent)
+	if err != nil {
+		return nil, err
+	}
+
+	pkScript, err := b.GetPayToAddrScript(from)
+	if err != nil {
+		return nil, err
+	}
+	txIn, err := b.NewTxIn(point.Hash, point.Index, pkScript)
+	if err != nil {
+		return nil, err
+	}
+	txOut := b.NewTxOut(0, pkScript)
+	scripts := [][]byte{pkScript}
+	childSize := b.estimateSize(scripts, []*wireTxOutType{txOut}, false, false)
+
+	childFee := txrules.FeeForSerializeSize(relayFeePerKb, int(parentSize)+childSize) - btcAmountType(parentFee)
+	if minFee := txrules.FeeForSerializeSize(relayFeePerKb, childSize); childFee < minFee {
+		childFee = minFee
+	}
+	// replacement must pay more absolute fee than the replaced (BIP125)
+	if minFee := btcAmountType(replaceFee + incrementalRelayFeePerKb*int64(childSize)/1000); childFee < minFee {
+		childFee = minFee
+	}
+	changeValue := btcAmountType(*parent.Vout[changeIndex].Value)
+	outValue := changeValue - childFee
+	if threshold := getDustThreshold(len(pkScript)); outValue < threshold {
+		return nil, fmt.Errorf("not enough change value %v to pay cpfp fee %v", changeValue, childFee)
+	}
+	txOut.Value = int64(outValue)
+
+	unsignedTransaction := b.NewMsgTx([]*wireTxInType{txIn}, []*wireTxOutType{txOut}, 0)
+	setReplaceableSequence(unsignedTransaction)
+	updateExtraInfo(extra, unsignedTransaction.TxIn)
+
+	return &txauthor.AuthoredTx{
+		Tx:              unsignedTransaction,
+		PrevScripts:     scripts,
+		PrevInputValues: []btcAmountType{changeValue},
+		TotalInput:      changeValue,
+		ChangeIndex:     0,
+	}, nil
+}
+
+// verifyReplaceCPFPTx the replaced child must spend only the change output of parent
+func (b *Bridge) verifyReplaceCPFPTx(replaceTx string, point *tokens.BtcOutPoint) (tx *electrs.ElectTx, fee int64, err error) {
+	tx, err = b.getUnconfirmedTx(replaceTx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(tx.Vin) != 1 || !isSpendingOutPoint(tx.Vin[0], point) {
+		return nil, 0, fmt.Errorf("replace tx %v is not child of (%v, %v)", replaceTx, point.Hash, point.Index)
+	}
+	fee, _, err = getTxFeeAndVsize(tx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tx, fee, nil
+}
+
+func (b *Bridge) getUnconfirmedTx(txHash string) (*electrs.ElectTx, error) {
+	tx, err := b.getTransactionByHashWithRetry(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Status != nil && tx.Status.Confirmed != nil && *tx.Status.Confirmed {
+		return nil, errTxAlreadyConfirmed
+	}
+	return tx, nil
+}
+
+func (b *Bridge) hasMemoOutput(tx *electrs.ElectTx, memo string) bool {
+	nullScript, err := b.NullDataScript(memo)
+	if err != nil {
+		return false
+	}
+	memoScript := hex.EncodeToString(nullScript)
+	for _, output := range tx.Vout {
+		if output.Scriptpubkey != nil && *output.Scriptpubkey == memoScript {
+			return true
+		}
+	}
+	return false
+}
+
+// isSpentByReplaceTx outpoint spent in pool by the replaced tx can be spent again
+func isSpentByReplaceTx(outspend *electrs.ElectOutspend, point *tokens.BtcOutPoint, replaceTx *electrs.ElectTx) bool {
+	if replaceTx == nil || replaceTx.Txid == nil {
+		return false
+	}
+	if outspend.Status != nil && outspend.Status.Confirmed != nil && *outspend.Status.Confirmed {
+		return false
+	}
+	if outspend.Txid != nil {
+		return *outspend.Txid == *replaceTx.Txid
+	}
+	// bitcoin core backend does not tell the spending tx,
+	// the unconfirmed replaced tx must spend the outpoint itself
+	for _, txin := range replaceTx.Vin {
+		if isSpendingOutPoint(txin, point) {
+			return true
+		}
+	}
+	return false
+}
+
+func isSpendingOutPoint(txin *electrs.ElectTxin, point *tokens.BtcOutPoint) bool {
+	return txin.Txid != nil && txin.Vout != nil && *txin.Txid == point.Hash && *txin.Vout == point.Index
+}
+
+func setReplaceableSequence(tx *wire.MsgTx) {
+	if cfgDisableRBF {
+		return
+	}
+	for _, txin := range tx.TxIn {
+		txin.Sequence = rbfSequence
+	}
+}
+
+func isReplaceableTx(tx *electrs.ElectTx) bool {
+	if cfgDisableRBF {
+		return false
+	}
+	for _, txin := range tx.Vin {
+		if txin.Sequence != nil && *txin.Sequence < maxReplaceableSequence {
+			return true
+		}
+	}
+	return false
+}
+
+func getOutputIndexOf(tx *electrs.ElectTx, address string) int {
+	for i, output := range tx.Vout {
+		if output.ScriptpubkeyAddress != nil && *output.ScriptpubkeyAddress == address {
+			return i
+		}
+	}
+	return -1
+}
+
+func getPreviousOutPoints(tx *electrs.ElectTx) []*tokens.BtcOutPoint {
+	points := make([]*tokens.BtcOutPoint, len(tx.Vin))
+	for i, txin := range tx.Vin {
+		points[i] = &tokens.BtcOutPoint{
+			Hash:  *txin.Txid,
+			Index: *txin.Vout,
+		}
+	}
+	return points
+}
+
+// getTxFeeAndVsize calc fee from prevouts, vsize from weight
+func getTxFeeAndVsize(tx *electrs.ElectTx) (fee, vsize int64, err error) {
+	var inputValue, outputValue uint64
+	for _, txin := range tx.Vin {
+		if txin.Prevout == nil || txin.Prevout.Value == nil {
+			return 0, 0, errTxMissingPrevout
+		}
+		inputValue += *txin.Prevout.Value
+	}
+	for _, output := range tx.Vout {
+		outputValue += *output.Value
+	}
+	if inputValue < outputValue {
+		return 0, 0, fmt.Errorf("tx input value %v < output value %v", inputValue, outputValue)
+	}
+	switch {
+	case tx.Weight != nil && *tx.Weight > 0:
+		vsize = int64(*tx.Weight+witnessScaleFactor-1) / witnessScaleFactor
+	case tx.Size != nil && *tx.Size > 0:
+		vsize = int64(*tx.Size)
+	default:
+		return 0, 0, errors.New("tx without size info")
+	}
+	return int64(inputValue - outputValue), vsize, nil
+}
@@ -0,0 +1,399 @@
+package btc
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+func newTestElectTx(inputValues, outputValues []uint64, sequence, weight uint32) *electrs.ElectTx {
+	tx := &electrs.ElectTx{Weight: &weight}
+	for i := range inputValues {
+		tx.Vin = append(tx.Vin, &electrs.ElectTxin{
+			Sequence: &sequence,
+			Prevout:  &electrs.ElectTxOut{Value: &inputValues[i]},
+		})
+	}
+	for i := range outputValues {
+		tx.Vout = append(tx.Vout, &electrs.ElectTxOut{Value: &outputValues[i]})
+	}
+	return tx
+}
+
+func TestGetTxFeeAndVsize(t *testing.T) {
+	tx := newTestElectTx([]uint64{50000, 30000}, []uint64{60000, 15000}, rbfSequence, 901)
+	fee, vsize, err := getTxFeeAndVsize(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != 5000 || vsize != 226 {
+		t.Fatalf("wrong fee or vsize, have (%v, %v) want (5000, 226)", fee, vsize)
+	}
+	if !isReplaceableTx(tx) {
+		t.Fatal("tx with rbf sequence should be replaceable")
+	}
+	if isReplaceableTx(newTestElectTx([]uint64{1000}, []uint64{500}, maxReplaceableSequence, 400)) {
+		t.Fatal("tx with final sequence should not be replaceable")
+	}
+	if _, _, err = getTxFeeAndVsize(newTestElectTx([]uint64{1000}, []uint64{1500}, rbfSequence, 400)); err == nil {
+		t.Fatal("tx with output value larger than input value should fail")
+	}
+}
+
+const (
+	testPairID      = "btc"
+	testMemo        = "testswapmemo"
+	testDcrmAddress = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+
+	testInputTxid  = "1111111111111111111111111111111111111111111111111111111111111111"
+	testSwapTxid   = "2222222222222222222222222222222222222222222222222222222222222222"
+	testChildTxid  = "3333333333333333333333333333333333333333333333333333333333333333"
+	testOtherTxid  = "4444444444444444444444444444444444444444444444444444444444444444"
+	testSwapValue  = uint64(50000)
+	testSwapWeight = uint32(901)
+)
+
+var errTestTxNotFound = errors.New("tx not found")
+
+// testChainAPI stub chain api, serves txs and outspends from memory
+type testChainAPI struct {
+	ChainAPI
+	txs       map[string]*electrs.ElectTx
+	outspends map[tokens.BtcOutPoint]*electrs.ElectOutspend
+}
+
+func newTestChainAPI(txs ...*electrs.ElectTx) *testChainAPI {
+	api := &testChainAPI{
+		txs:       make(map[string]*electrs.ElectTx),
+		outspends: make(map[tokens.BtcOutPoint]*electrs.ElectOutspend),
+	}
+	for _, tx := range txs {
+		api.txs[*tx.Txid] = tx
+	}
+	return api
+}
+
+func (api *testChainAPI) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	if tx, exist := api.txs[txHash]; exist {
+		return tx, nil
+	}
+	return nil, errTestTxNotFound
+}
+
+func (api *testChainAPI) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	if outspend, exist := api.outspends[tokens.BtcOutPoint{Hash: txHash, Index: vout}]; exist {
+		return outspend, nil
+	}
+	spent := false
+	return &electrs.ElectOutspend{Spent: &spent}, nil
+}
+
+func (api *testChainAPI) EstimateFeePerKb(blocks int) (int64, error) {
+	return 1000, nil
+}
+
+func (api *testChainAPI) setSpent(txHash string, vout uint32, spender string, confirmed bool) {
+	spent := true
+	outspend := &electrs.ElectOutspend{
+		Spent:  &spent,
+		Status: &electrs.ElectTxStatus{Confirmed: &confirmed},
+	}
+	if spender != "" {
+		outspend.Txid = &spender
+	}
+	api.outspends[tokens.BtcOutPoint{Hash: txHash, Index: vout}] = outspend
+}
+
+func newTestFeeBumpBridge(api ChainAPI) *Bridge {
+	b := newTestBridge()
+	b.ChainAPI = api
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		testPairID: {
+			PairID:   testPairID,
+			SrcToken: &tokens.TokenConfig{DcrmAddress: testDcrmAddress},
+		},
+	}, false)
+	return b
+}
+
+// newTestSwapTx swap tx with outputs of (swap value, memo, optional change to dcrm address)
+func newTestSwapTx(txid string, prevouts []*tokens.BtcOutPoint, inputValues []uint64, changeValue uint64, sequence uint32) *electrs.ElectTx {
+	outputValues := []uint64{testSwapValue, 0}
+	if changeValue > 0 {
+		outputValues = append(outputValues, changeValue)
+	}
+	tx := newTestElectTx(inputValues, outputValues, sequence, testSwapWeight)
+	tx.Txid = &txid
+	for i := range prevouts {
+		tx.Vin[i].Txid = &prevouts[i].Hash
+		tx.Vin[i].Vout = &prevouts[i].Index
+	}
+	nullScript, _ := txscript.NullDataScript([]byte(testMemo))
+	memoScript := hex.EncodeToString(nullScript)
+	tx.Vout[1].Scriptpubkey = &memoScript
+	if changeValue > 0 {
+		dcrmAddress := testDcrmAddress
+		tx.Vout[2].ScriptpubkeyAddress = &dcrmAddress
+	}
+	return tx
+}
+
+// newTestChildTx cpfp child tx spending the outpoint
+func newTestChildTx(txid string, point *tokens.BtcOutPoint, inputValue, outputValue uint64) *electrs.ElectTx {
+	tx := newTestElectTx([]uint64{inputValue}, []uint64{outputValue}, rbfSequence, 768)
+	tx.Txid = &txid
+	tx.Vin[0].Txid = &point.Hash
+	tx.Vin[0].Vout = &point.Index
+	return tx
+}
+
+func setTestTxConfirmed(tx *electrs.ElectTx) *electrs.ElectTx {
+	confirmed := true
+	tx.Status = &electrs.ElectTxStatus{Confirmed: &confirmed}
+	return tx
+}
+
+func TestGetFeeBumpExtra(t *testing.T) {
+	defer func(disableRBF bool, maxRelayFeePerKb int64) {
+		cfgDisableRBF, cfgMaxRelayFeePerKb = disableRBF, maxRelayFeePerKb
+	}(cfgDisableRBF, cfgMaxRelayFeePerKb)
+
+	prevout := &tokens.BtcOutPoint{Hash: testInputTxid, Index: 1}
+	// fee 5000, vsize 226, bumped fee per kb = 5000*1000/226*110/100 + 1000
+	wantRelayFeePerKb := int64(25335)
+
+	tests := []struct {
+		name        string
+		sequence    uint32
+		changeValue uint64
+		changeSpent bool
+		confirmed   bool
+		disableRBF  bool
+		maxRelayFee int64
+		wantReplace bool
+		wantParent  bool
+		wantErr     error
+	}{
+		{name: "replaceable tx uses rbf", sequence: rbfSequence, changeValue: 45000, wantReplace: true},
+		{name: "replaceable tx without change uses rbf", sequence: rbfSequence, wantReplace: true},
+		{name: "final tx uses cpfp", sequence: maxReplaceableSequence, changeValue: 45000, wantParent: true},
+		{name: "rbf disabled uses cpfp", sequence: rbfSequence, changeValue: 45000, disableRBF: true, wantParent: true},
+		{name: "final tx without change", sequence: maxReplaceableSequence, wantErr: errTxWithoutChange},
+		{name: "change spent by descendant", sequence: rbfSequence, changeValue: 45000, changeSpent: true, wantErr: errTxHasDescendant},
+		{name: "confirmed tx", sequence: rbfSequence, changeValue: 45000, confirmed: true, wantErr: errTxAlreadyConfirmed},
+		{name: "bumped fee too high", sequence: rbfSequence, changeValue: 45000, maxRelayFee: 20000, wantErr: errBumpFeeTooHigh},
+	}
+	for _, test := range tests {
+		cfgDisableRBF = test.disableRBF
+		cfgMaxRelayFeePerKb = 500000
+		if test.maxRelayFee > 0 {
+			cfgMaxRelayFeePerKb = test.maxRelayFee
+		}
+		inputValue := testSwapValue + test.changeValue + 5000
+		swapTx := newTestSwapTx(testSwapTxid, []*tokens.BtcOutPoint{prevout}, []uint64{inputValue}, test.changeValue, test.sequence)
+		if test.confirmed {
+			setTestTxConfirmed(swapTx)
+		}
+		api := newTestChainAPI(swapTx)
+		if test.changeSpent {
+			api.setSpent(testSwapTxid, 2, testChildTxid, false)
+		}
+		b := newTestFeeBumpBridge(api)
+
+		extra, err := b.GetFeeBumpExtra(testPairID, testSwapTxid, "")
+		if test.wantErr != nil {
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("%v: want error %v, have %v", test.name, test.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: get fee bump extra failed: %v", test.name, err)
+			continue
+		}
+		if *extra.RelayFeePerKb != wantRelayFeePerKb {
+			t.Errorf("%v: want relay fee per kb %v, have %v", test.name, wantRelayFeePerKb, *extra.RelayFeePerKb)
+		}
+		if test.wantReplace {
+			if extra.ReplaceTx != testSwapTxid || extra.ParentTx != "" ||
+				len(extra.PreviousOutPoints) != 1 || *extra.PreviousOutPoints[0] != *prevout {
+				t.Errorf("%v: want rbf extra, have %+v", test.name, extra)
+			}
+		}
+		if test.wantParent {
+			if extra.ParentTx != testSwapTxid || extra.ReplaceTx != "" || len(extra.PreviousOutPoints) != 0 {
+				t.Errorf("%v: want cpfp extra, have %+v", test.name, extra)
+			}
+		}
+	}
+}
+
+func TestVerifyReplaceTx(t *testing.T) {
+	point0 := &tokens.BtcOutPoint{Hash: testInputTxid, Index: 0}
+	point1 := &tokens.BtcOutPoint{Hash: testOtherTxid, Index: 3}
+	replaceTx := newTestSwapTx(testSwapTxid, []*tokens.BtcOutPoint{point0, point1}, []uint64{30000, 30000}, 5000, rbfSequence)
+	confirmedTx := setTestTxConfirmed(newTestSwapTx(testChildTxid, []*tokens.BtcOutPoint{point0}, []uint64{60000}, 5000, rbfSequence))
+	b := newTestFeeBumpBridge(newTestChainAPI(replaceTx, confirmedTx))
+
+	tests := []struct {
+		name      string
+		replaceTx string
+		memo      string
+		points    []*tokens.BtcOutPoint
+		wantErr   string
+	}{
+		{name: "same inputs", replaceTx: testSwapTxid, memo: testMemo, points: []*tokens.BtcOutPoint{point0, point1}},
+		{name: "other swap", replaceTx: testSwapTxid, memo: "otherswapmemo", points: []*tokens.BtcOutPoint{point0, point1}, wantErr: errTxOfOtherSwap.Error()},
+		{name: "inputs count mismatch", replaceTx: testSwapTxid, memo: testMemo, points: []*tokens.BtcOutPoint{point0}, wantErr: "inputs count mismatch"},
+		{name: "inputs order mismatch", replaceTx: testSwapTxid, memo: testMemo, points: []*tokens.BtcOutPoint{point1, point0}, wantErr: "input 0 mismatch"},
+		{name: "confirmed tx", replaceTx: testChildTxid, memo: testMemo, points: []*tokens.BtcOutPoint{point0}, wantErr: errTxAlreadyConfirmed.Error()},
+	}
+	for _, test := range tests {
+		extra := &tokens.BtcExtraArgs{ReplaceTx: test.replaceTx, PreviousOutPoints: test.points}
+		tx, err := b.verifyReplaceTx(extra, test.memo)
+		switch {
+		case test.wantErr == "" && (err != nil || tx != replaceTx):
+			t.Errorf("%v: verify replace tx failed: %v", test.name, err)
+		case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+			t.Errorf("%v: want error %q, have %v", test.name, test.wantErr, err)
+		}
+	}
+}
+
+func TestBuildCPFPTransaction(t *testing.T) {
+	b := newTestBridge()
+	pkScript, err := b.GetPayToAddrScript(testDcrmAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// parent fee 5000 and vsize 226, child is p2pkh 1-in-1-out
+	childSize := b.estimateSize([][]byte{pkScript}, []*wireTxOutType{b.NewTxOut(0, pkScript)}, false, false)
+	if childSize != 193 {
+		t.Fatalf("wrong child size %v", childSize)
+	}
+	prevout := &tokens.BtcOutPoint{Hash: testInputTxid, Index: 0}
+	changePoint := &tokens.BtcOutPoint{Hash: testSwapTxid, Index: 2}
+	otherChangePoint := &tokens.BtcOutPoint{Hash: testOtherTxid, Index: 2}
+
+	tests := []struct {
+		name          string
+		changeValue   uint64
+		relayFeePerKb int64
+		replaceTx     *electrs.ElectTx
+		spender       *string // nil means change is not spent, empty means unknown spender
+		wantFee       int64
+		wantErr       string
+	}{
+		{
+			// package fee 419*40 = 16760, minus parent fee 5000
+			name: "child pays for package", changeValue: 45000, relayFeePerKb: 40000, wantFee: 11760,
+		},
+		{
+			// parent fee rate is higher, child pays 193*20 for itself
+			name: "child pays at least its own fee", changeValue: 45000, relayFeePerKb: 20000, wantFee: 3860,
+		},
+		{
+			// replaced child fee 5000 plus incremental relay fee 193*1
+			name: "replacement pays more absolute fee", changeValue: 45000, relayFeePerKb: 20000,
+			replaceTx: newTestChildTx(testChildTxid, changePoint, 45000, 40000), spender: new(string), wantFee: 5193,
+		},
+		{
+			name: "replacement spent by known replaced child", changeValue: 45000, relayFeePerKb: 40000,
+			replaceTx: newTestChildTx(testChildTxid, changePoint, 45000, 40000), spender: stringPtr(testChildTxid), wantFee: 11760,
+		},
+		{
+			name: "change spent by other tx", changeValue: 45000, relayFeePerKb: 40000,
+			spender: new(string), wantErr: errTxHasDescendant.Error(),
+		},
+		{
+			name: "change spent by other tx while replacing", changeValue: 45000, relayFeePerKb: 40000,
+			replaceTx: newTestChildTx(testChildTxid, changePoint, 45000, 40000), spender: stringPtr(testOtherTxid), wantErr: errTxHasDescendant.Error(),
+		},
+		{
+			name: "replaced tx is not child", changeValue: 45000, relayFeePerKb: 40000,
+			replaceTx: newTestChildTx(testChildTxid, otherChangePoint, 45000, 40000), spender: new(string), wantErr: "is not child of",
+		},
+		{
+			// 12000 - 11760 is less than dust threshold
+			name: "change below dust after fee", changeValue: 12000, relayFeePerKb: 40000, wantErr: "not enough change value",
+		},
+		{
+			name: "parent without change", relayFeePerKb: 40000, wantErr: errTxWithoutChange.Error(),
+		},
+	}
+	for _, test := range tests {
+		inputValue := testSwapValue + test.changeValue + 5000
+		parent := newTestSwapTx(testSwapTxid, []*tokens.BtcOutPoint{prevout}, []uint64{inputValue}, test.changeValue, maxReplaceableSequence)
+		api := newTestChainAPI(parent)
+		extra := &tokens.BtcExtraArgs{ParentTx: testSwapTxid}
+		if test.replaceTx != nil {
+			api.txs[*test.replaceTx.Txid] = test.replaceTx
+			extra.ReplaceTx = *test.replaceTx.Txid
+		}
+		if test.spender != nil {
+			api.setSpent(testSwapTxid, 2, *test.spender, false)
+		}
+		b = newTestFeeBumpBridge(api)
+
+		authoredTx, err := b.buildCPFPTransaction(testDcrmAddress, testMemo, extra, btcAmountType(test.relayFeePerKb))
+		if test.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("%v: want error %q, have %v", test.name, test.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: build cpfp tx failed: %v", test.name, err)
+			continue
+		}
+		tx := authoredTx.Tx
+		if len(tx.TxIn) != 1 || len(tx.TxOut) != 1 {
+			t.Errorf("%v: wrong cpfp tx inputs or outputs count", test.name)
+			continue
+		}
+		if point := tx.TxIn[0].PreviousOutPoint; point.Hash.String() != testSwapTxid || point.Index != 2 || tx.TxIn[0].Sequence != rbfSequence {
+			t.Errorf("%v: wrong cpfp tx input %v", test.name, point)
+		}
+		if fee := int64(test.changeValue) - tx.TxOut[0].Value; fee != test.wantFee {
+			t.Errorf("%v: want child fee %v, have %v", test.name, test.wantFee, fee)
+		}
+	}
+}
+
+func TestIsSpentByReplaceTx(t *testing.T) {
+	point := &tokens.BtcOutPoint{Hash: testSwapTxid, Index: 2}
+	replaceTx := newTestChildTx(testChildTxid, point, 45000, 40000)
+	otherTx := newTestChildTx(testOtherTxid, &tokens.BtcOutPoint{Hash: testSwapTxid, Index: 0}, 45000, 40000)
+
+	tests := []struct {
+		name      string
+		spender   string
+		confirmed bool
+		replaceTx *electrs.ElectTx
+		want      bool
+	}{
+		{name: "no replaced tx", spender: testChildTxid},
+		{name: "spent by replaced tx", spender: testChildTxid, replaceTx: replaceTx, want: true},
+		{name: "spent by other tx", spender: testOtherTxid, replaceTx: replaceTx},
+		{name: "spent by replaced tx on chain", spender: testChildTxid, confirmed: true, replaceTx: replaceTx},
+		{name: "unknown spender, replaced tx spends outpoint", replaceTx: replaceTx, want: true},
+		{name: "unknown spender, replaced tx spends other outpoint", replaceTx: otherTx},
+	}
+	for _, test := range tests {
+		api := newTestChainAPI()
+		api.setSpent(point.Hash, point.Index, test.spender, test.confirmed)
+		outspend, _ := api.GetOutspend(point.Hash, point.Index)
+		if have := isSpentByReplaceTx(outspend, point, test.replaceTx); have != test.want {
+			t.Errorf("%v: want %v, have %v", test.name, test.want, have)
+		}
+	}
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -7,79 +7,94 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 )
 
+func (b *Bridge) getChainAPI() ChainAPI {
+	if b.ChainAPI != nil {
+		return b.ChainAPI
+	}
+	return electrsAPI{b: b}
+}
+
+// WatchAddress import address to watch-only wallet if chain api requires
+func (b *Bridge) WatchAddress(addr string, rescan bool) error {
+	if watcher, ok := b.getChainAPI().(AddressWatcher); ok {
+		return watcher.WatchAddress(addr, rescan)
+	}
+	return nil
+}
+
 // GetLatestBlockNumberOf impl
 func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
-	return electrs.GetLatestBlockNumberOf(apiAddress)
+	return b.getChainAPI().GetLatestBlockNumberOf(apiAddress)
 }
 
 // GetLatestBlockNumber impl
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
-	return electrs.GetLatestBlockNumber(b)
+	return b.getChainAPI().GetLatestBlockNumber()
 }
 
 // GetTransactionByHash impl
 func (b *Bridge) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
-	return electrs.GetTransactionByHash(b, txHash)
+	return b.getChainAPI().GetTransactionByHash(txHash)
 }
 
 // GetElectTransactionStatus impl
 func (b *Bridge) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
-	return electrs.GetElectTransactionStatus(b, txHash)
+	return b.getChainAPI().GetElectTransactionStatus(txHash)
 }
 
 // FindUtxos impl
 func (b *Bridge) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
-	return electrs.FindUtxos(b, addr)
+	return b.getChainAPI().FindUtxos(addr)
 }
 
 // GetPoolTxidList impl
 func (b *Bridge) GetPoolTxidList() ([]string, error) {
-	return electrs.GetPoolTxidList(b)
+	return b.getChainAPI().GetPoolTxidList()
 }
 
 // GetPoolTransactions impl
 func (b *Bridge) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
-	return electrs.GetPoolTransactions(b, addr)
+	return b.getChainAPI().GetPoolTransactions(addr)
 }
 
 // GetTransactionHistory impl
 func (b *Bridge) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
-	return electrs.GetTransactionHistory(b, addr, lastSeenTxid)
+	return b.getChainAPI().GetTransactionHistory(addr, lastSeenTxid)
 }
 
 // GetOutspend impl
 func (b *Bridge) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
-	return electrs.GetOutspend(b, txHash, vout)
+	return b.getChainAPI().GetOutspend(txHash, vout)
 }
 
 // PostTransaction impl
 func (b *Bridge) PostTransaction(txHex string) (txHash string, err error) {
-	return electrs.PostTransaction(b, txHex)
+	return b.getChainAPI().PostTransaction(txHex)
 }
 
 // GetBlockHash impl
 func (b *Bridge) GetBlockHash(height uint64) (string, error) {
-	return electrs.GetBlockHash(b, height)
+	return b.getChainAPI().GetBlockHash(height)
 }
 
 // GetBlockTxids impl
 func (b *Bridge) GetBlockTxids(blockHash string) ([]string, error) {
-	return electrs.GetBlockTxids(b, blockHash)
+	return b.getChainAPI().GetBlockTxids(blockHash)
 }
 
 // GetBlock impl
 func (b *Bridge) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
-	return electrs.GetBlock(b, blockHash)
+	return b.getChainAPI().GetBlock(blockHash)
 }
 
 // GetBlockTransactions impl
 func (b *Bridge) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
-	return electrs.GetBlockTransactions(b, blockHash, startIndex)
+	return b.getChainAPI().GetBlockTransactions(blockHash, startIndex)
 }
 
 // EstimateFeePerKb impl
 func (b *Bridge) EstimateFeePerKb(blocks int) (int64, error) {
-	return electrs.EstimateFeePerKb(b, blocks)
+	return b.getChainAPI().EstimateFeePerKb(blocks)
 }
 
 // GetBalance impl
@@ -0,0 +1,101 @@
+package btc
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+// ChainAPI btc chain data source, electrs rest api is used by default
+type ChainAPI interface {
+	GetLatestBlockNumberOf(apiAddress string) (uint64, error)
+	GetLatestBlockNumber() (uint64, error)
+	GetTransactionByHash(txHash string) (*electrs.ElectTx, error)
+	GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error)
+	FindUtxos(addr string) ([]*electrs.ElectUtxo, error)
+	GetPoolTxidList() ([]string, error)
+	GetPoolTransactions(addr string) ([]*electrs.ElectTx, error)
+	GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error)
+	GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error)
+	PostTransaction(txHex string) (txHash string, err error)
+	GetBlockHash(height uint64) (string, error)
+	GetBlockTxids(blockHash string) ([]string, error)
+	GetBlock(blockHash string) (*electrs.ElectBlock, error)
+	GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error)
+	EstimateFeePerKb(blocks int) (int64, error)
+}
+
+// AddressWatcher chain api which only knows about watched addresses (eg. bitcoin core wallet),
+// rescan is to find history of address, which is only needed by existing addresses.
+type AddressWatcher interface {
+	WatchAddress(addr string, rescan bool) error
+}
+
+// electrsAPI call electrs rest api with gateway config of bridge
+type electrsAPI struct {
+	b *Bridge
+}
+
+// NewElectrsAPI new electrs chain api with gateway config of bridge,
+// it is used to wrap the default chain api.
+func NewElectrsAPI(b *Bridge) ChainAPI {
+	return electrsAPI{b: b}
+}
+
+func (e electrsAPI) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	return electrs.GetLatestBlockNumberOf(apiAddress)
+}
+
+func (e electrsAPI) GetLatestBlockNumber() (uint64, error) {
+	return electrs.GetLatestBlockNumber(e.b)
+}
+
+func (e electrsAPI) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	return electrs.GetTransactionByHash(e.b, txHash)
+}
+
+func (e electrsAPI) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	return electrs.GetElectTransactionStatus(e.b, txHash)
+}
+
+func (e electrsAPI) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	return electrs.FindUtxos(e.b, addr)
+}
+
+func (e electrsAPI) GetPoolTxidList() ([]string, error) {
+	return electrs.GetPoolTxidList(e.b)
+}
+
+func (e electrsAPI) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	return electrs.GetPoolTransactions(e.b, addr)
+}
+
+func (e electrsAPI) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	return electrs.GetTransactionHistory(e.b, addr, lastSeenTxid)
+}
+
+func (e electrsAPI) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	return electrs.GetOutspend(e.b, txHash, vout)
+}
+
+func (e electrsAPI) PostTransaction(txHex string) (txHash string, err error) {
+	return electrs.PostTransaction(e.b, txHex)
+}
+
+func (e electrsAPI) GetBlockHash(height uint64) (string, error) {
+	return electrs.GetBlockHash(e.b, height)
+}
+
+func (e electrsAPI) GetBlockTxids(blockHash string) ([]string, error) {
+	return electrs.GetBlockTxids(e.b, blockHash)
+}
+
+func (e electrsAPI) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
+	return electrs.GetBlock(e.b, blockHash)
+}
+
+func (e electrsAPI) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
+	return electrs.GetBlockTransactions(e.b, blockHash, startIndex)
+}
+
+func (e electrsAPI) EstimateFeePerKb(blocks int) (int64, error) {
+	return electrs.EstimateFeePerKb(e.b, blocks)
+}
@@ -0,0 +1,343 @@
+// Package coinselect selects utxos to fund tx for utxo-like bridges.
+//
+// Selection must be deterministic, because oracles rebuild the same tx
+// from the same utxos set when verifying the leader's tx, so all sorting
+// here has total order (ties are broken by txid and vout).
+package coinselect
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// utxo select strategies
+const (
+	// LargestFirst spend utxos with largest value first (default)
+	LargestFirst = "largest"
+	// SmallestFirst spend utxos with smallest value first to consolidate utxos
+	SmallestFirst = "smallest"
+	// BranchAndBound search changeless selection with minimum waste,
+	// fallback to largest first if not found
+	BranchAndBound = "bnb"
+	// AvoidReuse spend all outputs of the same funding tx together,
+	// so that outputs linked on chain once will not link other txs again
+	AvoidReuse = "avoidreuse"
+)
+
+const maxBnBTries = 100000
+
+// ErrInsufficientFunds not enough balance
+var ErrInsufficientFunds = errors.New("insufficient funds available to construct transaction")
+
+// Utxo candidate utxo
+type Utxo struct {
+	Txid      string
+	Vout      uint32
+	Value     int64
+	Confirmed bool
+}
+
+// Params select params, all amounts are in the smallest unit
+type Params struct {
+	// Target amount of outputs plus fee of tx except inputs
+	Target int64
+	// InputFee fee of spending one input at current fee rate
+	InputFee int64
+	// LongTermInputFee fee of spending one input at long term fee rate
+	LongTermInputFee int64
+	// CostOfChange fee of creating change output now and spending it later
+	CostOfChange int64
+	// MaxChangelessExcess excess not larger than it is dropped to fee without change
+	MaxChangelessExcess int64
+}
+
+// Selection select result
+type Selection struct {
+	Utxos      []*Utxo
+	Total      int64
+	Waste      int64
+	Changeless bool
+}
+
+// IsValidStrategy is valid strategy (empty means default)
+func IsValidStrategy(strategy string) bool {
+	switch strategy {
+	case "", LargestFirst, SmallestFirst, BranchAndBound, AvoidReuse:
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *Params) effectiveValue(utxo *Utxo) int64 {
+	return utxo.Value - p.InputFee
+}
+
+// Waste waste metric of spending utxos (ref. bitcoin core),
+// timing cost of inputs plus cost of change or excess dropped to fee
+func (p *Params) Waste(utxos []*Utxo) (waste int64, changeless bool) {
+	var effective int64
+	for _, utxo := range utxos {
+		effective += p.effectiveValue(utxo)
+		waste += p.InputFee - p.LongTermInputFee
+	}
+	excess := effective - p.Target
+	changeless = excess <= p.MaxChangelessExcess
+	if changeless {
+		waste += excess
+	} else {
+		waste += p.CostOfChange
+	}
+	return waste, changeless
+}
+
+func (p *Params) newSelection(utxos []*Utxo) *Selection {
+	sel := &Selection{Utxos: utxos}
+	for _, utxo := range utxos {
+		sel.Total += utxo.Value
+	}
+	sel.Waste, sel.Changeless = p.Waste(utxos)
+	return sel
+}
+
+// Select select utxos with strategy. isValid checks selected utxos,
+// invalid ones are removed from candidates and selection is retried.
+func Select(strategy string, utxos []*Utxo, params *Params, isValid func(*Utxo) bool) (*Selection, error) {
+	candidates := make([]*Utxo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if utxo.Value > 0 {
+			candidates = append(candidates, utxo)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return lessOutPoint(candidates[i], candidates[j])
+	})
+
+	checked := make(map[*Utxo]bool)
+	for {
+		selected := selectConfirmedFirst(strategy, candidates, params)
+		if selected == nil {
+			return nil, ErrInsufficientFunds
+		}
+		invalids := make(map[*Utxo]bool)
+		for _, utxo := range selected {
+			valid, exist := checked[utxo]
+			if !exist {
+				valid = isValid == nil || isValid(utxo)
+				checked[utxo] = valid
+			}
+			if !valid {
+				invalids[utxo] = true
+			}
+		}
+		if len(invalids) == 0 {
+			return params.newSelection(selected), nil
+		}
+		remains := candidates[:0]
+		for _, utxo := range candidates {
+			if !invalids[utxo] {
+				remains = append(remains, utxo)
+			}
+		}
+		candidates = remains
+	}
+}
+
+func selectConfirmedFirst(strategy string, candidates []*Utxo, params *Params) []*Utxo {
+	confirmed := make([]*Utxo, 0, len(candidates))
+	for _, utxo := range candidates {
+		if utxo.Confirmed {
+			confirmed = append(confirmed, utxo)
+		}
+	}
+	if len(confirmed) < len(candidates) {
+		if selected := selectByStrategy(strategy, confirmed, params); selected != nil {
+			return selected
+		}
+	}
+	return selectByStrategy(strategy, candidates, params)
+}
+
+func selectByStrategy(strategy string, utxos []*Utxo, params *Params) []*Utxo {
+	switch strategy {
+	case SmallestFirst:
+		return accumulate(sortByValue(utxos, false), params)
+	case BranchAndBound:
+		if selected := branchAndBound(utxos, params); selected != nil {
+			return selected
+		}
+		return accumulate(sortByValue(utxos, true), params)
+	case AvoidReuse:
+		return accumulateGroups(utxos, params)
+	default:
+		return accumulate(sortByValue(utxos, true), params)
+	}
+}
+
+func lessOutPoint(a, b *Utxo) bool {
+	if a.Txid != b.Txid {
+		return a.Txid < b.Txid
+	}
+	return a.Vout < b.Vout
+}
+
+func sortByValue(utxos []*Utxo, descending bool) []*Utxo {
+	sorted := make([]*Utxo, len(utxos))
+	copy(sorted, utxos)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Value != sorted[j].Value {
+			return (sorted[i].Value > sorted[j].Value) == descending
+		}
+		return lessOutPoint(sorted[i], sorted[j])
+	})
+	return sorted
+}
+
+// accumulate add sorted utxos until reach target, skip uneconomical ones
+func accumulate(sorted []*Utxo, params *Params) []*Utxo {
+	var (
+		selected  []*Utxo
+		effective int64
+	)
+	for _, utxo := range sorted {
+		value := params.effectiveValue(utxo)
+		if value <= 0 {
+			continue
+		}
+		selected = append(selected, utxo)
+		effective += value
+		if effective >= params.Target {
+			return selected
+		}
+	}
+	return nil
+}
+
+// accumulateGroups add utxos grouped by funding tx until reach target
+func accumulateGroups(utxos []*Utxo, params *Params) []*Utxo {
+	type utxoGroup struct {
+		utxos     []*Utxo
+		effective int64
+	}
+	var groups []*utxoGroup
+	groupIndex := make(map[string]int)
+	for _, utxo := range utxos {
+		index, exist := groupIndex[utxo.Txid]
+		if !exist {
+			index = len(groups)
+			groupIndex[utxo.Txid] = index
+			groups = append(groups, &utxoGroup{})
+		}
+		group := groups[index]
+		group.utxos = append(group.utxos, utxo)
+		group.effective += params.effectiveValue(utxo)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].effective != groups[j].effective {
+			return groups[i].effective > groups[j].effective
+		}
+		return lessOutPoint(groups[i].utxos[0], groups[j].utxos[0])
+	})
+
+	var (
+		selected  []*Utxo
+		effective int64
+	)
+	for _, group := range groups {
+		if group.effective <= 0 {
+			continue
+		}
+		selected = append(selected, group.utxos...)
+		effective += group.effective
+		if effective >= params.Target {
+			return selected
+		}
+	}
+	return nil
+}
+
+// branchAndBound depth first search for changeless selection
+// ref. https://github.com/bitcoin/bitcoin/blob/master/src/wallet/coinselection.cpp
+func branchAndBound(utxos []*Utxo, params *Params) []*Utxo {
+	pool := make([]*Utxo, 0, len(utxos))
+	for _, utxo := range sortByValue(utxos, true) {
+		if params.effectiveValue(utxo) > 0 {
+			pool = append(pool, utxo)
+		}
+	}
+
+	var available int64
+	for _, utxo := range pool {
+		available += params.effectiveValue(utxo)
+	}
+	if available < params.Target {
+		return nil
+	}
+
+	var (
+		upper      = params.Target + params.MaxChangelessExcess
+		feeDiff    = params.InputFee - params.LongTermInputFee
+		current    []int
+		best       []int
+		curValue   int64
+		curWaste   int64
+		bestWaste  = int64(math.MaxInt64)
+		index      int
+		backtrack  bool
+		effectives = make([]int64, len(pool))
+	)
+	for i, utxo := range pool {
+		effectives[i] = params.effectiveValue(utxo)
+	}
+
+	for tries := 0; tries < maxBnBTries; tries, index = tries+1, index+1 {
+		backtrack = false
+		switch {
+		case curValue+available < params.Target,
+			curValue > upper,
+			curWaste > bestWaste && feeDiff > 0:
+			backtrack = true
+		case curValue >= params.Target:
+			if waste := curWaste + curValue - params.Target; waste <= bestWaste {
+				best = append(best[:0], current...)
+				bestWaste = waste
+			}
+			backtrack = true
+		}
+
+		if backtrack {
+			if len(current) == 0 {
+				break
+			}
+			// restore the utxos omitted after the last included one
+			for index--; index > current[len(current)-1]; index-- {
+				available += effectives[index]
+			}
+			// exclude the last included one and try the next branch
+			curValue -= effectives[index]
+			curWaste -= feeDiff
+			current = current[:len(current)-1]
+			continue
+		}
+
+		available -= effectives[index]
+		// skip utxo equivalent to the previous excluded one, which is searched
+		if len(current) != 0 && current[len(current)-1] != index-1 &&
+			effectives[index] == effectives[index-1] {
+			continue
+		}
+		current = append(current, index)
+		curValue += effectives[index]
+		curWaste += feeDiff
+	}
+
+	if len(best) == 0 {
+		return nil
+	}
+	selected := make([]*Utxo, len(best))
+	for i, idx := range best {
+		selected[i] = pool[idx]
+	}
+	return selected
+}
@@ -0,0 +1,119 @@
+package coinselect
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newUtxos(values ...int64) []*Utxo {
+	utxos := make([]*Utxo, len(values))
+	for i, value := range values {
+		utxos[i] = &Utxo{
+			Txid:      fmt.Sprintf("%064x", i),
+			Value:     value,
+			Confirmed: true,
+		}
+	}
+	return utxos
+}
+
+func sumValues(utxos []*Utxo) (total int64) {
+	for _, utxo := range utxos {
+		total += utxo.Value
+	}
+	return total
+}
+
+var testParams = &Params{
+	Target:              10000,
+	InputFee:            100,
+	LongTermInputFee:    50,
+	CostOfChange:        300,
+	MaxChangelessExcess: 300,
+}
+
+func TestLargestAndSmallestFirst(t *testing.T) {
+	utxos := newUtxos(3000, 9000, 5000, 50, 20000)
+
+	sel, err := Select(LargestFirst, utxos, testParams, nil)
+	if err != nil || len(sel.Utxos) != 1 || sel.Total != 20000 {
+		t.Fatalf("largest first selected %v, err %v", sel, err)
+	}
+
+	sel, err = Select(SmallestFirst, utxos, testParams, nil)
+	if err != nil || len(sel.Utxos) != 3 || sel.Total != 17000 {
+		t.Fatalf("smallest first selected %v, err %v", sel, err)
+	}
+	for _, utxo := range sel.Utxos {
+		if utxo.Value == 50 {
+			t.Fatal("smallest first selected uneconomical utxo")
+		}
+	}
+}
+
+func TestBranchAndBoundChangeless(t *testing.T) {
+	utxos := newUtxos(20000, 7100, 4000, 3100, 1000)
+
+	sel, err := Select(BranchAndBound, utxos, testParams, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sel.Changeless || sumValues(sel.Utxos) != 10200 {
+		t.Fatalf("bnb selected total %v changeless %v", sel.Total, sel.Changeless)
+	}
+
+	// no changeless solution, fallback to largest first
+	sel, err = Select(BranchAndBound, newUtxos(20000, 5000), testParams, nil)
+	if err != nil || sel.Total != 20000 || sel.Changeless {
+		t.Fatalf("bnb fallback selected %v, err %v", sel, err)
+	}
+}
+
+func TestAvoidReuse(t *testing.T) {
+	utxos := newUtxos(6000, 6000, 8000)
+	utxos[1].Txid = utxos[0].Txid
+	utxos[1].Vout = 1
+
+	sel, err := Select(AvoidReuse, utxos, testParams, nil)
+	if err != nil || len(sel.Utxos) != 2 || sel.Utxos[0].Txid != sel.Utxos[1].Txid {
+		t.Fatalf("avoid reuse selected %v, err %v", sel, err)
+	}
+}
+
+func TestSelectValidAndConfirmedFirst(t *testing.T) {
+	utxos := newUtxos(30000, 20000, 12000)
+	utxos[0].Confirmed = false
+	invalid := utxos[1]
+
+	sel, err := Select(LargestFirst, utxos, testParams, func(utxo *Utxo) bool {
+		return utxo != invalid
+	})
+	if err != nil || len(sel.Utxos) != 1 || sel.Total != 12000 {
+		t.Fatalf("selected %v, err %v", sel, err)
+	}
+
+	_, err = Select(LargestFirst, newUtxos(5000, 5000), testParams, nil)
+	if err != ErrInsufficientFunds {
+		t.Fatalf("want insufficient funds, got %v", err)
+	}
+}
+
+func TestSelectDeterministic(t *testing.T) {
+	utxos := newUtxos(4000, 4000, 4000, 3000, 3000, 2100)
+	reversed := make([]*Utxo, len(utxos))
+	for i, utxo := range utxos {
+		reversed[len(utxos)-1-i] = utxo
+	}
+	for _, strategy := range []string{LargestFirst, SmallestFirst, BranchAndBound, AvoidReuse} {
+		sel1, err1 := Select(strategy, utxos, testParams, nil)
+		sel2, err2 := Select(strategy, reversed, testParams, nil)
+		if err1 != nil || err2 != nil || len(sel1.Utxos) != len(sel2.Utxos) {
+			t.Fatalf("strategy %v not deterministic", strategy)
+		}
+		for i := range sel1.Utxos {
+			if sel1.Utxos[i] != sel2.Utxos[i] {
+				t.Fatalf("strategy %v not deterministic", strategy)
+			}
+		}
+	}
+}
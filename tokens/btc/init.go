@@ -3,12 +3,14 @@ package btc
 import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/coinselect"
 )
 
 var (
 	cfgMinRelayFee       int64 = 400
 	cfgMinRelayFeePerKb  int64 = 2000
 	cfgMaxRelayFeePerKb  int64 = 500000
+	cfgMaxMinRelayFee    int64 = 100000 // 0.001 btc
 	cfgPlusFeePercentage uint64
 	cfgEstimateFeeBlocks = 6
 
@@ -17,8 +19,74 @@ var (
 	cfgUtxoAggregateMinCount  = 20
 	cfgUtxoAggregateMinValue  = uint64(1000000)
 	cfgUtxoAggregateToAddress string
+
+	cfgUtxoSelectStrategy string
+	cfgLongTermFeePerKb   int64
+
+	cfgDustLimit     int64
+	cfgSigHashForkID bool
+	cfgDisableRBF    bool
 )
 
+// SetDefaultRelayFee set default relay fees of chain derived from btc,
+// zero value keeps the btc default, and 'BtcExtra' config overrides them.
+func SetDefaultRelayFee(minRelayFee, minRelayFeePerKb, maxRelayFeePerKb int64) {
+	if minRelayFee > 0 {
+		cfgMinRelayFee = minRelayFee
+	}
+	if minRelayFeePerKb > 0 {
+		cfgMinRelayFeePerKb = minRelayFeePerKb
+	}
+	if maxRelayFeePerKb > 0 {
+		cfgMaxRelayFeePerKb = maxRelayFeePerKb
+	}
+}
+
+// SetMaxMinRelayFee set upper bound of configed min relay fee of chain derived
+// from btc, zero value keeps the btc default.
+func SetMaxMinRelayFee(maxMinRelayFee int64) {
+	if maxMinRelayFee > 0 {
+		cfgMaxMinRelayFee = maxMinRelayFee
+	}
+}
+
+// SetDefaultPlusFeePercentage set default plus percentage of estimated fee
+// of chain derived from btc, 'BtcExtra' config overrides it.
+func SetDefaultPlusFeePercentage(plusFeePercentage uint64) {
+	if plusFeePercentage > 0 {
+		cfgPlusFeePercentage = plusFeePercentage
+	}
+}
+
+// SetDefaultAggregate set default utxo aggregate thresholds of chain derived
+// from btc, zero value keeps the btc default, and 'BtcExtra' config overrides them.
+func SetDefaultAggregate(minCount int, minValue uint64) {
+	if minCount > 0 {
+		cfgUtxoAggregateMinCount = minCount
+	}
+	if minValue > 0 {
+		cfgUtxoAggregateMinValue = minValue
+	}
+}
+
+// SetDustLimit set dust limit of chain derived from btc,
+// zero value means use the dust threshold of default relay fee.
+func SetDustLimit(dustLimit int64) {
+	cfgDustLimit = dustLimit
+}
+
+// SetSigHashForkID set whether to sign with SIGHASH_FORKID (replay protection
+// of bitcoin cash), then all inputs are signed with BIP143 sighash.
+func SetSigHashForkID(forkID bool) {
+	cfgSigHashForkID = forkID
+}
+
+// SetDisableRBF set whether replace-by-fee is disabled (eg. bitcoin cash
+// does not relay replacement), then stuck swap txs are bumped by CPFP only.
+func SetDisableRBF(disable bool) {
+	cfgDisableRBF = disable
+}
+
 // Init init btc extra
 func Init(btcExtra *tokens.BtcExtraConfig) {
 	if BridgeInstance == nil {
@@ -32,6 +100,25 @@ func Init(btcExtra *tokens.BtcExtraConfig) {
 	initFromPublicKey()
 	initRelayFee(btcExtra)
 	initAggregate(btcExtra)
+	initUtxoSelect(btcExtra)
+	initWatchAddresses()
+}
+
+func initWatchAddresses() {
+	watcher, ok := BridgeInstance.(AddressWatcher)
+	if !ok {
+		return
+	}
+	// existing addresses rescan to find their utxos
+	tokenCfg := BridgeInstance.GetTokenConfig(PairID)
+	for _, addr := range []string{tokenCfg.DcrmAddress, tokenCfg.DepositAddress} {
+		if addr == "" {
+			continue
+		}
+		if err := watcher.WatchAddress(addr, true); err != nil {
+			log.Fatal("watch btc address failed", "address", addr, "err", err)
+		}
+	}
 }
 
 func initFromPublicKey() {
@@ -54,7 +141,7 @@ func initFromPublicKey() {
 func initRelayFee(btcExtra *tokens.BtcExtraConfig) {
 	if btcExtra.MinRelayFee > 0 {
 		cfgMinRelayFee = btcExtra.MinRelayFee
-		maxMinRelayFee, _ := newAmount(0.001)
+		maxMinRelayFee := btcAmountType(cfgMaxMinRelayFee)
 		minRelayFee := btcAmountType(cfgMinRelayFee)
 		if minRelayFee > maxMinRelayFee {
 			log.Fatal("BtcMinRelayFee is too large", "value", minRelayFee, "max", maxMinRelayFee)
@@ -106,3 +193,17 @@ func initAggregate(btcExtra *tokens.BtcExtraConfig) {
 
 	log.Info("Init Btc extra", "UtxoAggregateMinCount", cfgUtxoAggregateMinCount, "UtxoAggregateMinValue", cfgUtxoAggregateMinValue, "UtxoAggregateToAddress", cfgUtxoAggregateToAddress)
 }
+
+func initUtxoSelect(btcExtra *tokens.BtcExtraConfig) {
+	if !coinselect.IsValidStrategy(btcExtra.UtxoSelectStrategy) {
+		log.Fatal("wrong utxo select strategy", "strategy", btcExtra.UtxoSelectStrategy)
+	}
+	cfgUtxoSelectStrategy = btcExtra.UtxoSelectStrategy
+
+	cfgLongTermFeePerKb = cfgMinRelayFeePerKb
+	if btcExtra.LongTermFeePerKb > 0 {
+		cfgLongTermFeePerKb = btcExtra.LongTermFeePerKb
+	}
+
+	log.Info("Init Btc extra", "UtxoSelectStrategy", cfgUtxoSelectStrategy, "LongTermFeePerKb", cfgLongTermFeePerKb)
+}
@@ -8,20 +8,33 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 )
 
-func (b *Bridge) getP2shAddressWithMemo(memo, pubKeyHash []byte) (p2shAddress string, redeemScript []byte, err error) {
+func (b *Bridge) getP2shAddressWithMemo(memo, pubKeyHash []byte, isSegWit bool) (p2shAddress string, redeemScript []byte, err error) {
 	redeemScript, err = b.GetP2shRedeemScript(memo, pubKeyHash)
 	if err != nil {
 		return
 	}
+	p2shAddress, err = b.getScriptHashAddress(redeemScript, isSegWit)
+	return
+}
+
+// getScriptHashAddress get p2wsh address if isSegWit, otherwise p2sh address
+func (b *Bridge) getScriptHashAddress(redeemScript []byte, isSegWit bool) (string, error) {
+	if isSegWit {
+		addressWitnessScriptHash, err := b.NewAddressWitnessScriptHash(redeemScript)
+		if err != nil {
+			return "", err
+		}
+		return addressWitnessScriptHash.EncodeAddress(), nil
+	}
 	addressScriptHash, err := b.NewAddressScriptHash(redeemScript)
 	if err != nil {
-		return
+		return "", err
 	}
-	p2shAddress = addressScriptHash.EncodeAddress()
-	return
+	return addressScriptHash.EncodeAddress(), nil
 }
 
-// GetP2shAddress get p2sh address from bind address
+// GetP2shAddress get p2sh address from bind address,
+// get p2wsh address instead if dcrm address is native segwit (p2wpkh).
 func (b *Bridge) GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error) {
 	if !tokens.GetCrossChainBridge(!b.IsSrc).IsValidAddress(bindAddr) {
 		return "", nil, fmt.Errorf("invalid bind address %v", bindAddr)
@@ -39,7 +52,7 @@ func (b *Bridge) GetP2shAddress(bindAddr string) (p2shAddress string, redeemScri
 		return "", nil, fmt.Errorf("invalid dcrm address %v, %w", dcrmAddress, err)
 	}
 	pubKeyHash := address.ScriptAddress()
-	return b.getP2shAddressWithMemo(memo, pubKeyHash)
+	return b.getP2shAddressWithMemo(memo, pubKeyHash, isSegWitAddress(address))
 }
 
 func (b *Bridge) getRedeemScriptByOutputScrpit(preScript []byte) ([]byte, error) {
@@ -81,3 +94,12 @@ func (b *Bridge) GetP2shSigScript(redeemScript []byte) ([]byte, error) {
 	}
 	return b.GetPayToAddrScript(p2shAddr)
 }
+
+// GetP2wshSigScript get p2wsh output script
+func (b *Bridge) GetP2wshSigScript(witnessScript []byte) ([]byte, error) {
+	p2wshAddr, err := b.getScriptHashAddress(witnessScript, true)
+	if err != nil {
+		return nil, err
+	}
+	return b.GetPayToAddrScript(p2wshAddr)
+}
@@ -87,8 +87,8 @@ func (b *Bridge) CheckSwapinTxType(tx *electrs.ElectTx) (p2shBindAddrs []string,
 			continue
 		}
 		switch *output.ScriptpubkeyType {
-		case p2shType:
-			// use the first registered p2sh address
+		case p2shType, p2wshType:
+			// use the first registered p2sh (or p2wsh) address
 			p2shAddress := *output.ScriptpubkeyAddress
 			if _, exist := p2shAddressMap[p2shAddress]; exist {
 				continue
@@ -98,7 +98,7 @@ func (b *Bridge) CheckSwapinTxType(tx *electrs.ElectTx) (p2shBindAddrs []string,
 			if p2shBindAddr != "" {
 				p2shBindAddrs = append(p2shBindAddrs, p2shBindAddr)
 			}
-		case p2pkhType:
+		case p2pkhType, p2wpkhType:
 			if p2pkhSwapinPrior && *output.ScriptpubkeyAddress == depositAddress {
 				return nil, nil // use p2pkh if exist
 			}
@@ -0,0 +1,93 @@
+package btc
+
+import (
+	"encoding/hex"
+	"errors"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+const (
+	p2wpkhType = "v0_p2wpkh"
+	p2wshType  = "v0_p2wsh"
+
+	witnessScaleFactor = blockchain.WitnessScaleFactor
+
+	// witness of p2wsh bind address input: items count, signature,
+	// compressed public key and witness script with memo (at most 64 bytes)
+	redeemP2WSHInputWitnessWeight = 1 + 1 + 73 + 1 + 33 + 1 + (1 + 64 + 1 + 25)
+)
+
+var errMissingInputValues = errors.New("missing previous input values to sign segwit inputs")
+
+func isSegWitAddress(address btcutil.Address) bool {
+	switch address.(type) {
+	case *btcutil.AddressWitnessPubKeyHash, *btcutil.AddressWitnessScriptHash:
+		return true
+	default:
+		return false
+	}
+}
+
+// getScriptPubkeyType get electrs script pubkey type of address
+func (b *Bridge) getScriptPubkeyType(addr string) string {
+	address, err := b.DecodeAddress(addr)
+	if err != nil {
+		return ""
+	}
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return p2pkhType
+	case *btcutil.AddressScriptHash:
+		return p2shType
+	case *btcutil.AddressWitnessPubKeyHash:
+		return p2wpkhType
+	case *btcutil.AddressWitnessScriptHash:
+		return p2wshType
+	default:
+		return ""
+	}
+}
+
+// getSigHashes calc message hashes to sign of all inputs,
+// segwit inputs (or all inputs if SIGHASH_FORKID) use BIP143 sighash which commits to the input value.
+// sigScripts are the redeem (or witness) scripts if spending script hash outputs, otherwise nil.
+func (b *Bridge) getSigHashes(authoredTx *txauthor.AuthoredTx) (msgHashes []string, sigScripts [][]byte, err error) {
+	var (
+		hasP2shInput bool
+		sigHash      []byte
+	)
+	sigHashes := b.NewTxSigHashes(authoredTx.Tx)
+	for i, preScript := range authoredTx.PrevScripts {
+		sigScript := preScript
+		isP2wsh := b.IsPayToWitnessScriptHash(preScript)
+		if b.IsPayToScriptHash(preScript) || isP2wsh {
+			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
+			if err != nil {
+				return nil, nil, err
+			}
+			hasP2shInput = true
+		}
+
+		if isP2wsh || b.IsPayToWitnessPubKeyHash(preScript) || cfgSigHashForkID {
+			if i >= len(authoredTx.PrevInputValues) {
+				return nil, nil, errMissingInputValues
+			}
+			amount := int64(authoredTx.PrevInputValues[i])
+			sigHash, err = b.CalcWitnessSignatureHash(sigScript, sigHashes, authoredTx.Tx, i, amount)
+		} else {
+			sigHash, err = b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		msgHashes = append(msgHashes, hex.EncodeToString(sigHash))
+		sigScripts = append(sigScripts, sigScript)
+	}
+	if !hasP2shInput {
+		sigScripts = nil
+	}
+	return msgHashes, sigScripts, nil
+}
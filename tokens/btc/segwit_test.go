@@ -0,0 +1,101 @@
+package btc
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+)
+
+func newTestBridge() *Bridge {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{BlockChain: "Bitcoin", NetID: netMainnet}
+	return b
+}
+
+func TestSignSegWitAndLegacyInputs(t *testing.T) {
+	b := newTestBridge()
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	cPkData := privKey.PubKey().SerializeCompressed()
+
+	p2wpkhAddr, err := b.NewAddressWitnessPubKeyHash(cPkData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2pkhAddr, err := b.NewAddressPubKeyHash(cPkData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.IsP2wpkhAddress(p2wpkhAddr.EncodeAddress()) || b.getScriptPubkeyType(p2wpkhAddr.EncodeAddress()) != p2wpkhType {
+		t.Fatalf("wrong p2wpkh address %v", p2wpkhAddr.EncodeAddress())
+	}
+
+	p2wpkhScript, _ := b.GetPayToAddrScript(p2wpkhAddr.EncodeAddress())
+	p2pkhScript, _ := b.GetPayToAddrScript(p2pkhAddr.EncodeAddress())
+	prevScripts := [][]byte{p2wpkhScript, p2pkhScript}
+	prevValues := []btcAmountType{100000, 200000}
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	for i := range prevScripts {
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{byte(i + 1)}, uint32(i)), nil, nil))
+	}
+	tx.AddTxOut(wire.NewTxOut(250000, p2wpkhScript))
+	authoredTx := &txauthor.AuthoredTx{
+		Tx:              tx,
+		PrevScripts:     prevScripts,
+		PrevInputValues: prevValues,
+		TotalInput:      300000,
+		ChangeIndex:     -1,
+	}
+
+	_, _, err = b.SignTransactionWithPrivateKey(authoredTx, privKey.ToECDSA())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tx.TxIn[0].Witness) != 2 || len(tx.TxIn[0].SignatureScript) != 0 {
+		t.Fatal("p2wpkh input should be signed with witness")
+	}
+	if len(tx.TxIn[1].Witness) != 0 || len(tx.TxIn[1].SignatureScript) == 0 {
+		t.Fatal("p2pkh input should be signed with signature script")
+	}
+
+	sigHashes := txscript.NewTxSigHashes(tx)
+	for i, prevScript := range prevScripts {
+		vm, err := txscript.NewEngine(prevScript, tx, i, txscript.StandardVerifyFlags, nil, sigHashes, int64(prevValues[i]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = vm.Execute(); err != nil {
+			t.Fatalf("verify input %v failed, %v", i, err)
+		}
+	}
+
+	msgHashes, _, err := b.getSigHashes(authoredTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.VerifyMsgHash(authoredTx, msgHashes); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestEstimateSizeWithSegWitInputs(t *testing.T) {
+	b := newTestBridge()
+	p2wpkhScript := append([]byte{txscript.OP_0, txscript.OP_DATA_20}, make([]byte, 20)...)
+	p2pkhScript, _ := txscript.NewScriptBuilder().AddOp(txscript.OP_DUP).AddOp(txscript.OP_HASH160).
+		AddData(make([]byte, 20)).AddOp(txscript.OP_EQUALVERIFY).AddOp(txscript.OP_CHECKSIG).Script()
+	txOuts := []*wire.TxOut{wire.NewTxOut(1, p2wpkhScript)}
+
+	segwitSize := b.estimateSize([][]byte{p2wpkhScript, p2wpkhScript}, txOuts, true, false)
+	legacySize := b.estimateSize([][]byte{p2pkhScript, p2pkhScript}, txOuts, true, false)
+	if segwitSize >= legacySize {
+		t.Fatalf("segwit size %v should be less than legacy size %v", segwitSize, legacySize)
+	}
+}
@@ -3,7 +3,6 @@ package btc
 import (
 	"bytes"
 	"crypto/ecdsa"
-	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,13 +13,24 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/btcsuite/btcutil"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 )
 
 func (b *Bridge) verifyTransactionWithArgs(tx *txauthor.AuthoredTx, args *tokens.BuildTxArgs) error {
+	if len(args.Batch) != 0 {
+		return b.verifyBatchTransactionWithArgs(tx, args)
+	}
 	checkReceiver := args.Bind
 	if args.Identifier == tokens.AggregateIdentifier {
 		checkReceiver = cfgUtxoAggregateToAddress
+	} else if extra := args.Extra; extra != nil && extra.BtcExtra != nil && extra.BtcExtra.ParentTx != "" {
+		// child-pays-for-parent tx pays back to dcrm address
+		token := b.GetTokenConfig(args.PairID)
+		if token == nil {
+			return tokens.ErrUnknownPairID
+		}
+		checkReceiver = token.DcrmAddress
 	}
 	payToReceiverScript, err := b.GetPayToAddrScript(checkReceiver)
 	if err != nil {
@@ -39,6 +49,23 @@ func (b *Bridge) verifyTransactionWithArgs(tx *txauthor.AuthoredTx, args *tokens
 	return nil
 }
 
+func (b *Bridge) verifyBatchTransactionWithArgs(tx *txauthor.AuthoredTx, args *tokens.BuildTxArgs) error {
+	for _, item := range args.Batch {
+		if item.OutputIndex < 0 || item.OutputIndex >= len(tx.Tx.TxOut) || item.SwapValue == nil {
+			return fmt.Errorf("[sign] verify batch tx output of swap %v failed", item.SwapID)
+		}
+		payToReceiverScript, err := b.GetPayToAddrScript(item.Bind)
+		if err != nil {
+			return err
+		}
+		out := tx.Tx.TxOut[item.OutputIndex]
+		if !bytes.Equal(out.PkScript, payToReceiverScript) || out.Value != item.SwapValue.Int64() {
+			return fmt.Errorf("[sign] verify batch tx output of swap %v failed", item.SwapID)
+		}
+	}
+	return nil
+}
+
 // DcrmSignTransaction dcrm sign raw tx
 func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
 	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
@@ -56,37 +83,12 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 		return nil, "", err
 	}
 
-	var (
-		msgHashes    []string
-		rsvs         []string
-		sigScripts   [][]byte
-		hasP2shInput bool
-		sigHash      []byte
-	)
-
-	for i, preScript := range authoredTx.PrevScripts {
-		sigScript := preScript
-		if b.IsPayToScriptHash(preScript) {
-			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
-			if err != nil {
-				return nil, "", err
-			}
-			hasP2shInput = true
-		}
-
-		sigHash, err = b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
-		if err != nil {
-			return nil, "", err
-		}
-		msgHash := hex.EncodeToString(sigHash)
-		msgHashes = append(msgHashes, msgHash)
-		sigScripts = append(sigScripts, sigScript)
-	}
-	if !hasP2shInput {
-		sigScripts = nil
+	msgHashes, sigScripts, err := b.getSigHashes(authoredTx)
+	if err != nil {
+		return nil, "", err
 	}
 
-	rsvs, err = b.DcrmSignMsgHash(msgHashes, args)
+	rsvs, err := b.DcrmSignMsgHash(msgHashes, args)
 	if err != nil {
 		return nil, "", err
 	}
@@ -125,7 +127,18 @@ func (b *Bridge) MakeSignedTransaction(authoredTx *txauthor.AuthoredTx, msgHash,
 			return nil, "", errors.New("wrong RSV data")
 		}
 
-		sigScript, err := b.GetSigScript(sigScripts, authoredTx.PrevScripts[i], signData, cPkData, i)
+		prevScript := authoredTx.PrevScripts[i]
+		if b.IsPayToWitnessPubKeyHash(prevScript) || b.IsPayToWitnessScriptHash(prevScript) {
+			witness, err := b.GetWitness(sigScripts, prevScript, signData, cPkData, i)
+			if err != nil {
+				return nil, "", err
+			}
+			txin.SignatureScript = nil
+			txin.Witness = witness
+			continue
+		}
+
+		sigScript, err := b.GetSigScript(sigScripts, prevScript, signData, cPkData, i)
 		if err != nil {
 			return nil, "", err
 		}
@@ -136,9 +149,13 @@ func (b *Bridge) MakeSignedTransaction(authoredTx *txauthor.AuthoredTx, msgHash,
 	return authoredTx, txHash, nil
 }
 
-// VerifyRedeemScript verify redeem script
+// VerifyRedeemScript verify redeem script (or witness script of p2wsh)
 func (b *Bridge) VerifyRedeemScript(prevScript, redeemScript []byte) error {
-	p2shScript, err := b.GetP2shSigScript(redeemScript)
+	getScript := b.GetP2shSigScript
+	if b.IsPayToWitnessScriptHash(prevScript) {
+		getScript = b.GetP2wshSigScript
+	}
+	p2shScript, err := getScript(redeemScript)
 	if err != nil {
 		return err
 	}
@@ -176,7 +193,13 @@ func (b *Bridge) verifyPublickeyData(pkData []byte) error {
 	if dcrmAddress == "" {
 		return nil
 	}
-	address, err := b.NewAddressPubKeyHash(pkData)
+	var address btcutil.Address
+	var err error
+	if b.IsP2wpkhAddress(dcrmAddress) {
+		address, err = b.NewAddressWitnessPubKeyHash(pkData)
+	} else {
+		address, err = b.NewAddressPubKeyHash(pkData)
+	}
 	if err != nil {
 		return err
 	}
@@ -295,35 +318,12 @@ func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa
 		return nil, "", tokens.ErrWrongRawTx
 	}
 
-	var (
-		msgHashes    []string
-		rsvs         []string
-		sigScripts   [][]byte
-		hasP2shInput bool
-	)
-
-	for i, preScript := range authoredTx.PrevScripts {
-		sigScript := preScript
-		if b.IsPayToScriptHash(preScript) {
-			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
-			if err != nil {
-				return nil, "", err
-			}
-			hasP2shInput = true
-		}
-
-		sigHash, err := b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
-		if err != nil {
-			return nil, "", err
-		}
-		msgHash := hex.EncodeToString(sigHash)
-		msgHashes = append(msgHashes, msgHash)
-		sigScripts = append(sigScripts, sigScript)
-	}
-	if !hasP2shInput {
-		sigScripts = nil
+	msgHashes, sigScripts, err := b.getSigHashes(authoredTx)
+	if err != nil {
+		return nil, "", err
 	}
 
+	var rsvs []string
 	for _, msgHash := range msgHashes {
 		rsv, errf := b.SignWithECDSA(privKey, common.FromHex(msgHash))
 		if errf != nil {
@@ -45,7 +45,7 @@ func (b *Bridge) verifyP2shSwapinTx(pairID, txHash, bindAddress string, allowUns
 	if txStatus.BlockTime != nil {
 		swapInfo.Timestamp = *txStatus.BlockTime // Timestamp
 	}
-	value, _, rightReceiver := b.GetReceivedValue(tx.Vout, p2shAddress, p2shType)
+	value, _, rightReceiver := b.GetReceivedValue(tx.Vout, p2shAddress, b.getScriptPubkeyType(p2shAddress))
 	if !rightReceiver {
 		return swapInfo, tokens.ErrTxWithWrongReceiver
 	}
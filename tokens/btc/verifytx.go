@@ -1,7 +1,6 @@
 package btc
 
 import (
-	"encoding/hex"
 	"regexp"
 	"strings"
 
@@ -13,7 +12,8 @@ import (
 )
 
 var (
-	regexMemo = regexp.MustCompile(`^OP_RETURN OP_PUSHBYTES_\d* `)
+	// memo longer than 75 bytes is pushed by OP_PUSHDATA1 (eg. bitcoin cash allows 220 bytes)
+	regexMemo = regexp.MustCompile(`^OP_RETURN (OP_PUSHBYTES_\d*|OP_PUSHDATA1) `)
 )
 
 // GetTransaction impl
@@ -58,20 +58,16 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string) (err error)
 	if !ok {
 		return tokens.ErrWrongRawTx
 	}
-	for i, preScript := range authoredTx.PrevScripts {
-		sigScript := preScript
-		if b.IsPayToScriptHash(sigScript) {
-			sigScript, err = b.getRedeemScriptByOutputScrpit(preScript)
-			if err != nil {
-				return err
-			}
-		}
-		sigHash, err := b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
-		if err != nil {
-			return err
-		}
-		if hex.EncodeToString(sigHash) != msgHash[i] {
-			log.Trace("message hash mismatch", "index", i, "want", msgHash[i], "have", hex.EncodeToString(sigHash))
+	sigHashes, _, err := b.getSigHashes(authoredTx)
+	if err != nil {
+		return err
+	}
+	if len(sigHashes) != len(msgHash) {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	for i, sigHash := range sigHashes {
+		if sigHash != msgHash[i] {
+			log.Trace("message hash mismatch", "index", i, "want", msgHash[i], "have", sigHash)
 			return tokens.ErrMsgHashMismatch
 		}
 	}
@@ -91,7 +87,7 @@ func (b *Bridge) verifySwapinTx(pairID, txHash string, allowUnstable bool) (*tok
 	if tokenCfg == nil {
 		return nil, tokens.ErrUnknownPairID
 	}
-	if tokenCfg.DisableSwap {
+	if tokenCfg.IsSwapDisabled() {
 		return nil, tokens.ErrSwapIsClosed
 	}
 	swapInfo := &tokens.TxSwapInfo{}
@@ -116,7 +112,7 @@ func (b *Bridge) verifySwapinTx(pairID, txHash string, allowUnstable bool) (*tok
 		swapInfo.Timestamp = *txStatus.BlockTime // Timestamp
 	}
 	depositAddress := tokenCfg.DepositAddress
-	value, memoScript, rightReceiver := b.GetReceivedValue(tx.Vout, depositAddress, p2pkhType)
+	value, memoScript, rightReceiver := b.GetReceivedValue(tx.Vout, depositAddress, b.getScriptPubkeyType(depositAddress))
 	if !rightReceiver {
 		return swapInfo, tokens.ErrTxWithWrongReceiver
 	}
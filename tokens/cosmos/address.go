@@ -0,0 +1,40 @@
+package cosmos
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+const addressLength = 20
+
+var errInvalidAddress = errors.New("invalid cosmos address")
+
+// IsValidAddress check address (bech32 with chain prefix)
+func (b *Bridge) IsValidAddress(address string) bool {
+	_, err := b.decodeAddress(address)
+	return err == nil
+}
+
+func (b *Bridge) decodeAddress(address string) ([]byte, error) {
+	hrp, data, err := bech32.DecodeToBase256(address)
+	if err != nil {
+		return nil, err
+	}
+	if hrp != b.getBech32Prefix() || len(data) != addressLength {
+		return nil, errInvalidAddress
+	}
+	return data, nil
+}
+
+// PublicKeyToAddress bech32 address of ripemd160(sha256(compressed public key))
+func (b *Bridge) PublicKeyToAddress(pubKey *ecdsa.PublicKey) (string, error) {
+	return b.compressedPubKeyToAddress(crypto.CompressPubkey(pubKey))
+}
+
+func (b *Bridge) compressedPubKeyToAddress(pubKey []byte) (string, error) {
+	return bech32.EncodeFromBase256(b.getBech32Prefix(), btcutil.Hash160(pubKey))
+}
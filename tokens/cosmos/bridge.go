@@ -0,0 +1,174 @@
+// Package cosmos implements the bridge interfaces for cosmos-sdk chains.
+//
+// Swapin is a bank send (MsgSend) of the configed denom to the deposit address,
+// the bind address is specified by memo with prefix 'SWAPTO:'.
+// Swapout is a bank send from the dcrm address signed with 'SIGN_MODE_DIRECT',
+// the account sequence is managed as nonce (see 'tokens.NonceSetter').
+// Chain data are queried from the LCD (REST) endpoints of gateway.
+package cosmos
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	// BlockChain block chain name of cosmos-sdk chain
+	BlockChain = "Cosmos"
+
+	defaultGasLimit uint64 = 200000
+)
+
+// Bridge cosmos bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	*tools.SrcNonceSetterBase
+}
+
+func init() {
+	tokens.RegisterBridgeFactory(BlockChain, func(isSrc bool) tokens.CrossChainBridge {
+		return NewCrossChainBridge(isSrc)
+	})
+}
+
+// NewCrossChainBridge new cosmos bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	if !isSrc {
+		log.Fatalf("cosmos::NewCrossChainBridge error %v", tokens.ErrBridgeDestinationNotSupported)
+	}
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc),
+		SrcNonceSetterBase:   tools.NewSrcNonceSetterBase(),
+	}
+}
+
+// SetChainAndGateway set chain and gateway config
+func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	b.VerifyChainConfig()
+	b.VerifyChainID()
+	b.InitLatestBlockNumber()
+}
+
+// VerifyChainConfig verify chain config
+func (b *Bridge) VerifyChainConfig() {
+	chainCfg := b.ChainConfig
+	if chainCfg.CosmosChain == nil {
+		log.Fatal("cosmos chain must config 'CosmosChain'")
+	}
+	if err := chainCfg.CosmosChain.CheckConfig(); err != nil {
+		log.Fatal("check cosmos chain config failed", "err", err)
+	}
+}
+
+// VerifyChainID verify chain id of gateway with 'NetID'
+func (b *Bridge) VerifyChainID() {
+	var (
+		nodeInfo *NodeInfo
+		err      error
+	)
+	for i := 0; i < 5; i++ {
+		nodeInfo, err = b.GetNodeInfo()
+		if err == nil {
+			break
+		}
+		log.Errorf("can not get gateway node info. %v", err)
+		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		time.Sleep(1 * time.Second)
+	}
+	if err != nil {
+		log.Fatal("get node info failed", "err", err)
+	}
+	chainID := nodeInfo.DefaultNodeInfo.Network
+	if chainID != b.ChainConfig.NetID {
+		log.Fatalf("gateway chain id %v is not %v", chainID, b.ChainConfig.NetID)
+	}
+	log.Info("VerifyChainID succeed", "chainID", chainID, "denom", b.getDenom())
+}
+
+// VerifyTokenConfig verify token config
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	if !b.IsValidAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address: %v", tokenCfg.DcrmAddress)
+	}
+	if !b.IsValidAddress(tokenCfg.DepositAddress) {
+		return fmt.Errorf("invalid deposit address: %v", tokenCfg.DepositAddress)
+	}
+	if tokenCfg.ContractAddress != "" {
+		return fmt.Errorf("cosmos bridge does not support contract address: %v", tokenCfg.ContractAddress)
+	}
+	return b.verifyDcrmPublicKey(tokenCfg)
+}
+
+func (b *Bridge) verifyDcrmPublicKey(tokenCfg *tokens.TokenConfig) error {
+	if tokenCfg.DcrmPubkey == "" {
+		return nil
+	}
+	pubKey, err := crypto.UnmarshalPubkey(common.FromHex(tokenCfg.DcrmPubkey))
+	if err != nil {
+		return fmt.Errorf("wrong dcrm public key: %w", err)
+	}
+	address, err := b.PublicKeyToAddress(pubKey)
+	if err != nil {
+		return err
+	}
+	if address != tokenCfg.DcrmAddress {
+		return fmt.Errorf("dcrm address %v and public key address %v is not match", tokenCfg.DcrmAddress, address)
+	}
+	return nil
+}
+
+// InitLatestBlockNumber init latest block number
+func (b *Bridge) InitLatestBlockNumber() {
+	for {
+		latest, err := b.GetLatestBlockNumber()
+		if err == nil {
+			tokens.SetLatestBlockHeight(latest, b.IsSrc)
+			log.Info("get latst block number succeed.", "number", latest, "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID)
+			break
+		}
+		log.Error("get latst block number failed.", "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID, "err", err)
+		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		time.Sleep(3 * time.Second)
+	}
+}
+
+func (b *Bridge) getBech32Prefix() string {
+	return b.ChainConfig.CosmosChain.Bech32Prefix
+}
+
+func (b *Bridge) getDenom() string {
+	return b.ChainConfig.CosmosChain.Denom
+}
+
+func (b *Bridge) getGasLimit() uint64 {
+	if gasLimit := b.ChainConfig.CosmosChain.GasLimit; gasLimit > 0 {
+		return gasLimit
+	}
+	return defaultGasLimit
+}
+
+// calcFee fee = ceil(gas * gasPrice)
+func (b *Bridge) calcFee(gas uint64) *big.Int {
+	gasPrice := b.ChainConfig.CosmosChain.GasPrice
+	if gasPrice == "" {
+		return big.NewInt(0)
+	}
+	price, ok := new(big.Rat).SetString(gasPrice)
+	if !ok {
+		return big.NewInt(0)
+	}
+	fee := price.Mul(price, new(big.Rat).SetInt(new(big.Int).SetUint64(gas)))
+	quo, rem := new(big.Int).QuoRem(fee.Num(), fee.Denom(), new(big.Int))
+	if rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return quo
+}
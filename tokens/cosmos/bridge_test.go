@@ -0,0 +1,392 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	testChainID = "cosmoshub-4"
+	testDenom   = "uatom"
+)
+
+func newTestBridge(lcdURLs ...string) *Bridge {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain: BlockChain,
+		NetID:      testChainID,
+		CosmosChain: &tokens.CosmosChainConfig{
+			Bech32Prefix: "cosmos",
+			Denom:        testDenom,
+		},
+	}
+	b.GatewayConfig = &tokens.GatewayConfig{
+		APIAddress: lcdURLs,
+	}
+	return b
+}
+
+// fixed key (master key of bip32 test vector 1) and its address
+const (
+	testPrivKey = "e8f32e723decf4051aefac8e2c93c9c5b214313817cdb01a1494b917c8436b35"
+	testPubKey  = "0339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c2"
+	testAddress = "cosmos1x3ppj0smkuy3d6g525sh9n2w9k7fm7q3st3g09"
+
+	testZeroAddress = "cosmos1qqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqnrql8a" // account 0x00...00
+	testOnesAddress = "cosmos1zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3pahzj0" // account 0x11...11
+)
+
+func TestAddress(t *testing.T) {
+	b := newTestBridge()
+	privKey, err := crypto.HexToECDSA(testPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	address, err := b.PublicKeyToAddress(&privKey.PublicKey)
+	if err != nil || address != testAddress {
+		t.Fatalf("wrong address %v, want %v, err %v", address, testAddress, err)
+	}
+	address, err = b.compressedPubKeyToAddress(common.FromHex(testPubKey))
+	if err != nil || address != testAddress {
+		t.Fatalf("wrong address of compressed public key %v, err %v", address, err)
+	}
+	for _, addr := range []string{testAddress, testZeroAddress, testOnesAddress} {
+		if !b.IsValidAddress(addr) {
+			t.Fatalf("address %v should be valid", addr)
+		}
+	}
+
+	invalids := []string{
+		"osmo1x3ppj0smkuy3d6g525sh9n2w9k7fm7q3cszceh",   // wrong prefix
+		"cosmos1x3ppj0smkuy3d6g525sh9n2w9k7fm7q3st3g0q", // wrong checksum
+		"0x1111111111111111111111111111111111111111",
+		"",
+	}
+	for _, addr := range invalids {
+		if b.IsValidAddress(addr) {
+			t.Fatalf("address %v should be invalid", addr)
+		}
+	}
+}
+
+// known answers of sign doc, tx raw and tx hash encoded by cosmos-sdk v0.47
+const (
+	testSignDoc = "0a9d010a8d010a1c2f636f736d6f732e62616e6b2e763162657461312e4d736753656e64126d0a2d636f736d6f733171717171717171717171717171717171717171717171717171717171717171716e72716c3861122d636f736d6f73317a7967337a7967337a7967337a7967337a7967337a7967337a7967337a7967337061687a6a301a0d0a057561746f6d120431303030120b5357415054583a3078303112670a500a460a1f2f636f736d6f732e63727970746f2e736563703235366b312e5075624b657912230a2102a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc12040a020801180512130a0d0a057561746f6d12043530303010c09a0c1a0b636f736d6f736875622d34200c"
+	testTxRaw   = "0a9d010a8d010a1c2f636f736d6f732e62616e6b2e763162657461312e4d736753656e64126d0a2d636f736d6f733171717171717171717171717171717171717171717171717171717171717171716e72716c3861122d636f736d6f73317a7967337a7967337a7967337a7967337a7967337a7967337a7967337a7967337061687a6a301a0d0a057561746f6d120431303030120b5357415054583a3078303112670a500a460a1f2f636f736d6f732e63727970746f2e736563703235366b312e5075624b657912230a2102a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc12040a020801180512130a0d0a057561746f6d12043530303010c09a0c1a400102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f40"
+	testTxHash  = "C4056F5FC5C9A3BF317A608D80B85797E828C624A0324AD0B816B4FA0FB8666A"
+)
+
+func TestTransactionEncoding(t *testing.T) {
+	signature := make([]byte, 64)
+	for i := range signature {
+		signature[i] = byte(i + 1)
+	}
+	msg := &MsgSend{
+		FromAddress: testZeroAddress,
+		ToAddress:   testOnesAddress,
+		Amount:      []*Coin{{Denom: testDenom, Amount: big.NewInt(1000)}},
+	}
+	tx := &Transaction{
+		Msgs:          []*MsgSend{msg},
+		Memo:          tokens.UnlockMemoPrefix + "0x01",
+		PubKey:        common.FromHex("0x02a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc"),
+		Sequence:      5,
+		Fee:           &Coin{Denom: testDenom, Amount: big.NewInt(5000)},
+		Gas:           defaultGasLimit,
+		ChainID:       testChainID,
+		AccountNumber: 12,
+	}
+	if have := hex.EncodeToString(tx.SignBytes()); have != testSignDoc {
+		t.Fatalf("wrong sign doc\nhave %v\nwant %v", have, testSignDoc)
+	}
+	tx.Signature = signature
+	txBytes := tx.Marshal()
+	if have := hex.EncodeToString(txBytes); have != testTxRaw {
+		t.Fatalf("wrong tx raw\nhave %v\nwant %v", have, testTxRaw)
+	}
+	if tx.Hash() != testTxHash {
+		t.Fatalf("wrong tx hash %v, want %v", tx.Hash(), testTxHash)
+	}
+
+	msgs, memo, err := decodeTxMsgSends(txBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memo != tx.Memo || len(msgs) != 1 {
+		t.Fatalf("wrong decoded memo %v or count of msgs %v", memo, len(msgs))
+	}
+	decoded := msgs[0]
+	if decoded.FromAddress != msg.FromAddress || decoded.ToAddress != msg.ToAddress ||
+		decoded.GetAmount(testDenom).Cmp(big.NewInt(1000)) != 0 {
+		t.Fatalf("wrong decoded msg %+v", decoded)
+	}
+}
+
+// signature, tx raw and hash of a tx signed by the fixed key with cosmos-sdk v0.47
+const (
+	testSignature    = "bbc1581201f6991ea242a0cb62ac39ae5e241638647fcf29e97ef2798e993a9c6dcfa81d1c49414749eab9406db3ab67db9b072097c206b9b5ac06e87672f6d7"
+	testSignedTxRaw  = "0a9d010a8d010a1c2f636f736d6f732e62616e6b2e763162657461312e4d736753656e64126d0a2d636f736d6f7331783370706a30736d6b7579336436673532357368396e3277396b37666d377133737433673039122d636f736d6f73317a7967337a7967337a7967337a7967337a7967337a7967337a7967337a7967337061687a6a301a0d0a057561746f6d120431303030120b5357415054583a3078303212670a500a460a1f2f636f736d6f732e63727970746f2e736563703235366b312e5075624b657912230a210339a36013301597daef41fbe593a02cc513d0b55527ec2df1050e2e8ff49c85c212040a020801180512130a0d0a057561746f6d12043530303010c09a0c1a40bbc1581201f6991ea242a0cb62ac39ae5e241638647fcf29e97ef2798e993a9c6dcfa81d1c49414749eab9406db3ab67db9b072097c206b9b5ac06e87672f6d7"
+	testSignedTxHash = "0EF022832E9A4898124A31F24E3CF6927B48B85C63031178B86153A4777DBA81"
+)
+
+func TestSignTransaction(t *testing.T) {
+	lcd := newTestLCD(t)
+	defer lcd.Close()
+	b := newTestBridge(lcd.URL)
+	b.GatewayConfig.APIAddressExt = []string{lcd.URL}
+
+	privKey, err := crypto.HexToECDSA(testPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := &Transaction{
+		Msgs: []*MsgSend{{
+			FromAddress: testAddress,
+			ToAddress:   testOnesAddress,
+			Amount:      []*Coin{{Denom: testDenom, Amount: big.NewInt(1000)}},
+		}},
+		Memo:          tokens.UnlockMemoPrefix + "0x02",
+		PubKey:        common.FromHex(testPubKey),
+		Sequence:      5,
+		Fee:           &Coin{Denom: testDenom, Amount: big.NewInt(5000)},
+		Gas:           defaultGasLimit,
+		ChainID:       testChainID,
+		AccountNumber: 12,
+	}
+	if err = b.VerifyMsgHash(tx, []string{tx.SignHash().String()}); err != nil {
+		t.Fatal(err)
+	}
+	_, txHash, err := b.SignTransactionWithPrivateKey(tx, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := hex.EncodeToString(tx.Signature); have != testSignature {
+		t.Fatalf("wrong signature\nhave %v\nwant %v", have, testSignature)
+	}
+	if txHash != testSignedTxHash {
+		t.Fatalf("wrong signed tx hash %v, want %v", txHash, testSignedTxHash)
+	}
+
+	// dcrm signature may have high s
+	highS := make([]byte, 65)
+	copy(highS, tx.Signature)
+	copy(highS[32:64], common.LeftPadBytes(new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(tx.Signature[32:])).Bytes(), 32))
+	if hex.EncodeToString(toLowSSignature(highS)) != testSignature {
+		t.Fatal("convert high s signature failed")
+	}
+
+	// signed tx is broadcasted to every gateway
+	txHash, err = b.SendTransaction(tx)
+	if err != nil || txHash != testSignedTxHash {
+		t.Fatalf("send tx hash %v, err %v", txHash, err)
+	}
+	broadcasts := lcd.getBroadcasts()
+	if len(broadcasts) != 2 {
+		t.Fatalf("tx is broadcasted %v times", len(broadcasts))
+	}
+	for _, broadcast := range broadcasts {
+		if broadcast != testSignedTxRaw {
+			t.Fatalf("wrong broadcasted tx\nhave %v\nwant %v", broadcast, testSignedTxRaw)
+		}
+	}
+	if len(b.GatewayConfig.APIAddress) != 1 {
+		t.Fatal("gateway urls are modified by broadcasting")
+	}
+}
+
+func TestCalcFee(t *testing.T) {
+	b := newTestBridge()
+	if fee := b.calcFee(200000); fee.Sign() != 0 {
+		t.Fatalf("fee should be zero without gas price, have %v", fee)
+	}
+	b.ChainConfig.CosmosChain.GasPrice = "0.025"
+	if fee := b.calcFee(200000); fee.Cmp(big.NewInt(5000)) != 0 {
+		t.Fatalf("wrong fee %v, want 5000", fee)
+	}
+	if fee := b.calcFee(200001); fee.Cmp(big.NewInt(5001)) != 0 {
+		t.Fatalf("wrong fee %v, want 5001 (rounded up)", fee)
+	}
+}
+
+// testLCD fake LCD rest api (grpc-gateway) of cosmos-sdk node,
+// missing items are responded with status 404 and grpc NotFound code.
+type testLCD struct {
+	*httptest.Server
+	t *testing.T
+
+	lock       sync.Mutex
+	height     uint64
+	txs        map[string]interface{} // txs by upper case hash
+	accounts   map[string]interface{} // base accounts by address
+	broadcasts []string               // hex of broadcasted tx bytes
+}
+
+func newTestLCD(t *testing.T) *testLCD {
+	lcd := &testLCD{
+		t:        t,
+		txs:      make(map[string]interface{}),
+		accounts: make(map[string]interface{}),
+	}
+	lcd.Server = httptest.NewServer(http.HandlerFunc(lcd.serve))
+	return lcd
+}
+
+func (lcd *testLCD) serve(w http.ResponseWriter, r *http.Request) {
+	lcd.lock.Lock()
+	defer lcd.lock.Unlock()
+
+	var result interface{}
+	path := r.URL.Path
+	switch {
+	case r.Method == http.MethodPost && path == "/cosmos/tx/v1beta1/txs":
+		result = lcd.broadcast(r)
+	case r.Method != http.MethodGet:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	case path == "/cosmos/base/tendermint/v1beta1/blocks/latest":
+		result = map[string]interface{}{
+			"block_id": map[string]interface{}{"hash": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+			"block":    map[string]interface{}{"header": map[string]interface{}{"height": strconv.FormatUint(lcd.height, 10)}},
+		}
+	case strings.HasPrefix(path, "/cosmos/tx/v1beta1/txs/"):
+		result = lcd.txs[strings.TrimPrefix(path, "/cosmos/tx/v1beta1/txs/")]
+	case strings.HasPrefix(path, "/cosmos/auth/v1beta1/accounts/"):
+		if account, exist := lcd.accounts[strings.TrimPrefix(path, "/cosmos/auth/v1beta1/accounts/")]; exist {
+			result = map[string]interface{}{"account": account}
+		}
+	}
+	if result == nil {
+		w.WriteHeader(http.StatusNotFound)
+		result = map[string]interface{}{"code": 5, "message": path + ": not found", "details": []interface{}{}}
+	}
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		lcd.t.Error(err)
+	}
+}
+
+// broadcast record tx bytes and respond tx response of sync mode
+func (lcd *testLCD) broadcast(r *http.Request) interface{} {
+	var req struct {
+		TxBytes string `json:"tx_bytes"`
+		Mode    string `json:"mode"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Mode != "BROADCAST_MODE_SYNC" {
+		lcd.t.Errorf("wrong broadcast request %+v, err %v", req, err)
+	}
+	txBytes, err := base64.StdEncoding.DecodeString(req.TxBytes)
+	if err != nil {
+		lcd.t.Error(err)
+	}
+	lcd.broadcasts = append(lcd.broadcasts, hex.EncodeToString(txBytes))
+	hash := sha256.Sum256(txBytes)
+	return map[string]interface{}{
+		"tx_response": map[string]interface{}{"txhash": strings.ToUpper(hex.EncodeToString(hash[:])), "code": 0},
+	}
+}
+
+func (lcd *testLCD) getBroadcasts() []string {
+	lcd.lock.Lock()
+	defer lcd.lock.Unlock()
+	return lcd.broadcasts
+}
+
+func TestVerifyBankSendTx(t *testing.T) {
+	txHash := "6EC3FCB8D8A9EEAF8EEF6C3DCFBB1F7A04A1D2D2B26F0D8A5CFD0B4AA2E2C6B1"
+	sender, deposit, dcrm := testZeroAddress, testOnesAddress, testAddress
+	bind := "0x3333333333333333333333333333333333333333"
+
+	lcd := newTestLCD(t)
+	defer lcd.Close()
+	lcd.height = 100
+	lcd.txs[txHash] = map[string]interface{}{
+		"tx": map[string]interface{}{
+			"body": map[string]interface{}{
+				"messages": []interface{}{
+					map[string]interface{}{
+						"@type":        msgSendTypeURL,
+						"from_address": sender,
+						"to_address":   deposit,
+						"amount": []interface{}{
+							map[string]interface{}{"denom": testDenom, "amount": "1000"},
+							map[string]interface{}{"denom": "uosmo", "amount": "7"},
+						},
+					},
+					map[string]interface{}{
+						"@type":        msgSendTypeURL,
+						"from_address": sender,
+						"to_address":   deposit,
+						"amount":       []interface{}{map[string]interface{}{"denom": testDenom, "amount": "234"}},
+					},
+				},
+				"memo": tokens.LockMemoPrefix + bind,
+			},
+		},
+		"tx_response": map[string]interface{}{
+			"height":    "90",
+			"txhash":    txHash,
+			"code":      0,
+			"timestamp": "2021-01-01T00:00:00Z",
+		},
+	}
+	lcd.accounts[dcrm] = map[string]interface{}{
+		"@type":          "/cosmos.auth.v1beta1.BaseAccount",
+		"address":        dcrm,
+		"account_number": "12",
+		"sequence":       "34",
+	}
+	b := newTestBridge(lcd.URL)
+
+	latest, err := b.GetLatestBlockNumber()
+	if err != nil || latest != 100 {
+		t.Fatalf("get latest block number %v, err %v", latest, err)
+	}
+	nonce, err := b.GetPoolNonce(dcrm, "latest")
+	if err != nil || nonce != 34 {
+		t.Fatalf("get pool nonce %v, err %v", nonce, err)
+	}
+
+	tx, err := b.GetTransactionByHash(strings.ToLower(txHash))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := b.GetTransactionReceipt(txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *receipt.Status != 1 || receipt.BlockNumber.ToInt().Uint64() != 90 {
+		t.Fatal("wrong receipt status or block number")
+	}
+	if _, err = b.GetTransactionByHash(testSignedTxHash); err == nil {
+		t.Fatal("get unknown tx should fail")
+	}
+
+	swapInfo := &tokens.TxSwapInfo{Hash: txHash}
+	err = b.verifyBankSendTx(swapInfo, tx, &tokens.TokenConfig{DepositAddress: deposit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapInfo.From != sender || swapInfo.To != deposit || swapInfo.Bind != bind {
+		t.Fatalf("wrong swapin sender %v, receiver %v or bind %v", swapInfo.From, swapInfo.To, swapInfo.Bind)
+	}
+	if swapInfo.Value.Cmp(big.NewInt(1234)) != 0 || swapInfo.Height != 90 || swapInfo.Timestamp != 1609459200 {
+		t.Fatalf("wrong swapin value %v, height %v or timestamp %v", swapInfo.Value, swapInfo.Height, swapInfo.Timestamp)
+	}
+
+	err = b.verifyBankSendTx(&tokens.TxSwapInfo{Hash: txHash}, tx, &tokens.TokenConfig{DepositAddress: dcrm})
+	if err != tokens.ErrTxWithWrongReceiver {
+		t.Fatalf("verify with wrong deposit address should fail, err %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+var (
+	errEmptyIdentifier        = errors.New("build swaptx without identifier")
+	errNoSenderSpecified      = errors.New("build swaptx without specify sender")
+	errNonzeroValueSpecified  = errors.New("build swap tx with non-zero value")
+	errInvalidReceiverAddress = errors.New("invalid receiver address")
+	errNoPublicKey            = errors.New("no dcrm public key or private key")
+	errWrongFee               = errors.New("wrong fee in extra args")
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	err = b.checkBuildTxArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	token := b.GetTokenConfig(args.PairID)
+	if token == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	if !b.IsValidAddress(args.From) {
+		return nil, errNoSenderSpecified
+	}
+	if !b.IsValidAddress(args.Bind) {
+		log.Warn("swapout to wrong address", "receiver", args.Bind)
+		return nil, errInvalidReceiverAddress
+	}
+
+	swapValue := tokens.CalcSwappedValue(args.PairID, args.OriginValue, false)
+	args.SwapValue = swapValue // swap value
+	args.To = args.Bind        // to
+	args.Value = swapValue     // value
+
+	extra, err := b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+	fee, ok := parseAmount(*extra.Fee)
+	if !ok || fee.Sign() < 0 {
+		return nil, errWrongFee
+	}
+	pubKey, err := b.getSenderPublicKey(token)
+	if err != nil {
+		return nil, err
+	}
+
+	denom := b.getDenom()
+	tx := &Transaction{
+		Msgs: []*MsgSend{{
+			FromAddress: args.From,
+			ToAddress:   args.Bind,
+			Amount:      []*Coin{{Denom: denom, Amount: swapValue}},
+		}},
+		Memo:          tokens.UnlockMemoPrefix + args.SwapID,
+		PubKey:        pubKey,
+		Sequence:      *extra.Sequence,
+		Fee:           &Coin{Denom: denom, Amount: fee},
+		Gas:           *extra.Gas,
+		ChainID:       b.ChainConfig.NetID,
+		AccountNumber: *extra.AccountNumber,
+	}
+
+	err = b.checkBalance(args.From, new(big.Int).Add(swapValue, fee))
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("build raw tx", "pairID", args.PairID, "identifier", args.Identifier,
+		"swapID", args.SwapID, "swapType", args.SwapType,
+		"bind", args.Bind, "originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"from", args.From, "to", args.To, "value", args.Value, "memo", tx.Memo,
+		"accountNumber", tx.AccountNumber, "sequence", tx.Sequence,
+		"gas", tx.Gas, "fee", fee, "chainID", tx.ChainID, "msghash", tx.SignHash().String())
+
+	return tx, nil
+}
+
+func (b *Bridge) checkBuildTxArgs(args *tokens.BuildTxArgs) error {
+	if args.Identifier == "" {
+		return errEmptyIdentifier
+	}
+	if args.From == "" {
+		return errNoSenderSpecified
+	}
+	if args.Value != nil && args.Value.Sign() != 0 {
+		return errNonzeroValueSpecified
+	}
+
+	switch args.SwapType {
+	case tokens.SwapoutType:
+		if !b.IsSrc {
+			return tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+	case tokens.SwapinType:
+		return tokens.ErrBuildSwapTxInWrongEndpoint
+	default:
+		return tokens.ErrUnknownSwapType
+	}
+
+	return nil
+}
+
+// setDefaults account number, sequence, gas and fee are set if not specified,
+// and then recorded in extra args to rebuild the same tx.
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (extra *tokens.CosmosExtraArgs, err error) {
+	if args.Extra == nil || args.Extra.CosmosExtra == nil {
+		extra = &tokens.CosmosExtraArgs{}
+		args.Extra = &tokens.AllExtras{CosmosExtra: extra}
+	} else {
+		extra = args.Extra.CosmosExtra
+	}
+	if extra.AccountNumber == nil || extra.Sequence == nil {
+		account, err := b.GetAccount(args.From)
+		if err != nil {
+			return nil, err
+		}
+		if extra.AccountNumber == nil {
+			accountNumber, err := account.GetAccountNumber()
+			if err != nil {
+				return nil, err
+			}
+			extra.AccountNumber = &accountNumber
+		}
+		if extra.Sequence == nil {
+			sequence, err := account.GetSequence()
+			if err != nil {
+				return nil, err
+			}
+			sequence = b.AdjustNonce(args.PairID, sequence)
+			extra.Sequence = &sequence
+		}
+	}
+	if extra.Gas == nil {
+		gas := b.getGasLimit()
+		extra.Gas = &gas
+	}
+	if extra.Fee == nil {
+		fee := b.calcFee(*extra.Gas).String()
+		extra.Fee = &fee
+	}
+	return extra, nil
+}
+
+// getSenderPublicKey get compressed public key of dcrm address,
+// from dcrm public key or private key (if sign with private key)
+func (b *Bridge) getSenderPublicKey(token *tokens.TokenConfig) ([]byte, error) {
+	if token.DcrmPubkey != "" {
+		pubKey, err := crypto.UnmarshalPubkey(common.FromHex(token.DcrmPubkey))
+		if err != nil {
+			return nil, fmt.Errorf("wrong dcrm public key: %w", err)
+		}
+		return crypto.CompressPubkey(pubKey), nil
+	}
+	if privKey := token.GetDcrmAddressPrivateKey(); privKey != nil {
+		return crypto.CompressPubkey(&privKey.PublicKey), nil
+	}
+	return nil, errNoPublicKey
+}
+
+func (b *Bridge) checkBalance(account string, amount *big.Int) error {
+	balance, err := b.GetBalance(account)
+	if err != nil {
+		log.Warn("get balance error", "account", account, "err", err)
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("not enough %v balance. %v < %v", b.getDenom(), balance, amount)
+	}
+	return nil
+}
@@ -0,0 +1,267 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const rpcTimeout = 10 // seconds
+
+var (
+	errEmptyURLs    = errors.New("empty URLs")
+	errNoTxResponse = errors.New("no tx response")
+)
+
+// callAPI get from LCD endpoint (eg. '/cosmos/base/tendermint/v1beta1/blocks/latest')
+func callAPI(result interface{}, url, path string, params map[string]string) error {
+	fullURL := strings.TrimSuffix(url, "/") + path
+	return client.RPCGetRequest(result, fullURL, params, nil, rpcTimeout)
+}
+
+// callAPIOfGateway call LCD endpoint of gateway urls until success
+func (b *Bridge) callAPIOfGateway(result interface{}, path string, params map[string]string) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = callAPI(result, url, path, params)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// GetNodeInfo call /cosmos/base/tendermint/v1beta1/node_info
+func (b *Bridge) GetNodeInfo() (*NodeInfo, error) {
+	var result NodeInfo
+	err := b.callAPIOfGateway(&result, "/cosmos/base/tendermint/v1beta1/node_info", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetLatestBlockNumberOf call /cosmos/base/tendermint/v1beta1/blocks/latest
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	var result BlockResult
+	err := callAPI(&result, url, "/cosmos/base/tendermint/v1beta1/blocks/latest", nil)
+	if err != nil {
+		return 0, err
+	}
+	return result.Height(), nil
+}
+
+// GetLatestBlockNumber get max latest block number of gateways
+func (b *Bridge) GetLatestBlockNumber() (maxHeight uint64, err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return 0, errEmptyURLs
+	}
+	for _, url := range urls {
+		height, errf := b.GetLatestBlockNumberOf(url)
+		if errf != nil {
+			err = errf
+			continue
+		}
+		if height > maxHeight {
+			maxHeight = height
+		}
+	}
+	if maxHeight > 0 {
+		tokens.CmpAndSetLatestBlockHeight(maxHeight, b.IsSrcEndpoint())
+		return maxHeight, nil
+	}
+	return 0, err
+}
+
+// GetBlockByNumber call /cosmos/base/tendermint/v1beta1/blocks/{height}
+func (b *Bridge) GetBlockByNumber(number uint64) (*BlockResult, error) {
+	var result BlockResult
+	err := b.callAPIOfGateway(&result, fmt.Sprintf("/cosmos/base/tendermint/v1beta1/blocks/%d", number), nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.Height() != number {
+		return nil, fmt.Errorf("block %v not found", number)
+	}
+	return &result, nil
+}
+
+// GetBlockHash get block hash of height
+func (b *Bridge) GetBlockHash(height uint64) (string, error) {
+	block, err := b.GetBlockByNumber(height)
+	if err != nil {
+		return "", err
+	}
+	return block.Hash(), nil
+}
+
+// GetTransactionByHash call /cosmos/tx/v1beta1/txs/{hash}
+func (b *Bridge) GetTransactionByHash(txHash string) (*TxResult, error) {
+	var result TxResult
+	err := b.callAPIOfGateway(&result, "/cosmos/tx/v1beta1/txs/"+strings.ToUpper(txHash), nil)
+	if err != nil {
+		return nil, err
+	}
+	if result.TxResponse == nil || result.TxResponse.BlockHeight() == 0 {
+		return nil, tokens.ErrTxNotFound
+	}
+	return &result, nil
+}
+
+// GetTransactionReceipt get tx response in form of ethereum receipt,
+// status is 1 if tx is executed successfully (code is 0).
+func (b *Bridge) GetTransactionReceipt(txHash string) (*types.RPCTxReceipt, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return toReceipt(tx.TxResponse), nil
+}
+
+func toReceipt(txResp *TxResponse) *types.RPCTxReceipt {
+	status := hexutil.Uint64(0)
+	if txResp.IsSuccess() {
+		status = 1
+	}
+	txHash := common.HexToHash(txResp.TxHash)
+	blockNumber := (*hexutil.Big)(new(big.Int).SetUint64(txResp.BlockHeight()))
+	gasUsed, _ := strconv.ParseUint(txResp.GasUsed, 10, 64)
+	gas := hexutil.Uint64(gasUsed)
+	return &types.RPCTxReceipt{
+		TxHash:      &txHash,
+		BlockNumber: blockNumber,
+		Status:      &status,
+		GasUsed:     &gas,
+	}
+}
+
+// GetAccount call /cosmos/auth/v1beta1/accounts/{address}
+func (b *Bridge) GetAccount(address string) (*AccountResult, error) {
+	var result AccountResult
+	err := b.callAPIOfGateway(&result, "/cosmos/auth/v1beta1/accounts/"+address, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBalance get balance of chain denom
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	return b.GetDenomBalance(b.getDenom(), account)
+}
+
+// GetDenomBalance call /cosmos/bank/v1beta1/balances/{address}/by_denom
+func (b *Bridge) GetDenomBalance(denom, account string) (*big.Int, error) {
+	var result BalanceResult
+	err := b.callAPIOfGateway(&result, "/cosmos/bank/v1beta1/balances/"+account+"/by_denom", map[string]string{"denom": denom})
+	if err != nil {
+		return nil, err
+	}
+	if result.Balance.Amount == "" {
+		return big.NewInt(0), nil
+	}
+	balance, ok := parseAmount(result.Balance.Amount)
+	if !ok {
+		return nil, fmt.Errorf("wrong balance amount %v", result.Balance.Amount)
+	}
+	return balance, nil
+}
+
+// GetTokenBalance get balance of denom (tokenAddress is denom)
+func (b *Bridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
+	return b.GetDenomBalance(tokenAddress, accountAddress)
+}
+
+// GetTokenSupply call /cosmos/bank/v1beta1/supply/by_denom (tokenAddress is denom)
+func (b *Bridge) GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error) {
+	var result SupplyResult
+	err := b.callAPIOfGateway(&result, "/cosmos/bank/v1beta1/supply/by_denom", map[string]string{"denom": tokenAddress})
+	if err != nil {
+		return nil, err
+	}
+	supply, ok := parseAmount(result.Amount.Amount)
+	if !ok {
+		return nil, fmt.Errorf("wrong supply amount %v", result.Amount.Amount)
+	}
+	return supply, nil
+}
+
+// BroadcastTransaction post tx to /cosmos/tx/v1beta1/txs of all gateway urls
+func (b *Bridge) BroadcastTransaction(tx *Transaction) (txHash string, err error) {
+	params := map[string]interface{}{
+		"tx_bytes": base64.StdEncoding.EncodeToString(tx.Marshal()),
+		"mode":     "BROADCAST_MODE_SYNC",
+	}
+	gateway := b.GatewayConfig
+	urls := make([]string, 0, len(gateway.APIAddress)+len(gateway.APIAddressExt))
+	urls = append(urls, gateway.APIAddress...)
+	urls = append(urls, gateway.APIAddressExt...)
+	if len(urls) == 0 {
+		return "", errEmptyURLs
+	}
+	success := false
+	for _, url := range urls {
+		var result BroadcastResult
+		errf := postAPI(&result, url, "/cosmos/tx/v1beta1/txs", params)
+		switch {
+		case errf != nil:
+			err = errf
+		case result.TxResponse == nil:
+			err = errNoTxResponse
+		case !result.TxResponse.IsSuccess():
+			err = fmt.Errorf("broadcast tx failed, code %v, log: %v", result.TxResponse.Code, result.TxResponse.RawLog)
+		default:
+			txHash = result.TxResponse.TxHash
+			success = true
+		}
+		if err != nil {
+			log.Trace("broadcast tx error", "url", url, "err", err)
+		}
+	}
+	if success {
+		return txHash, nil
+	}
+	return "", err
+}
+
+// postAPI post json params to LCD endpoint
+func postAPI(result interface{}, url, path string, params interface{}) error {
+	fullURL := strings.TrimSuffix(url, "/") + path
+	resp, err := client.HTTPPost(fullURL, params, nil, nil, rpcTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	if err != nil {
+		return fmt.Errorf("read body error: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return fmt.Errorf("unmarshal result error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package cosmos
+
+// GetTxBlockInfo impl NonceSetter interface
+func (b *Bridge) GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return 0, 0
+	}
+	return tx.TxResponse.BlockHeight(), tx.TxResponse.BlockTime()
+}
+
+// GetPoolNonce impl NonceSetter interface, get account sequence
+// (cosmos has no pending state of account, height is ignored)
+func (b *Bridge) GetPoolNonce(address, height string) (uint64, error) {
+	account, err := b.GetAccount(address)
+	if err != nil {
+		return 0, err
+	}
+	return account.GetSequence()
+}
@@ -0,0 +1,150 @@
+package cosmos
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+var (
+	errWrongRawTx = errors.New("wrong raw tx param")
+
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
+func (b *Bridge) verifyTransactionWithArgs(rawTx interface{}, args *tokens.BuildTxArgs) (*Transaction, error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok {
+		return nil, errWrongRawTx
+	}
+	tokenCfg := b.GetTokenConfig(args.PairID)
+	if tokenCfg == nil {
+		return nil, fmt.Errorf("[sign] verify tx with unknown pairID '%v'", args.PairID)
+	}
+	if len(tx.Msgs) != 1 || tx.Msgs[0].ToAddress != args.Bind {
+		return nil, fmt.Errorf("[sign] verify tx receiver failed")
+	}
+	return tx, nil
+}
+
+// DcrmSignTransaction dcrm sign raw tx
+func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signTx interface{}, txHash string, err error) {
+	tx, err := b.verifyTransactionWithArgs(rawTx, args)
+	if err != nil {
+		return nil, "", err
+	}
+	msgHash := tx.SignHash()
+	jsondata, _ := json.Marshal(args)
+	msgContext := string(jsondata)
+
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction start", "msghash", msgHash.String(), "txid", args.SwapID)
+	keyID, rsvs, err := dcrm.DoSignOne(b.GetDcrmPublicKey(args.PairID), msgHash.String(), msgContext)
+	if err != nil {
+		return nil, "", err
+	}
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction finished", "keyID", keyID, "msghash", msgHash.String(), "txid", args.SwapID)
+
+	if len(rsvs) != 1 {
+		return nil, "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(rsvs), keyID)
+	}
+
+	rsv := rsvs[0]
+	log.Trace(b.ChainConfig.BlockChain+" DcrmSignTransaction get rsv success", "keyID", keyID, "txid", args.SwapID, "rsv", rsv)
+	signature := common.FromHex(rsv)
+	if len(signature) != crypto.SignatureLength {
+		log.Error("DcrmSignTransaction wrong length of signature")
+		return nil, "", errors.New("wrong signature of keyID " + keyID)
+	}
+
+	token := b.GetTokenConfig(args.PairID)
+	err = b.signTxWithSignature(tx, signature, token.DcrmAddress)
+	if err != nil {
+		return nil, "", err
+	}
+	txHash = tx.Hash()
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction success", "keyID", keyID, "txid", args.SwapID, "txhash", txHash)
+	return tx, txHash, nil
+}
+
+// signTxWithSignature set signature of tx if it's signed by signer,
+// v of signature is adjusted as dcrm may return either parity.
+func (b *Bridge) signTxWithSignature(tx *Transaction, signature []byte, signer string) error {
+	msgHash := tx.SignHash()
+	vPos := crypto.SignatureLength - 1
+	for i := 0; i < 2; i++ {
+		pubKey, err := crypto.SigToPub(msgHash[:], signature)
+		if err == nil {
+			address, _ := b.PublicKeyToAddress(pubKey)
+			if address == signer {
+				tx.Signature = toLowSSignature(signature)
+				return nil
+			}
+		}
+		signature[vPos] ^= 0x1 // v can only be 0 or 1
+	}
+	return errors.New("wrong sender address")
+}
+
+// toLowSSignature convert 'r || s || v' to 'r || s' with low s,
+// cosmos-sdk rejects signatures with high s value.
+func toLowSSignature(signature []byte) []byte {
+	r := signature[:32]
+	s := new(big.Int).SetBytes(signature[32:64])
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+	sig := make([]byte, 0, 64)
+	sig = append(sig, r...)
+	sig = append(sig, common.LeftPadBytes(s.Bytes(), 32)...)
+	return sig
+}
+
+// SignTransaction sign tx with pairID
+func (b *Bridge) SignTransaction(rawTx interface{}, pairID string) (signTx interface{}, txHash string, err error) {
+	privKey := b.GetTokenConfig(pairID).GetDcrmAddressPrivateKey()
+	return b.SignTransactionWithPrivateKey(rawTx, privKey)
+}
+
+// SignTransactionWithPrivateKey sign tx with ECDSA private key
+func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa.PrivateKey) (signTx interface{}, txHash string, err error) {
+	tx, ok := rawTx.(*Transaction)
+	if !ok {
+		return nil, "", errWrongRawTx
+	}
+	msgHash := tx.SignHash()
+	signature, err := crypto.Sign(msgHash[:], privKey)
+	if err != nil {
+		return nil, "", fmt.Errorf("sign tx failed, %w", err)
+	}
+	tx.Signature = toLowSSignature(signature)
+	txHash = tx.Hash()
+	log.Info(b.ChainConfig.BlockChain+" SignTransaction success", "txhash", txHash)
+	return tx, txHash, nil
+}
+
+// SendTransaction send signed tx
+func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	tx, ok := signedTx.(*Transaction)
+	if !ok {
+		return "", errWrongRawTx
+	}
+	if len(tx.Signature) == 0 {
+		return "", errors.New("send unsigned tx")
+	}
+	txHash, err = b.BroadcastTransaction(tx)
+	if err != nil {
+		log.Info("SendTransaction failed", "hash", tx.Hash(), "err", err)
+		return "", err
+	}
+	log.Info("SendTransaction success", "hash", txHash)
+	return txHash, nil
+}
@@ -0,0 +1,222 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/internal/protobuf"
+)
+
+// type urls of cosmos-sdk protobuf messages
+const (
+	msgSendTypeURL         = "/cosmos.bank.v1beta1.MsgSend"
+	secp256k1PubKeyTypeURL = "/cosmos.crypto.secp256k1.PubKey"
+
+	signModeDirect = 1 // SIGN_MODE_DIRECT
+)
+
+// Coin amount of denom
+type Coin struct {
+	Denom  string
+	Amount *big.Int
+}
+
+// Marshal protobuf encoding of 'cosmos.base.v1beta1.Coin'
+func (c *Coin) Marshal() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, []byte(c.Denom))
+	buf = protobuf.AppendBytesField(buf, 2, []byte(c.Amount.String()))
+	return buf
+}
+
+// MsgSend bank send message
+type MsgSend struct {
+	FromAddress string
+	ToAddress   string
+	Amount      []*Coin
+}
+
+// Marshal protobuf encoding of 'cosmos.bank.v1beta1.MsgSend'
+func (msg *MsgSend) Marshal() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, []byte(msg.FromAddress))
+	buf = protobuf.AppendBytesField(buf, 2, []byte(msg.ToAddress))
+	for _, coin := range msg.Amount {
+		buf = protobuf.AppendBytesField(buf, 3, coin.Marshal())
+	}
+	return buf
+}
+
+// GetAmount get amount of denom
+func (msg *MsgSend) GetAmount(denom string) *big.Int {
+	amount := big.NewInt(0)
+	for _, coin := range msg.Amount {
+		if coin.Denom == denom && coin.Amount != nil {
+			amount.Add(amount, coin.Amount)
+		}
+	}
+	return amount
+}
+
+// Transaction cosmos tx signed by one secp256k1 key with 'SIGN_MODE_DIRECT'
+type Transaction struct {
+	Msgs          []*MsgSend
+	Memo          string
+	PubKey        []byte // compressed secp256k1 public key
+	Sequence      uint64
+	Fee           *Coin
+	Gas           uint64
+	ChainID       string
+	AccountNumber uint64
+	Signature     []byte // r || s (low s)
+}
+
+// BodyBytes protobuf encoding of 'cosmos.tx.v1beta1.TxBody'
+func (tx *Transaction) BodyBytes() (buf []byte) {
+	for _, msg := range tx.Msgs {
+		buf = protobuf.AppendBytesField(buf, 1, protobuf.MarshalAny(msgSendTypeURL, msg.Marshal()))
+	}
+	buf = protobuf.AppendBytesField(buf, 2, []byte(tx.Memo))
+	return buf
+}
+
+// AuthInfoBytes protobuf encoding of 'cosmos.tx.v1beta1.AuthInfo'
+func (tx *Transaction) AuthInfoBytes() (buf []byte) {
+	var single, modeInfo, signerInfo, fee []byte
+
+	single = protobuf.AppendVarintField(single, 1, signModeDirect)
+	modeInfo = protobuf.AppendMessageField(modeInfo, 1, single)
+
+	signerInfo = protobuf.AppendBytesField(signerInfo, 1, protobuf.MarshalAny(secp256k1PubKeyTypeURL, protobuf.AppendBytesField(nil, 1, tx.PubKey)))
+	signerInfo = protobuf.AppendMessageField(signerInfo, 2, modeInfo)
+	signerInfo = protobuf.AppendVarintField(signerInfo, 3, tx.Sequence)
+
+	if tx.Fee != nil && tx.Fee.Amount.Sign() > 0 {
+		fee = protobuf.AppendBytesField(fee, 1, tx.Fee.Marshal())
+	}
+	fee = protobuf.AppendVarintField(fee, 2, tx.Gas)
+
+	buf = protobuf.AppendBytesField(buf, 1, signerInfo)
+	buf = protobuf.AppendMessageField(buf, 2, fee)
+	return buf
+}
+
+// SignBytes protobuf encoding of 'cosmos.tx.v1beta1.SignDoc'
+func (tx *Transaction) SignBytes() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, tx.BodyBytes())
+	buf = protobuf.AppendBytesField(buf, 2, tx.AuthInfoBytes())
+	buf = protobuf.AppendBytesField(buf, 3, []byte(tx.ChainID))
+	buf = protobuf.AppendVarintField(buf, 4, tx.AccountNumber)
+	return buf
+}
+
+// SignHash message hash to sign, sha256 of sign doc
+func (tx *Transaction) SignHash() common.Hash {
+	return sha256.Sum256(tx.SignBytes())
+}
+
+// Marshal protobuf encoding of 'cosmos.tx.v1beta1.TxRaw' (for broadcasting)
+func (tx *Transaction) Marshal() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, tx.BodyBytes())
+	buf = protobuf.AppendBytesField(buf, 2, tx.AuthInfoBytes())
+	buf = protobuf.AppendBytesField(buf, 3, tx.Signature)
+	return buf
+}
+
+// Hash tx hash, upper case hex of sha256 of tx raw
+func (tx *Transaction) Hash() string {
+	return calcTxHash(tx.Marshal())
+}
+
+func calcTxHash(txBytes []byte) string {
+	hash := sha256.Sum256(txBytes)
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+// decodeTxMsgSends decode bank send messages and memo of tx raw
+// (other messages are ignored), it's used to filter txs of block in scan job.
+func decodeTxMsgSends(txBytes []byte) (msgs []*MsgSend, memo string, err error) {
+	var bodyBytes []byte
+	err = protobuf.RangeFields(txBytes, func(fieldNum uint64, data []byte) error {
+		if fieldNum == 1 {
+			bodyBytes = data
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	err = protobuf.RangeFields(bodyBytes, func(fieldNum uint64, data []byte) error {
+		switch fieldNum {
+		case 1:
+			msg, errf := decodeMsgSendAny(data)
+			if errf != nil {
+				return errf
+			}
+			if msg != nil {
+				msgs = append(msgs, msg)
+			}
+		case 2:
+			memo = string(data)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return msgs, memo, nil
+}
+
+func decodeMsgSendAny(anyBytes []byte) (*MsgSend, error) {
+	var typeURL string
+	var value []byte
+	err := protobuf.RangeFields(anyBytes, func(fieldNum uint64, data []byte) error {
+		switch fieldNum {
+		case 1:
+			typeURL = string(data)
+		case 2:
+			value = data
+		}
+		return nil
+	})
+	if err != nil || typeURL != msgSendTypeURL {
+		return nil, err
+	}
+	msg := &MsgSend{}
+	err = protobuf.RangeFields(value, func(fieldNum uint64, data []byte) error {
+		switch fieldNum {
+		case 1:
+			msg.FromAddress = string(data)
+		case 2:
+			msg.ToAddress = string(data)
+		case 3:
+			coin, errf := decodeCoin(data)
+			if errf != nil {
+				return errf
+			}
+			msg.Amount = append(msg.Amount, coin)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func decodeCoin(coinBytes []byte) (*Coin, error) {
+	coin := &Coin{Amount: big.NewInt(0)}
+	err := protobuf.RangeFields(coinBytes, func(fieldNum uint64, data []byte) error {
+		switch fieldNum {
+		case 1:
+			coin.Denom = string(data)
+		case 2:
+			if _, ok := coin.Amount.SetString(string(data), 10); !ok {
+				return protobuf.ErrWrongProtobuf
+			}
+		}
+		return nil
+	})
+	return coin, err
+}
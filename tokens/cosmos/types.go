@@ -0,0 +1,157 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NodeInfo result of '/cosmos/base/tendermint/v1beta1/node_info'
+type NodeInfo struct {
+	DefaultNodeInfo struct {
+		Network string `json:"network"`
+		Moniker string `json:"moniker"`
+	} `json:"default_node_info"`
+}
+
+// BlockResult result of '/cosmos/base/tendermint/v1beta1/blocks/{height}'
+type BlockResult struct {
+	BlockID struct {
+		Hash string `json:"hash"` // base64
+	} `json:"block_id"`
+	Block struct {
+		Header struct {
+			Height string `json:"height"`
+			Time   string `json:"time"`
+		} `json:"header"`
+		Data struct {
+			Txs []string `json:"txs"` // base64 of tx raw
+		} `json:"data"`
+	} `json:"block"`
+}
+
+// Height block height
+func (b *BlockResult) Height() uint64 {
+	height, _ := strconv.ParseUint(b.Block.Header.Height, 10, 64)
+	return height
+}
+
+// Hash block hash in upper case hex
+func (b *BlockResult) Hash() string {
+	return base64ToHex(b.BlockID.Hash)
+}
+
+// Time block time in seconds
+func (b *BlockResult) Time() uint64 {
+	return parseTimestamp(b.Block.Header.Time)
+}
+
+// CoinResult coin in json
+type CoinResult struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+// TxMessage message of tx in json, fields of bank send message are parsed
+type TxMessage struct {
+	Type        string       `json:"@type"`
+	FromAddress string       `json:"from_address"`
+	ToAddress   string       `json:"to_address"`
+	Amount      []CoinResult `json:"amount"`
+}
+
+// TxResponse tx response of tx query and broadcast
+type TxResponse struct {
+	Height    string `json:"height"`
+	TxHash    string `json:"txhash"`
+	Code      uint32 `json:"code"`
+	RawLog    string `json:"raw_log"`
+	GasWanted string `json:"gas_wanted"`
+	GasUsed   string `json:"gas_used"`
+	Timestamp string `json:"timestamp"`
+}
+
+// TxResult result of '/cosmos/tx/v1beta1/txs/{hash}'
+type TxResult struct {
+	Tx struct {
+		Body struct {
+			Messages []*TxMessage `json:"messages"`
+			Memo     string       `json:"memo"`
+		} `json:"body"`
+	} `json:"tx"`
+	TxResponse *TxResponse `json:"tx_response"`
+}
+
+// BlockHeight block height of tx
+func (r *TxResponse) BlockHeight() uint64 {
+	height, _ := strconv.ParseUint(r.Height, 10, 64)
+	return height
+}
+
+// BlockTime block time of tx in seconds
+func (r *TxResponse) BlockTime() uint64 {
+	return parseTimestamp(r.Timestamp)
+}
+
+// IsSuccess tx is executed successfully or not
+func (r *TxResponse) IsSuccess() bool {
+	return r.Code == 0
+}
+
+// AccountResult result of '/cosmos/auth/v1beta1/accounts/{address}'
+type AccountResult struct {
+	Account struct {
+		Type          string `json:"@type"`
+		Address       string `json:"address"`
+		AccountNumber string `json:"account_number"`
+		Sequence      string `json:"sequence"`
+	} `json:"account"`
+}
+
+// GetAccountNumber get account number
+func (r *AccountResult) GetAccountNumber() (uint64, error) {
+	return strconv.ParseUint(r.Account.AccountNumber, 10, 64)
+}
+
+// GetSequence get sequence
+func (r *AccountResult) GetSequence() (uint64, error) {
+	return strconv.ParseUint(r.Account.Sequence, 10, 64)
+}
+
+// BalanceResult result of '/cosmos/bank/v1beta1/balances/{address}/by_denom'
+type BalanceResult struct {
+	Balance CoinResult `json:"balance"`
+}
+
+// SupplyResult result of '/cosmos/bank/v1beta1/supply/by_denom'
+type SupplyResult struct {
+	Amount CoinResult `json:"amount"`
+}
+
+// BroadcastResult result of '/cosmos/tx/v1beta1/txs' (POST)
+type BroadcastResult struct {
+	TxResponse *TxResponse `json:"tx_response"`
+}
+
+func parseAmount(amount string) (*big.Int, bool) {
+	return new(big.Int).SetString(amount, 10)
+}
+
+func parseTimestamp(timestamp string) uint64 {
+	t, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return 0
+	}
+	return uint64(t.Unix())
+}
+
+func base64ToHex(s string) string {
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+	return strings.ToUpper(hex.EncodeToString(data))
+}
@@ -0,0 +1,62 @@
+// Package doge implements the bridge interfaces for dogecoin.
+//
+// Dogecoin has the same tx format and sighash as bitcoin (without segwit),
+// so the bridge derives from the utxo engine with dogecoin chain params,
+// fee rules and dust limit. The chain api is electrs (with dogecoin address
+// encoding) or dogecoin core rpc if gateway 'BtcCoreExtra' is configed.
+package doge
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/utxo"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	// BlockChain block chain name of dogecoin
+	BlockChain = "Dogecoin"
+)
+
+// Bridge doge bridge inherit from utxo engine
+type Bridge struct {
+	*utxo.Bridge
+}
+
+func init() {
+	tokens.RegisterBridgeFactory(BlockChain, func(isSrc bool) tokens.CrossChainBridge {
+		return NewCrossChainBridge(isSrc)
+	})
+}
+
+// NewCrossChainBridge new doge bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	bridge := &Bridge{
+		Bridge: utxo.NewCrossChainBridgeWithPresets(BlockChain, presets, isSrc),
+	}
+	bridge.SetInherit(bridge)
+	btc.BridgeInstance = bridge
+	return bridge
+}
+
+// VerifyTokenConfig verify token config
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	if !b.IsP2pkhAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address (not p2pkh): %v", tokenCfg.DcrmAddress)
+	}
+	return b.Bridge.VerifyTokenConfig(tokenCfg)
+}
+
+// DecodeWIF decode WIF and check it is for the configed network
+func (b *Bridge) DecodeWIF(wifStr string) (*btcutil.WIF, error) {
+	wif, err := btcutil.DecodeWIF(wifStr)
+	if err != nil {
+		return nil, err
+	}
+	if !wif.IsForNet(b.GetChainParams()) {
+		return nil, fmt.Errorf("invalid WIF for net %v", b.GetChainParams().Name)
+	}
+	return wif, nil
+}
@@ -0,0 +1,47 @@
+package doge
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// dogecoin fee rules (since dogecoin core 1.14.5), in koinu (1e-8 DOGE)
+const (
+	// outputs below 0.01 DOGE are not relayed
+	dustLimit = 1000000
+	// recommended fee rate 0.01 DOGE/kB
+	minRelayFeePerKb = 1000000
+	// legacy fee rate 1 DOGE/kB, caps estimated fee
+	maxRelayFeePerKb = 100000000
+	// pay at least 0.01 DOGE for a tx
+	minRelayFee = 1000000
+)
+
+// presets chain params of dogecoin, keyed by network
+var presets = map[string]*tokens.UtxoChainConfig{
+	"mainnet": {
+		Symbol:           "DOGE",
+		PairID:           "doge",
+		NetMagic:         0xc0c0c0c0,
+		PubKeyHashAddrID: 0x1e, // starts with D
+		ScriptHashAddrID: 0x16, // starts with 9 or A
+		PrivateKeyID:     0x9e,
+		HDCoinType:       3,
+		DustLimit:        dustLimit,
+		MinRelayFee:      minRelayFee,
+		MinRelayFeePerKb: minRelayFeePerKb,
+		MaxRelayFeePerKb: maxRelayFeePerKb,
+	},
+	"testnet": {
+		Symbol:           "DOGE",
+		PairID:           "doge",
+		NetMagic:         0xdcb7c1fc,
+		PubKeyHashAddrID: 0x71, // starts with n
+		ScriptHashAddrID: 0xc4, // starts with 2
+		PrivateKeyID:     0xf1,
+		HDCoinType:       1,
+		DustLimit:        dustLimit,
+		MinRelayFee:      minRelayFee,
+		MinRelayFeePerKb: minRelayFeePerKb,
+		MaxRelayFeePerKb: maxRelayFeePerKb,
+	},
+}
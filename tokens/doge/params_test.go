@@ -0,0 +1,64 @@
+package doge
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+)
+
+func newTestBridge(netID string) *Bridge {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain: BlockChain,
+		NetID:      netID,
+	}
+	return b
+}
+
+func TestAddressAndWIF(t *testing.T) {
+	tests := []struct {
+		netID     string
+		p2pkh     string
+		p2sh      string
+		wifPrefix string
+	}{
+		{"mainnet", "D", "9A", "Q"},
+		{"testnet", "n", "2", "c"},
+	}
+	priKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), []byte("dogecoin bridge test private key"))
+
+	for _, test := range tests {
+		b := newTestBridge(test.netID)
+		params := b.GetChainParams()
+
+		p2pkh, _ := b.NewAddressPubKeyHash(priKey.PubKey().SerializeCompressed())
+		if !strings.HasPrefix(p2pkh.EncodeAddress(), test.p2pkh) || !b.IsP2pkhAddress(p2pkh.EncodeAddress()) {
+			t.Fatalf("%v: wrong p2pkh address %v", test.netID, p2pkh.EncodeAddress())
+		}
+		p2sh, _ := b.NewAddressScriptHash([]byte("bind address redeem script"))
+		if !strings.ContainsAny(p2sh.EncodeAddress()[:1], test.p2sh) || !b.IsP2shAddress(p2sh.EncodeAddress()) {
+			t.Fatalf("%v: wrong p2sh address %v", test.netID, p2sh.EncodeAddress())
+		}
+
+		wif, _ := btcutil.NewWIF(priKey, params, true)
+		if !strings.HasPrefix(wif.String(), test.wifPrefix) {
+			t.Fatalf("%v: wrong WIF prefix %v", test.netID, wif.String()[:1])
+		}
+		if _, err := b.DecodeWIF(wif.String()); err != nil {
+			t.Fatalf("%v: decode WIF failed: %v", test.netID, err)
+		}
+		btcWIF, _ := btcutil.NewWIF(priKey, &chaincfg.MainNetParams, true)
+		if _, err := b.DecodeWIF(btcWIF.String()); err == nil {
+			t.Fatalf("%v: decode bitcoin WIF should fail", test.netID)
+		}
+
+		btcAddr, _ := btcutil.NewAddressPubKeyHash(p2pkh.ScriptAddress(), &chaincfg.MainNetParams)
+		if b.IsValidAddress(btcAddr.EncodeAddress()) {
+			t.Fatalf("%v: bitcoin address should be invalid", test.netID)
+		}
+	}
+}
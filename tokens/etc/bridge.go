@@ -22,6 +22,14 @@ type Bridge struct {
 	*eth.Bridge
 }
 
+func init() {
+	tokens.RegisterBridgeFactory("EthClassic", newBridgeFactory)
+}
+
+func newBridgeFactory(isSrc bool) tokens.CrossChainBridge {
+	return NewCrossChainBridge(isSrc)
+}
+
 // NewCrossChainBridge new etc bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	bridge := &Bridge{Bridge: eth.NewCrossChainBridge(isSrc)}
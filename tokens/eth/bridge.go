@@ -27,6 +27,14 @@ type Bridge struct {
 	SignerChainID *big.Int
 }
 
+func init() {
+	tokens.RegisterBridgeFactory("Ethereum", newBridgeFactory)
+}
+
+func newBridgeFactory(isSrc bool) tokens.CrossChainBridge {
+	return NewCrossChainBridge(isSrc)
+}
+
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	return &Bridge{
@@ -104,7 +112,7 @@ func (b *Bridge) VerifyChainID() {
 	}
 
 	b.SignerChainID = chainID
-	b.Signer = types.MakeSigner("EIP155", chainID)
+	b.Signer = types.MakeSigner("London", chainID)
 
 	log.Info("VerifyChainID succeed", "networkID", networkID, "chainID", chainID)
 }
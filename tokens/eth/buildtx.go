@@ -17,6 +17,9 @@ var (
 	retryRPCCount    = 3
 	retryRPCInterval = 1 * time.Second
 
+	// replacing tx in pool requires both fee caps bumped at least 10 percent
+	minReplaceBumpPercent = uint64(10)
+
 	minReserveFee  *big.Int
 	latestGasPrice *big.Int
 	baseGasPrice   *big.Int
@@ -115,6 +118,11 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs, extra *tokens.EthExtraArgs, i
 		gasLimit = *extra.Gas
 		gasPrice = extra.GasPrice
 	)
+	// same priority as setDefaults, gas price is preferred if specified
+	isDynamicFeeTx := extra.GasPrice == nil && extra.GasTipCap != nil && extra.GasFeeCap != nil
+	if isDynamicFeeTx {
+		gasPrice = extra.GasFeeCap
+	}
 
 	needValue := big.NewInt(0)
 	if value != nil && value.Sign() > 0 {
@@ -132,13 +140,17 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs, extra *tokens.EthExtraArgs, i
 		return nil, err
 	}
 
-	rawTx = types.NewTransaction(nonce, to, value, gasLimit, gasPrice, input)
+	if isDynamicFeeTx {
+		rawTx = types.NewDynamicFeeTransaction(b.SignerChainID, nonce, to, value, gasLimit, extra.GasTipCap, extra.GasFeeCap, input)
+	} else {
+		rawTx = types.NewTransaction(nonce, to, value, gasLimit, gasPrice, input)
+	}
 
 	log.Info("build raw tx", "pairID", args.PairID, "identifier", args.Identifier,
 		"swapID", args.SwapID, "swapType", args.SwapType,
 		"bind", args.Bind, "originValue", args.OriginValue, "swapValue", args.SwapValue,
 		"from", args.From, "to", to.String(), "value", value, "nonce", nonce,
-		"gasLimit", gasLimit, "gasPrice", gasPrice, "data", common.ToHex(input))
+		"gasLimit", gasLimit, "gasPrice", gasPrice, "gasTipCap", extra.GasTipCap, "data", common.ToHex(input))
 
 	return rawTx, nil
 }
@@ -165,7 +177,15 @@ func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (extra *tokens.EthExtraAr
 	} else {
 		extra = args.Extra.EthExtra
 	}
-	if extra.GasPrice == nil {
+	switch {
+	case extra.GasPrice != nil:
+	case extra.GasTipCap != nil && extra.GasFeeCap != nil:
+	case b.ChainConfig.EnableDynamicFeeTx:
+		extra.GasTipCap, extra.GasFeeCap, err = b.getGasFeeCaps(args)
+		if err != nil {
+			return nil, err
+		}
+	default:
 		extra.GasPrice, err = b.getGasPrice(args)
 		if err != nil {
 			return nil, err
@@ -222,24 +242,115 @@ func (b *Bridge) getGasPrice(args *tokens.BuildTxArgs) (price *big.Int, err erro
 	return price, err
 }
 
-// args and oldGasPrice should be read only
-func (b *Bridge) adjustSwapGasPrice(args *tokens.BuildTxArgs, oldGasPrice *big.Int) (newGasPrice *big.Int, err error) {
+// getGasFeeCaps estimate fee caps of dynamic fee tx (EIP-1559),
+// max fee per gas is twice the latest base fee plus the max priority fee.
+func (b *Bridge) getGasFeeCaps(args *tokens.BuildTxArgs) (gasTipCap, gasFeeCap *big.Int, err error) {
+	var baseFee *big.Int
+	for i := 0; i < retryRPCCount; i++ {
+		gasTipCap, err = b.SuggestGasTipCap()
+		if err == nil {
+			baseFee, err = b.GetBaseFee()
+		}
+		if err == nil {
+			break
+		}
+		time.Sleep(retryRPCInterval)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	gasFeeCap = new(big.Int).Mul(baseFee, big.NewInt(2))
+	gasFeeCap.Add(gasFeeCap, gasTipCap)
+	if args != nil && args.SwapType != tokens.NoSwapType {
+		addPercent, err := b.getSwapPlusGasPricePercent(args)
+		if err != nil {
+			return nil, nil, err
+		}
+		gasTipCap = addGasPricePercent(gasTipCap, addPercent)
+		gasFeeCap = addGasPricePercent(gasFeeCap, addPercent)
+	}
+	if baseGasPrice != nil {
+		maxGasPrice := new(big.Int).Mul(baseGasPrice, big.NewInt(10))
+		if gasFeeCap.Cmp(maxGasPrice) > 0 {
+			log.Info("gas fee cap exceeds upper bound", "baseGasPrice", baseGasPrice, "maxGasPrice", maxGasPrice, "gasFeeCap", gasFeeCap)
+			gasFeeCap = maxGasPrice
+		}
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasTipCap = new(big.Int).Set(gasFeeCap)
+	}
+	return gasTipCap, gasFeeCap, nil
+}
+
+// GetReplaceGasFeeCaps impl DynamicFeeTxReplacer,
+// both fee caps are bumped at least 10 percent to replace the old swap tx.
+func (b *Bridge) GetReplaceGasFeeCaps(args *tokens.BuildTxArgs, oldSwapTx string) (gasTipCap, gasFeeCap *big.Int, err error) {
+	if !b.ChainConfig.EnableDynamicFeeTx {
+		return nil, nil, nil
+	}
+	gasTipCap, gasFeeCap, err = b.getGasFeeCaps(args)
+	if err != nil {
+		return nil, nil, err
+	}
+	oldTx, err := getTxByHash(b, oldSwapTx, true)
+	if err != nil || oldTx == nil {
+		log.Warn("get old swap tx to replace failed", "swaptx", oldSwapTx, "err", err)
+		return gasTipCap, gasFeeCap, nil
+	}
+	oldTipCap, oldFeeCap := oldTx.GasTipCap, oldTx.GasFeeCap
+	if oldTipCap == nil || oldFeeCap == nil { // legacy tx
+		oldTipCap, oldFeeCap = oldTx.Price, oldTx.Price
+	}
+	if oldTipCap != nil {
+		minTipCap := addGasPricePercent(oldTipCap.ToInt(), minReplaceBumpPercent)
+		if gasTipCap.Cmp(minTipCap) < 0 {
+			gasTipCap = minTipCap
+		}
+	}
+	if oldFeeCap != nil {
+		minFeeCap := addGasPricePercent(oldFeeCap.ToInt(), minReplaceBumpPercent)
+		if gasFeeCap.Cmp(minFeeCap) < 0 {
+			gasFeeCap = minFeeCap
+		}
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasFeeCap = new(big.Int).Set(gasTipCap)
+	}
+	log.Info("get replace gas fee caps", "swaptx", oldSwapTx, "oldTipCap", oldTipCap, "oldFeeCap", oldFeeCap, "gasTipCap", gasTipCap, "gasFeeCap", gasFeeCap)
+	return gasTipCap, gasFeeCap, nil
+}
+
+func (b *Bridge) getSwapPlusGasPricePercent(args *tokens.BuildTxArgs) (addPercent uint64, err error) {
 	tokenCfg := b.GetTokenConfig(args.PairID)
 	if tokenCfg == nil {
-		return nil, tokens.ErrUnknownPairID
+		return 0, tokens.ErrUnknownPairID
 	}
-	addPercent := tokenCfg.PlusGasPricePercentage
+	addPercent = tokenCfg.PlusGasPricePercentage
 	if args.ReplaceNum > 0 {
 		addPercent += args.ReplaceNum * b.ChainConfig.ReplacePlusGasPricePercent
 	}
 	if addPercent > tokens.MaxPlusGasPricePercentage {
 		addPercent = tokens.MaxPlusGasPricePercentage
 	}
-	newGasPrice = new(big.Int).Set(oldGasPrice) // clone from old
+	return addPercent, nil
+}
+
+func addGasPricePercent(gasPrice *big.Int, addPercent uint64) *big.Int {
+	newGasPrice := new(big.Int).Set(gasPrice) // clone from old
 	if addPercent > 0 {
 		newGasPrice.Mul(newGasPrice, big.NewInt(int64(100+addPercent)))
 		newGasPrice.Div(newGasPrice, big.NewInt(100))
 	}
+	return newGasPrice
+}
+
+// args and oldGasPrice should be read only
+func (b *Bridge) adjustSwapGasPrice(args *tokens.BuildTxArgs, oldGasPrice *big.Int) (newGasPrice *big.Int, err error) {
+	addPercent, err := b.getSwapPlusGasPricePercent(args)
+	if err != nil {
+		return nil, err
+	}
+	newGasPrice = addGasPricePercent(oldGasPrice, addPercent)
 	maxGasPriceFluctPercent := b.ChainConfig.MaxGasPriceFluctPercent
 	if maxGasPriceFluctPercent > 0 {
 		if latestGasPrice != nil && newGasPrice.Cmp(latestGasPrice) < 0 {
@@ -10,7 +10,6 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
-	"github.com/anyswap/CrossChain-Bridge/tools/rlp"
 	"github.com/anyswap/CrossChain-Bridge/types"
 )
 
@@ -29,6 +28,28 @@ func (b *Bridge) GetLatestBlockNumberOf(url string) (latest uint64, err error) {
 	return 0, err
 }
 
+// GetBlockNumberByTagOf get number of block with tag (eg. 'safe', 'finalized')
+func (b *Bridge) GetBlockNumberByTagOf(url, tag string) (uint64, error) {
+	var result *types.RPCBlock
+	err := client.RPCPost(&result, url, "eth_getBlockByNumber", tag, false)
+	if err != nil {
+		return 0, err
+	}
+	if result == nil || result.Number == nil {
+		return 0, errors.New("block not found")
+	}
+	return result.Number.ToInt().Uint64(), nil
+}
+
+// getStableBaseBlockNumberOf confirmations are counted from the returned block number,
+// which is the finality block if 'FinalityBlockTag' is configed, otherwise the latest block.
+func (b *Bridge) getStableBaseBlockNumberOf(url string) (uint64, error) {
+	if tag := b.ChainConfig.FinalityBlockTag; tag != "" {
+		return b.GetBlockNumberByTagOf(url, tag)
+	}
+	return b.GetLatestBlockNumberOf(url)
+}
+
 // GetLatestBlockNumber call eth_blockNumber
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
 	gateway := b.GatewayConfig
@@ -285,11 +306,32 @@ func getMaxPoolNonce(account common.Address, height string, urls []string) (maxN
 
 // SuggestPrice call eth_gasPrice
 func (b *Bridge) SuggestPrice() (maxGasPrice *big.Int, err error) {
+	return b.getMaxGasPriceOf("eth_gasPrice")
+}
+
+// SuggestGasTipCap call eth_maxPriorityFeePerGas
+func (b *Bridge) SuggestGasTipCap() (maxGasTipCap *big.Int, err error) {
+	return b.getMaxGasPriceOf("eth_maxPriorityFeePerGas")
+}
+
+// GetBaseFee get base fee per gas of latest block
+func (b *Bridge) GetBaseFee() (*big.Int, error) {
+	block, err := b.GetBlockByNumber(nil)
+	if err != nil {
+		return nil, err
+	}
+	if block.BaseFee == nil {
+		return nil, errors.New("block without base fee")
+	}
+	return block.BaseFee.ToInt(), nil
+}
+
+func (b *Bridge) getMaxGasPriceOf(method string) (maxGasPrice *big.Int, err error) {
 	gateway := b.GatewayConfig
 	if len(gateway.APIAddressExt) > 0 {
-		maxGasPrice, err = getMaxGasPrice(gateway.APIAddressExt)
+		maxGasPrice, err = getMaxGasPrice(gateway.APIAddressExt, method)
 	}
-	maxGasPrice2, err2 := getMaxGasPrice(gateway.APIAddress)
+	maxGasPrice2, err2 := getMaxGasPrice(gateway.APIAddress, method)
 	if err2 == nil {
 		if maxGasPrice == nil || maxGasPrice2.Cmp(maxGasPrice) > 0 {
 			maxGasPrice = maxGasPrice2
@@ -303,14 +345,14 @@ func (b *Bridge) SuggestPrice() (maxGasPrice *big.Int, err error) {
 	return nil, err
 }
 
-func getMaxGasPrice(urls []string) (maxGasPrice *big.Int, err error) {
+func getMaxGasPrice(urls []string, method string) (maxGasPrice *big.Int, err error) {
 	if len(urls) == 0 {
 		return nil, errEmptyURLs
 	}
 	var success bool
 	var result hexutil.Big
 	for _, url := range urls {
-		err = client.RPCPost(&result, url, "eth_gasPrice")
+		err = client.RPCPost(&result, url, method)
 		if err == nil {
 			success = true
 			if maxGasPrice == nil || result.ToInt().Cmp(maxGasPrice) > 0 {
@@ -326,7 +368,7 @@ func getMaxGasPrice(urls []string) (maxGasPrice *big.Int, err error) {
 
 // SendSignedTransaction call eth_sendRawTransaction
 func (b *Bridge) SendSignedTransaction(tx *types.Transaction) (txHash string, err error) {
-	data, err := rlp.EncodeToBytes(tx)
+	data, err := tx.MarshalBinary()
 	if err != nil {
 		return "", err
 	}
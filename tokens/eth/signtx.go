@@ -43,11 +43,15 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 	if err != nil {
 		return nil, "", err
 	}
-	gasPrice, err := b.getGasPrice(args)
-	if err == nil && args.Extra.EthExtra.GasPrice.Cmp(gasPrice) < 0 {
-		log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction update gas price", "txid", args.SwapID, "oldGasPrice", args.Extra.EthExtra.GasPrice, "newGasPrice", gasPrice)
-		args.Extra.EthExtra.GasPrice = gasPrice
-		tx.SetGasPrice(gasPrice)
+	if tx.Type() == types.DynamicFeeTxType {
+		b.updateGasFeeCaps(tx, args)
+	} else {
+		gasPrice, err := b.getGasPrice(args)
+		if err == nil && args.Extra.EthExtra.GasPrice.Cmp(gasPrice) < 0 {
+			log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction update gas price", "txid", args.SwapID, "oldGasPrice", args.Extra.EthExtra.GasPrice, "newGasPrice", gasPrice)
+			args.Extra.EthExtra.GasPrice = gasPrice
+			tx.SetGasPrice(gasPrice)
+		}
 	}
 	signer := b.Signer
 	msgHash := signer.Hash(tx)
@@ -179,3 +183,29 @@ func (b *Bridge) GetSignedTxHashOfKeyID(keyID, pairID string, rawTx interface{})
 	}
 	return txHash, nil
 }
+
+// updateGasFeeCaps raise fee caps of dynamic fee tx before dcrm signing
+func (b *Bridge) updateGasFeeCaps(tx *types.Transaction, args *tokens.BuildTxArgs) {
+	gasTipCap, gasFeeCap, err := b.getGasFeeCaps(args)
+	if err != nil {
+		return
+	}
+	extra := args.Extra.EthExtra
+	if extra.GasTipCap != nil && extra.GasTipCap.Cmp(gasTipCap) > 0 {
+		gasTipCap = extra.GasTipCap
+	}
+	if extra.GasFeeCap != nil && extra.GasFeeCap.Cmp(gasFeeCap) > 0 {
+		gasFeeCap = extra.GasFeeCap
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasFeeCap = gasTipCap
+	}
+	if gasTipCap.Cmp(tx.GasTipCap()) == 0 && gasFeeCap.Cmp(tx.GasFeeCap()) == 0 {
+		return
+	}
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction update gas fee caps", "txid", args.SwapID,
+		"oldGasTipCap", extra.GasTipCap, "oldGasFeeCap", extra.GasFeeCap, "newGasTipCap", gasTipCap, "newGasFeeCap", gasFeeCap)
+	extra.GasTipCap = gasTipCap
+	extra.GasFeeCap = gasFeeCap
+	tx.SetGasFeeCaps(gasTipCap, gasFeeCap)
+}
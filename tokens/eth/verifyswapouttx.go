@@ -23,7 +23,7 @@ func (b *Bridge) verifySwapoutTxWithPairID(pairID, txHash string, allowUnstable
 		return swapInfo, tokens.ErrUnknownPairID
 	}
 
-	if token.DisableSwap {
+	if token.IsSwapDisabled() {
 		return swapInfo, tokens.ErrSwapIsClosed
 	}
 
@@ -63,10 +63,10 @@ func (b *Bridge) verifySwapoutTxReceipt(swapInfo *tokens.TxSwapInfo, receipt *ty
 	swapInfo.To = txRecipient                              // To
 	swapInfo.From = strings.ToLower(receipt.From.String()) // From
 
-	bindAddress, value, err := parseSwapoutTxLogs(receipt.Logs, token.ContractAddress)
+	bindAddress, value, err := ParseSwapoutTxLogs(receipt.Logs, token.ContractAddress)
 	if err != nil {
 		if !errors.Is(err, tokens.ErrSwapoutLogNotFound) {
-			log.Debug(b.ChainConfig.BlockChain+" parseSwapoutTxLogs fail", "tx", swapInfo.Hash, "err", err)
+			log.Debug(b.ChainConfig.BlockChain+" ParseSwapoutTxLogs fail", "tx", swapInfo.Hash, "err", err)
 		}
 		return err
 	}
@@ -148,10 +148,10 @@ func (b *Bridge) verifySwapoutTxWithReceipt(commonInfo *tokens.TxSwapInfo, recei
 
 		swapInfo.PairID = pairID // PairID
 
-		bindAddress, value, err := parseSwapoutTxLogs(receipt.Logs, token.ContractAddress)
+		bindAddress, value, err := ParseSwapoutTxLogs(receipt.Logs, token.ContractAddress)
 		if err != nil {
 			if !errors.Is(err, tokens.ErrSwapoutLogNotFound) {
-				log.Debug(b.ChainConfig.BlockChain+" parseSwapoutTxLogs fail", "tx", txHash, "err", err)
+				log.Debug(b.ChainConfig.BlockChain+" ParseSwapoutTxLogs fail", "tx", txHash, "err", err)
 			}
 			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
 			continue
@@ -283,7 +283,8 @@ func ParseSwapoutTxInput(input *[]byte) (string, *big.Int, error) {
 	return parseTxInputEncodedData(encData)
 }
 
-func parseSwapoutTxLogs(logs []*types.RPCLog, targetContract string) (bind string, value *big.Int, err error) {
+// ParseSwapoutTxLogs parse swapout tx logs
+func ParseSwapoutTxLogs(logs []*types.RPCLog, targetContract string) (bind string, value *big.Int, err error) {
 	if isMbtcSwapout() {
 		return parseSwapoutToBtcTxLogs(logs)
 	}
@@ -0,0 +1,112 @@
+package evm
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const netCustom = "custom"
+
+var (
+	retryRPCCount    = 5
+	retryRPCInterval = 1 * time.Second
+)
+
+// Bridge generic evm bridge inherit from eth bridge,
+// chain id, signer type and finality rules are all from chain config.
+type Bridge struct {
+	*eth.Bridge
+}
+
+func init() {
+	tokens.RegisterBridgeFactory("EVM", newBridgeFactory)
+}
+
+func newBridgeFactory(isSrc bool) tokens.CrossChainBridge {
+	return NewCrossChainBridge(isSrc)
+}
+
+// NewCrossChainBridge new evm bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	bridge := &Bridge{Bridge: eth.NewCrossChainBridge(isSrc)}
+	bridge.Inherit = bridge
+	return bridge
+}
+
+// SetChainAndGateway set token and gateway config
+func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	b.VerifyChainID()
+	b.Init()
+}
+
+// VerifyChainID verify chain id with the configed 'ChainID',
+// 'NetID' is only a network name except 'custom' which skips the check.
+func (b *Bridge) VerifyChainID() {
+	if err := b.verifyChainID(); err != nil {
+		log.Fatal("evm verify chain id failed", "networkID", b.ChainConfig.NetID, "err", err)
+	}
+	log.Info("VerifyChainID succeed", "networkID", b.ChainConfig.NetID, "chainID", b.SignerChainID, "signerType", b.ChainConfig.SignerType, "finalityBlockTag", b.ChainConfig.FinalityBlockTag)
+}
+
+func (b *Bridge) verifyChainID() error {
+	networkID := b.ChainConfig.NetID
+
+	var wantChainID *big.Int
+	if b.ChainConfig.ChainID != "" {
+		var err error
+		wantChainID, err = common.GetBigIntFromStr(b.ChainConfig.ChainID)
+		if err != nil {
+			return fmt.Errorf("wrong evm chain config 'ChainID' %v", b.ChainConfig.ChainID)
+		}
+	} else if networkID != netCustom {
+		return fmt.Errorf("evm network %v must config 'ChainID'", networkID)
+	}
+
+	var (
+		chainID *big.Int
+		err     error
+	)
+
+	for i := 0; i < retryRPCCount; i++ {
+		chainID, err = b.GetSignerChainID()
+		if err == nil {
+			break
+		}
+		log.Errorf("can not get gateway chainID. %v", err)
+		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		time.Sleep(retryRPCInterval)
+	}
+	if err != nil {
+		return fmt.Errorf("get chain ID failed: %w", err)
+	}
+
+	if wantChainID != nil && chainID.Cmp(wantChainID) != 0 {
+		return fmt.Errorf("gateway chainID %v is not %v of network %v", chainID, wantChainID, networkID)
+	}
+
+	signerType := b.ChainConfig.SignerType
+	if signerType == "" {
+		signerType = "EIP155"
+	}
+	if b.ChainConfig.EnableDynamicFeeTx {
+		if signerType != "London" {
+			return fmt.Errorf("evm network %v enables dynamic fee tx but signer type is %v", networkID, signerType)
+		}
+		// blocks of london chain have base fee
+		if _, err = b.GetBaseFee(); err != nil {
+			return fmt.Errorf("evm network %v enables dynamic fee tx but chain is not london: %w", networkID, err)
+		}
+	}
+
+	b.SignerChainID = chainID
+	b.Signer = types.MakeSigner(signerType, chainID)
+	return nil
+}
@@ -0,0 +1,207 @@
+package evm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const testTxHash = "0x6c1a4a6d1bb5c3d1b9e5e0a8e5c4b0f57a0bd3b0c2a6d1e0d0c3a2b1f0e9d8c7"
+
+// testGateway fake gateway, reply rpc results by method (and block tag)
+type testGateway struct {
+	chainID   string
+	baseFee   string // empty means not london
+	latest    uint64
+	finalized uint64
+	txHeight  uint64
+}
+
+func (g *testGateway) result(method string, params []interface{}) interface{} {
+	block := func(number uint64) map[string]interface{} {
+		result := map[string]interface{}{"number": hexUint64(number)}
+		if g.baseFee != "" {
+			result["baseFeePerGas"] = g.baseFee
+		}
+		return result
+	}
+	switch method {
+	case "eth_chainId":
+		return g.chainID
+	case "eth_blockNumber":
+		return hexUint64(g.latest)
+	case "eth_getBlockByNumber":
+		if len(params) > 0 && params[0] == "finalized" {
+			return block(g.finalized)
+		}
+		return block(g.latest)
+	case "eth_getTransactionReceipt":
+		return map[string]interface{}{
+			"transactionHash": testTxHash,
+			"blockNumber":     hexUint64(g.txHeight),
+			"blockHash":       "0x1111111111111111111111111111111111111111111111111111111111111111",
+			"status":          "0x1",
+			"logs":            []interface{}{},
+		}
+	}
+	return nil
+}
+
+func hexUint64(n uint64) string {
+	return fmt.Sprintf("0x%x", n)
+}
+
+func newTestBridge(gateway *testGateway, chainCfg *tokens.ChainConfig) (*Bridge, func()) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int           `json:"id"`
+			Method string        `json:"method"`
+			Params []interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  gateway.result(req.Method, req.Params),
+		})
+	}))
+	retryRPCCount = 1
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = chainCfg
+	b.GatewayConfig = &tokens.GatewayConfig{APIAddress: []string{server.URL}}
+	return b, server.Close
+}
+
+func TestVerifyChainID(t *testing.T) {
+	tests := []struct {
+		name    string
+		gateway *testGateway
+		config  *tokens.ChainConfig
+		wantErr string
+	}{
+		{
+			name:    "match",
+			gateway: &testGateway{chainID: "0x89"},
+			config:  &tokens.ChainConfig{NetID: "polygon", ChainID: "137"},
+		},
+		{
+			name:    "chain id mismatch",
+			gateway: &testGateway{chainID: "0x38"},
+			config:  &tokens.ChainConfig{NetID: "polygon", ChainID: "137"},
+			wantErr: "gateway chainID 56 is not 137",
+		},
+		{
+			name:    "chain id not configed",
+			gateway: &testGateway{chainID: "0x89"},
+			config:  &tokens.ChainConfig{NetID: "polygon"},
+			wantErr: "must config 'ChainID'",
+		},
+		{
+			name:    "custom network skips chain id check",
+			gateway: &testGateway{chainID: "0x89"},
+			config:  &tokens.ChainConfig{NetID: netCustom},
+		},
+		{
+			name:    "dynamic fee tx with eip155 signer",
+			gateway: &testGateway{chainID: "0x89", baseFee: "0x3b9aca00"},
+			config:  &tokens.ChainConfig{NetID: "polygon", ChainID: "137", EnableDynamicFeeTx: true},
+			wantErr: "signer type is EIP155",
+		},
+		{
+			name:    "dynamic fee tx on non london chain",
+			gateway: &testGateway{chainID: "0x89"},
+			config:  &tokens.ChainConfig{NetID: "polygon", ChainID: "137", SignerType: "London", EnableDynamicFeeTx: true},
+			wantErr: "chain is not london",
+		},
+		{
+			name:    "dynamic fee tx on london chain",
+			gateway: &testGateway{chainID: "0x89", baseFee: "0x3b9aca00"},
+			config:  &tokens.ChainConfig{NetID: "polygon", ChainID: "137", SignerType: "London", EnableDynamicFeeTx: true},
+		},
+	}
+	for _, test := range tests {
+		b, closer := newTestBridge(test.gateway, test.config)
+		err := b.verifyChainID()
+		closer()
+		switch {
+		case test.wantErr == "" && err != nil:
+			t.Errorf("%v: verify chain id failed: %v", test.name, err)
+		case test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)):
+			t.Errorf("%v: want error %q, have %v", test.name, test.wantErr, err)
+		case err == nil && (b.Signer == nil || b.SignerChainID.Uint64() != 137):
+			t.Errorf("%v: signer is not set", test.name)
+		}
+	}
+}
+
+func TestCheckEvmConfig(t *testing.T) {
+	newConfig := func() *tokens.ChainConfig {
+		confirmations, initialHeight := uint64(10), uint64(0)
+		return &tokens.ChainConfig{
+			BlockChain:    "EVM",
+			NetID:         "polygon",
+			Confirmations: &confirmations,
+			InitialHeight: &initialHeight,
+		}
+	}
+	if err := newConfig().CheckConfig(); err != nil {
+		t.Fatalf("check valid config failed: %v", err)
+	}
+	londonConfig := newConfig()
+	londonConfig.SignerType, londonConfig.EnableDynamicFeeTx = "London", true
+	if err := londonConfig.CheckConfig(); err != nil {
+		t.Fatalf("check dynamic fee tx config of london signer failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(c *tokens.ChainConfig)
+		wantErr string
+	}{
+		{"wrong chain id", func(c *tokens.ChainConfig) { c.ChainID = "polygon" }, "wrong 'ChainID'"},
+		{"zero chain id", func(c *tokens.ChainConfig) { c.ChainID = "0" }, "wrong 'ChainID'"},
+		{"negative chain id", func(c *tokens.ChainConfig) { c.ChainID = "-137" }, "wrong 'ChainID'"},
+		{"wrong signer type", func(c *tokens.ChainConfig) { c.SignerType = "Berlin" }, "wrong 'SignerType'"},
+		{"wrong finality block tag", func(c *tokens.ChainConfig) { c.FinalityBlockTag = "latest" }, "wrong 'FinalityBlockTag'"},
+		{"dynamic fee tx with eip155 signer", func(c *tokens.ChainConfig) { c.EnableDynamicFeeTx = true }, "'EnableDynamicFeeTx'"},
+		{"dynamic fee tx of fusion", func(c *tokens.ChainConfig) { c.BlockChain, c.SignerType, c.EnableDynamicFeeTx = "Fusion", "London", true }, "'EnableDynamicFeeTx'"},
+	}
+	for _, test := range tests {
+		config := newConfig()
+		test.modify(config)
+		if err := config.CheckConfig(); err == nil || !strings.Contains(err.Error(), test.wantErr) {
+			t.Errorf("%v: want error %q, have %v", test.name, test.wantErr, err)
+		}
+	}
+}
+
+func TestConfirmationsFromFinalityBlock(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		finalized uint64
+		want      uint64
+	}{
+		{name: "from latest block", want: 20},
+		{name: "tx above finalized block", tag: "finalized", finalized: 90, want: 0},
+		{name: "tx is finalized block", tag: "finalized", finalized: 100, want: 0},
+		{name: "tx below finalized block", tag: "finalized", finalized: 105, want: 5},
+	}
+	for _, test := range tests {
+		gateway := &testGateway{chainID: "0x1", latest: 120, finalized: test.finalized, txHeight: 100}
+		b, closer := newTestBridge(gateway, &tokens.ChainConfig{NetID: "mainnet", FinalityBlockTag: test.tag})
+		txStatus := b.GetTransactionStatus(testTxHash)
+		closer()
+		if txStatus.BlockHeight != 100 || txStatus.Confirmations != test.want {
+			t.Errorf("%v: want confirmations %v, have %v (height %v)", test.name, test.want, txStatus.Confirmations, txStatus.BlockHeight)
+		}
+	}
+}
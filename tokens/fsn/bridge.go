@@ -23,6 +23,14 @@ type Bridge struct {
 	*eth.Bridge
 }
 
+func init() {
+	tokens.RegisterBridgeFactory("Fusion", newBridgeFactory)
+}
+
+func newBridgeFactory(isSrc bool) tokens.CrossChainBridge {
+	return NewCrossChainBridge(isSrc)
+}
+
 // NewCrossChainBridge new fsn bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	bridge := &Bridge{Bridge: eth.NewCrossChainBridge(isSrc)}
@@ -21,6 +21,7 @@ var (
 	ErrTxBeforeInitialHeight         = errors.New("transaction before initial block height")
 	ErrAddressIsInBlacklist          = errors.New("address is in black list")
 	ErrSwapIsClosed                  = errors.New("swap is closed")
+	ErrUnknownBlockChain             = errors.New("unknown block chain")
 
 	ErrTodo = errors.New("developing: TODO")
 
@@ -110,6 +111,39 @@ type NonceSetter interface {
 	InitNonces(nonces map[string]uint64)
 }
 
+// BatchTxBuilder interface (for utxo-like) build one tx paying multiple swaps
+type BatchTxBuilder interface {
+	// BuildBatchRawTransaction build tx paying every swap of args.Batch,
+	// fills SwapValue, OutputIndex and Memo of each batch item
+	BuildBatchRawTransaction(args *BuildTxArgs) (rawTx interface{}, err error)
+	// VerifyBatchRawTransaction verify every swap of args.Batch is paid
+	// by the output of raw tx with its OutputIndex and SwapValue
+	VerifyBatchRawTransaction(rawTx interface{}, args *BuildTxArgs) error
+}
+
+// DynamicFeeTxReplacer interface (for eth-like) estimate fee caps to replace dynamic fee tx
+type DynamicFeeTxReplacer interface {
+	// GetReplaceGasFeeCaps returns nil fee caps if dynamic fee tx is not enabled,
+	// otherwise both fee caps are bumped enough to replace the old swap tx
+	GetReplaceGasFeeCaps(args *BuildTxArgs, oldSwapTx string) (gasTipCap, gasFeeCap *big.Int, err error)
+}
+
+// FeeBumper interface (for utxo-like) speed up unconfirmed swap tx
+type FeeBumper interface {
+	// GetFeeBumpExtra returns extra args to build a replace-by-fee tx of swapTx
+	// if it signals replaceability, otherwise a child-pays-for-parent tx of it.
+	// cpfpTx is the previous child-pays-for-parent tx of swapTx which will be replaced
+	GetFeeBumpExtra(pairID, swapTx, cpfpTx string) (*BtcExtraArgs, error)
+}
+
+// TxExpirer interface (for tron-like) rebuild swap tx dropped after expiration
+type TxExpirer interface {
+	// IsTxExpired returns true if txHash is not packed and can never be packed
+	// as the expiration of it has passed. txTime is the unix time after which
+	// txHash is built, and the tx expires a fixed duration after its building.
+	IsTxExpired(txHash string, txTime int64) (bool, error)
+}
+
 // ForkChecker fork checker interface
 type ForkChecker interface {
 	GetBlockHashOf(urls []string, height uint64) (hash string, err error)
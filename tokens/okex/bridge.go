@@ -21,6 +21,14 @@ type Bridge struct {
 	*eth.Bridge
 }
 
+func init() {
+	tokens.RegisterBridgeFactory("OKEx", newBridgeFactory)
+}
+
+func newBridgeFactory(isSrc bool) tokens.CrossChainBridge {
+	return NewCrossChainBridge(isSrc)
+}
+
 // NewCrossChainBridge new okex bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
 	bridge := &Bridge{Bridge: eth.NewCrossChainBridge(isSrc)}
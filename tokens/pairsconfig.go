@@ -7,6 +7,7 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/anyswap/CrossChain-Bridge/common"
@@ -17,6 +18,10 @@ var (
 	tokenPairsConfigDirectory string
 
 	tokenPairsConfig map[string]*TokenPairConfig
+
+	// guards DisableSwap of token configs, it is changed at runtime
+	// by admin and reorg monitor
+	disableSwapLock sync.RWMutex
 )
 
 // TokenPairConfig pair config
@@ -26,6 +31,33 @@ type TokenPairConfig struct {
 	DestToken *TokenConfig
 }
 
+// IsSwapDisabled is swap of token disabled
+func (c *TokenConfig) IsSwapDisabled() bool {
+	disableSwapLock.RLock()
+	defer disableSwapLock.RUnlock()
+	return c.DisableSwap
+}
+
+// SetDisableSwap disable or enable swap of token at runtime
+func (c *TokenConfig) SetDisableSwap(flag bool) {
+	disableSwapLock.Lock()
+	defer disableSwapLock.Unlock()
+	c.DisableSwap = flag
+}
+
+// Clone copy pair config, it is safe to be read while swap is
+// disabled or enabled at runtime
+func (c *TokenPairConfig) Clone() *TokenPairConfig {
+	disableSwapLock.RLock()
+	defer disableSwapLock.RUnlock()
+	srcToken, dstToken := *c.SrcToken, *c.DestToken
+	return &TokenPairConfig{
+		PairID:    c.PairID,
+		SrcToken:  &srcToken,
+		DestToken: &dstToken,
+	}
+}
+
 // SetTokenPairsDir set token pairs directory
 func SetTokenPairsDir(dir string) {
 	log.Printf("set token pairs config directory to '%v'\n", dir)
@@ -165,7 +197,7 @@ func checkTokenPairsConfig(pairsConfig map[string]*TokenPairConfig) (err error)
 				return fmt.Errorf("duplicate destination contract '%v'", tokenPair.DestToken.ContractAddress)
 			}
 			dstContractsMap[dstContract] = struct{}{}
-		} else if !tokenPair.DestToken.DisableSwap {
+		} else if !tokenPair.DestToken.IsSwapDisabled() {
 			return fmt.Errorf("must close withdraw if is delegate swapin")
 		}
 		// check config
@@ -315,7 +347,7 @@ func checkAddTokenPairsConfig(pairConfig *TokenPairConfig) (err error) {
 		return fmt.Errorf("source contract address is empty, need restart program")
 	}
 	isDelegateSwapin := pairConfig.SrcToken.IsDelegateContract
-	if isDelegateSwapin && !pairConfig.DestToken.DisableSwap {
+	if isDelegateSwapin && !pairConfig.DestToken.IsSwapDisabled() {
 		return fmt.Errorf("must close withdraw if is delegate swapin")
 	}
 	dstContract := strings.ToLower(pairConfig.DestToken.ContractAddress)
@@ -0,0 +1,64 @@
+package tokens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// BridgeFactory new bridge of a block chain
+type BridgeFactory func(isSrc bool) CrossChainBridge
+
+var (
+	bridgeFactories     = make(map[string]BridgeFactory)
+	bridgeFactoriesLock sync.RWMutex
+)
+
+// RegisterBridgeFactory register bridge factory of block chain,
+// chain packages call it from init, name is matched as case insensitive prefix
+// of 'BlockChain' in chain config (eg. 'Ethereum' matches 'ETHEREUM').
+func RegisterBridgeFactory(name string, factory BridgeFactory) {
+	key := strings.ToUpper(name)
+	if key == "" || factory == nil {
+		panic("register bridge factory with empty name or nil factory")
+	}
+	bridgeFactoriesLock.Lock()
+	defer bridgeFactoriesLock.Unlock()
+	if _, exist := bridgeFactories[key]; exist {
+		panic("register bridge factory twice for block chain " + name)
+	}
+	bridgeFactories[key] = factory
+}
+
+// GetRegisteredBlockChains get sorted names of registered block chains
+func GetRegisteredBlockChains() []string {
+	bridgeFactoriesLock.RLock()
+	defer bridgeFactoriesLock.RUnlock()
+	names := make([]string, 0, len(bridgeFactories))
+	for name := range bridgeFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewCrossChainBridge new bridge by registered factory of the longest matched name
+func NewCrossChainBridge(blockChain string, isSrc bool) (CrossChainBridge, error) {
+	blockChainIden := strings.ToUpper(blockChain)
+	bridgeFactoriesLock.RLock()
+	var (
+		matched string
+		factory BridgeFactory
+	)
+	for name, f := range bridgeFactories {
+		if strings.HasPrefix(blockChainIden, name) && len(name) > len(matched) {
+			matched, factory = name, f
+		}
+	}
+	bridgeFactoriesLock.RUnlock()
+	if factory == nil {
+		return nil, fmt.Errorf("%w: %v, registered block chains are %v", ErrUnknownBlockChain, blockChain, GetRegisteredBlockChains())
+	}
+	return factory(isSrc), nil
+}
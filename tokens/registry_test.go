@@ -0,0 +1,35 @@
+package tokens
+
+import (
+	"errors"
+	"testing"
+)
+
+type testBridge struct {
+	CrossChainBridge
+	name string
+}
+
+func TestNewCrossChainBridgeByRegistry(t *testing.T) {
+	RegisterBridgeFactory("TestChain", func(isSrc bool) CrossChainBridge { return &testBridge{name: "testchain"} })
+	RegisterBridgeFactory("TestChainX", func(isSrc bool) CrossChainBridge { return &testBridge{name: "testchainx"} })
+
+	bridge, err := NewCrossChainBridge("testchain-mainnet", true)
+	if err != nil || bridge.(*testBridge).name != "testchain" {
+		t.Fatalf("new bridge failed, err %v", err)
+	}
+	bridge, err = NewCrossChainBridge("TESTCHAINX", true)
+	if err != nil || bridge.(*testBridge).name != "testchainx" {
+		t.Fatalf("longest matched name is not used, err %v", err)
+	}
+	if _, err = NewCrossChainBridge("unknown", true); !errors.Is(err, ErrUnknownBlockChain) {
+		t.Fatalf("want unknown block chain error, have %v", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("register twice should panic")
+		}
+	}()
+	RegisterBridgeFactory("testchain", func(isSrc bool) CrossChainBridge { return nil })
+}
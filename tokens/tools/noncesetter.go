@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// SrcNonceSetterBase base nonce setter of bridges which are source endpoint only
+// (eg. cosmos, xrp), so there are only swapout nonces. nonce is the account sequence.
+// bridges embed it and implement 'GetTxBlockInfo' and 'GetPoolNonce' of NonceSetter.
+type SrcNonceSetterBase struct {
+	SwapoutNonce map[string]uint64
+}
+
+// NewSrcNonceSetterBase new base nonce setter of source endpoint
+func NewSrcNonceSetterBase() *SrcNonceSetterBase {
+	return &SrcNonceSetterBase{
+		SwapoutNonce: make(map[string]uint64),
+	}
+}
+
+// SetNonce set nonce directly always increase
+func (b *SrcNonceSetterBase) SetNonce(pairID string, value uint64) {
+	tokenCfg := tokens.GetTokenConfig(pairID, true)
+	account := tokenCfg.DcrmAddress
+	if b.SwapoutNonce[account] < value {
+		b.SwapoutNonce[account] = value
+		_ = mongodb.UpdateLatestSwapoutNonce(account, value)
+	}
+}
+
+// AdjustNonce adjust account nonce
+func (b *SrcNonceSetterBase) AdjustNonce(pairID string, value uint64) (nonce uint64) {
+	tokenCfg := tokens.GetTokenConfig(pairID, true)
+	account := tokenCfg.DcrmAddress
+	nonce = value
+	if b.SwapoutNonce[account] > value {
+		nonce = b.SwapoutNonce[account]
+	}
+	return nonce
+}
+
+// InitNonces init nonces
+func (b *SrcNonceSetterBase) InitNonces(nonces map[string]uint64) {
+	b.SwapoutNonce = nonces
+	log.Info("init swap nonces finished", "isSwapin", false, "nonces", nonces)
+}
@@ -46,6 +46,21 @@ func IsSwapExist(txid, pairID, bind string, isSwapin bool) bool {
 	return false
 }
 
+// SwapTxVerifier verify swap tx, returns swap infos with corresponding verify errors
+type SwapTxVerifier func(txHash string, allowUnstable bool) ([]*tokens.TxSwapInfo, []error)
+
+// ProcessSwapin verify and register swapin of scanned tx
+func ProcessSwapin(txid string, verifySwapinTx SwapTxVerifier) {
+	swapInfos, errs := verifySwapinTx(txid, true)
+	RegisterSwapin(txid, swapInfos, errs)
+}
+
+// ProcessSwapout verify and register swapout of scanned tx
+func ProcessSwapout(txid string, verifySwapoutTx SwapTxVerifier) {
+	swapInfos, errs := verifySwapoutTx(txid, true)
+	RegisterSwapout(txid, swapInfos, errs)
+}
+
 // RegisterSwapin register swapin
 func RegisterSwapin(txid string, swapInfos []*tokens.TxSwapInfo, verifyErrors []error) {
 	registerSwap(true, txid, swapInfos, verifyErrors)
@@ -184,6 +199,23 @@ func GetP2shBindAddress(p2shAddress string) (bindAddress string) {
 	return ""
 }
 
+// GetDestinationTagBindAddress get bind address of xrp destination tag
+func GetDestinationTagBindAddress(tag uint32) (bindAddress string) {
+	if mongodb.HasSession() {
+		bindAddress, _ = mongodb.FindDestinationTagBindAddress(tag)
+		return bindAddress
+	}
+	var result tokens.DestinationTagInfo
+	for i := 0; i < retryRPCCount; i++ {
+		err := client.RPCPostWithTimeout(swapRPCTimeout, &result, params.ServerAPIAddress, "swap.GetDestinationTagInfo", tag)
+		if err == nil {
+			return result.BindAddress
+		}
+		time.Sleep(retryRPCInterval)
+	}
+	return ""
+}
+
 // GetLatestScanHeight get latest scanned block height
 func GetLatestScanHeight(isSrc bool) uint64 {
 	if mongodb.HasSession() {
@@ -0,0 +1,96 @@
+package tron
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// addressPrefix version byte of tron address
+const addressPrefix byte = 0x41
+
+var errInvalidAddress = errors.New("invalid tron address")
+
+// IsValidAddress check address,
+// base58check (eg. 'T...') and hex (with '41' prefix or ethereum style) address are both valid,
+// as they are converted one-to-one with ethereum address of the same key.
+func (b *Bridge) IsValidAddress(address string) bool {
+	_, err := DecodeAddress(address)
+	return err == nil
+}
+
+// DecodeAddress decode base58check or hex address to 20 bytes ethereum address
+func DecodeAddress(address string) (common.Address, error) {
+	switch {
+	case common.IsHexAddress(address):
+		return common.HexToAddress(address), nil
+	case len(address) == 2*(1+common.AddressLength) && strings.HasPrefix(address, "41"):
+		data := common.FromHex(address)
+		if len(data) != 1+common.AddressLength {
+			return common.Address{}, errInvalidAddress
+		}
+		return common.BytesToAddress(data[1:]), nil
+	}
+	data, version, err := base58.CheckDecode(address)
+	if err != nil || version != addressPrefix || len(data) != common.AddressLength {
+		return common.Address{}, errInvalidAddress
+	}
+	return common.BytesToAddress(data), nil
+}
+
+// EncodeAddress encode ethereum address to base58check tron address
+func EncodeAddress(addr common.Address) string {
+	return base58.CheckEncode(addr.Bytes(), addressPrefix)
+}
+
+// ToBase58Address convert address to base58check encoding
+func ToBase58Address(address string) (string, error) {
+	addr, err := DecodeAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return EncodeAddress(addr), nil
+}
+
+// ToHexAddress convert address to hex encoding with '41' prefix (used by tron api)
+func ToHexAddress(address string) (string, error) {
+	addr, err := DecodeAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return toHexAddress(addr), nil
+}
+
+// ToEthAddress convert address to ethereum address of the same key
+func ToEthAddress(address string) (string, error) {
+	addr, err := DecodeAddress(address)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(addr.String()), nil
+}
+
+func toHexAddress(addr common.Address) string {
+	return common.Bytes2Hex(toAddressBytes(addr))
+}
+
+// toAddressBytes address bytes in protobuf transaction
+func toAddressBytes(addr common.Address) []byte {
+	return append([]byte{addressPrefix}, addr.Bytes()...)
+}
+
+// PublicKeyToAddress get base58check tron address of public key
+func PublicKeyToAddress(pubKey *ecdsa.PublicKey) string {
+	return EncodeAddress(crypto.PubkeyToAddress(*pubKey))
+}
+
+// isSameAddress compare addresses in any encoding
+func isSameAddress(addr1, addr2 string) bool {
+	a1, err1 := DecodeAddress(addr1)
+	a2, err2 := DecodeAddress(addr2)
+	return err1 == nil && err2 == nil && a1 == a2
+}
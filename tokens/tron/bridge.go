@@ -0,0 +1,172 @@
+// Package tron implements the bridge interfaces for tron (TRX and TRC20).
+//
+// Addresses are base58check encoded with version byte 0x41, they are
+// one-to-one mapped with ethereum addresses of the same key, so the bind
+// address of swapin defaults to the ethereum address of the sender (or
+// specified by 'SWAPTO:' memo in the data field of the deposit tx).
+// Transactions are protobuf encoded and signed with secp256k1 keys.
+// Chain data are queried from the tron http api (eg. '/wallet/getnowblock').
+package tron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	// BlockChain block chain name of tron
+	BlockChain = "Tron"
+
+	netMainnet = "mainnet"
+	netShasta  = "shasta"
+	netNile    = "nile"
+	netCustom  = "custom"
+
+	trxDecimals = 6
+)
+
+// Bridge tron bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+}
+
+func init() {
+	tokens.RegisterBridgeFactory(BlockChain, func(isSrc bool) tokens.CrossChainBridge {
+		return NewCrossChainBridge(isSrc)
+	})
+}
+
+// NewCrossChainBridge new tron bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc),
+	}
+}
+
+// SetChainAndGateway set chain and gateway config
+func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	b.VerifyChainConfig()
+	eth.InitExtCodeParts()
+	b.InitLatestBlockNumber()
+}
+
+// VerifyChainConfig verify chain config
+func (b *Bridge) VerifyChainConfig() {
+	networkID := strings.ToLower(b.ChainConfig.NetID)
+	switch networkID {
+	case netMainnet, netShasta, netNile, netCustom:
+	default:
+		log.Fatalf("unsupported tron network: %v", b.ChainConfig.NetID)
+	}
+	log.Info("verify chain config succeed", "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID)
+}
+
+// VerifyTokenConfig verify token config
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) (err error) {
+	if !b.IsValidAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address: %v", tokenCfg.DcrmAddress)
+	}
+	if b.IsSrc && !b.IsValidAddress(tokenCfg.DepositAddress) {
+		return fmt.Errorf("invalid deposit address: %v", tokenCfg.DepositAddress)
+	}
+	if tokenCfg.ContractAddress != "" && !b.IsValidAddress(tokenCfg.ContractAddress) {
+		return fmt.Errorf("invalid contract address: %v", tokenCfg.ContractAddress)
+	}
+	if tokenCfg.IsDelegateContract || tokenCfg.IsProxyErc20() {
+		return fmt.Errorf("delegate and proxy contract are not supported on %v", BlockChain)
+	}
+
+	err = b.verifyDcrmPublicKey(tokenCfg)
+	if err != nil {
+		return err
+	}
+
+	err = b.verifyDecimals(tokenCfg)
+	if err != nil {
+		return err
+	}
+
+	return b.verifyContractAddress(tokenCfg)
+}
+
+func (b *Bridge) verifyDcrmPublicKey(tokenCfg *tokens.TokenConfig) error {
+	if tokenCfg.DcrmPubkey == "" {
+		return nil
+	}
+	pubKey, err := crypto.UnmarshalPubkey(common.FromHex(tokenCfg.DcrmPubkey))
+	if err != nil {
+		return fmt.Errorf("wrong dcrm public key, %w", err)
+	}
+	if !isSameAddress(PublicKeyToAddress(pubKey), tokenCfg.DcrmAddress) {
+		return fmt.Errorf("dcrm address %v and public key address %v is not match", tokenCfg.DcrmAddress, PublicKeyToAddress(pubKey))
+	}
+	return nil
+}
+
+func (b *Bridge) verifyDecimals(tokenCfg *tokens.TokenConfig) error {
+	configedDecimals := *tokenCfg.Decimals
+	if tokenCfg.ContractAddress == "" {
+		if configedDecimals != trxDecimals {
+			return fmt.Errorf("invalid decimals: want %v but have %v", trxDecimals, configedDecimals)
+		}
+		log.Info(tokenCfg.Symbol+" verify decimals success", "decimals", configedDecimals)
+		return nil
+	}
+	decimals, err := b.GetTrc20Decimals(tokenCfg.ContractAddress)
+	if err != nil {
+		log.Error("get trc20 decimals failed", "address", tokenCfg.ContractAddress, "err", err)
+		return err
+	}
+	if decimals != configedDecimals {
+		return fmt.Errorf("invalid decimals for %v, want %v but configed %v", tokenCfg.Symbol, decimals, configedDecimals)
+	}
+	log.Info(tokenCfg.Symbol+" verify decimals success", "address", tokenCfg.ContractAddress, "decimals", configedDecimals)
+	return nil
+}
+
+func (b *Bridge) verifyContractAddress(tokenCfg *tokens.TokenConfig) error {
+	contractAddr := tokenCfg.ContractAddress
+	if contractAddr == "" {
+		return nil
+	}
+	if b.IsSrc && !tokenCfg.IsErc20() {
+		return fmt.Errorf("source token %v is not TRC20", contractAddr)
+	}
+	code, err := b.GetContractCode(contractAddr)
+	if err != nil {
+		return fmt.Errorf("get contract code of %v failed, %w", contractAddr, err)
+	}
+	if b.IsSrc {
+		err = eth.VerifyErc20ContractCode(code)
+	} else {
+		err = eth.VerifySwapContractCode(code)
+	}
+	if err != nil {
+		return fmt.Errorf("wrong contract address: %v, %w", contractAddr, err)
+	}
+	log.Info("verify contract address pass", "address", contractAddr)
+	return nil
+}
+
+// InitLatestBlockNumber init latest block number
+func (b *Bridge) InitLatestBlockNumber() {
+	for {
+		latest, err := b.GetLatestBlockNumber()
+		if err == nil {
+			tokens.SetLatestBlockHeight(latest, b.IsSrc)
+			log.Info("get latst block number succeed.", "number", latest, "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID)
+			break
+		}
+		log.Error("get latst block number failed.", "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID, "err", err)
+		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		time.Sleep(3 * time.Second)
+	}
+}
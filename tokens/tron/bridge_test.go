@@ -0,0 +1,431 @@
+package tron
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/internal/protobuf"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	testContract    = "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"
+	testContractHex = "41a614f803b6fd780986a42c78ec9c7f77e6ded13c"
+)
+
+func newTestBridge(apiAddress string) *Bridge {
+	b := NewCrossChainBridge(false)
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain: BlockChain,
+		NetID:      netCustom,
+	}
+	b.GatewayConfig = &tokens.GatewayConfig{
+		APIAddress: []string{apiAddress},
+	}
+	return b
+}
+
+// testTronNode fake http api of tron full node, every '/wallet/*' api is
+// posted with json params, and unknown tx is returned as empty object.
+type testTronNode struct {
+	*httptest.Server
+	t *testing.T
+
+	lock       sync.Mutex
+	nowBlock   interface{}
+	txs        map[string]interface{} // '/wallet/gettransactionbyid' results by txid
+	txInfos    map[string]interface{} // '/wallet/gettransactioninfobyid' results by txid
+	broadcasts []string               // hex of broadcasted txs
+}
+
+func newTestTronNode(t *testing.T) *testTronNode {
+	node := &testTronNode{
+		t:       t,
+		txs:     make(map[string]interface{}),
+		txInfos: make(map[string]interface{}),
+	}
+	node.Server = httptest.NewServer(http.HandlerFunc(node.serve))
+	return node
+}
+
+func (node *testTronNode) setNowBlock(number, timestamp int64) {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	node.nowBlock = map[string]interface{}{
+		"blockID": fmt.Sprintf("%016x", number) + strings.Repeat("a1b2", 12),
+		"block_header": map[string]interface{}{
+			"raw_data": map[string]interface{}{"number": number, "timestamp": timestamp},
+		},
+	}
+}
+
+func (node *testTronNode) serve(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	var params map[string]string
+	_ = json.NewDecoder(r.Body).Decode(&params)
+
+	node.lock.Lock()
+	var result interface{} = map[string]interface{}{}
+	switch r.URL.Path {
+	case "/wallet/getnowblock":
+		result = node.nowBlock
+	case "/wallet/gettransactionbyid":
+		if tx, exist := node.txs[params["value"]]; exist {
+			result = tx
+		}
+	case "/wallet/gettransactioninfobyid":
+		if info, exist := node.txInfos[params["value"]]; exist {
+			result = info
+		}
+	case "/wallet/broadcasthex":
+		node.broadcasts = append(node.broadcasts, params["transaction"])
+		result = broadcastHexResult(params["transaction"])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+	node.lock.Unlock()
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		node.t.Error(err)
+	}
+}
+
+// broadcastHexResult txid is sha256 of raw data (field 1) of the tx
+func broadcastHexResult(txHex string) interface{} {
+	var rawData []byte
+	err := protobuf.RangeFields(common.FromHex(txHex), func(fieldNum uint64, data []byte) error {
+		if fieldNum == 1 {
+			rawData = data
+		}
+		return nil
+	})
+	if err != nil || rawData == nil {
+		return map[string]interface{}{"result": false, "code": "TRANSACTION_EXPIRATION_ERROR"}
+	}
+	txid := sha256.Sum256(rawData)
+	return map[string]interface{}{"result": true, "txid": hex.EncodeToString(txid[:])}
+}
+
+func (node *testTronNode) getBroadcasts() []string {
+	node.lock.Lock()
+	defer node.lock.Unlock()
+	return node.broadcasts
+}
+
+func TestAddress(t *testing.T) {
+	b := newTestBridge("")
+	hexAddr, err := ToHexAddress(testContract)
+	if err != nil || hexAddr != testContractHex {
+		t.Fatalf("convert %v to %v, want %v, err %v", testContract, hexAddr, testContractHex, err)
+	}
+	for _, addr := range []string{testContract, testContractHex, "0x" + testContractHex[2:]} {
+		if !b.IsValidAddress(addr) {
+			t.Fatalf("address %v should be valid", addr)
+		}
+		base58Addr, err := ToBase58Address(addr)
+		if err != nil || base58Addr != testContract {
+			t.Fatalf("convert %v to %v, want %v, err %v", addr, base58Addr, testContract, err)
+		}
+	}
+	if !isSameAddress(testContract, "0x"+testContractHex[2:]) {
+		t.Fatal("base58 and ethereum form of address should be the same")
+	}
+
+	invalids := []string{
+		"TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6u", // wrong checksum
+		"42a614f803b6fd780986a42c78ec9c7f77e6ded13c",
+		"",
+	}
+	for _, addr := range invalids {
+		if b.IsValidAddress(addr) {
+			t.Fatalf("address %v should be invalid", addr)
+		}
+	}
+}
+
+// known answers of tx raw data and txid, the first one is the raw data of
+// a mainnet tx (from gotron-sdk), and the others are encoded by protobuf-go
+// with the message types generated from tron protocol.
+var testTransactions = []struct {
+	contract Contract
+	memo     string
+	extra    *tokens.TronExtraArgs
+	rawData  string
+	txid     string
+}{
+	{
+		contract: &TriggerSmartContract{
+			OwnerAddress:    common.HexToAddress("0x9df085719e7e0bd5bf4fd1b2a6aed6afd2b8416d"),
+			ContractAddress: common.HexToAddress("0x157a629d8e8d7d43218b83240afaa02e8c300b36"),
+			Data:            common.FromHex("0x97a5d5b50000000000000000000000009df085719e7e0bd5bf4fd1b2a6aed6afd2b8416d"),
+		},
+		extra: &tokens.TronExtraArgs{
+			RefBlockBytes: "0cd2",
+			RefBlockHash:  "1e6d180d0ea1be13",
+			Expiration:    1598308680000,
+			Timestamp:     1598308622469,
+		},
+		rawData: "0a020cd222081e6d180d0ea1be1340c082fc94c22e5a8e01081f1289010a31747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e54726967676572536d617274436f6e747261637412540a15419df085719e7e0bd5bf4fd1b2a6aed6afd2b8416d121541157a629d8e8d7d43218b83240afaa02e8c300b36222497a5d5b50000000000000000000000009df085719e7e0bd5bf4fd1b2a6aed6afd2b8416d7085c1f894c22e",
+		txid:    "9e7f17a36f425d35f3af402b1e0c13b9957497a9b92c8de6f68ef3706d357e2e",
+	},
+	{
+		contract: &TransferContract{
+			OwnerAddress: common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			ToAddress:    common.HexToAddress("0x2222222222222222222222222222222222222222"),
+			Amount:       1000000,
+		},
+		memo: "SWAPTX:0x01",
+		extra: &tokens.TronExtraArgs{
+			RefBlockBytes: "a1b2",
+			RefBlockHash:  "0102030405060708",
+			Expiration:    1600000060000,
+			Timestamp:     1600000000000,
+			FeeLimit:      defaultFeeLimit, // not used by transfer contract
+		},
+		rawData: "0a02a1b22208010203040506070840e0d4bdbbc82e520b5357415054583a307830315a67080112630a2d747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e5472616e73666572436f6e747261637412320a15411111111111111111111111111111111111111111121541222222222222222222222222222222222222222218c0843d708080babbc82e",
+		txid:    "5fdbfededc2198e62a8c6e915642aa31a0ffddc3b3f42496f0ea20c7e95a0b56",
+	},
+	{
+		contract: &TriggerSmartContract{
+			OwnerAddress:    common.HexToAddress("0x1111111111111111111111111111111111111111"),
+			ContractAddress: common.HexToAddress("0x" + testContractHex[2:]),
+			Data:            eth.PackDataWithFuncHash(transferFuncHash, common.HexToAddress("0x2222222222222222222222222222222222222222"), big.NewInt(10)),
+		},
+		extra: &tokens.TronExtraArgs{
+			RefBlockBytes: "a1b2",
+			RefBlockHash:  "0102030405060708",
+			Expiration:    1600000060000,
+			Timestamp:     1600000000000,
+			FeeLimit:      defaultFeeLimit,
+		},
+		rawData: "0a02a1b22208010203040506070840e0d4bdbbc82e5aae01081f12a9010a31747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e54726967676572536d617274436f6e747261637412740a15411111111111111111111111111111111111111111121541a614f803b6fd780986a42c78ec9c7f77e6ded13c2244a9059cbb0000000000000000000000002222222222222222222222222222222222222222000000000000000000000000000000000000000000000000000000000000000a708080babbc82e900180c2d72f",
+		txid:    "da8c6c4e354f07a337d658f19947575b700e0b4a3ac26d73647b76ae9e7dbc84",
+	},
+}
+
+func TestTransactionEncoding(t *testing.T) {
+	for i, test := range testTransactions {
+		var memo []byte
+		if test.memo != "" {
+			memo = []byte(test.memo)
+		}
+		tx, err := newTransaction(test.contract, memo, test.extra)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have := hex.EncodeToString(tx.RawData()); have != test.rawData {
+			t.Fatalf("test %v: wrong raw data\nhave %v\nwant %v", i, have, test.rawData)
+		}
+		if have := hex.EncodeToString(tx.Hash().Bytes()); have != test.txid {
+			t.Fatalf("test %v: wrong txid %v, want %v", i, have, test.txid)
+		}
+	}
+}
+
+// fixed key and its signature of a trc20 transfer tx,
+// which are signed and encoded by decred secp256k1 (RFC6979) and gotron-sdk.
+const (
+	testPrivKey   = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	testSigner    = "TE2H9hWjzYdwzDFRJfx9BFhr4MmjH1CHaz"
+	testSignTxID  = "a61b225648bdbf4890df16ccdfcfb19ad72050581ee100f4835cc7e007473e3d"
+	testSignature = "a8ebf2f5c6dd8d67fedc71e414415c3466e7d030b47d5df97900d89bf8b1b41c04d27d09c2df781f69dde3835d589d1937563e4b3b3263d380a37f5a684c1b3400"
+	testSignedTx  = "0ad3010a02a1b22208010203040506070840e0d4bdbbc82e5aae01081f12a9010a31747970652e676f6f676c65617069732e636f6d2f70726f746f636f6c2e54726967676572536d617274436f6e747261637412740a15412c7536e3605d9c16a7a3d7b1898e529396a65c23121541a614f803b6fd780986a42c78ec9c7f77e6ded13c2244a9059cbb000000000000000000000000222222222222222222222222222222222222222200000000000000000000000000000000000000000000000000000000000f4240708080babbc82e900180c2d72f1241a8ebf2f5c6dd8d67fedc71e414415c3466e7d030b47d5df97900d89bf8b1b41c04d27d09c2df781f69dde3835d589d1937563e4b3b3263d380a37f5a684c1b3400"
+)
+
+func TestSignTransaction(t *testing.T) {
+	node := newTestTronNode(t)
+	defer node.Close()
+	b := newTestBridge(node.URL)
+	b.GatewayConfig.APIAddressExt = []string{node.URL}
+
+	privKey, err := crypto.HexToECDSA(testPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signer := PublicKeyToAddress(&privKey.PublicKey); signer != testSigner {
+		t.Fatalf("wrong signer %v, want %v", signer, testSigner)
+	}
+	from, _ := DecodeAddress(testSigner)
+	contract := &TriggerSmartContract{
+		OwnerAddress:    from,
+		ContractAddress: common.HexToAddress("0x" + testContractHex[2:]),
+		Data:            eth.PackDataWithFuncHash(transferFuncHash, common.HexToAddress("0x2222222222222222222222222222222222222222"), big.NewInt(1000000)),
+	}
+	tx, err := newTransaction(contract, nil, &tokens.TronExtraArgs{
+		RefBlockBytes: "a1b2",
+		RefBlockHash:  "0102030405060708",
+		Expiration:    1600000060000,
+		Timestamp:     1600000000000,
+		FeeLimit:      defaultFeeLimit,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.VerifyMsgHash(tx, []string{"0x" + testSignTxID}); err != nil {
+		t.Fatal(err)
+	}
+	_, txHash, err := b.SignTransactionWithPrivateKey(tx, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txHash != testSignTxID {
+		t.Fatalf("wrong signed tx hash %v, want %v", txHash, testSignTxID)
+	}
+	if have := hex.EncodeToString(tx.Signature); have != testSignature {
+		t.Fatalf("wrong signature\nhave %v\nwant %v", have, testSignature)
+	}
+
+	// signed tx is broadcasted to every gateway
+	txHash, err = b.SendTransaction(tx)
+	if err != nil || txHash != testSignTxID {
+		t.Fatalf("send tx hash %v, err %v", txHash, err)
+	}
+	broadcasts := node.getBroadcasts()
+	if len(broadcasts) != 2 {
+		t.Fatalf("tx is broadcasted %v times", len(broadcasts))
+	}
+	for _, broadcast := range broadcasts {
+		if broadcast != testSignedTx {
+			t.Fatalf("wrong broadcasted tx\nhave %v\nwant %v", broadcast, testSignedTx)
+		}
+	}
+	if len(b.GatewayConfig.APIAddress) != 1 {
+		t.Fatal("gateway urls are modified by broadcasting")
+	}
+}
+
+func TestVerifySwapoutReceipt(t *testing.T) {
+	eth.InitExtCodeParts()
+
+	txid := "6ec3fcb8d8a9eeaf8eef6c3dcfbb1f7a04a1d2d2b26f0d8a5cfd0b4aa2e2c6b1"
+	sender := "411111111111111111111111111111111111111111"
+	bind := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	value := big.NewInt(12345678)
+
+	node := newTestTronNode(t)
+	defer node.Close()
+	node.setNowBlock(100, 1600000000000)
+	node.txs[txid] = map[string]interface{}{
+		"txID": txid,
+		"raw_data": map[string]interface{}{
+			"contract": []interface{}{map[string]interface{}{
+				"type": triggerSmartContractName,
+				"parameter": map[string]interface{}{
+					"value": map[string]interface{}{
+						"owner_address":    sender,
+						"contract_address": testContractHex,
+						"data":             hex.EncodeToString(eth.PackDataWithFuncHash(eth.ExtCodeParts["SwapoutFuncHash"], value, bind)),
+					},
+				},
+			}},
+		},
+		"ret": []interface{}{map[string]interface{}{"contractRet": "SUCCESS"}},
+	}
+	node.txInfos[txid] = map[string]interface{}{
+		"id":          txid,
+		"blockNumber": 90,
+		"receipt":     map[string]interface{}{"result": "SUCCESS"},
+		"log": []interface{}{map[string]interface{}{
+			"address": testContractHex[2:],
+			"topics": []string{
+				hex.EncodeToString(eth.ExtCodeParts["LogSwapoutTopic"]),
+				"000000000000000000000000" + sender[2:],
+				hex.EncodeToString(common.LeftPadBytes(bind.Bytes(), 32)),
+			},
+			"data": hex.EncodeToString(common.LeftPadBytes(value.Bytes(), 32)),
+		}},
+	}
+
+	b := newTestBridge(node.URL)
+	latest, err := b.GetLatestBlockNumber()
+	if err != nil || latest != 100 {
+		t.Fatalf("get latest block number %v, err %v", latest, err)
+	}
+
+	tx, err := b.GetTransactionByHash(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := b.GetTransactionReceipt(txid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *receipt.Status != 1 || receipt.BlockNumber.ToInt().Uint64() != 90 {
+		t.Fatal("wrong receipt status or block number")
+	}
+	if _, err = b.GetTransactionByHash(testSignTxID); err == nil {
+		t.Fatal("get unknown tx should fail")
+	}
+
+	token := &tokens.TokenConfig{ContractAddress: testContract}
+	wantFrom, _ := ToBase58Address(sender)
+	check := func(swapInfo *tokens.TxSwapInfo) {
+		if swapInfo.From != wantFrom || swapInfo.To != testContract {
+			t.Fatalf("wrong swapout sender %v or receiver %v", swapInfo.From, swapInfo.To)
+		}
+		if swapInfo.Bind != bind.String() || swapInfo.Value.Cmp(value) != 0 {
+			t.Fatalf("wrong swapout bind %v or value %v", swapInfo.Bind, swapInfo.Value)
+		}
+	}
+
+	swapInfo := &tokens.TxSwapInfo{Hash: txid}
+	if err = b.verifySwapoutTxReceipt(swapInfo, tx, receipt, token); err != nil {
+		t.Fatal(err)
+	}
+	check(swapInfo)
+
+	swapInfo = &tokens.TxSwapInfo{Hash: txid}
+	if err = b.verifySwapoutRawTx(swapInfo, tx, token); err != nil {
+		t.Fatal(err)
+	}
+	check(swapInfo)
+}
+
+func TestIsTxExpired(t *testing.T) {
+	txHash := "6ec3fcb8d8a9eeaf8eef6c3dcfbb1f7a04a1d2d2b26f0d8a5cfd0b4aa2e2c6b1"
+	txTime := int64(1600000000)
+	expiredTime := (txTime + int64((txExpiration + expirationDelay).Seconds())) * 1000
+
+	node := newTestTronNode(t)
+	defer node.Close()
+	b := newTestBridge(node.URL)
+
+	testCases := []struct {
+		blockTime int64
+		onChain   bool
+		expired   bool
+	}{
+		{blockTime: expiredTime, onChain: false, expired: false},
+		{blockTime: expiredTime + 3000, onChain: false, expired: true},
+		{blockTime: expiredTime + 3000, onChain: true, expired: false},
+	}
+	for i, tc := range testCases {
+		node.setNowBlock(100, tc.blockTime)
+		node.lock.Lock()
+		delete(node.txs, txHash)
+		if tc.onChain {
+			node.txs[txHash] = map[string]interface{}{"txID": txHash}
+		}
+		node.lock.Unlock()
+		expired, err := b.IsTxExpired(txHash, txTime)
+		if err != nil {
+			t.Fatalf("test case %v failed, err %v", i, err)
+		}
+		if expired != tc.expired {
+			t.Fatalf("test case %v failed, expired %v, want %v", i, expired, tc.expired)
+		}
+	}
+}
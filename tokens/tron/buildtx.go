@@ -0,0 +1,282 @@
+package tron
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+)
+
+var (
+	defaultFeeLimit = int64(100000000) // 100 TRX
+	txExpiration    = 30 * time.Minute
+	// tolerance of clock difference between local and chain
+	expirationDelay = 5 * time.Minute
+
+	// keccak256 'transfer(address,uint256)'
+	transferFuncHash = common.FromHex("0xa9059cbb")
+
+	errEmptyIdentifier        = errors.New("build swaptx without identifier")
+	errNoSenderSpecified      = errors.New("build swaptx without specify sender")
+	errNonzeroValueSpecified  = errors.New("build swap tx with non-zero value")
+	errInvalidReceiverAddress = errors.New("invalid receiver address")
+	errWrongRefBlock          = errors.New("wrong reference block in extra args")
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	err = b.checkBuildTxArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	token := b.GetTokenConfig(args.PairID)
+	if token == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	from, err := DecodeAddress(args.From)
+	if err != nil {
+		return nil, errNoSenderSpecified
+	}
+
+	var (
+		contract Contract
+		memo     []byte
+	)
+	switch args.SwapType {
+	case tokens.SwapinType:
+		contract, err = b.buildSwapinContract(args, token, from)
+	case tokens.SwapoutType:
+		contract, memo, err = b.buildSwapoutContract(args, token, from)
+	default:
+		return nil, tokens.ErrUnknownSwapType
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	_, isContractCall := contract.(*TriggerSmartContract)
+	extra, err := b.setDefaults(args, isContractCall)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := newTransaction(contract, memo, extra)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.checkResource(args.From, tx)
+	if err != nil {
+		log.Warn("check resource failed", "account", args.From, "err", err)
+		return nil, err
+	}
+
+	log.Info("build raw tx", "pairID", args.PairID, "identifier", args.Identifier,
+		"swapID", args.SwapID, "swapType", args.SwapType,
+		"bind", args.Bind, "originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"from", args.From, "to", args.To, "value", args.Value, "contract", contract.ContractName(),
+		"refBlockBytes", extra.RefBlockBytes, "refBlockHash", extra.RefBlockHash,
+		"expiration", extra.Expiration, "feeLimit", extra.FeeLimit, "txid", tx.Hash().String())
+
+	return tx, nil
+}
+
+func (b *Bridge) checkBuildTxArgs(args *tokens.BuildTxArgs) error {
+	if args.Identifier == "" {
+		return errEmptyIdentifier
+	}
+	if args.From == "" {
+		return errNoSenderSpecified
+	}
+	if args.Value != nil && args.Value.Sign() != 0 {
+		return errNonzeroValueSpecified
+	}
+
+	switch args.SwapType {
+	case tokens.SwapinType:
+		if b.IsSrc {
+			return tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+	case tokens.SwapoutType:
+		if !b.IsSrc {
+			return tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+	default:
+		return tokens.ErrUnknownSwapType
+	}
+
+	return nil
+}
+
+// build contract calling `Swapin(bytes32 txhash, address account, uint256 amount)`
+func (b *Bridge) buildSwapinContract(args *tokens.BuildTxArgs, token *tokens.TokenConfig, from common.Address) (Contract, error) {
+	receiver, err := DecodeAddress(args.Bind)
+	if err != nil || receiver == (common.Address{}) {
+		log.Warn("swapin to wrong address", "receiver", args.Bind)
+		return nil, errInvalidReceiverAddress
+	}
+	contractAddr, err := DecodeAddress(token.ContractAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	swapValue := tokens.CalcSwappedValue(args.PairID, args.OriginValue, true)
+	args.SwapValue = swapValue      // swap value
+	args.To = token.ContractAddress // to
+
+	funcHash := eth.ExtCodeParts["SwapinFuncHash"]
+	txHash := common.HexToHash(args.SwapID)
+	input := eth.PackDataWithFuncHash(funcHash, txHash, receiver, swapValue)
+	args.Input = &input // input
+
+	return &TriggerSmartContract{
+		OwnerAddress:    from,
+		ContractAddress: contractAddr,
+		Data:            input,
+	}, nil
+}
+
+// build TRX transfer with unlock memo, or TRC20 transfer
+func (b *Bridge) buildSwapoutContract(args *tokens.BuildTxArgs, token *tokens.TokenConfig, from common.Address) (contract Contract, memo []byte, err error) {
+	receiver, err := DecodeAddress(args.Bind)
+	if err != nil || receiver == (common.Address{}) {
+		log.Warn("swapout to wrong address", "receiver", args.Bind)
+		return nil, nil, errInvalidReceiverAddress
+	}
+
+	swapValue := tokens.CalcSwappedValue(args.PairID, args.OriginValue, false)
+	args.SwapValue = swapValue // swap value
+
+	if token.ContractAddress == "" {
+		if !swapValue.IsInt64() {
+			return nil, nil, tokens.ErrWrongSwapValue
+		}
+		memo = []byte(tokens.UnlockMemoPrefix + args.SwapID)
+		args.To = EncodeAddress(receiver) // to
+		args.Value = swapValue            // value
+		return &TransferContract{
+			OwnerAddress: from,
+			ToAddress:    receiver,
+			Amount:       swapValue.Int64(),
+		}, memo, nil
+	}
+
+	contractAddr, err := DecodeAddress(token.ContractAddress)
+	if err != nil {
+		return nil, nil, err
+	}
+	input := eth.PackDataWithFuncHash(transferFuncHash, receiver, swapValue)
+	args.Input = &input             // input
+	args.To = token.ContractAddress // to
+
+	err = b.checkTrc20Balance(token.ContractAddress, args.From, swapValue)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &TriggerSmartContract{
+		OwnerAddress:    from,
+		ContractAddress: contractAddr,
+		Data:            input,
+	}, nil, nil
+}
+
+// setDefaults reference block, expiration and fee limit are set if not specified,
+// and then recorded in extra args to rebuild the same tx.
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs, isContractCall bool) (extra *tokens.TronExtraArgs, err error) {
+	if args.Value == nil {
+		args.Value = new(big.Int)
+	}
+	if args.Extra == nil || args.Extra.TronExtra == nil {
+		extra = &tokens.TronExtraArgs{}
+		args.Extra = &tokens.AllExtras{TronExtra: extra}
+	} else {
+		extra = args.Extra.TronExtra
+	}
+	if extra.RefBlockBytes == "" || extra.RefBlockHash == "" {
+		block, err := b.GetLatestBlock()
+		if err != nil {
+			return nil, err
+		}
+		blockID := common.FromHex(block.BlockID)
+		if len(blockID) != common.HashLength {
+			return nil, fmt.Errorf("wrong block id %v", block.BlockID)
+		}
+		extra.RefBlockBytes = common.Bytes2Hex(blockID[6:8])
+		extra.RefBlockHash = common.Bytes2Hex(blockID[8:16])
+		extra.Expiration = block.Timestamp() + txExpiration.Milliseconds()
+		extra.Timestamp = time.Now().UnixNano() / int64(time.Millisecond)
+	}
+	if isContractCall && extra.FeeLimit == 0 {
+		extra.FeeLimit = b.getDefaultFeeLimit(args.PairID)
+	}
+	return extra, nil
+}
+
+// IsTxExpired impl tokens.TxExpirer, as the reference block is the latest one
+// when building tx, the tx expires no later than txTime plus 'txExpiration'.
+// the latest block is got before querying tx, so that the tx is never packed
+// if it is not found at that time.
+func (b *Bridge) IsTxExpired(txHash string, txTime int64) (bool, error) {
+	block, err := b.GetLatestBlock()
+	if err != nil {
+		return false, err
+	}
+	expiration := (txTime + int64((txExpiration + expirationDelay).Seconds())) * 1000
+	if block.Timestamp() <= expiration {
+		return false, nil
+	}
+	_, err = b.GetTransactionByHash(txHash)
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, tokens.ErrTxNotFound):
+		return true, nil
+	default:
+		return false, err
+	}
+}
+
+// getDefaultFeeLimit 'DefaultGasLimit' of token config is the fee limit in SUN
+func (b *Bridge) getDefaultFeeLimit(pairID string) int64 {
+	tokenCfg := b.GetTokenConfig(pairID)
+	if tokenCfg != nil && tokenCfg.DefaultGasLimit > 0 {
+		return int64(tokenCfg.DefaultGasLimit)
+	}
+	return defaultFeeLimit
+}
+
+func newTransaction(contract Contract, memo []byte, extra *tokens.TronExtraArgs) (*Transaction, error) {
+	refBlockBytes := common.FromHex(extra.RefBlockBytes)
+	refBlockHash := common.FromHex(extra.RefBlockHash)
+	if len(refBlockBytes) != 2 || len(refBlockHash) != 8 {
+		return nil, errWrongRefBlock
+	}
+	tx := &Transaction{
+		RefBlockBytes: refBlockBytes,
+		RefBlockHash:  refBlockHash,
+		Expiration:    extra.Expiration,
+		Data:          memo,
+		Contract:      contract,
+		Timestamp:     extra.Timestamp,
+	}
+	if _, ok := contract.(*TriggerSmartContract); ok {
+		tx.FeeLimit = extra.FeeLimit
+	}
+	return tx, nil
+}
+
+func (b *Bridge) checkTrc20Balance(contract, account string, amount *big.Int) error {
+	balance, err := b.GetTrc20Balance(contract, account)
+	if err != nil {
+		log.Warn("get balance error", "token", contract, "account", account, "err", err)
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		return fmt.Errorf("not enough %v balance. %v < %v", contract, balance, amount)
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package tron
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const rpcTimeout = 10 // seconds
+
+var (
+	errEmptyURLs          = errors.New("empty URLs")
+	errWrongContractCount = errors.New("tx should have exactly one contract")
+	errEmptyResult        = errors.New("empty constant result")
+)
+
+type apiError struct {
+	Error string `json:"Error"`
+}
+
+// callAPI post json params to tron http api (eg. '/wallet/getnowblock')
+func callAPI(result interface{}, url, path string, params interface{}) error {
+	if params == nil {
+		params = struct{}{}
+	}
+	fullURL := strings.TrimSuffix(url, "/") + path
+	resp, err := client.HTTPPost(fullURL, params, nil, nil, rpcTimeout)
+	if err != nil {
+		log.Trace("call tron api error", "url", fullURL, "err", err)
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	if err != nil {
+		return fmt.Errorf("read body error: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
+	}
+	var apiErr apiError
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+		return fmt.Errorf("call %v error: %v", path, apiErr.Error)
+	}
+	err = json.Unmarshal(body, result)
+	if err != nil {
+		return fmt.Errorf("unmarshal result error: %w", err)
+	}
+	return nil
+}
+
+// callAPIOfGateway call tron http api of gateway urls until success
+func (b *Bridge) callAPIOfGateway(result interface{}, path string, params interface{}) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = callAPI(result, url, path, params)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// GetLatestBlockNumberOf call /wallet/getnowblock
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	var result Block
+	err := callAPI(&result, url, "/wallet/getnowblock", nil)
+	if err != nil {
+		return 0, err
+	}
+	return result.Number(), nil
+}
+
+// GetLatestBlockNumber get max latest block number of gateways
+func (b *Bridge) GetLatestBlockNumber() (maxHeight uint64, err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return 0, errEmptyURLs
+	}
+	for _, url := range urls {
+		height, errf := b.GetLatestBlockNumberOf(url)
+		if errf != nil {
+			err = errf
+			continue
+		}
+		if height > maxHeight {
+			maxHeight = height
+		}
+	}
+	if maxHeight > 0 {
+		tokens.CmpAndSetLatestBlockHeight(maxHeight, b.IsSrcEndpoint())
+		return maxHeight, nil
+	}
+	return 0, err
+}
+
+// GetLatestBlock call /wallet/getnowblock
+func (b *Bridge) GetLatestBlock() (*Block, error) {
+	var result Block
+	err := b.callAPIOfGateway(&result, "/wallet/getnowblock", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBlockByNumber call /wallet/getblockbynum
+func (b *Bridge) GetBlockByNumber(number uint64) (*Block, error) {
+	var result Block
+	err := b.callAPIOfGateway(&result, "/wallet/getblockbynum", map[string]interface{}{"num": number})
+	if err != nil {
+		return nil, err
+	}
+	if result.BlockID == "" {
+		return nil, fmt.Errorf("block %v not found", number)
+	}
+	return &result, nil
+}
+
+// GetBlockHash get block id of height
+func (b *Bridge) GetBlockHash(height uint64) (string, error) {
+	block, err := b.GetBlockByNumber(height)
+	if err != nil {
+		return "", err
+	}
+	return block.BlockID, nil
+}
+
+// GetTransactionByHash call /wallet/gettransactionbyid
+func (b *Bridge) GetTransactionByHash(txHash string) (*TransactionResult, error) {
+	var result TransactionResult
+	err := b.callAPIOfGateway(&result, "/wallet/gettransactionbyid", map[string]interface{}{"value": trimHexPrefix(txHash)})
+	if err != nil {
+		return nil, err
+	}
+	if result.TxID == "" {
+		return nil, tokens.ErrTxNotFound
+	}
+	return &result, nil
+}
+
+// GetTransactionInfo call /wallet/gettransactioninfobyid
+func (b *Bridge) GetTransactionInfo(txHash string) (*TransactionInfo, error) {
+	var result TransactionInfo
+	err := b.callAPIOfGateway(&result, "/wallet/gettransactioninfobyid", map[string]interface{}{"value": trimHexPrefix(txHash)})
+	if err != nil {
+		return nil, err
+	}
+	if result.ID == "" || result.BlockNumber == 0 {
+		return nil, tokens.ErrTxNotFound
+	}
+	return &result, nil
+}
+
+// GetTransactionReceipt get transaction info in form of ethereum receipt,
+// addresses of receipt and logs are converted to ethereum address.
+func (b *Bridge) GetTransactionReceipt(txHash string) (*types.RPCTxReceipt, error) {
+	info, err := b.GetTransactionInfo(txHash)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	return toReceipt(tx, info)
+}
+
+func toReceipt(tx *TransactionResult, info *TransactionInfo) (*types.RPCTxReceipt, error) {
+	_, param, err := tx.GetContract()
+	if err != nil {
+		return nil, err
+	}
+	status := hexutil.Uint64(0)
+	if info.IsSuccess() && (len(tx.Ret) == 0 || tx.IsSuccess()) {
+		status = 1
+	}
+	txHash := common.HexToHash(info.ID)
+	blockNumber := (*hexutil.Big)(new(big.Int).SetUint64(info.BlockNumber))
+	energyUsed := hexutil.Uint64(info.Receipt.EnergyUsageTotal)
+	receipt := &types.RPCTxReceipt{
+		TxHash:      &txHash,
+		BlockNumber: blockNumber,
+		Status:      &status,
+		GasUsed:     &energyUsed,
+		Logs:        make([]*types.RPCLog, 0, len(info.Log)),
+	}
+	if from, errf := DecodeAddress(param.OwnerAddress); errf == nil {
+		receipt.From = &from
+	}
+	recipient := param.ToAddress
+	if recipient == "" {
+		recipient = param.ContractAddress
+	}
+	if to, errf := DecodeAddress(recipient); errf == nil {
+		receipt.Recipient = &to
+	}
+	for _, txLog := range info.Log {
+		address := common.HexToAddress(txLog.Address)
+		topics := make([]common.Hash, len(txLog.Topics))
+		for i, topic := range txLog.Topics {
+			topics[i] = common.HexToHash(topic)
+		}
+		data := hexutil.Bytes(common.FromHex(txLog.Data))
+		receipt.Logs = append(receipt.Logs, &types.RPCLog{
+			Address: &address,
+			Topics:  topics,
+			Data:    &data,
+		})
+	}
+	return receipt, nil
+}
+
+// GetAccount call /wallet/getaccount
+func (b *Bridge) GetAccount(account string) (*Account, error) {
+	address, err := ToHexAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	var result Account
+	err = b.callAPIOfGateway(&result, "/wallet/getaccount", map[string]interface{}{"address": address})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetAccountResource call /wallet/getaccountresource
+func (b *Bridge) GetAccountResource(account string) (*AccountResource, error) {
+	address, err := ToHexAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	var result AccountResource
+	err = b.callAPIOfGateway(&result, "/wallet/getaccountresource", map[string]interface{}{"address": address})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetChainParameters call /wallet/getchainparameters
+func (b *Bridge) GetChainParameters() (*ChainParameters, error) {
+	var result ChainParameters
+	err := b.callAPIOfGateway(&result, "/wallet/getchainparameters", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBalance get TRX balance in SUN
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	result, err := b.GetAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(result.Balance), nil
+}
+
+// GetContractCode call /wallet/getcontract
+func (b *Bridge) GetContractCode(contract string) ([]byte, error) {
+	address, err := ToHexAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	var result SmartContract
+	err = b.callAPIOfGateway(&result, "/wallet/getcontract", map[string]interface{}{"value": address})
+	if err != nil {
+		return nil, err
+	}
+	return common.FromHex(result.Bytecode), nil
+}
+
+// IsContractAddress is contract address
+func (b *Bridge) IsContractAddress(address string) (bool, error) {
+	code, err := b.GetContractCode(address)
+	if err != nil {
+		return false, err
+	}
+	return len(code) > 0, nil
+}
+
+// CallContract call /wallet/triggerconstantcontract
+func (b *Bridge) CallContract(contract, funcSelector string, parameter []byte) ([]byte, error) {
+	address, err := ToHexAddress(contract)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]interface{}{
+		"owner_address":     address,
+		"contract_address":  address,
+		"function_selector": funcSelector,
+		"parameter":         common.Bytes2Hex(parameter),
+	}
+	var result ConstantResult
+	err = b.callAPIOfGateway(&result, "/wallet/triggerconstantcontract", params)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Result.Result {
+		return nil, fmt.Errorf("call %v of %v failed: %v", funcSelector, contract, string(common.FromHex(result.Result.Message)))
+	}
+	if len(result.ConstantResult) == 0 {
+		return nil, errEmptyResult
+	}
+	return common.FromHex(result.ConstantResult[0]), nil
+}
+
+// GetTrc20Decimals get TRC20 decimals
+func (b *Bridge) GetTrc20Decimals(contract string) (uint8, error) {
+	res, err := b.CallContract(contract, "decimals()", nil)
+	if err != nil {
+		return 0, err
+	}
+	return uint8(common.GetBigInt(res, 0, 32).Uint64()), nil
+}
+
+// GetTrc20Balance get TRC20 balance
+func (b *Bridge) GetTrc20Balance(contract, account string) (*big.Int, error) {
+	addr, err := DecodeAddress(account)
+	if err != nil {
+		return nil, err
+	}
+	res, err := b.CallContract(contract, "balanceOf(address)", common.LeftPadBytes(addr.Bytes(), 32))
+	if err != nil {
+		return nil, err
+	}
+	return common.GetBigInt(res, 0, 32), nil
+}
+
+// GetTokenBalance impl, only support TRC20
+func (b *Bridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
+	switch strings.ToUpper(tokenType) {
+	case "TRC20", "ERC20":
+		return b.GetTrc20Balance(tokenAddress, accountAddress)
+	default:
+		return nil, fmt.Errorf("[%v] can not get token balance of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
+	}
+}
+
+// GetTokenSupply impl, only support TRC20
+func (b *Bridge) GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error) {
+	switch strings.ToUpper(tokenType) {
+	case "TRC20", "ERC20":
+		res, err := b.CallContract(tokenAddress, "totalSupply()", nil)
+		if err != nil {
+			return nil, err
+		}
+		return common.GetBigInt(res, 0, 32), nil
+	default:
+		return nil, fmt.Errorf("[%v] can not get token supply of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
+	}
+}
+
+// BroadcastTransaction call /wallet/broadcasthex on all gateways
+func (b *Bridge) BroadcastTransaction(tx *Transaction) (txHash string, err error) {
+	gateway := b.GatewayConfig
+	urls := make([]string, 0, len(gateway.APIAddress)+len(gateway.APIAddressExt))
+	urls = append(urls, gateway.APIAddress...)
+	urls = append(urls, gateway.APIAddressExt...)
+	if len(urls) == 0 {
+		return "", errEmptyURLs
+	}
+	params := map[string]interface{}{"transaction": common.Bytes2Hex(tx.Marshal())}
+	success := false
+	for _, url := range urls {
+		var result BroadcastResult
+		errf := callAPI(&result, url, "/wallet/broadcasthex", params)
+		switch {
+		case errf != nil:
+			err = errf
+		case !result.Result:
+			err = fmt.Errorf("broadcast tx failed, code %v, message %v", result.Code, string(common.FromHex(result.Message)))
+		default:
+			success = true
+			txHash = result.TxID
+		}
+		if err != nil {
+			log.Trace("broadcast tron tx failed", "url", url, "err", err)
+		}
+	}
+	if success {
+		return txHash, nil
+	}
+	return "", err
+}
+
+func trimHexPrefix(txHash string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(txHash, "0x"), "0X")
+}
@@ -0,0 +1,13 @@
+package tron
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+)
+
+func (b *Bridge) processTransaction(txid string) {
+	if b.IsSrc {
+		tools.ProcessSwapin(txid, b.verifySwapinTx)
+	} else {
+		tools.ProcessSwapout(txid, b.verifySwapoutTx)
+	}
+}
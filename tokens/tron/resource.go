@@ -0,0 +1,79 @@
+package tron
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+const (
+	defaultTransactionFee = int64(1000) // SUN per bandwidth (byte)
+	defaultEnergyFee      = int64(420)  // SUN per energy
+
+	// signature and result (at most 64 bytes) are also charged for bandwidth
+	extraBandwidthOfTx = int64(65 + 64)
+)
+
+// getResourcePrices get prices of bandwidth and energy in SUN
+func (b *Bridge) getResourcePrices() (transactionFee, energyFee int64) {
+	transactionFee, energyFee = defaultTransactionFee, defaultEnergyFee
+	params, err := b.GetChainParameters()
+	if err != nil {
+		log.Warn("get chain parameters failed, use default resource prices", "err", err)
+		return transactionFee, energyFee
+	}
+	if value, exist := params.Get("getTransactionFee"); exist {
+		transactionFee = value
+	}
+	if value, exist := params.Get("getEnergyFee"); exist {
+		energyFee = value
+	}
+	return transactionFee, energyFee
+}
+
+// checkResource check TRX balance of sender is enough for value of tx,
+// and for burning bandwidth and energy which are not covered by its resources.
+// energy burnt is at most the fee limit of tx.
+func (b *Bridge) checkResource(account string, tx *Transaction) error {
+	resource, err := b.GetAccountResource(account)
+	if err != nil {
+		return err
+	}
+	balance, err := b.GetBalance(account)
+	if err != nil {
+		return err
+	}
+	transactionFee, energyFee := b.getResourcePrices()
+
+	var value int64
+	switch contract := tx.Contract.(type) {
+	case *TransferContract:
+		value = contract.Amount
+	case *TriggerSmartContract:
+		value = contract.CallValue
+	}
+
+	var bandwidthFee int64
+	bandwidth := int64(len(tx.Marshal())) + extraBandwidthOfTx
+	if resource.AvailableBandwidth() < bandwidth {
+		bandwidthFee = bandwidth * transactionFee
+	}
+
+	var burnEnergyFee int64
+	if tx.FeeLimit > 0 {
+		burnEnergyFee = tx.FeeLimit - resource.AvailableEnergy()*energyFee
+		if burnEnergyFee < 0 {
+			burnEnergyFee = 0
+		}
+	}
+
+	needValue := value + bandwidthFee + burnEnergyFee
+	if balance.Int64() < needValue {
+		return fmt.Errorf("not enough TRX balance for value and resources. %v < %v (value %v, bandwidth fee %v, energy fee %v)",
+			balance, needValue, value, bandwidthFee, burnEnergyFee)
+	}
+	log.Debug("check resource success", "account", account, "balance", balance, "value", value,
+		"bandwidth", bandwidth, "availableBandwidth", resource.AvailableBandwidth(), "bandwidthFee", bandwidthFee,
+		"feeLimit", tx.FeeLimit, "availableEnergy", resource.AvailableEnergy(), "energyFee", burnEnergyFee)
+	return nil
+}
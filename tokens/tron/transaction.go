@@ -0,0 +1,117 @@
+package tron
+
+import (
+	"crypto/sha256"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/internal/protobuf"
+)
+
+// contract types in tron protocol (core/Tron.proto)
+const (
+	TransferContractType     = 1
+	TriggerSmartContractType = 31
+
+	contractTypeURLPrefix = "type.googleapis.com/protocol."
+)
+
+// Contract contract of tron transaction
+type Contract interface {
+	ContractType() uint64
+	ContractName() string
+	Marshal() []byte
+}
+
+// TransferContract transfer TRX
+type TransferContract struct {
+	OwnerAddress common.Address
+	ToAddress    common.Address
+	Amount       int64 // SUN
+}
+
+// ContractType impl Contract
+func (c *TransferContract) ContractType() uint64 {
+	return TransferContractType
+}
+
+// ContractName impl Contract
+func (c *TransferContract) ContractName() string {
+	return "TransferContract"
+}
+
+// Marshal impl Contract
+func (c *TransferContract) Marshal() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, toAddressBytes(c.OwnerAddress))
+	buf = protobuf.AppendBytesField(buf, 2, toAddressBytes(c.ToAddress))
+	buf = protobuf.AppendVarintField(buf, 3, uint64(c.Amount))
+	return buf
+}
+
+// TriggerSmartContract call smart contract (eg. TRC20 transfer)
+type TriggerSmartContract struct {
+	OwnerAddress    common.Address
+	ContractAddress common.Address
+	CallValue       int64 // SUN
+	Data            []byte
+}
+
+// ContractType impl Contract
+func (c *TriggerSmartContract) ContractType() uint64 {
+	return TriggerSmartContractType
+}
+
+// ContractName impl Contract
+func (c *TriggerSmartContract) ContractName() string {
+	return "TriggerSmartContract"
+}
+
+// Marshal impl Contract
+func (c *TriggerSmartContract) Marshal() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, toAddressBytes(c.OwnerAddress))
+	buf = protobuf.AppendBytesField(buf, 2, toAddressBytes(c.ContractAddress))
+	buf = protobuf.AppendVarintField(buf, 3, uint64(c.CallValue))
+	buf = protobuf.AppendBytesField(buf, 4, c.Data)
+	return buf
+}
+
+// Transaction tron transaction with one contract
+type Transaction struct {
+	RefBlockBytes []byte
+	RefBlockHash  []byte
+	Expiration    int64 // milliseconds
+	Data          []byte
+	Contract      Contract
+	Timestamp     int64 // milliseconds
+	FeeLimit      int64 // SUN
+	Signature     []byte
+}
+
+// RawData protobuf encoding of 'Transaction.raw',
+// fields are in ascending order and default values are omitted,
+// which is the same as the serialization of java-tron.
+func (tx *Transaction) RawData() (buf []byte) {
+	var contract []byte
+	contract = protobuf.AppendVarintField(contract, 1, tx.Contract.ContractType())
+	contract = protobuf.AppendBytesField(contract, 2, protobuf.MarshalAny(contractTypeURLPrefix+tx.Contract.ContractName(), tx.Contract.Marshal()))
+
+	buf = protobuf.AppendBytesField(buf, 1, tx.RefBlockBytes)
+	buf = protobuf.AppendBytesField(buf, 4, tx.RefBlockHash)
+	buf = protobuf.AppendVarintField(buf, 8, uint64(tx.Expiration))
+	buf = protobuf.AppendBytesField(buf, 10, tx.Data)
+	buf = protobuf.AppendBytesField(buf, 11, contract)
+	buf = protobuf.AppendVarintField(buf, 14, uint64(tx.Timestamp))
+	buf = protobuf.AppendVarintField(buf, 18, uint64(tx.FeeLimit))
+	return buf
+}
+
+// Hash transaction id, sha256 of raw data
+func (tx *Transaction) Hash() common.Hash {
+	return sha256.Sum256(tx.RawData())
+}
+
+// Marshal protobuf encoding of signed 'Transaction'
+func (tx *Transaction) Marshal() (buf []byte) {
+	buf = protobuf.AppendBytesField(buf, 1, tx.RawData())
+	buf = protobuf.AppendBytesField(buf, 2, tx.Signature)
+	return buf
+}
@@ -0,0 +1,179 @@
+package tron
+
+import (
+	"encoding/json"
+)
+
+// Block result of '/wallet/getnowblock' and '/wallet/getblockbynum'
+type Block struct {
+	BlockID     string `json:"blockID"`
+	BlockHeader struct {
+		RawData struct {
+			Number     uint64 `json:"number"`
+			Timestamp  int64  `json:"timestamp"`
+			ParentHash string `json:"parentHash"`
+		} `json:"raw_data"`
+	} `json:"block_header"`
+	Transactions []*TransactionResult `json:"transactions"`
+}
+
+// Number block number
+func (b *Block) Number() uint64 {
+	return b.BlockHeader.RawData.Number
+}
+
+// Timestamp block timestamp in milliseconds
+func (b *Block) Timestamp() int64 {
+	return b.BlockHeader.RawData.Timestamp
+}
+
+// TransactionResult result of '/wallet/gettransactionbyid'
+type TransactionResult struct {
+	TxID    string `json:"txID"`
+	RawData struct {
+		Contract []struct {
+			Parameter struct {
+				Value   json.RawMessage `json:"value"`
+				TypeURL string          `json:"type_url"`
+			} `json:"parameter"`
+			Type string `json:"type"`
+		} `json:"contract"`
+		RefBlockBytes string `json:"ref_block_bytes"`
+		RefBlockHash  string `json:"ref_block_hash"`
+		Expiration    int64  `json:"expiration"`
+		Timestamp     int64  `json:"timestamp"`
+		FeeLimit      int64  `json:"fee_limit"`
+		Data          string `json:"data"`
+	} `json:"raw_data"`
+	Ret []struct {
+		ContractRet string `json:"contractRet"`
+	} `json:"ret"`
+	Signature []string `json:"signature"`
+}
+
+// ContractParam parameter value of transfer and trigger smart contract,
+// addresses are hex encoded with '41' prefix.
+type ContractParam struct {
+	OwnerAddress    string `json:"owner_address"`
+	ToAddress       string `json:"to_address"`
+	Amount          int64  `json:"amount"`
+	ContractAddress string `json:"contract_address"`
+	CallValue       int64  `json:"call_value"`
+	Data            string `json:"data"`
+}
+
+// GetContract get type and parameter of the only contract
+func (tx *TransactionResult) GetContract() (contractType string, param *ContractParam, err error) {
+	if len(tx.RawData.Contract) != 1 {
+		return "", nil, errWrongContractCount
+	}
+	contract := tx.RawData.Contract[0]
+	param = &ContractParam{}
+	err = json.Unmarshal(contract.Parameter.Value, param)
+	if err != nil {
+		return "", nil, err
+	}
+	return contract.Type, param, nil
+}
+
+// IsSuccess contract execution is successful or not
+func (tx *TransactionResult) IsSuccess() bool {
+	return len(tx.Ret) > 0 && tx.Ret[0].ContractRet == "SUCCESS"
+}
+
+// TransactionInfo result of '/wallet/gettransactioninfobyid'
+type TransactionInfo struct {
+	ID             string `json:"id"`
+	Fee            int64  `json:"fee"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	BlockTimeStamp int64  `json:"blockTimeStamp"`
+	Receipt        struct {
+		EnergyUsageTotal int64  `json:"energy_usage_total"`
+		NetUsage         int64  `json:"net_usage"`
+		Result           string `json:"result"`
+	} `json:"receipt"`
+	Log    []*TransactionLog `json:"log"`
+	Result string            `json:"result"` // 'FAILED' if failed
+}
+
+// TransactionLog log of transaction info, address is hex encoded without '41' prefix
+type TransactionLog struct {
+	Address string   `json:"address"`
+	Topics  []string `json:"topics"`
+	Data    string   `json:"data"`
+}
+
+// IsSuccess transaction is successful or not
+func (info *TransactionInfo) IsSuccess() bool {
+	if info.Result == "FAILED" {
+		return false
+	}
+	return info.Receipt.Result == "" || info.Receipt.Result == "SUCCESS"
+}
+
+// Account result of '/wallet/getaccount'
+type Account struct {
+	Address string `json:"address"`
+	Balance int64  `json:"balance"`
+}
+
+// AccountResource result of '/wallet/getaccountresource'
+type AccountResource struct {
+	FreeNetUsed  int64 `json:"freeNetUsed"`
+	FreeNetLimit int64 `json:"freeNetLimit"`
+	NetUsed      int64 `json:"NetUsed"`
+	NetLimit     int64 `json:"NetLimit"`
+	EnergyUsed   int64 `json:"EnergyUsed"`
+	EnergyLimit  int64 `json:"EnergyLimit"`
+}
+
+// AvailableBandwidth available bandwidth (free and staked)
+func (r *AccountResource) AvailableBandwidth() int64 {
+	return r.FreeNetLimit - r.FreeNetUsed + r.NetLimit - r.NetUsed
+}
+
+// AvailableEnergy available energy (staked)
+func (r *AccountResource) AvailableEnergy() int64 {
+	return r.EnergyLimit - r.EnergyUsed
+}
+
+// ChainParameters result of '/wallet/getchainparameters'
+type ChainParameters struct {
+	ChainParameter []struct {
+		Key   string `json:"key"`
+		Value int64  `json:"value"`
+	} `json:"chainParameter"`
+}
+
+// Get get chain parameter value by key
+func (p *ChainParameters) Get(key string) (int64, bool) {
+	for _, param := range p.ChainParameter {
+		if param.Key == key {
+			return param.Value, true
+		}
+	}
+	return 0, false
+}
+
+// ConstantResult result of '/wallet/triggerconstantcontract'
+type ConstantResult struct {
+	Result struct {
+		Result  bool   `json:"result"`
+		Message string `json:"message"`
+	} `json:"result"`
+	ConstantResult []string `json:"constant_result"`
+}
+
+// SmartContract result of '/wallet/getcontract'
+type SmartContract struct {
+	Bytecode        string `json:"bytecode"`
+	ContractAddress string `json:"contract_address"`
+}
+
+// BroadcastResult result of '/wallet/broadcasthex'
+type BroadcastResult struct {
+	Result  bool   `json:"result"`
+	TxID    string `json:"txid"`
+	Code    string `json:"code"`
+	Message string `json:"message"` // hex encoded
+}
@@ -0,0 +1,179 @@
+package tron
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+// verifySwapoutTxWithPairID verify swapout with PairID
+func (b *Bridge) verifySwapoutTxWithPairID(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	swapInfo := &tokens.TxSwapInfo{}
+	swapInfo.PairID = pairID // PairID
+	swapInfo.Hash = txHash   // Hash
+
+	token := b.GetTokenConfig(pairID)
+	if token == nil {
+		return swapInfo, tokens.ErrUnknownPairID
+	}
+
+	if token.IsSwapDisabled() {
+		return swapInfo, tokens.ErrSwapIsClosed
+	}
+
+	receipt, err := b.getReceipt(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug("[verifySwapoutWithPairID] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		return swapInfo, tokens.ErrTxNotFound
+	}
+
+	if !allowUnstable || receipt != nil {
+		err = b.verifySwapoutTxReceipt(swapInfo, tx, receipt, token)
+	} else {
+		err = b.verifySwapoutRawTx(swapInfo, tx, token)
+	}
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapoutInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify swapout stable pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+	}
+
+	return swapInfo, nil
+}
+
+func (b *Bridge) verifySwapoutTxReceipt(swapInfo *tokens.TxSwapInfo, tx *TransactionResult, receipt *types.RPCTxReceipt, token *tokens.TokenConfig) error {
+	txRecipient := getTxRecipient(tx)
+	if txRecipient == "" || receipt.From == nil {
+		return tokens.ErrTxWithWrongContract
+	}
+	swapInfo.TxTo = txRecipient                  // TxTo
+	swapInfo.To = txRecipient                    // To
+	swapInfo.From = EncodeAddress(*receipt.From) // From
+
+	contractAddr, _ := ToEthAddress(token.ContractAddress)
+	bindAddress, value, err := eth.ParseSwapoutTxLogs(receipt.Logs, contractAddr)
+	if err != nil {
+		if !errors.Is(err, tokens.ErrSwapoutLogNotFound) {
+			log.Debug(b.ChainConfig.BlockChain+" ParseSwapoutTxLogs fail", "tx", swapInfo.Hash, "err", err)
+		}
+		return err
+	}
+	swapInfo.Bind = getSwapoutBindAddress(bindAddress, swapInfo.From) // Bind
+	swapInfo.Value = value                                            // Value
+	return nil
+}
+
+func (b *Bridge) verifySwapoutRawTx(swapInfo *tokens.TxSwapInfo, tx *TransactionResult, token *tokens.TokenConfig) error {
+	contractType, param, err := tx.GetContract()
+	if err != nil || contractType != triggerSmartContractName {
+		return tokens.ErrTxWithWrongContract
+	}
+	txRecipient := getTxRecipient(tx)
+	if !isSameAddress(txRecipient, token.ContractAddress) {
+		return tokens.ErrTxWithWrongContract
+	}
+	from, err := ToBase58Address(param.OwnerAddress)
+	if err != nil {
+		return tokens.ErrTxWithWrongSender
+	}
+
+	swapInfo.TxTo = txRecipient // TxTo
+	swapInfo.To = txRecipient   // To
+	swapInfo.From = from        // From
+
+	input := common.FromHex(param.Data)
+	bindAddress, value, err := eth.ParseSwapoutTxInput(&input)
+	if err != nil {
+		if !errors.Is(err, tokens.ErrTxFuncHashMismatch) {
+			log.Debug(b.ChainConfig.BlockChain+" ParseSwapoutTxInput fail", "tx", swapInfo.Hash, "err", err)
+		}
+		return err
+	}
+	swapInfo.Bind = getSwapoutBindAddress(bindAddress, swapInfo.From) // Bind
+	swapInfo.Value = value                                            // Value
+	return nil
+}
+
+// verifySwapoutTx verify swapout (in scan job)
+func (b *Bridge) verifySwapoutTx(txHash string, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug("[verifySwapout] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		addSwapInfoConsiderError(nil, tokens.ErrTxNotFound, &swapInfos, &errs)
+		return swapInfos, errs
+	}
+	txRecipient := getTxRecipient(tx)
+	tokenCfgs, pairIDs := tokens.FindTokenConfig(txRecipient, false)
+	if len(pairIDs) == 0 {
+		addSwapInfoConsiderError(nil, tokens.ErrTxWithWrongContract, &swapInfos, &errs)
+		return swapInfos, errs
+	}
+
+	for i, pairID := range pairIDs {
+		token := tokenCfgs[i]
+
+		swapInfo := &tokens.TxSwapInfo{}
+		swapInfo.Hash = txHash   // Hash
+		swapInfo.PairID = pairID // PairID
+
+		receipt, err := b.getReceipt(swapInfo, allowUnstable)
+		if err != nil {
+			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+			continue
+		}
+		if receipt != nil {
+			err = b.verifySwapoutTxReceipt(swapInfo, tx, receipt, token)
+		} else {
+			err = b.verifySwapoutRawTx(swapInfo, tx, token)
+		}
+		if err != nil {
+			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+			continue
+		}
+
+		err = b.checkSwapoutInfo(swapInfo)
+		addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+
+		if !allowUnstable && err == nil {
+			log.Debug("verify swapout stable pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+		}
+	}
+
+	return swapInfos, errs
+}
+
+// getSwapoutBindAddress bind address defaults to the ethereum address of sender
+func getSwapoutBindAddress(bindAddress, from string) string {
+	if bindAddress != "" {
+		return bindAddress
+	}
+	bind, _ := ToEthAddress(from)
+	return bind
+}
+
+func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.TxSwapInfo) error {
+	if !tokens.CheckSwapValue(swapInfo.PairID, swapInfo.Value, b.IsSrc) {
+		return tokens.ErrTxWithWrongValue
+	}
+	if !tokens.SrcBridge.IsValidAddress(swapInfo.Bind) {
+		log.Debug("wrong bind address in swapout", "bind", swapInfo.Bind)
+		return tokens.ErrTxWithWrongMemo
+	}
+	return nil
+}
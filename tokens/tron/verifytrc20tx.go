@@ -0,0 +1,116 @@
+package tron
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+// verifyTrc20SwapinTx verify trc20 swapin with pairID
+func (b *Bridge) verifyTrc20SwapinTx(pairID, txHash string, allowUnstable bool, token *tokens.TokenConfig) (*tokens.TxSwapInfo, error) {
+	swapInfo := &tokens.TxSwapInfo{}
+	swapInfo.PairID = pairID // PairID
+	swapInfo.Hash = txHash   // Hash
+
+	receipt, err := b.getReceipt(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if receipt == nil && token.AllowSwapinFromContract {
+		return swapInfo, tokens.ErrTxNotFound
+	}
+
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug("[verifyTrc20Swapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		return swapInfo, tokens.ErrTxNotFound
+	}
+
+	if !allowUnstable || receipt != nil {
+		err = b.verifyTrc20SwapinTxReceipt(swapInfo, tx, receipt, token)
+	} else {
+		err = b.verifyTrc20SwapinRawTx(swapInfo, tx, token)
+	}
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapinInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify trc20 swapin pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+	}
+	return swapInfo, nil
+}
+
+func (b *Bridge) verifyTrc20SwapinTxReceipt(swapInfo *tokens.TxSwapInfo, tx *TransactionResult, receipt *types.RPCTxReceipt, token *tokens.TokenConfig) error {
+	txRecipient := getTxRecipient(tx)
+	if !isSameAddress(txRecipient, token.ContractAddress) && !token.AllowSwapinFromContract {
+		return tokens.ErrTxWithWrongContract
+	}
+	if receipt.From == nil {
+		return tokens.ErrTxWithWrongSender
+	}
+	from := EncodeAddress(*receipt.From)
+	swapInfo.TxTo = txRecipient // TxTo
+	swapInfo.From = from        // From
+
+	contractAddr, _ := ToEthAddress(token.ContractAddress)
+	depositAddr, _ := ToEthAddress(token.DepositAddress)
+	logFrom, _, value, err := eth.ParseErc20SwapinTxLogs(receipt.Logs, contractAddr, depositAddr)
+	if err != nil {
+		if !errors.Is(err, tokens.ErrTxWithWrongReceiver) {
+			log.Debug(b.ChainConfig.BlockChain+" ParseErc20SwapinTxLogs failed", "tx", swapInfo.Hash, "err", err)
+		}
+		return err
+	}
+	// token owner may be different with sender if transfer is called by contract
+	owner := EncodeAddress(common.HexToAddress(logFrom))
+	swapInfo.To = token.DepositAddress        // To
+	swapInfo.Value = value                    // Value
+	swapInfo.Bind = getBindAddress(tx, owner) // Bind
+	return nil
+}
+
+func (b *Bridge) verifyTrc20SwapinRawTx(swapInfo *tokens.TxSwapInfo, tx *TransactionResult, token *tokens.TokenConfig) error {
+	contractType, param, err := tx.GetContract()
+	if err != nil || contractType != triggerSmartContractName {
+		return tokens.ErrTxWithWrongContract
+	}
+	txRecipient := getTxRecipient(tx)
+	if !isSameAddress(txRecipient, token.ContractAddress) {
+		return tokens.ErrTxWithWrongContract
+	}
+	from, err := ToBase58Address(param.OwnerAddress)
+	if err != nil {
+		return tokens.ErrTxWithWrongSender
+	}
+	swapInfo.TxTo = txRecipient // TxTo
+	swapInfo.From = from        // From
+
+	depositAddr, _ := ToEthAddress(token.DepositAddress)
+	input := common.FromHex(param.Data)
+	inputFrom, _, value, err := eth.ParseErc20SwapinTxInput(&input, depositAddr)
+	if err != nil {
+		if !errors.Is(err, tokens.ErrTxWithWrongReceiver) {
+			log.Debug(b.ChainConfig.BlockChain+" ParseErc20SwapinTxInput fail", "tx", swapInfo.Hash, "err", err)
+		}
+		return err
+	}
+	owner := from
+	if inputFrom != "" { // transferFrom
+		owner = EncodeAddress(common.HexToAddress(inputFrom))
+	}
+	swapInfo.To = token.DepositAddress        // To
+	swapInfo.Value = value                    // Value
+	swapInfo.Bind = getBindAddress(tx, owner) // Bind
+	return nil
+}
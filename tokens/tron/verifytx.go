@@ -0,0 +1,300 @@
+package tron
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+// contract types in json result of tron api
+const (
+	transferContractName     = "TransferContract"
+	triggerSmartContractName = "TriggerSmartContract"
+)
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	return b.GetTransactionByHash(txHash)
+}
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
+	var txStatus tokens.TxStatus
+	receipt, err := b.GetTransactionReceipt(txHash)
+	if err != nil {
+		log.Trace("GetTransactionReceipt fail", "hash", txHash, "err", err)
+		return &txStatus
+	}
+	txStatus.BlockHeight = receipt.BlockNumber.ToInt().Uint64()
+	if block, err := b.GetBlockByNumber(txStatus.BlockHeight); err == nil {
+		txStatus.BlockHash = block.BlockID
+		txStatus.BlockTime = uint64(block.Timestamp() / 1000)
+	}
+	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > txStatus.BlockHeight {
+		txStatus.Confirmations = latest - txStatus.BlockHeight
+	}
+	txStatus.Receipt = receipt
+	return &txStatus
+}
+
+// VerifyMsgHash verify msg hash
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*Transaction)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	msgHash := msgHashes[0]
+	sigHash := tx.Hash()
+	if sigHash.String() != msgHash {
+		log.Trace("message hash mismatch", "want", msgHash, "have", sigHash.String())
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+// VerifyTransaction impl
+func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	if !b.IsSrc {
+		return b.verifySwapoutTxWithPairID(pairID, txHash, allowUnstable)
+	}
+	return b.verifySwapinTxWithPairID(pairID, txHash, allowUnstable)
+}
+
+func (b *Bridge) verifySwapinTxWithPairID(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	swapInfo := &tokens.TxSwapInfo{}
+	swapInfo.PairID = pairID // PairID
+	swapInfo.Hash = txHash   // Hash
+
+	token := b.GetTokenConfig(pairID)
+	if token == nil {
+		return swapInfo, tokens.ErrUnknownPairID
+	}
+
+	if token.IsSwapDisabled() {
+		return swapInfo, tokens.ErrSwapIsClosed
+	}
+
+	if token.IsErc20() {
+		return b.verifyTrc20SwapinTx(pairID, txHash, allowUnstable, token)
+	}
+
+	_, err := b.getReceipt(swapInfo, allowUnstable)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		return swapInfo, tokens.ErrTxNotFound
+	}
+
+	err = b.verifyTransferTx(swapInfo, tx, token)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapinInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify swapin stable pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+	}
+	return swapInfo, nil
+}
+
+// verifyTransferTx verify TRX transfer to deposit address
+func (b *Bridge) verifyTransferTx(swapInfo *tokens.TxSwapInfo, tx *TransactionResult, token *tokens.TokenConfig) error {
+	contractType, param, err := tx.GetContract()
+	if err != nil || contractType != transferContractName {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	if !isSameAddress(param.ToAddress, token.DepositAddress) {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	if len(tx.Ret) > 0 && !tx.IsSuccess() {
+		return tokens.ErrTxWithWrongReceipt
+	}
+	from, err := ToBase58Address(param.OwnerAddress)
+	if err != nil {
+		return tokens.ErrTxWithWrongSender
+	}
+
+	swapInfo.TxTo = token.DepositAddress                 // TxTo
+	swapInfo.To = token.DepositAddress                   // To
+	swapInfo.From = from                                 // From
+	swapInfo.Bind = getBindAddress(tx, from)             // Bind
+	swapInfo.Value = new(big.Int).SetInt64(param.Amount) // Value
+	return nil
+}
+
+// verifySwapinTx verify swapin (in scan job)
+func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug(b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		addSwapInfoConsiderError(nil, tokens.ErrTxNotFound, &swapInfos, &errs)
+		return swapInfos, errs
+	}
+	txRecipient := getTxRecipient(tx)
+	tokenCfgs, pairIDs := tokens.FindTokenConfig(txRecipient, true)
+	if len(pairIDs) == 0 {
+		addSwapInfoConsiderError(nil, tokens.ErrTxWithWrongReceiver, &swapInfos, &errs)
+		return swapInfos, errs
+	}
+
+	for i, pairID := range pairIDs {
+		token := tokenCfgs[i]
+
+		if token.IsErc20() {
+			swapInfo, errf := b.verifyTrc20SwapinTx(pairID, txHash, allowUnstable, token)
+			addSwapInfoConsiderError(swapInfo, errf, &swapInfos, &errs)
+			continue
+		}
+
+		swapInfo := &tokens.TxSwapInfo{}
+		swapInfo.Hash = txHash   // Hash
+		swapInfo.PairID = pairID // PairID
+
+		err = b.verifyTransferTx(swapInfo, tx, token)
+		if err != nil {
+			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+			continue
+		}
+
+		if !allowUnstable {
+			_, err = b.getStableReceipt(swapInfo)
+			if err != nil {
+				addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+				continue
+			}
+		}
+
+		err = b.checkSwapinInfo(swapInfo)
+		addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+
+		if !allowUnstable && err == nil {
+			log.Debug("verify swapin stable pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+		}
+	}
+
+	return swapInfos, errs
+}
+
+// getTxRecipient get base58check address of TRX receiver or called contract
+func getTxRecipient(tx *TransactionResult) string {
+	_, param, err := tx.GetContract()
+	if err != nil {
+		return ""
+	}
+	recipient := param.ToAddress
+	if recipient == "" {
+		recipient = param.ContractAddress
+	}
+	address, err := ToBase58Address(recipient)
+	if err != nil {
+		return ""
+	}
+	return address
+}
+
+// getBindAddress bind address is specified by memo with prefix 'SWAPTO:' in data field,
+// otherwise it is the ethereum address of sender (which has the same key).
+func getBindAddress(tx *TransactionResult, from string) string {
+	memo := string(common.FromHex(tx.RawData.Data))
+	if strings.HasPrefix(memo, tokens.LockMemoPrefix) {
+		if bind := strings.TrimSpace(memo[len(tokens.LockMemoPrefix):]); bind != "" {
+			return bind
+		}
+	}
+	bind, _ := ToEthAddress(from)
+	return bind
+}
+
+func addSwapInfoConsiderError(swapInfo *tokens.TxSwapInfo, err error, swapInfos *[]*tokens.TxSwapInfo, errs *[]error) {
+	if !tokens.ShouldRegisterSwapForError(err) {
+		return
+	}
+	*swapInfos = append(*swapInfos, swapInfo)
+	*errs = append(*errs, err)
+}
+
+func (b *Bridge) getReceipt(swapInfo *tokens.TxSwapInfo, allowUnstable bool) (*types.RPCTxReceipt, error) {
+	if !allowUnstable {
+		return b.getStableReceipt(swapInfo)
+	}
+	receipt, _ := b.GetTransactionReceipt(swapInfo.Hash)
+	if receipt == nil {
+		return nil, nil // if receipt not found, then verify raw tx
+	}
+	swapInfo.Height = receipt.BlockNumber.ToInt().Uint64() // Height
+	if *receipt.Status != 1 {
+		return nil, tokens.ErrTxWithWrongReceipt
+	}
+	return receipt, nil
+}
+
+func (b *Bridge) getStableReceipt(swapInfo *tokens.TxSwapInfo) (*types.RPCTxReceipt, error) {
+	txStatus := b.GetTransactionStatus(swapInfo.Hash)
+	swapInfo.Height = txStatus.BlockHeight  // Height
+	swapInfo.Timestamp = txStatus.BlockTime // Timestamp
+	receipt, ok := txStatus.Receipt.(*types.RPCTxReceipt)
+	if !ok || receipt == nil {
+		return nil, tokens.ErrTxNotStable
+	}
+	if *receipt.Status != 1 {
+		return nil, tokens.ErrTxWithWrongReceipt
+	}
+	if txStatus.BlockHeight == 0 ||
+		txStatus.Confirmations < *b.GetChainConfig().Confirmations {
+		return nil, tokens.ErrTxNotStable
+	}
+	return receipt, nil
+}
+
+func (b *Bridge) checkSwapinInfo(swapInfo *tokens.TxSwapInfo) error {
+	if isSameAddress(swapInfo.Bind, swapInfo.To) {
+		return tokens.ErrTxWithWrongSender
+	}
+	if !tokens.CheckSwapValue(swapInfo.PairID, swapInfo.Value, b.IsSrc) {
+		return tokens.ErrTxWithWrongValue
+	}
+	token := b.GetTokenConfig(swapInfo.PairID)
+	if token == nil {
+		return tokens.ErrUnknownPairID
+	}
+	return b.checkSwapinBindAddress(swapInfo.Bind, token.AllowSwapinFromContract)
+}
+
+func (b *Bridge) checkSwapinBindAddress(bindAddr string, allowContractAddress bool) error {
+	if !tokens.DstBridge.IsValidAddress(bindAddr) {
+		log.Warn("wrong bind address in swapin", "bind", bindAddr)
+		return tokens.ErrTxWithWrongMemo
+	}
+	if params.MustRegisterAccount() && !tools.IsAddressRegistered(bindAddr) {
+		return tokens.ErrTxSenderNotRegistered
+	}
+	// bind address (which defaults to the sender) should not be a contract
+	if params.IsSwapServer && !allowContractAddress && b.IsValidAddress(bindAddr) {
+		isContract, err := b.IsContractAddress(bindAddr)
+		if err != nil {
+			log.Warn("query is contract address failed", "bindAddr", bindAddr, "err", err)
+			return tokens.ErrRPCQueryError
+		}
+		if isContract {
+			return tokens.ErrBindAddrIsContract
+		}
+	}
+	return nil
+}
@@ -23,6 +23,9 @@ type BtcExtraConfig struct {
 	UtxoAggregateMinCount  int
 	UtxoAggregateMinValue  uint64
 	UtxoAggregateToAddress string
+
+	UtxoSelectStrategy string
+	LongTermFeePerKb   int64
 }
 
 // ChainConfig struct
@@ -35,14 +38,62 @@ type ChainConfig struct {
 	EnableScanPool bool
 	ScanReceipt    bool `json:",omitempty"`
 
+	// generic evm chain (BlockChain = "EVM") settings
+	ChainID          string `json:",omitempty"` // expected chain id of gateway
+	SignerType       string `json:",omitempty"` // EIP155 (default) or London
+	FinalityBlockTag string `json:",omitempty"` // count confirmations from 'safe' or 'finalized' block instead of latest
+
+	// generic utxo chain (BlockChain = "UTXO") settings
+	UtxoChain *UtxoChainConfig `json:",omitempty"`
+
+	// cosmos-sdk chain (BlockChain = "Cosmos") settings, NetID is the chain id
+	CosmosChain *CosmosChainConfig `json:",omitempty"`
+
 	BaseGasPrice               string `json:",omitempty"`
 	MaxGasPriceFluctPercent    uint64 `json:",omitempty"`
 	ReplacePlusGasPricePercent uint64 `json:",omitempty"`
+	EnableDynamicFeeTx         bool   `json:",omitempty"` // build EIP-1559 txs
 	WaitTimeToReplace          int64  // seconds
 	MaxReplaceCount            int
 	EnableReplaceSwap          bool
 }
 
+// UtxoChainConfig chain params of bitcoin fork bridged by the utxo engine
+type UtxoChainConfig struct {
+	Symbol           string
+	PairID           string
+	NetMagic         uint32 // network magic, must be unique if segwit is supported
+	PubKeyHashAddrID byte
+	ScriptHashAddrID byte
+	PrivateKeyID     byte   // first byte of WIF
+	Bech32HRPSegwit  string `json:",omitempty"` // empty if segwit is not supported
+	HDCoinType       uint32
+
+	DustLimit        int64 `json:",omitempty"` // default to dust threshold of default relay fee
+	MinRelayFee      int64 `json:",omitempty"`
+	MinRelayFeePerKb int64 `json:",omitempty"`
+	MaxRelayFeePerKb int64 `json:",omitempty"`
+	MaxMinRelayFee   int64 `json:",omitempty"` // upper bound of 'MinRelayFee' in 'BtcExtra' (default 0.001 coin)
+
+	// defaults of 'BtcExtra' config
+	PlusFeePercentage     uint64 `json:",omitempty"`
+	UtxoAggregateMinCount int    `json:",omitempty"`
+	UtxoAggregateMinValue uint64 `json:",omitempty"`
+
+	SigHashForkID bool `json:",omitempty"` // sign with SIGHASH_FORKID (replay protection of bitcoin cash)
+	DisableRBF    bool `json:",omitempty"` // chain does not relay replace-by-fee txs
+
+	ChainAPI string // explorer api flavour, electrs (default, or bitcoind if 'BtcCoreExtra' is configed), electrs-btcaddr, electrs-colx, bitcoind or cloudchains
+}
+
+// CosmosChainConfig chain params of cosmos-sdk chain
+type CosmosChainConfig struct {
+	Bech32Prefix string // account address prefix (eg. cosmos)
+	Denom        string // denom of bridged coin, which also pays tx fee (eg. uatom)
+	GasLimit     uint64 `json:",omitempty"` // gas limit of swap tx (default 200000)
+	GasPrice     string `json:",omitempty"` // fee per gas in Denom (eg. 0.025), fee is rounded up
+}
+
 // GatewayConfig struct
 type GatewayConfig struct {
 	APIAddress    []string
@@ -52,7 +103,15 @@ type GatewayConfig struct {
 
 // GatewayExtras struct
 type GatewayExtras struct {
-	BlockExtra *BlockExtraArgs
+	BlockExtra   *BlockExtraArgs
+	BtcCoreExtra *BtcCoreExtraArgs `json:",omitempty"`
+}
+
+// BtcCoreExtraArgs use bitcoin core json-rpc instead of electrs as btc gateway,
+// dcrm, deposit and p2sh addresses are imported to a watch-only wallet.
+type BtcCoreExtraArgs struct {
+	CoreAPIs        []BlocknetCoreAPIArgs // APIAddress should be wallet endpoint, eg. 127.0.0.1:8332/wallet/bridge
+	ImportTimestamp int64                 `json:",omitempty"` // rescan from this unix time when import dcrm and deposit addresses, 0 means whole chain, negative means no rescan
 }
 
 // BlockExtraArgs struct
@@ -84,6 +143,9 @@ type TokenConfig struct {
 	MaximumSwap            *float64 // whole unit (eg. BTC, ETH, FSN), not Satoshi
 	MinimumSwap            *float64 // whole unit
 	BigValueThreshold      *float64
+	PairHourlyLimit        *float64 `json:",omitempty"` // whole unit, max total swap value of pair in rolling hour
+	PairDailyLimit         *float64 `json:",omitempty"` // whole unit, max total swap value of pair in rolling day
+	BindDailyLimit         *float64 `json:",omitempty"` // whole unit, max total swap value of bind address in rolling day
 	SwapFeeRate            *float64
 	MaximumSwapFee         *float64
 	MinimumSwapFee         *float64
@@ -108,11 +170,14 @@ type TokenConfig struct {
 	maxSwapFee       *big.Int
 	minSwapFee       *big.Int
 	bigValThreshhold *big.Int
+	pairHourlyLimit  *big.Int
+	pairDailyLimit   *big.Int
+	bindDailyLimit   *big.Int
 }
 
-// IsErc20 return if token is erc20
+// IsErc20 return if token is erc20 (TRC20 of tron is compatible with erc20)
 func (c *TokenConfig) IsErc20() bool {
-	return strings.EqualFold(c.ID, "ERC20") || c.IsProxyErc20()
+	return strings.EqualFold(c.ID, "ERC20") || strings.EqualFold(c.ID, "TRC20") || c.IsProxyErc20()
 }
 
 // IsProxyErc20 return if token is proxy contract of erc20
@@ -201,15 +266,25 @@ type SwapInfo struct {
 // BuildTxArgs struct
 type BuildTxArgs struct {
 	SwapInfo    `json:"swapInfo,omitempty"`
-	From        string     `json:"from,omitempty"`
-	To          string     `json:"to,omitempty"`
-	Value       *big.Int   `json:"value,omitempty"`
-	OriginValue *big.Int   `json:"originValue,omitempty"`
-	SwapValue   *big.Int   `json:"swapvalue,omitempty"`
-	Memo        string     `json:"memo,omitempty"`
-	Input       *[]byte    `json:"input,omitempty"`
-	Extra       *AllExtras `json:"extra,omitempty"`
-	ReplaceNum  uint64     `json:"replaceNum,omitempty"`
+	From        string           `json:"from,omitempty"`
+	To          string           `json:"to,omitempty"`
+	Value       *big.Int         `json:"value,omitempty"`
+	OriginValue *big.Int         `json:"originValue,omitempty"`
+	SwapValue   *big.Int         `json:"swapvalue,omitempty"`
+	Memo        string           `json:"memo,omitempty"`
+	Input       *[]byte          `json:"input,omitempty"`
+	Extra       *AllExtras       `json:"extra,omitempty"`
+	ReplaceNum  uint64           `json:"replaceNum,omitempty"`
+	Batch       []*BatchSwapArgs `json:"batch,omitempty"`
+}
+
+// BatchSwapArgs swap paid by one output of batch tx
+type BatchSwapArgs struct {
+	SwapInfo    `json:"swapInfo,omitempty"`
+	OriginValue *big.Int `json:"originValue,omitempty"`
+	SwapValue   *big.Int `json:"swapvalue,omitempty"`
+	OutputIndex int      `json:"outputIndex"`
+	Memo        string   `json:"memo,omitempty"`
 }
 
 // GetExtraArgs get extra args
@@ -217,6 +292,7 @@ func (args *BuildTxArgs) GetExtraArgs() *BuildTxArgs {
 	return &BuildTxArgs{
 		SwapInfo: args.SwapInfo,
 		Extra:    args.Extra,
+		Batch:    args.Batch,
 	}
 }
 
@@ -225,6 +301,12 @@ func (args *BuildTxArgs) GetTxNonce() uint64 {
 	if args.Extra != nil && args.Extra.EthExtra != nil && args.Extra.EthExtra.Nonce != nil {
 		return *args.Extra.EthExtra.Nonce
 	}
+	if args.Extra != nil && args.Extra.CosmosExtra != nil && args.Extra.CosmosExtra.Sequence != nil {
+		return *args.Extra.CosmosExtra.Sequence
+	}
+	if args.Extra != nil && args.Extra.XrpExtra != nil && args.Extra.XrpExtra.Sequence != nil {
+		return uint64(*args.Extra.XrpExtra.Sequence)
+	}
 	return 0
 }
 
@@ -240,25 +322,64 @@ func (args *BuildTxArgs) SetTxNonce(nonce uint64) {
 	extra.Nonce = &nonce
 }
 
-// GetTxGasPrice get tx gas price
+// GetTxGasPrice get tx gas price (max fee per gas of dynamic fee tx)
 func (args *BuildTxArgs) GetTxGasPrice() *big.Int {
-	if args.Extra != nil && args.Extra.EthExtra != nil && args.Extra.EthExtra.GasPrice != nil {
-		return args.Extra.EthExtra.GasPrice
+	if args.Extra != nil && args.Extra.EthExtra != nil {
+		if args.Extra.EthExtra.GasPrice != nil {
+			return args.Extra.EthExtra.GasPrice
+		}
+		return args.Extra.EthExtra.GasFeeCap
 	}
 	return nil
 }
 
 // AllExtras struct
 type AllExtras struct {
-	BtcExtra *BtcExtraArgs `json:"btcExtra,omitempty"`
-	EthExtra *EthExtraArgs `json:"ethExtra,omitempty"`
+	BtcExtra    *BtcExtraArgs    `json:"btcExtra,omitempty"`
+	EthExtra    *EthExtraArgs    `json:"ethExtra,omitempty"`
+	TronExtra   *TronExtraArgs   `json:"tronExtra,omitempty"`
+	CosmosExtra *CosmosExtraArgs `json:"cosmosExtra,omitempty"`
+	XrpExtra    *XrpExtraArgs    `json:"xrpExtra,omitempty"`
 }
 
 // EthExtraArgs struct
+// legacy tx is built if GasPrice is specified,
+// dynamic fee tx (EIP-1559) is built if GasTipCap and GasFeeCap are specified.
 type EthExtraArgs struct {
-	Gas      *uint64  `json:"gas,omitempty"`
-	GasPrice *big.Int `json:"gasPrice,omitempty"`
-	Nonce    *uint64  `json:"nonce,omitempty"`
+	Gas       *uint64  `json:"gas,omitempty"`
+	GasPrice  *big.Int `json:"gasPrice,omitempty"`
+	GasTipCap *big.Int `json:"gasTipCap,omitempty"`
+	GasFeeCap *big.Int `json:"gasFeeCap,omitempty"`
+	Nonce     *uint64  `json:"nonce,omitempty"`
+}
+
+// TronExtraArgs struct
+// reference block and expiration are decided when building tx,
+// oracles rebuild the same tx (and its txid) with them.
+type TronExtraArgs struct {
+	RefBlockBytes string `json:"refBlockBytes,omitempty"` // hex of bytes [6:8] of reference block id
+	RefBlockHash  string `json:"refBlockHash,omitempty"`  // hex of bytes [8:16] of reference block id
+	Expiration    int64  `json:"expiration,omitempty"`    // milliseconds
+	Timestamp     int64  `json:"timestamp,omitempty"`     // milliseconds
+	FeeLimit      int64  `json:"feeLimit,omitempty"`      // SUN, max TRX burnt for energy of contract call
+}
+
+// CosmosExtraArgs struct
+// account number and sequence (the swap nonce) are part of sign doc,
+// oracles rebuild the same sign doc with them.
+type CosmosExtraArgs struct {
+	AccountNumber *uint64 `json:"accountNumber,omitempty"`
+	Sequence      *uint64 `json:"sequence,omitempty"`
+	Gas           *uint64 `json:"gas,omitempty"`
+	Fee           *string `json:"fee,omitempty"` // amount in chain denom
+}
+
+// XrpExtraArgs struct
+// sequence (the swap nonce) and fee are part of signed payment,
+// oracles rebuild the same payment with them.
+type XrpExtraArgs struct {
+	Sequence *uint32 `json:"sequence,omitempty"`
+	Fee      *uint64 `json:"fee,omitempty"` // drops
 }
 
 // BtcOutPoint struct
@@ -272,6 +393,8 @@ type BtcExtraArgs struct {
 	RelayFeePerKb     *int64         `json:"relayFeePerKb,omitempty"`
 	ChangeAddress     *string        `json:"-"`
 	PreviousOutPoints []*BtcOutPoint `json:"previousOutPoints,omitempty"`
+	ReplaceTx         string         `json:"replaceTx,omitempty"` // replace-by-fee this unconfirmed swap tx
+	ParentTx          string         `json:"parentTx,omitempty"`  // child-pays-for-parent of this unconfirmed swap tx
 }
 
 // P2shAddressInfo struct
@@ -282,6 +405,13 @@ type P2shAddressInfo struct {
 	RedeemScriptDisasm string
 }
 
+// DestinationTagInfo struct
+type DestinationTagInfo struct {
+	BindAddress    string
+	DepositAddress string
+	DestinationTag uint32
+}
+
 // CheckConfig check chain config
 func (c *ChainConfig) CheckConfig() error {
 	if c.BlockChain == "" {
@@ -307,9 +437,98 @@ func (c *ChainConfig) CheckConfig() error {
 			return errors.New("wrong 'BaseGasPrice'")
 		}
 	}
+	if err := c.checkEvmConfig(); err != nil {
+		return err
+	}
+	if c.UtxoChain != nil {
+		return c.UtxoChain.CheckConfig()
+	}
+	if c.CosmosChain != nil {
+		return c.CosmosChain.CheckConfig()
+	}
+	return nil
+}
+
+// CheckConfig check cosmos chain config
+func (c *CosmosChainConfig) CheckConfig() error {
+	if c.Bech32Prefix == "" {
+		return errors.New("cosmos chain must config 'Bech32Prefix'")
+	}
+	if c.Denom == "" {
+		return errors.New("cosmos chain must config 'Denom'")
+	}
+	if c.GasPrice != "" {
+		if gasPrice, ok := new(big.Rat).SetString(c.GasPrice); !ok || gasPrice.Sign() < 0 {
+			return errors.New("wrong cosmos chain 'GasPrice'")
+		}
+	}
+	return nil
+}
+
+// CheckConfig check utxo chain config
+func (c *UtxoChainConfig) CheckConfig() error {
+	if c.Symbol == "" {
+		return errors.New("utxo chain must config 'Symbol'")
+	}
+	if c.PairID == "" {
+		return errors.New("utxo chain must config 'PairID'")
+	}
+	if c.PubKeyHashAddrID == c.ScriptHashAddrID {
+		return errors.New("utxo chain 'PubKeyHashAddrID' and 'ScriptHashAddrID' are equal")
+	}
+	if c.DustLimit < 0 || c.MinRelayFee < 0 || c.MinRelayFeePerKb < 0 || c.MaxRelayFeePerKb < 0 {
+		return errors.New("utxo chain dust limit and relay fees must not be negative")
+	}
+	switch c.ChainAPI {
+	case "", "electrs", "electrs-btcaddr", "electrs-colx", "bitcoind", "cloudchains":
+	default:
+		return errors.New("wrong utxo chain 'ChainAPI' (must be 'electrs', 'electrs-btcaddr', 'electrs-colx', 'bitcoind' or 'cloudchains')")
+	}
+	return nil
+}
+
+func (c *ChainConfig) checkEvmConfig() error {
+	if c.ChainID != "" {
+		if chainID, err := common.GetBigIntFromStr(c.ChainID); err != nil || chainID.Sign() <= 0 {
+			return errors.New("wrong 'ChainID'")
+		}
+	}
+	switch c.SignerType {
+	case "", "EIP155", "London":
+	default:
+		return errors.New("wrong 'SignerType' (must be 'EIP155' or 'London')")
+	}
+	if c.EnableDynamicFeeTx && c.getSignerType() != "London" {
+		return errors.New("'EnableDynamicFeeTx' requires 'London' signer of block chain")
+	}
+	switch c.FinalityBlockTag {
+	case "", "safe", "finalized":
+	default:
+		return errors.New("wrong 'FinalityBlockTag' (must be 'safe' or 'finalized')")
+	}
 	return nil
 }
 
+// getSignerType get tx signer type of eth-like block chain, empty if not eth-like
+func (c *ChainConfig) getSignerType() string {
+	blockChain := strings.ToUpper(c.BlockChain)
+	switch {
+	case strings.HasPrefix(blockChain, "ETHEREUM"):
+		return "London"
+	case strings.HasPrefix(blockChain, "EVM"):
+		if c.SignerType == "" {
+			return "EIP155"
+		}
+		return c.SignerType
+	case strings.HasPrefix(blockChain, "FUSION"),
+		strings.HasPrefix(blockChain, "ETHCLASSIC"),
+		strings.HasPrefix(blockChain, "OKEX"):
+		return "EIP155"
+	default:
+		return ""
+	}
+}
+
 // CheckConfig check token config
 //nolint:funlen,gocyclo // keep TokenConfig check as whole
 func (c *TokenConfig) CheckConfig(isSrc bool) error {
@@ -349,6 +568,11 @@ func (c *TokenConfig) CheckConfig(isSrc bool) error {
 	if c.BigValueThreshold == nil {
 		return errors.New("token must config 'BigValueThreshold'")
 	}
+	if (c.PairHourlyLimit != nil && *c.PairHourlyLimit < 0) ||
+		(c.PairDailyLimit != nil && *c.PairDailyLimit < 0) ||
+		(c.BindDailyLimit != nil && *c.BindDailyLimit < 0) {
+		return errors.New("token velocity limits must be non-negative")
+	}
 	if c.DcrmAddress == "" {
 		return errors.New("token must config 'DcrmAddress'")
 	}
@@ -409,6 +633,16 @@ func (c *TokenConfig) CalcAndStoreValue() {
 	c.maxSwapFee = ToBits(*c.MaximumSwapFee, *c.Decimals)
 	c.minSwapFee = ToBits(*c.MinimumSwapFee, *c.Decimals)
 	c.bigValThreshhold = ToBits(*c.BigValueThreshold+smallBiasValue, *c.Decimals)
+	c.pairHourlyLimit = toLimitBits(c.PairHourlyLimit, smallBiasValue, *c.Decimals)
+	c.pairDailyLimit = toLimitBits(c.PairDailyLimit, smallBiasValue, *c.Decimals)
+	c.bindDailyLimit = toLimitBits(c.BindDailyLimit, smallBiasValue, *c.Decimals)
+}
+
+func toLimitBits(limit *float64, smallBiasValue float64, decimals uint8) *big.Int {
+	if limit == nil {
+		return nil
+	}
+	return ToBits(*limit+smallBiasValue, decimals)
 }
 
 // GetDcrmAddressPrivateKey get private key
@@ -0,0 +1,24 @@
+package tokens
+
+import (
+	"testing"
+)
+
+func TestUtxoChainConfigCheckChainAPI(t *testing.T) {
+	c := &UtxoChainConfig{
+		Symbol:           "COLX",
+		PairID:           "colx",
+		PubKeyHashAddrID: 30,
+		ScriptHashAddrID: 13,
+	}
+	for _, chainAPI := range []string{"", "electrs", "electrs-btcaddr", "electrs-colx", "bitcoind", "cloudchains"} {
+		c.ChainAPI = chainAPI
+		if err := c.CheckConfig(); err != nil {
+			t.Fatalf("check config with chain api '%v' failed: %v", chainAPI, err)
+		}
+	}
+	c.ChainAPI = "electrs-unknown"
+	if err := c.CheckConfig(); err == nil {
+		t.Fatal("check config with unknown chain api should fail")
+	}
+}
@@ -0,0 +1,186 @@
+// Package utxo implements the unified bridge engine of bitcoin forks.
+//
+// The engine derives from btc bridge and is parameterised by chain params,
+// which are from presets of in-tree chains (eg. Litecoin, Block, Colossus)
+// or from 'UtxoChain' in chain config (BlockChain = "UTXO"), so a new
+// bitcoin fork can be bridged without a new package.
+package utxo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/bitcoind"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+const (
+	netMainnet = "mainnet"
+	netCustom  = "custom"
+
+	// configuredChain block chain name of utxo chain configed by 'UtxoChain'
+	configuredChain = "UTXO"
+)
+
+// chain api flavours
+const (
+	apiElectrs        = "electrs"
+	apiElectrsBtcAddr = "electrs-btcaddr"
+	apiColxElectrs    = "electrs-colx"
+	apiBitcoind       = "bitcoind"
+	apiCloudchains    = "cloudchains"
+)
+
+// Bridge utxo chain bridge inherit from btc bridge
+type Bridge struct {
+	*btc.Bridge
+	Preset string // empty if configed by 'UtxoChain'
+
+	nets        map[string]*tokens.UtxoChainConfig
+	initOnce    sync.Once
+	utxoCfg     *tokens.UtxoChainConfig
+	chainParams *chaincfg.Params
+}
+
+func init() {
+	for name := range presets {
+		preset := name
+		tokens.RegisterBridgeFactory(preset, func(isSrc bool) tokens.CrossChainBridge {
+			return NewCrossChainBridge(preset, isSrc)
+		})
+	}
+	tokens.RegisterBridgeFactory(configuredChain, func(isSrc bool) tokens.CrossChainBridge {
+		return NewCrossChainBridge("", isSrc)
+	})
+}
+
+// NewCrossChainBridge new utxo chain bridge of preset,
+// empty preset means chain params are configed by 'UtxoChain'.
+func NewCrossChainBridge(preset string, isSrc bool) *Bridge {
+	if preset != "" && presets[preset] == nil {
+		log.Fatalf("utxo::NewCrossChainBridge error: unknown preset %v", preset)
+	}
+	return NewCrossChainBridgeWithPresets(preset, presets[preset], isSrc)
+}
+
+// NewCrossChainBridgeWithPresets new utxo chain bridge with chain params
+// keyed by network, it is used by chain packages derived from the engine.
+func NewCrossChainBridgeWithPresets(preset string, nets map[string]*tokens.UtxoChainConfig, isSrc bool) *Bridge {
+	bridge := &Bridge{
+		Bridge: btc.NewCrossChainBridge(isSrc),
+		Preset: preset,
+		nets:   nets,
+	}
+	bridge.SetInherit(bridge)
+	btc.BridgeInstance = bridge
+	return bridge
+}
+
+// SetChainAndGateway set chain and gateway config
+func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	b.VerifyChainConfig()
+	b.InitChainAPI()
+	b.InitLatestBlockNumber()
+}
+
+// VerifyChainConfig verify chain config and apply chain params
+func (b *Bridge) VerifyChainConfig() {
+	utxoCfg := b.GetUtxoChainConfig()
+	btc.PairID = utxoCfg.PairID
+	btc.SetDefaultRelayFee(utxoCfg.MinRelayFee, utxoCfg.MinRelayFeePerKb, utxoCfg.MaxRelayFeePerKb)
+	btc.SetMaxMinRelayFee(utxoCfg.MaxMinRelayFee)
+	btc.SetDefaultPlusFeePercentage(utxoCfg.PlusFeePercentage)
+	btc.SetDefaultAggregate(utxoCfg.UtxoAggregateMinCount, utxoCfg.UtxoAggregateMinValue)
+	btc.SetDustLimit(utxoCfg.DustLimit)
+	btc.SetSigHashForkID(utxoCfg.SigHashForkID)
+	btc.SetDisableRBF(utxoCfg.DisableRBF)
+	log.Info("init utxo chain params", "blockChain", b.ChainConfig.BlockChain, "netID", b.ChainConfig.NetID, "symbol", utxoCfg.Symbol, "pairID", utxoCfg.PairID, "chainAPI", utxoCfg.ChainAPI)
+}
+
+// VerifyTokenConfig verify token config
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	if err := b.Bridge.VerifyTokenConfig(tokenCfg); err != nil {
+		return err
+	}
+	symbol := b.GetUtxoChainConfig().Symbol
+	if strings.EqualFold(tokenCfg.Symbol, symbol) && *tokenCfg.Decimals != 8 {
+		return fmt.Errorf("invalid decimals for %v: want 8 but have %v", symbol, *tokenCfg.Decimals)
+	}
+	return nil
+}
+
+// GetUtxoChainConfig get chain params of preset on configed network,
+// or 'UtxoChain' in chain config if not a preset.
+func (b *Bridge) GetUtxoChainConfig() *tokens.UtxoChainConfig {
+	b.initOnce.Do(b.initChainParams)
+	return b.utxoCfg
+}
+
+// GetChainParams get chain config (net params)
+func (b *Bridge) GetChainParams() *chaincfg.Params {
+	b.initOnce.Do(b.initChainParams)
+	return b.chainParams
+}
+
+func (b *Bridge) initChainParams() {
+	chainCfg := b.ChainConfig
+	networkID := strings.ToLower(chainCfg.NetID)
+	if b.Preset == "" {
+		if chainCfg.UtxoChain == nil {
+			log.Fatal("utxo chain must config 'UtxoChain'", "blockChain", chainCfg.BlockChain)
+		}
+		b.utxoCfg = chainCfg.UtxoChain
+	} else {
+		if networkID == netCustom {
+			networkID = netMainnet
+		}
+		b.utxoCfg = b.nets[networkID]
+		if b.utxoCfg == nil {
+			log.Fatal("unsupported network of utxo chain", "blockChain", chainCfg.BlockChain, "netID", chainCfg.NetID)
+		}
+	}
+	b.chainParams = newChainParams(networkID, b.utxoCfg)
+}
+
+// InitChainAPI init chain api of configed flavour
+func (b *Bridge) InitChainAPI() {
+	utxoCfg := b.GetUtxoChainConfig()
+	chainAPI := utxoCfg.ChainAPI
+	if chainAPI == "" {
+		// default to bitcoin core rpc if configed, otherwise electrs
+		if extras := b.GatewayConfig.Extras; extras != nil && extras.BtcCoreExtra != nil {
+			chainAPI = apiBitcoind
+		}
+	}
+	switch chainAPI {
+	case "", apiElectrs:
+		b.ChainAPI = nil
+	case apiElectrsBtcAddr:
+		b.ChainAPI = &btcAddrElectrsAPI{b: b}
+	case apiColxElectrs:
+		b.ChainAPI = newColxElectrsAPI(b)
+	case apiBitcoind:
+		extras := b.GatewayConfig.Extras
+		if extras == nil || extras.BtcCoreExtra == nil {
+			log.Fatal("utxo chain with bitcoind api must config gateway 'BtcCoreExtra'")
+		}
+		client, err := bitcoind.NewClient(extras.BtcCoreExtra, b.GetChainParams())
+		if err != nil {
+			log.Fatal("init bitcoin core client failed", "err", err)
+		}
+		b.ChainAPI = client
+	case apiCloudchains:
+		extras := b.GatewayConfig.Extras
+		if extras == nil || extras.BlockExtra == nil {
+			log.Fatal("utxo chain with cloudchains api must config gateway 'BlockExtra'")
+		}
+		b.ChainAPI = newCloudchainsAPI(extras.BlockExtra)
+	default:
+		log.Fatal("unknown utxo chain api", "chainAPI", utxoCfg.ChainAPI)
+	}
+}
@@ -0,0 +1,146 @@
+package utxo
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// btcAddrElectrsAPI call electrs which indexes addresses in bitcoin mainnet
+// encoding (eg. litecoin electrs), convert addresses in request and result
+type btcAddrElectrsAPI struct {
+	b *Bridge
+}
+
+// convertAddress convert address encoding between networks
+func convertAddress(addr string, from, to *chaincfg.Params) (string, error) {
+	address, err := btcutil.DecodeAddress(addr, from)
+	if err != nil {
+		return "", err
+	}
+	if !address.IsForNet(from) {
+		return "", fmt.Errorf("invalid address for net %v", from.Name)
+	}
+	pkScript, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		return "", err
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, to)
+	if err != nil {
+		return "", err
+	}
+	if len(addrs) != 1 {
+		return "", fmt.Errorf("can not convert address %v", addr)
+	}
+	return addrs[0].EncodeAddress(), nil
+}
+
+func (e *btcAddrElectrsAPI) toBtcAddress(addr string) string {
+	if converted, err := convertAddress(addr, e.b.GetChainParams(), &chaincfg.MainNetParams); err == nil {
+		return converted
+	}
+	return addr
+}
+
+func (e *btcAddrElectrsAPI) fromBtcVout(vout *electrs.ElectTxOut) {
+	if vout == nil || vout.ScriptpubkeyAddress == nil {
+		return
+	}
+	if converted, err := convertAddress(*vout.ScriptpubkeyAddress, &chaincfg.MainNetParams, e.b.GetChainParams()); err == nil {
+		*vout.ScriptpubkeyAddress = converted
+	}
+}
+
+func (e *btcAddrElectrsAPI) fromBtcTx(tx *electrs.ElectTx) {
+	for _, vin := range tx.Vin {
+		e.fromBtcVout(vin.Prevout)
+	}
+	for _, vout := range tx.Vout {
+		e.fromBtcVout(vout)
+	}
+}
+
+func (e *btcAddrElectrsAPI) fromBtcTxs(txs []*electrs.ElectTx) {
+	for _, tx := range txs {
+		e.fromBtcTx(tx)
+	}
+}
+
+func (e *btcAddrElectrsAPI) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	return electrs.GetLatestBlockNumberOf(apiAddress)
+}
+
+func (e *btcAddrElectrsAPI) GetLatestBlockNumber() (uint64, error) {
+	return electrs.GetLatestBlockNumber(e.b)
+}
+
+func (e *btcAddrElectrsAPI) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	result, err := electrs.GetTransactionByHash(e.b, txHash)
+	if err == nil {
+		e.fromBtcTx(result)
+	}
+	return result, err
+}
+
+func (e *btcAddrElectrsAPI) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	return electrs.GetElectTransactionStatus(e.b, txHash)
+}
+
+func (e *btcAddrElectrsAPI) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	return electrs.FindUtxos(e.b, e.toBtcAddress(addr))
+}
+
+func (e *btcAddrElectrsAPI) GetPoolTxidList() ([]string, error) {
+	return electrs.GetPoolTxidList(e.b)
+}
+
+func (e *btcAddrElectrsAPI) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	results, err := electrs.GetPoolTransactions(e.b, e.toBtcAddress(addr))
+	if err == nil {
+		e.fromBtcTxs(results)
+	}
+	return results, err
+}
+
+func (e *btcAddrElectrsAPI) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	results, err := electrs.GetTransactionHistory(e.b, e.toBtcAddress(addr), lastSeenTxid)
+	if err == nil {
+		e.fromBtcTxs(results)
+	}
+	return results, err
+}
+
+func (e *btcAddrElectrsAPI) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	return electrs.GetOutspend(e.b, txHash, vout)
+}
+
+func (e *btcAddrElectrsAPI) PostTransaction(txHex string) (txHash string, err error) {
+	return electrs.PostTransaction(e.b, txHex)
+}
+
+func (e *btcAddrElectrsAPI) GetBlockHash(height uint64) (string, error) {
+	return electrs.GetBlockHash(e.b, height)
+}
+
+func (e *btcAddrElectrsAPI) GetBlockTxids(blockHash string) ([]string, error) {
+	return electrs.GetBlockTxids(e.b, blockHash)
+}
+
+func (e *btcAddrElectrsAPI) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
+	return electrs.GetBlock(e.b, blockHash)
+}
+
+func (e *btcAddrElectrsAPI) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
+	results, err := electrs.GetBlockTransactions(e.b, blockHash, startIndex)
+	if err == nil {
+		e.fromBtcTxs(results)
+	}
+	return results, err
+}
+
+func (e *btcAddrElectrsAPI) EstimateFeePerKb(blocks int) (int64, error) {
+	return electrs.EstimateFeePerKb(e.b, blocks)
+}
@@ -1,15 +1,15 @@
-package block
+package utxo
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"math/big"
 	"net/http"
 	"sort"
 	"strings"
 
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 	"github.com/btcsuite/btcd/btcjson"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -46,55 +46,46 @@ func (c *Client) Closer() {
 	}
 }
 
-var blockClient *Client
-
-var cclis = make([]CoreClient, 0)
-
-// GetClient returns new Client
-func (b *Bridge) GetClient() *Client {
-	if blockClient != nil {
-		return blockClient
-	}
-
-	cfg := b.GetGatewayConfig()
-	if cfg.Extras == nil || cfg.Extras.BlockExtra == nil {
-		return nil
-	}
+// cloudchainsAPI call blocknet core json-rpc, and find utxos by cloudchains utxo api
+type cloudchainsAPI struct {
+	cli *Client
+}
 
-	if len(cclis) == 0 {
-		for _, args := range cfg.Extras.BlockExtra.CoreAPIs {
-			connCfg := &rpcclient.ConnConfig{
-				Host:         args.APIAddress,
-				User:         args.RPCUser,
-				Pass:         args.RPCPassword,
-				HTTPPostMode: true,            // Bitcoin core only supports HTTP POST mode
-				DisableTLS:   args.DisableTLS, // Bitcoin core does not provide TLS by default
-			}
+func newCloudchainsAPI(extra *tokens.BlockExtraArgs) *cloudchainsAPI {
+	cclis := make([]CoreClient, 0, len(extra.CoreAPIs))
+	for _, args := range extra.CoreAPIs {
+		connCfg := &rpcclient.ConnConfig{
+			Host:         args.APIAddress,
+			User:         args.RPCUser,
+			Pass:         args.RPCPassword,
+			HTTPPostMode: true,            // Bitcoin core only supports HTTP POST mode
+			DisableTLS:   args.DisableTLS, // Bitcoin core does not provide TLS by default
+		}
 
-			client, err := rpcclient.New(connCfg, nil)
-			if err != nil {
-				continue
-			}
+		client, err := rpcclient.New(connCfg, nil)
+		if err != nil {
+			continue
+		}
 
-			ccli := CoreClient{
-				Client:  client,
-				Address: connCfg.Host,
-				Closer:  client.Shutdown,
-			}
-			cclis = append(cclis, ccli)
+		ccli := CoreClient{
+			Client:  client,
+			Address: connCfg.Host,
+			Closer:  client.Shutdown,
 		}
+		cclis = append(cclis, ccli)
 	}
 
-	blockClient = &Client{
-		CClients:         cclis,
-		UTXOAPIAddresses: cfg.Extras.BlockExtra.UTXOAPIAddresses,
+	return &cloudchainsAPI{
+		cli: &Client{
+			CClients:         cclis,
+			UTXOAPIAddresses: extra.UTXOAPIAddresses,
+		},
 	}
-	return blockClient
 }
 
 // GetLatestBlockNumberOf impl
-func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	for _, ccli := range cli.CClients {
 		if ccli.Address == apiAddress {
@@ -106,8 +97,8 @@ func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
 }
 
 // GetLatestBlockNumber impl
-func (b *Bridge) GetLatestBlockNumber() (blocknumber uint64, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetLatestBlockNumber() (blocknumber uint64, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	for _, ccli := range cli.CClients {
@@ -122,8 +113,8 @@ func (b *Bridge) GetLatestBlockNumber() (blocknumber uint64, err error) {
 }
 
 // GetTransactionByHash impl
-func (b *Bridge) GetTransactionByHash(txHash string) (etx *electrs.ElectTx, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetTransactionByHash(txHash string) (etx *electrs.ElectTx, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	hash, err := chainhash.NewHashFromStr(txHash)
@@ -143,8 +134,8 @@ func (b *Bridge) GetTransactionByHash(txHash string) (etx *electrs.ElectTx, err
 }
 
 // GetElectTransactionStatus impl
-func (b *Bridge) GetElectTransactionStatus(txHash string) (txstatus *electrs.ElectTxStatus, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetElectTransactionStatus(txHash string) (txstatus *electrs.ElectTxStatus, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	hash, err := chainhash.NewHashFromStr(txHash)
@@ -156,7 +147,7 @@ func (b *Bridge) GetElectTransactionStatus(txHash string) (txstatus *electrs.Ele
 		if err0 == nil {
 			txstatus = TxStatus(txraw)
 			if h := txstatus.BlockHash; h != nil {
-				if blk, err1 := b.GetBlock(*h); err1 == nil {
+				if blk, err1 := c.GetBlock(*h); err1 == nil {
 					*txstatus.BlockHeight = uint64(*blk.Height)
 				}
 			}
@@ -169,11 +160,11 @@ func (b *Bridge) GetElectTransactionStatus(txHash string) (txstatus *electrs.Ele
 }
 
 // FindUtxos impl
-func (b *Bridge) FindUtxos(addr string) (utxos []*electrs.ElectUtxo, err error) {
+func (c *cloudchainsAPI) FindUtxos(addr string) (utxos []*electrs.ElectUtxo, err error) {
 	// cloudchainsinc
-	cli := b.GetClient()
+	cli := c.cli
 
-	currentHeight, err := b.GetLatestBlockNumber()
+	currentHeight, err := c.GetLatestBlockNumber()
 	if err != nil {
 		return nil, err
 	}
@@ -201,9 +192,9 @@ func (b *Bridge) FindUtxos(addr string) (utxos []*electrs.ElectUtxo, err error)
 				}
 				status.Confirmed = &confirmed
 
-				if blkhash, err1 := b.GetBlockHash(cutxo.BlockNumber); err1 != nil {
+				if blkhash, err1 := c.GetBlockHash(cutxo.BlockNumber); err1 != nil {
 					status.BlockHash = &blkhash
-					if blk, err2 := b.GetBlock(blkhash); err2 != nil {
+					if blk, err2 := c.GetBlock(blkhash); err2 != nil {
 						status.BlockTime = new(uint64)
 						*status.BlockTime = uint64(*blk.Timestamp)
 					}
@@ -267,8 +258,8 @@ type CloudchainUtxo struct {
 }
 
 // GetPoolTxidList impl
-func (b *Bridge) GetPoolTxidList() (txids []string, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetPoolTxidList() (txids []string, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	txids = make([]string, 0)
 	errs := make([]error, 0)
@@ -287,14 +278,14 @@ func (b *Bridge) GetPoolTxidList() (txids []string, err error) {
 }
 
 // GetPoolTransactions impl
-func (b *Bridge) GetPoolTransactions(addr string) (txs []*electrs.ElectTx, err error) {
-	txids, err := b.GetPoolTxidList()
+func (c *cloudchainsAPI) GetPoolTransactions(addr string) (txs []*electrs.ElectTx, err error) {
+	txids, err := c.GetPoolTxidList()
 	if err != nil {
 		return
 	}
 	errs := make([]error, 0)
 	for _, txid := range txids {
-		tx, err0 := b.GetTransactionByHash(txid)
+		tx, err0 := c.GetTransactionByHash(txid)
 		if err0 != nil {
 			errs = append(errs, err0)
 			continue
@@ -311,14 +302,14 @@ func (b *Bridge) GetPoolTransactions(addr string) (txs []*electrs.ElectTx, err e
 
 // GetTransactionHistory impl
 // lastSeenTxis 以后所有的交易
-func (b *Bridge) GetTransactionHistory(addr, lastSeenTxid string) (etxs []*electrs.ElectTx, err error) {
+func (c *cloudchainsAPI) GetTransactionHistory(addr, lastSeenTxid string) (etxs []*electrs.ElectTx, err error) {
 	return
 }
 
 // GetOutspend impl
 // Only to find out if txout is spent, does not tell in which transactions it is spent.
-func (b *Bridge) GetOutspend(txHash string, vout uint32) (evout *electrs.ElectOutspend, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetOutspend(txHash string, vout uint32) (evout *electrs.ElectOutspend, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	hash, err := chainhash.NewHashFromStr(txHash)
@@ -338,8 +329,8 @@ func (b *Bridge) GetOutspend(txHash string, vout uint32) (evout *electrs.ElectOu
 }
 
 // PostTransaction impl
-func (b *Bridge) PostTransaction(txHex string) (txHash string, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) PostTransaction(txHex string) (txHash string, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	var success bool
@@ -360,8 +351,8 @@ func (b *Bridge) PostTransaction(txHex string) (txHash string, err error) {
 }
 
 // GetBlockHash impl
-func (b *Bridge) GetBlockHash(height uint64) (hash string, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetBlockHash(height uint64) (hash string, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	for _, ccli := range cli.CClients {
@@ -377,8 +368,8 @@ func (b *Bridge) GetBlockHash(height uint64) (hash string, err error) {
 }
 
 // GetBlockTxids impl
-func (b *Bridge) GetBlockTxids(blockHash string) (txids []string, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetBlockTxids(blockHash string) (txids []string, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	for _, ccli := range cli.CClients {
@@ -398,8 +389,8 @@ func (b *Bridge) GetBlockTxids(blockHash string) (txids []string, err error) {
 }
 
 // GetBlock impl
-func (b *Bridge) GetBlock(blockHash string) (eblock *electrs.ElectBlock, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetBlock(blockHash string) (eblock *electrs.ElectBlock, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	hash, err := chainhash.NewHashFromStr(blockHash)
@@ -419,8 +410,8 @@ func (b *Bridge) GetBlock(blockHash string) (eblock *electrs.ElectBlock, err err
 }
 
 // GetBlockTransactions impl
-func (b *Bridge) GetBlockTransactions(blockHash string, startIndex uint32) (etxs []*electrs.ElectTx, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) GetBlockTransactions(blockHash string, startIndex uint32) (etxs []*electrs.ElectTx, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	hash, err := chainhash.NewHashFromStr(blockHash)
@@ -432,7 +423,7 @@ func (b *Bridge) GetBlockTransactions(blockHash string, startIndex uint32) (etxs
 		if err0 == nil {
 			txs := block.Tx
 			for _, txid := range txs {
-				etx, err1 := b.GetTransactionByHash(txid)
+				etx, err1 := c.GetTransactionByHash(txid)
 				if err1 != nil {
 					continue
 				}
@@ -447,8 +438,8 @@ func (b *Bridge) GetBlockTransactions(blockHash string, startIndex uint32) (etxs
 }
 
 // EstimateFeePerKb impl
-func (b *Bridge) EstimateFeePerKb(blocks int) (fee int64, err error) {
-	cli := b.GetClient()
+func (c *cloudchainsAPI) EstimateFeePerKb(blocks int) (fee int64, err error) {
+	cli := c.cli
 	//# defer cli.Closer()
 	errs := make([]error, 0)
 	for _, ccli := range cli.CClients {
@@ -465,26 +456,3 @@ func (b *Bridge) EstimateFeePerKb(blocks int) (fee int64, err error) {
 	err = fmt.Errorf("%+v", errs)
 	return
 }
-
-// GetBalance impl
-func (b *Bridge) GetBalance(account string) (*big.Int, error) {
-	utxos, err := b.FindUtxos(account)
-	if err != nil {
-		return nil, err
-	}
-	var balance uint64
-	for _, utxo := range utxos {
-		balance += *utxo.Value
-	}
-	return new(big.Int).SetUint64(balance), nil
-}
-
-// GetTokenBalance impl
-func (b *Bridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
-	return nil, fmt.Errorf("[%v] can not get token balance of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
-}
-
-// GetTokenSupply impl
-func (b *Bridge) GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error) {
-	return nil, fmt.Errorf("[%v] can not get token supply of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
-}
@@ -1,4 +1,4 @@
-package block
+package utxo
 
 import (
 	"bytes"
@@ -11,6 +11,13 @@ import (
 	"github.com/btcsuite/btcd/wire"
 )
 
+// electrs script pubkey types
+const (
+	p2pkhType    = "p2pkh"
+	p2shType     = "p2sh"
+	opReturnType = "op_return"
+)
+
 // ConvertTx converts btcjson raw tx result to elect tx
 func ConvertTx(tx *btcjson.TxRawResult) *electrs.ElectTx {
 	etx := &electrs.ElectTx{
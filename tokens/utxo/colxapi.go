@@ -0,0 +1,151 @@
+package utxo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/wire"
+)
+
+const (
+	// colossus electrs reports tip height rebased by this offset
+	colxHeightOffset = 499999
+	// colossus electrs does not estimate fee
+	colxFeePerKb = 100100000
+	// inputs of sent tx are locked until the tx is confirmed or timeout
+	colxLockUtxoTimeout = 5 * 24 * time.Hour
+)
+
+// colxElectrsAPI call colossus electrs, which indexes addresses in bitcoin
+// mainnet encoding, rebases block heights and does not estimate fee.
+// It still lists utxos spent in pool, so inputs of sent txs are locked.
+type colxElectrsAPI struct {
+	*btcAddrElectrsAPI
+
+	lock        sync.Mutex
+	lockedUtxos map[tokens.BtcOutPoint]*colxLockedUtxo
+}
+
+type colxLockedUtxo struct {
+	spentBy  string
+	deadline time.Time
+}
+
+func newColxElectrsAPI(b *Bridge) *colxElectrsAPI {
+	return &colxElectrsAPI{
+		btcAddrElectrsAPI: &btcAddrElectrsAPI{b: b},
+		lockedUtxos:       make(map[tokens.BtcOutPoint]*colxLockedUtxo),
+	}
+}
+
+func (e *colxElectrsAPI) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	height, err := e.btcAddrElectrsAPI.GetLatestBlockNumberOf(apiAddress)
+	if err != nil {
+		return 0, err
+	}
+	return height + colxHeightOffset, nil
+}
+
+func (e *colxElectrsAPI) GetLatestBlockNumber() (uint64, error) {
+	height, err := e.btcAddrElectrsAPI.GetLatestBlockNumber()
+	if err != nil {
+		return 0, err
+	}
+	return height + colxHeightOffset, nil
+}
+
+func (e *colxElectrsAPI) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	status, err := e.btcAddrElectrsAPI.GetElectTransactionStatus(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if status.BlockHeight != nil {
+		*status.BlockHeight += colxHeightOffset
+	}
+	return status, nil
+}
+
+func (e *colxElectrsAPI) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	utxos, err := e.btcAddrElectrsAPI.FindUtxos(addr)
+	if err != nil {
+		return nil, err
+	}
+	unlocked := make([]*electrs.ElectUtxo, 0, len(utxos))
+	for _, utxo := range utxos {
+		if e.isUtxoLocked(tokens.BtcOutPoint{Hash: *utxo.Txid, Index: *utxo.Vout}) {
+			continue
+		}
+		unlocked = append(unlocked, utxo)
+	}
+	return unlocked, nil
+}
+
+func (e *colxElectrsAPI) PostTransaction(txHex string) (txHash string, err error) {
+	txHash, err = e.btcAddrElectrsAPI.PostTransaction(txHex)
+	if err != nil {
+		return "", err
+	}
+	if errf := e.lockInputs(txHash, txHex); errf != nil {
+		log.Warn("lock colx utxos of sent tx failed", "txHash", txHash, "err", errf)
+	}
+	return txHash, nil
+}
+
+func (e *colxElectrsAPI) EstimateFeePerKb(blocks int) (int64, error) {
+	return colxFeePerKb, nil
+}
+
+func (e *colxElectrsAPI) lockInputs(txHash, txHex string) error {
+	txData, err := hex.DecodeString(txHex)
+	if err != nil {
+		return err
+	}
+	var tx wire.MsgTx
+	if err = tx.Deserialize(bytes.NewReader(txData)); err != nil {
+		return err
+	}
+	now := time.Now()
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for point, locked := range e.lockedUtxos {
+		if now.After(locked.deadline) {
+			delete(e.lockedUtxos, point)
+		}
+	}
+	for _, txin := range tx.TxIn {
+		point := tokens.BtcOutPoint{
+			Hash:  txin.PreviousOutPoint.Hash.String(),
+			Index: txin.PreviousOutPoint.Index,
+		}
+		e.lockedUtxos[point] = &colxLockedUtxo{
+			spentBy:  txHash,
+			deadline: now.Add(colxLockUtxoTimeout),
+		}
+	}
+	return nil
+}
+
+// isUtxoLocked utxo is unlocked after the spending tx is confirmed or timeout
+func (e *colxElectrsAPI) isUtxoLocked(point tokens.BtcOutPoint) bool {
+	e.lock.Lock()
+	locked, exist := e.lockedUtxos[point]
+	e.lock.Unlock()
+	if !exist {
+		return false
+	}
+	if time.Now().Before(locked.deadline) {
+		status, err := e.GetElectTransactionStatus(locked.spentBy)
+		if err != nil || status.Confirmed == nil || !*status.Confirmed {
+			return true
+		}
+	}
+	e.lock.Lock()
+	delete(e.lockedUtxos, point)
+	e.lock.Unlock()
+	return false
+}
@@ -0,0 +1,123 @@
+package utxo
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+const (
+	testColxAddress = "DG9xjuuxvMXgo3Xq6zTj71DkdyNHS9KiyR"
+	testColxTxid    = "b07069fdea77c082fb45f7b1c389b8e68e2864c27fbf82e358d36f79877134b8"
+	// spends 1111...1111:1 of testColxAddress
+	testColxRawTx = "01000000011111111111111111111111111111111111111111111111111111111111111111010000001976a91478d34a0cd1f306bc55d0f7cec2d1eebb1c028a7b88acffffffff0280d1f008000000001976a91478d34a0cd1f306bc55d0f7cec2d1eebb1c028a7b88ac00000000000000000f6a0d5357415054583a30783132333400000000"
+)
+
+// testColxElectrs fake colossus electrs
+type testColxElectrs struct {
+	lock      sync.Mutex
+	confirmed bool
+	utxoPath  string
+}
+
+func (s *testColxElectrs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	switch {
+	case r.URL.Path == "/blocks/tip/height":
+		_, _ = fmt.Fprint(w, 100)
+	case r.Method == http.MethodPost && r.URL.Path == "/tx":
+		_, _ = ioutil.ReadAll(r.Body)
+		_, _ = fmt.Fprint(w, testColxTxid)
+	case r.URL.Path == "/tx/"+testColxTxid+"/status":
+		height := uint64(200)
+		_ = json.NewEncoder(w).Encode(&electrs.ElectTxStatus{Confirmed: &s.confirmed, BlockHeight: &height})
+	case strings.HasSuffix(r.URL.Path, "/utxo"):
+		s.utxoPath = r.URL.Path
+		_, _ = fmt.Fprint(w, `[
+			{"txid":"1111111111111111111111111111111111111111111111111111111111111111","vout":1,"value":160000000,"status":{"confirmed":true}},
+			{"txid":"1111111111111111111111111111111111111111111111111111111111111111","vout":2,"value":100000000,"status":{"confirmed":true}}
+		]`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *testColxElectrs) setConfirmed(confirmed bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.confirmed = confirmed
+}
+
+func TestColxElectrsAPI(t *testing.T) {
+	server := &testColxElectrs{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	b := NewCrossChainBridge("Colossus", true)
+	b.ChainConfig = &tokens.ChainConfig{BlockChain: "Colossus", NetID: netMainnet}
+	b.GatewayConfig = &tokens.GatewayConfig{APIAddress: []string{httpServer.URL}}
+	api := newColxElectrsAPI(b)
+
+	if height, err := api.GetLatestBlockNumber(); err != nil || height != 100+colxHeightOffset {
+		t.Fatalf("wrong latest block number %v, err %v", height, err)
+	}
+	if fee, _ := api.EstimateFeePerKb(6); fee != colxFeePerKb {
+		t.Fatalf("wrong estimate fee %v", fee)
+	}
+
+	findUtxos := func() (vouts []uint32) {
+		utxos, err := api.FindUtxos(testColxAddress)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, utxo := range utxos {
+			vouts = append(vouts, *utxo.Vout)
+		}
+		return vouts
+	}
+	if vouts := findUtxos(); len(vouts) != 2 {
+		t.Fatalf("wrong utxos %v", vouts)
+	}
+	// colossus electrs indexes addresses in bitcoin encoding
+	if server.utxoPath != "/address/1C1sCeyKcwdQG3MENQUAZF49kqdz7aWMoy/utxo" {
+		t.Fatalf("wrong utxo path %v", server.utxoPath)
+	}
+
+	txHash, err := api.PostTransaction(testColxRawTx)
+	if err != nil || txHash != testColxTxid {
+		t.Fatalf("post transaction failed, txHash %v, err %v", txHash, err)
+	}
+	if vouts := findUtxos(); len(vouts) != 1 || vouts[0] != 2 {
+		t.Fatalf("utxo spent by unconfirmed tx is not locked, utxos %v", vouts)
+	}
+
+	status, err := api.GetElectTransactionStatus(txHash)
+	if err != nil || *status.BlockHeight != 200+colxHeightOffset {
+		t.Fatalf("wrong tx status %v, err %v", status, err)
+	}
+
+	server.setConfirmed(true)
+	if vouts := findUtxos(); len(vouts) != 2 {
+		t.Fatalf("utxo spent by confirmed tx is not unlocked, utxos %v", vouts)
+	}
+
+	// unlock after timeout even if the tx is dropped
+	server.setConfirmed(false)
+	_, _ = api.PostTransaction(testColxRawTx)
+	for _, locked := range api.lockedUtxos {
+		locked.deadline = time.Now().Add(-time.Second)
+	}
+	if vouts := findUtxos(); len(vouts) != 2 {
+		t.Fatalf("utxo is not unlocked after timeout, utxos %v", vouts)
+	}
+}
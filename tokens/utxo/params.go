@@ -0,0 +1,108 @@
+package utxo
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// presets chain params of in-tree utxo chains, keyed by block chain name and network
+var presets = map[string]map[string]*tokens.UtxoChainConfig{
+	"Litecoin": {
+		netMainnet: {
+			Symbol:           "LTC",
+			PairID:           "ltc",
+			NetMagic:         0xdbb6c0fb,
+			PubKeyHashAddrID: 0x30, // starts with L
+			ScriptHashAddrID: 0x32, // starts with M
+			PrivateKeyID:     0xb0,
+			Bech32HRPSegwit:  "ltc",
+			HDCoinType:       2,
+			ChainAPI:         apiElectrsBtcAddr,
+		},
+		"testnet4": {
+			Symbol:           "LTC",
+			PairID:           "ltc",
+			NetMagic:         0xf1c8d2fd,
+			PubKeyHashAddrID: 0x6f, // starts with m or n
+			ScriptHashAddrID: 0x3a, // starts with Q
+			PrivateKeyID:     0xef,
+			Bech32HRPSegwit:  "tltc",
+			HDCoinType:       1,
+			ChainAPI:         apiElectrsBtcAddr,
+		},
+	},
+	"Block": {
+		netMainnet: {
+			Symbol:           "BLOCK",
+			PairID:           "block",
+			NetMagic:         0xa3a2a0a1,
+			PubKeyHashAddrID: 0x1a, // starts with B
+			ScriptHashAddrID: 0x1c, // starts with C
+			PrivateKeyID:     0x9a,
+			Bech32HRPSegwit:  "block",
+			MinRelayFee:      3000,
+			MinRelayFeePerKb: 10000,
+			ChainAPI:         apiCloudchains,
+		},
+	},
+	"Colossus": colossusNets,
+	"COLX":     colossusNets,
+}
+
+// colossusNets chain params of Colossus, it does not support segwit and RBF.
+var colossusNets = map[string]*tokens.UtxoChainConfig{
+	netMainnet: {
+		Symbol:                "COLX",
+		PairID:                "colx",
+		PubKeyHashAddrID:      0x1e, // starts with D
+		ScriptHashAddrID:      0x0d, // starts with 6
+		PrivateKeyID:          0xd4,
+		MinRelayFee:           300000000,
+		MinRelayFeePerKb:      100000000,
+		MaxRelayFeePerKb:      1000000000,
+		MaxMinRelayFee:        500000000,
+		PlusFeePercentage:     20,
+		UtxoAggregateMinCount: 1,
+		UtxoAggregateMinValue: 100000000,
+		DisableRBF:            true,
+		ChainAPI:              apiColxElectrs,
+	},
+	"testnet4": {
+		Symbol:                "COLX",
+		PairID:                "colx",
+		PubKeyHashAddrID:      0x8b, // starts with x or y
+		ScriptHashAddrID:      0x13, // starts with 8 or 9
+		PrivateKeyID:          0xef,
+		MinRelayFee:           300000000,
+		MinRelayFeePerKb:      100000000,
+		MaxRelayFeePerKb:      1000000000,
+		MaxMinRelayFee:        500000000,
+		PlusFeePercentage:     20,
+		UtxoAggregateMinCount: 1,
+		UtxoAggregateMinValue: 100000000,
+		DisableRBF:            true,
+		ChainAPI:              apiColxElectrs,
+	},
+}
+
+// newChainParams derive chain params from bitcoin mainnet params,
+// only address encoding and network magic are different.
+func newChainParams(networkID string, utxoCfg *tokens.UtxoChainConfig) *chaincfg.Params {
+	params := chaincfg.MainNetParams
+	params.Name = networkID
+	params.Net = wire.BitcoinNet(utxoCfg.NetMagic)
+	params.PubKeyHashAddrID = utxoCfg.PubKeyHashAddrID
+	params.ScriptHashAddrID = utxoCfg.ScriptHashAddrID
+	params.PrivateKeyID = utxoCfg.PrivateKeyID
+	params.Bech32HRPSegwit = utxoCfg.Bech32HRPSegwit
+	params.HDCoinType = utxoCfg.HDCoinType
+	if params.Bech32HRPSegwit != "" {
+		// bech32 address is decoded only if its prefix is registered
+		if err := chaincfg.Register(&params); err != nil {
+			log.Warn("register utxo chain params failed, segwit address is not supported", "net", networkID, "err", err)
+		}
+	}
+	return &params
+}
@@ -0,0 +1,115 @@
+package utxo
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+func TestConvertAddress(t *testing.T) {
+	ltcParams := newChainParams(netMainnet, presets["Litecoin"][netMainnet])
+	btcParams := &chaincfg.MainNetParams
+	hash160 := btcutil.Hash160([]byte("utxo engine"))
+
+	p2pkh, _ := btcutil.NewAddressPubKeyHash(hash160, btcParams)
+	p2sh, _ := btcutil.NewAddressScriptHashFromHash(hash160, btcParams)
+	p2wpkh, _ := btcutil.NewAddressWitnessPubKeyHash(hash160, btcParams)
+
+	tests := []struct {
+		btcAddr string
+		prefix  string
+	}{
+		{p2pkh.EncodeAddress(), "L"},
+		{p2sh.EncodeAddress(), "M"},
+		{p2wpkh.EncodeAddress(), "ltc1"},
+	}
+	for _, test := range tests {
+		ltcAddr, err := convertAddress(test.btcAddr, btcParams, ltcParams)
+		if err != nil {
+			t.Fatalf("convert %v failed: %v", test.btcAddr, err)
+		}
+		if !strings.HasPrefix(ltcAddr, test.prefix) {
+			t.Fatalf("convert %v to %v, want prefix %v", test.btcAddr, ltcAddr, test.prefix)
+		}
+		btcAddr, err := convertAddress(ltcAddr, ltcParams, btcParams)
+		if err != nil || btcAddr != test.btcAddr {
+			t.Fatalf("convert back %v to %v, want %v, err %v", ltcAddr, btcAddr, test.btcAddr, err)
+		}
+	}
+
+	if _, err := convertAddress(p2pkh.EncodeAddress(), ltcParams, btcParams); err == nil {
+		t.Fatal("convert address of other network should fail")
+	}
+}
+
+// TestColossusPreset addresses, raw tx and sighash are generated by
+// github.com/giangnamnabka/btcd (the colossus fork of btcd)
+func TestColossusPreset(t *testing.T) {
+	const (
+		pubkey    = "04d38309dfdfd9adf129287b68cf2e1f1124e0cbc40cc98f94e5f2d23c26712fa3b33d63280dd1448319a6a4f4111722d6b3a730ebe07652ed2b3770947b3de2e2"
+		prevTxid  = "1111111111111111111111111111111111111111111111111111111111111111"
+		wantRawTx = "01000000011111111111111111111111111111111111111111111111111111111111111111010000001976a91478d34a0cd1f306bc55d0f7cec2d1eebb1c028a7b88acffffffff0280d1f008000000001976a91478d34a0cd1f306bc55d0f7cec2d1eebb1c028a7b88ac00000000000000000f6a0d5357415054583a30783132333400000000"
+		wantHash  = "61ebed225b95ffc5c0c4114e10c98aeaa1df38b17c8c2a80545669d2d4076d6b"
+	)
+	tests := []struct {
+		netID     string
+		wantP2pkh string
+		wantP2sh  string
+	}{
+		{netMainnet, "DG9xjuuxvMXgo3Xq6zTj71DkdyNHS9KiyR", "6RQi14r4rHentgAMgroJrsbNwPzFHzutwB"},
+		{"testnet4", "y7zi4kQNK23BwHjGmimTxftWExT6tRxHxx", "8qSKuido7NS3oGzsqNoDmdE6iRXueECVyB"},
+	}
+	for _, test := range tests {
+		b := NewCrossChainBridge("COLX", true)
+		b.ChainConfig = &tokens.ChainConfig{BlockChain: "Colossus", NetID: test.netID}
+		cPkData, err := b.ToCompressedPublicKey(common.FromHex(pubkey))
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2pkh, err := b.NewAddressPubKeyHash(cPkData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2sh, err := btcutil.NewAddressScriptHashFromHash(btcutil.Hash160(cPkData), b.GetChainParams())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p2pkh.EncodeAddress() != test.wantP2pkh || p2sh.EncodeAddress() != test.wantP2sh {
+			t.Fatalf("%v: wrong addresses (%v, %v)", test.netID, p2pkh.EncodeAddress(), p2sh.EncodeAddress())
+		}
+	}
+
+	b := NewCrossChainBridge("Colossus", true)
+	b.ChainConfig = &tokens.ChainConfig{BlockChain: "Colossus", NetID: netMainnet}
+	pkScript, err := b.GetPayToAddrScript(tests[0].wantP2pkh)
+	if err != nil {
+		t.Fatal(err)
+	}
+	memoScript, _ := b.NullDataScript("SWAPTX:0x1234")
+	txIn, err := b.NewTxIn(prevTxid, 1, pkScript)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := b.NewMsgTx([]*wire.TxIn{txIn}, []*wire.TxOut{b.NewTxOut(150000000, pkScript), b.NewTxOut(0, memoScript)}, 0)
+	var buf bytes.Buffer
+	if err = tx.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if rawTx := hex.EncodeToString(buf.Bytes()); rawTx != wantRawTx {
+		t.Fatalf("wrong raw tx %v", rawTx)
+	}
+	sigHash, err := b.CalcSignatureHash(pkScript, tx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(sigHash) != wantHash {
+		t.Fatalf("wrong sighash %x", sigHash)
+	}
+}
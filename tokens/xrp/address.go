@@ -0,0 +1,82 @@
+package xrp
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"errors"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	// alphabet of XRP base58, it's a permutation of bitcoin base58 alphabet
+	xrpAlphabet = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+	btcAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	accountIDVersion = 0x00
+	accountIDLength  = 20
+)
+
+var (
+	errInvalidAddress = errors.New("invalid xrp address")
+
+	toXrpAlphabet = strings.NewReplacer(pairs(btcAlphabet, xrpAlphabet)...)
+	toBtcAlphabet = strings.NewReplacer(pairs(xrpAlphabet, btcAlphabet)...)
+)
+
+func pairs(from, to string) []string {
+	result := make([]string, 0, 2*len(from))
+	for i := 0; i < len(from); i++ {
+		result = append(result, from[i:i+1], to[i:i+1])
+	}
+	return result
+}
+
+// AccountID 20 bytes account id
+type AccountID [accountIDLength]byte
+
+// IsValidAddress check address
+func (b *Bridge) IsValidAddress(address string) bool {
+	_, err := DecodeAddress(address)
+	return err == nil
+}
+
+// DecodeAddress decode classic address (base58check of account id)
+func DecodeAddress(address string) (accountID AccountID, err error) {
+	data := base58.Decode(toBtcAlphabet.Replace(address))
+	if len(data) != 1+accountIDLength+4 || data[0] != accountIDVersion {
+		return accountID, errInvalidAddress
+	}
+	payload, checksum := data[:1+accountIDLength], data[1+accountIDLength:]
+	if !bytes.Equal(calcChecksum(payload), checksum) {
+		return accountID, errInvalidAddress
+	}
+	copy(accountID[:], payload[1:])
+	return accountID, nil
+}
+
+// EncodeAddress encode account id to classic address
+func EncodeAddress(accountID AccountID) string {
+	payload := make([]byte, 0, 1+accountIDLength+4)
+	payload = append(payload, accountIDVersion)
+	payload = append(payload, accountID[:]...)
+	payload = append(payload, calcChecksum(payload)...)
+	return toXrpAlphabet.Replace(base58.Encode(payload))
+}
+
+// PublicKeyToAddress account id is hash160 of compressed public key
+func PublicKeyToAddress(pubKey *ecdsa.PublicKey) string {
+	var accountID AccountID
+	copy(accountID[:], btcutil.Hash160(crypto.CompressPubkey(pubKey)))
+	return EncodeAddress(accountID)
+}
+
+func calcChecksum(payload []byte) []byte {
+	first := sha256.Sum256(payload)
+	second := sha256.Sum256(first[:])
+	return second[:4]
+}
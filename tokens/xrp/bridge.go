@@ -0,0 +1,157 @@
+// Package xrp implements the bridge interfaces for XRP Ledger.
+//
+// Swapin is a payment of XRP to the dcrm account (which is also the deposit
+// address), the bind address is identified by the destination tag of the
+// payment, which is registered in advance and stored in database.
+// The delivered amount in tx metadata is verified as swap value
+// (not the 'Amount' field, which may be a partial payment).
+// Swapout is a payment from the dcrm account signed with secp256k1 key,
+// the account sequence is managed as nonce (see 'tokens.NonceSetter').
+// Chain data are queried from rippled json-rpc api.
+package xrp
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	// BlockChain block chain name of XRP Ledger
+	BlockChain = "XRP"
+
+	netMainnet = "mainnet"
+	netTestnet = "testnet"
+	netDevnet  = "devnet"
+	netCustom  = "custom"
+
+	xrpDecimals = 6
+)
+
+// network ids of rippled 'server_state'
+var networkIDs = map[string]uint32{
+	netMainnet: 0,
+	netTestnet: 1,
+	netDevnet:  2,
+}
+
+// Bridge xrp bridge
+type Bridge struct {
+	*tokens.CrossChainBridgeBase
+	*tools.SrcNonceSetterBase
+}
+
+func init() {
+	tokens.RegisterBridgeFactory(BlockChain, func(isSrc bool) tokens.CrossChainBridge {
+		return NewCrossChainBridge(isSrc)
+	})
+}
+
+// NewCrossChainBridge new xrp bridge
+func NewCrossChainBridge(isSrc bool) *Bridge {
+	if !isSrc {
+		log.Fatalf("xrp::NewCrossChainBridge error %v", tokens.ErrBridgeDestinationNotSupported)
+	}
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc),
+		SrcNonceSetterBase:   tools.NewSrcNonceSetterBase(),
+	}
+}
+
+// IsXrpBridge is xrp bridge
+func IsXrpBridge(bridge tokens.CrossChainBridge) bool {
+	_, ok := bridge.(*Bridge)
+	return ok
+}
+
+// SetChainAndGateway set chain and gateway config
+func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	b.VerifyChainID()
+	b.InitLatestBlockNumber()
+}
+
+// VerifyChainID verify network id of gateway with 'NetID'
+func (b *Bridge) VerifyChainID() {
+	networkID := strings.ToLower(b.ChainConfig.NetID)
+	wantID, exist := networkIDs[networkID]
+	if !exist {
+		if networkID != netCustom {
+			log.Fatalf("unsupported xrp network: %v", b.ChainConfig.NetID)
+		}
+		return
+	}
+	var (
+		state *ServerStateResult
+		err   error
+	)
+	for i := 0; i < 5; i++ {
+		state, err = b.GetServerState()
+		if err == nil {
+			break
+		}
+		log.Errorf("can not get gateway server state. %v", err)
+		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		time.Sleep(1 * time.Second)
+	}
+	if err != nil {
+		log.Fatal("get server state failed", "err", err)
+	}
+	if haveID := state.GetNetworkID(); haveID != wantID {
+		log.Fatalf("gateway network id %v is not %v of %v", haveID, wantID, b.ChainConfig.NetID)
+	}
+	log.Info("VerifyChainID succeed", "NetID", b.ChainConfig.NetID, "version", state.State.BuildVersion)
+}
+
+// VerifyTokenConfig verify token config
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	if !b.IsValidAddress(tokenCfg.DcrmAddress) {
+		return fmt.Errorf("invalid dcrm address: %v", tokenCfg.DcrmAddress)
+	}
+	if tokenCfg.DepositAddress != tokenCfg.DcrmAddress {
+		return fmt.Errorf("deposit address %v is not the dcrm address %v", tokenCfg.DepositAddress, tokenCfg.DcrmAddress)
+	}
+	if tokenCfg.ContractAddress != "" {
+		return fmt.Errorf("xrp bridge does not support contract address: %v", tokenCfg.ContractAddress)
+	}
+	if *tokenCfg.Decimals != xrpDecimals {
+		return fmt.Errorf("invalid decimals: want %v but have %v", xrpDecimals, *tokenCfg.Decimals)
+	}
+	return b.verifyDcrmPublicKey(tokenCfg)
+}
+
+func (b *Bridge) verifyDcrmPublicKey(tokenCfg *tokens.TokenConfig) error {
+	if tokenCfg.DcrmPubkey == "" {
+		return nil
+	}
+	pubKey, err := crypto.UnmarshalPubkey(common.FromHex(tokenCfg.DcrmPubkey))
+	if err != nil {
+		return fmt.Errorf("wrong dcrm public key: %w", err)
+	}
+	address := PublicKeyToAddress(pubKey)
+	if address != tokenCfg.DcrmAddress {
+		return fmt.Errorf("dcrm address %v and public key address %v is not match", tokenCfg.DcrmAddress, address)
+	}
+	return nil
+}
+
+// InitLatestBlockNumber init latest block number
+func (b *Bridge) InitLatestBlockNumber() {
+	for {
+		latest, err := b.GetLatestBlockNumber()
+		if err == nil {
+			tokens.SetLatestBlockHeight(latest, b.IsSrc)
+			log.Info("get latst block number succeed.", "number", latest, "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID)
+			break
+		}
+		log.Error("get latst block number failed.", "BlockChain", b.ChainConfig.BlockChain, "NetID", b.ChainConfig.NetID, "err", err)
+		log.Println("retry query gateway", b.GatewayConfig.APIAddress)
+		time.Sleep(3 * time.Second)
+	}
+}
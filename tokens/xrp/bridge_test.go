@@ -0,0 +1,518 @@
+package xrp
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+func newTestBridge(urls ...string) *Bridge {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{
+		BlockChain: BlockChain,
+		NetID:      netCustom,
+	}
+	b.GatewayConfig = &tokens.GatewayConfig{
+		APIAddress: urls,
+	}
+	return b
+}
+
+// private key of genesis account (secret of 'masterpassphrase')
+const testGenesisPrivKey = "1ACAAEDECE405B2A958212629E16F2EB46B153EEE94CDD350FDEFF52795525B7"
+
+func TestAddress(t *testing.T) {
+	b := newTestBridge()
+	var accountID AccountID
+	if address := EncodeAddress(accountID); address != "rrrrrrrrrrrrrrrrrrrrrhoLvTp" {
+		t.Fatalf("wrong address of zero account %v", address)
+	}
+	accountID[len(accountID)-1] = 1
+	if address := EncodeAddress(accountID); address != "rrrrrrrrrrrrrrrrrrrrBZbvji" {
+		t.Fatalf("wrong address of account one %v", address)
+	}
+
+	// genesis account
+	pubKey, err := crypto.DecompressPubkey(common.FromHex("0330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := PublicKeyToAddress(pubKey)
+	if address != "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh" || !b.IsValidAddress(address) {
+		t.Fatalf("wrong address of genesis account %v", address)
+	}
+	decoded, err := DecodeAddress(address)
+	if err != nil || hex.EncodeToString(decoded[:]) != "b5f762798a53d543a014caf8b297cff8f2f937e8" {
+		t.Fatalf("wrong decoded account id %x, err %v", decoded, err)
+	}
+
+	invalids := []string{
+		"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTj", // wrong checksum
+		"1Hb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+		"0x1111111111111111111111111111111111111111",
+		"",
+	}
+	for _, addr := range invalids {
+		if b.IsValidAddress(addr) {
+			t.Fatalf("address %v should be invalid", addr)
+		}
+	}
+}
+
+var testDestinationTag = uint32(305419896)
+
+// known answers of payment txs, the first one is in mainnet ledger 6000000,
+// and the second one is encoded by xrpl-go binary codec.
+var testPaymentTxs = []struct {
+	account        string
+	destination    string
+	destinationTag *uint32
+	amount         uint64
+	fee            uint64
+	sequence       uint32
+	flags          uint32
+	memo           string
+	signingPubKey  string
+	txnSignature   string
+	signingBlob    string
+	blob           string
+	hash           string
+}{
+	{
+		account:       "r3kmLJN5D28dHuH8vZNUZpMC43pEHpaocV",
+		destination:   "rLQBHVhFnaC5gLEkgr6HgBJJ3bgeZHg9cj",
+		amount:        10000000000,
+		fee:           10,
+		sequence:      62,
+		signingPubKey: "034AADB09CFF4A4804073701EC53C3510CDC95917C2BB0150FB742D0C66E6CEE9E",
+		txnSignature:  "3045022022EB32AECEF7C644C891C19F87966DF9C62B1F34BABA6BE774325E4BB8E2DD62022100A51437898C28C2B297112DF8131F2BB39EA5FE613487DDD611525F1796264639",
+		signingBlob:   "1200002200000000240000003E6140000002540BE40068400000000000000A7321034AADB09CFF4A4804073701EC53C3510CDC95917C2BB0150FB742D0C66E6CEE9E8114550FC62003E785DC231A1058A05E56E3F09CF4E68314D4CC8AB5B21D86A82C3E9E8D0ECF2404B77FECBA",
+		blob:          "1200002200000000240000003E6140000002540BE40068400000000000000A7321034AADB09CFF4A4804073701EC53C3510CDC95917C2BB0150FB742D0C66E6CEE9E74473045022022EB32AECEF7C644C891C19F87966DF9C62B1F34BABA6BE774325E4BB8E2DD62022100A51437898C28C2B297112DF8131F2BB39EA5FE613487DDD611525F17962646398114550FC62003E785DC231A1058A05E56E3F09CF4E68314D4CC8AB5B21D86A82C3E9E8D0ECF2404B77FECBA",
+		hash:          "3B1A4E1C9BB6A7208EB146BCDB86ECEA6068ED01466D933528CA2B4C64F753EF",
+	},
+	{
+		account:        "r3kmLJN5D28dHuH8vZNUZpMC43pEHpaocV",
+		destination:    "rLQBHVhFnaC5gLEkgr6HgBJJ3bgeZHg9cj",
+		destinationTag: &testDestinationTag,
+		amount:         123456789,
+		fee:            12,
+		sequence:       62,
+		flags:          tfFullyCanonicalSig,
+		memo:           "SWAPTX:0x1234",
+		signingPubKey:  "034AADB09CFF4A4804073701EC53C3510CDC95917C2BB0150FB742D0C66E6CEE9E",
+		txnSignature:   "3045022022EB32AECEF7C644C891C19F87966DF9C62B1F34BABA6BE774325E4BB8E2DD62022100A51437898C28C2B297112DF8131F2BB39EA5FE613487DDD611525F1796264639",
+		signingBlob:    "1200002280000000240000003E2E123456786140000000075BCD1568400000000000000C7321034AADB09CFF4A4804073701EC53C3510CDC95917C2BB0150FB742D0C66E6CEE9E8114550FC62003E785DC231A1058A05E56E3F09CF4E68314D4CC8AB5B21D86A82C3E9E8D0ECF2404B77FECBAF9EA7D0D5357415054583A307831323334E1F1",
+		blob:           "1200002280000000240000003E2E123456786140000000075BCD1568400000000000000C7321034AADB09CFF4A4804073701EC53C3510CDC95917C2BB0150FB742D0C66E6CEE9E74473045022022EB32AECEF7C644C891C19F87966DF9C62B1F34BABA6BE774325E4BB8E2DD62022100A51437898C28C2B297112DF8131F2BB39EA5FE613487DDD611525F17962646398114550FC62003E785DC231A1058A05E56E3F09CF4E68314D4CC8AB5B21D86A82C3E9E8D0ECF2404B77FECBAF9EA7D0D5357415054583A307831323334E1F1",
+		hash:           "1EAFE7F52CA89ACDEF806AE3D34E7AE8279F55B3A9C1724D917DA1AA0F749437",
+	},
+}
+
+func TestTransactionEncoding(t *testing.T) {
+	for i, test := range testPaymentTxs {
+		account, err := DecodeAddress(test.account)
+		if err != nil {
+			t.Fatal(err)
+		}
+		destination, err := DecodeAddress(test.destination)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := &Payment{
+			Account:        account,
+			Destination:    destination,
+			DestinationTag: test.destinationTag,
+			Amount:         test.amount,
+			Fee:            test.fee,
+			Sequence:       test.sequence,
+			Flags:          test.flags,
+			SigningPubKey:  common.FromHex(test.signingPubKey),
+		}
+		if test.memo != "" {
+			tx.Memos = [][]byte{[]byte(test.memo)}
+		}
+		if have := strings.ToUpper(hex.EncodeToString(tx.serialize(false))); have != test.signingBlob {
+			t.Fatalf("test %v: wrong signing encoding\nhave %v\nwant %v", i, have, test.signingBlob)
+		}
+		tx.TxnSignature = common.FromHex(test.txnSignature)
+		if have := strings.ToUpper(hex.EncodeToString(tx.Marshal())); have != test.blob {
+			t.Fatalf("test %v: wrong tx blob\nhave %v\nwant %v", i, have, test.blob)
+		}
+		if tx.Hash() != test.hash {
+			t.Fatalf("test %v: wrong tx hash %v, want %v", i, tx.Hash(), test.hash)
+		}
+	}
+
+	// the signature of the mainnet tx is valid (it has high s as signed before it is forbidden)
+	tx := testPaymentTxs[0]
+	rs := parseDERSignature(t, common.FromHex(tx.txnSignature))
+	lowS := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(rs[32:]))
+	copy(rs[32:], common.LeftPadBytes(lowS.Bytes(), 32))
+	account, _ := DecodeAddress(tx.account)
+	destination, _ := DecodeAddress(tx.destination)
+	msgHash := (&Payment{
+		Account:       account,
+		Destination:   destination,
+		Amount:        tx.amount,
+		Fee:           tx.fee,
+		Sequence:      tx.sequence,
+		SigningPubKey: common.FromHex(tx.signingPubKey),
+	}).SignHash()
+	if !crypto.VerifySignature(common.FromHex(tx.signingPubKey), msgHash[:], rs) {
+		t.Fatal("verify signature of mainnet tx failed")
+	}
+}
+
+// parseDERSignature parse DER signature to 'r || s'
+func parseDERSignature(t *testing.T, sig []byte) []byte {
+	if len(sig) < 8 || sig[0] != 0x30 || int(sig[1]) != len(sig)-2 || sig[2] != 0x02 {
+		t.Fatalf("wrong DER signature %x", sig)
+	}
+	rLen := int(sig[3])
+	r := sig[4 : 4+rLen]
+	if sig[4+rLen] != 0x02 || 6+rLen+int(sig[5+rLen]) != len(sig) {
+		t.Fatalf("wrong DER signature %x", sig)
+	}
+	s := sig[6+rLen:]
+	rs := common.LeftPadBytes(new(big.Int).SetBytes(r).Bytes(), 32)
+	return append(rs, common.LeftPadBytes(new(big.Int).SetBytes(s).Bytes(), 32)...)
+}
+
+// payment signed by the genesis key, which is signed and encoded by xrpl-go
+var testSignedPayment = struct {
+	destination    string
+	destinationTag uint32
+	amount         uint64
+	fee            uint64
+	sequence       uint32
+	memo           string
+	txnSignature   string
+	blob           string
+	hash           string
+}{
+	destination:    "rLQBHVhFnaC5gLEkgr6HgBJJ3bgeZHg9cj",
+	destinationTag: testDestinationTag,
+	amount:         1000000,
+	fee:            12,
+	sequence:       7,
+	memo:           "SWAPTX:0x5678",
+	txnSignature:   "30440220507C827375B03CB2726090086857E33536F253EF51D9FCAB764982F7D61653990220423477304B8D18F1D0008890BE2AB873F8BB1BABBFC64A4F774F7096A451747F",
+	blob:           "120000228000000024000000072E123456786140000000000F424068400000000000000C73210330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020744630440220507C827375B03CB2726090086857E33536F253EF51D9FCAB764982F7D61653990220423477304B8D18F1D0008890BE2AB873F8BB1BABBFC64A4F774F7096A451747F8114B5F762798A53D543A014CAF8B297CFF8F2F937E88314D4CC8AB5B21D86A82C3E9E8D0ECF2404B77FECBAF9EA7D0D5357415054583A307835363738E1F1",
+	hash:           "9A8BB8A8FA30FF8405725642C246FDE0CC4D6C88526F869BDBC847C0E5E48B55",
+}
+
+func TestSignTransaction(t *testing.T) {
+	rippled := newTestRippled(t)
+	defer rippled.Close()
+	b := newTestBridge(rippled.URL)
+	b.GatewayConfig.APIAddressExt = []string{rippled.URL}
+
+	privKey, err := crypto.HexToECDSA(testGenesisPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := PublicKeyToAddress(&privKey.PublicKey)
+	if signer != "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh" {
+		t.Fatalf("wrong signer %v", signer)
+	}
+	test := testSignedPayment
+	account, _ := DecodeAddress(signer)
+	destination, _ := DecodeAddress(test.destination)
+	destinationTag := test.destinationTag
+	tx := &Payment{
+		Account:        account,
+		Destination:    destination,
+		DestinationTag: &destinationTag,
+		Amount:         test.amount,
+		Fee:            test.fee,
+		Sequence:       test.sequence,
+		Flags:          tfFullyCanonicalSig,
+		Memos:          [][]byte{[]byte(test.memo)},
+		SigningPubKey:  crypto.CompressPubkey(&privKey.PublicKey),
+	}
+	if err = b.VerifyMsgHash(tx, []string{tx.SignHash().String()}); err != nil {
+		t.Fatal(err)
+	}
+	_, txHash, err := b.SignTransactionWithPrivateKey(tx, privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have := strings.ToUpper(hex.EncodeToString(tx.TxnSignature)); have != test.txnSignature {
+		t.Fatalf("wrong signature\nhave %v\nwant %v", have, test.txnSignature)
+	}
+	if txHash != test.hash {
+		t.Fatalf("wrong signed tx hash %v, want %v", txHash, test.hash)
+	}
+
+	// dcrm signature may have high s and either recovery id
+	msgHash := tx.SignHash()
+	signature, _ := crypto.Sign(msgHash[:], privKey)
+	highS := new(big.Int).Sub(secp256k1N, new(big.Int).SetBytes(signature[32:64]))
+	copy(signature[32:64], common.LeftPadBytes(highS.Bytes(), 32))
+	signature[64] ^= 0x1
+	tx.TxnSignature = nil
+	if err = signTxWithSignature(tx, signature, signer); err != nil {
+		t.Fatal(err)
+	}
+	if have := strings.ToUpper(hex.EncodeToString(tx.TxnSignature)); have != test.txnSignature {
+		t.Fatalf("convert high s signature failed, have %v", have)
+	}
+	if err = signTxWithSignature(tx, signature, test.destination); err == nil {
+		t.Fatal("sign with wrong signer should fail")
+	}
+
+	// signed tx is submitted to every gateway
+	txHash, err = b.SendTransaction(tx)
+	if err != nil || txHash != test.hash {
+		t.Fatalf("send tx hash %v, err %v", txHash, err)
+	}
+	submits := rippled.getSubmits()
+	if len(submits) != 2 {
+		t.Fatalf("tx is submitted %v times", len(submits))
+	}
+	for _, blob := range submits {
+		if blob != test.blob {
+			t.Fatalf("wrong submitted tx blob\nhave %v\nwant %v", blob, test.blob)
+		}
+	}
+	if len(b.GatewayConfig.APIAddress) != 1 {
+		t.Fatal("gateway urls are modified by submitting")
+	}
+}
+
+func TestParseDrops(t *testing.T) {
+	if drops, err := parseDrops(json.RawMessage(`"1000"`)); err != nil || drops.Uint64() != 1000 {
+		t.Fatalf("parse drops %v, err %v", drops, err)
+	}
+	iou := json.RawMessage(`{"currency":"USD","issuer":"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh","value":"1"}`)
+	if _, err := parseDrops(iou); err != errNotXrpAmount {
+		t.Fatalf("parse issued currency amount should fail, err %v", err)
+	}
+	if _, err := parseDrops(json.RawMessage(`"unavailable"`)); err != errUnavailableDelivered {
+		t.Fatalf("parse unavailable amount should fail, err %v", err)
+	}
+}
+
+// testRippled fake json-rpc of rippled, params is an array of one object,
+// and errors are responded in result with status 'error'.
+type testRippled struct {
+	*httptest.Server
+	t *testing.T
+
+	lock        sync.Mutex
+	ledgerIndex uint64                            // latest validated ledger
+	txs         map[string]map[string]interface{} // txs by upper case hash
+	accounts    map[string]interface{}            // account roots by address
+	submits     []string                          // submitted tx blobs
+}
+
+func newTestRippled(t *testing.T) *testRippled {
+	rippled := &testRippled{
+		t:        t,
+		txs:      make(map[string]map[string]interface{}),
+		accounts: make(map[string]interface{}),
+	}
+	rippled.Server = httptest.NewServer(http.HandlerFunc(rippled.serve))
+	return rippled
+}
+
+func (rippled *testRippled) serve(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Method string                   `json:"method"`
+		Params []map[string]interface{} `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Params) != 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	params := req.Params[0]
+
+	rippled.lock.Lock()
+	var result interface{}
+	switch req.Method {
+	case "ledger":
+		result = map[string]interface{}{
+			"status":       "success",
+			"ledger_hash":  strings.Repeat("AB", 32),
+			"ledger_index": rippled.ledgerIndex,
+			"validated":    true,
+		}
+	case "tx":
+		if tx, exist := rippled.txs[params["transaction"].(string)]; exist {
+			result = tx
+		} else {
+			result = rpcError("txnNotFound")
+		}
+	case "account_info":
+		if accountData, exist := rippled.accounts[params["account"].(string)]; exist {
+			result = map[string]interface{}{"status": "success", "account_data": accountData}
+		} else {
+			result = rpcError("actNotFound")
+		}
+	case "submit":
+		blob := params["tx_blob"].(string)
+		rippled.submits = append(rippled.submits, blob)
+		hash := sha512.Sum512(append([]byte("TXN\x00"), common.FromHex(blob)...))
+		result = map[string]interface{}{
+			"status":        "success",
+			"engine_result": txResultSuccess,
+			"accepted":      true,
+			"tx_json":       map[string]interface{}{"hash": strings.ToUpper(hex.EncodeToString(hash[:32]))},
+		}
+	default:
+		result = rpcError("unknownCmd")
+	}
+	rippled.lock.Unlock()
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"result": result}); err != nil {
+		rippled.t.Error(err)
+	}
+}
+
+func rpcError(err string) interface{} {
+	return map[string]interface{}{"status": "error", "error": err}
+}
+
+func (rippled *testRippled) getSubmits() []string {
+	rippled.lock.Lock()
+	defer rippled.lock.Unlock()
+	return rippled.submits
+}
+
+func newTestStore(t *testing.T) func() {
+	dir, err := ioutil.TempDir("", "xrpstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := mongodb.NewLvlStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := mongodb.GetStore()
+	mongodb.SetStore(s)
+	return func() {
+		if old != nil {
+			mongodb.SetStore(old)
+		}
+		_ = s.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestVerifyPaymentTx(t *testing.T) {
+	defer newTestStore(t)()
+
+	txHash := "E08D6E9754025BA2534A78707605E0601F03ACE063687A0CA1BDDACFCD1698C7"
+	sender := "r3kmLJN5D28dHuH8vZNUZpMC43pEHpaocV"
+	deposit := "rLQBHVhFnaC5gLEkgr6HgBJJ3bgeZHg9cj"
+	bind := "0x3333333333333333333333333333333333333333"
+	err := mongodb.AddDestinationTag(&mongodb.MgoDestinationTag{Key: bind, DestinationTag: 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta := map[string]interface{}{
+		"TransactionResult": txResultSuccess,
+		"delivered_amount":  "1000",
+	}
+	txResult := map[string]interface{}{
+		"status":          "success",
+		"TransactionType": txTypePaymentName,
+		"Account":         sender,
+		"Destination":     deposit,
+		"DestinationTag":  123,
+		"Amount":          "1000000000", // partial payment
+		"Fee":             "12",
+		"Flags":           0x00020000,
+		"Sequence":        7,
+		"hash":            txHash,
+		"ledger_index":    90,
+		"date":            662688000,
+		"meta":            meta,
+		"validated":       true,
+	}
+	rippled := newTestRippled(t)
+	defer rippled.Close()
+	rippled.ledgerIndex = 100
+	rippled.txs[txHash] = txResult
+	rippled.accounts[deposit] = map[string]interface{}{"Account": deposit, "Balance": "50000000", "Sequence": 34}
+	b := newTestBridge(rippled.URL)
+
+	latest, err := b.GetLatestBlockNumber()
+	if err != nil || latest != 100 {
+		t.Fatalf("get latest block number %v, err %v", latest, err)
+	}
+	nonce, err := b.GetPoolNonce(deposit, "latest")
+	if err != nil || nonce != 34 {
+		t.Fatalf("get pool nonce %v, err %v", nonce, err)
+	}
+	receipt, err := b.GetTransactionReceipt(txHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *receipt.Status != 1 || receipt.BlockNumber.ToInt().Uint64() != 90 {
+		t.Fatal("wrong receipt status or block number")
+	}
+	if _, err = b.GetTransactionByHash(testSignedPayment.hash); err == nil {
+		t.Fatal("get unknown tx should fail")
+	}
+	if _, err = b.GetPoolNonce(sender, "latest"); err == nil {
+		t.Fatal("get nonce of unknown account should fail")
+	}
+
+	verify := func(token *tokens.TokenConfig) (*tokens.TxSwapInfo, error) {
+		tx, errf := b.GetTransactionByHash(txHash)
+		if errf != nil {
+			t.Fatal(errf)
+		}
+		swapInfo := &tokens.TxSwapInfo{Hash: txHash}
+		return swapInfo, b.verifyPaymentTx(swapInfo, tx, token)
+	}
+	token := &tokens.TokenConfig{DepositAddress: deposit}
+
+	swapInfo, err := verify(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapInfo.From != sender || swapInfo.To != deposit || swapInfo.Bind != bind {
+		t.Fatalf("wrong swapin sender %v, receiver %v or bind %v", swapInfo.From, swapInfo.To, swapInfo.Bind)
+	}
+	if swapInfo.Value.Cmp(big.NewInt(1000)) != 0 || swapInfo.Height != 90 || swapInfo.Timestamp != 1609372800 {
+		t.Fatalf("wrong swapin value %v, height %v or timestamp %v", swapInfo.Value, swapInfo.Height, swapInfo.Timestamp)
+	}
+
+	if _, err = verify(&tokens.TokenConfig{DepositAddress: sender}); err != tokens.ErrTxWithWrongReceiver {
+		t.Fatalf("verify with wrong deposit address should fail, err %v", err)
+	}
+
+	txResult["DestinationTag"] = 456
+	if swapInfo, err = verify(token); err != nil || swapInfo.Bind != "" {
+		t.Fatalf("unregistered destination tag should have no bind address, bind %v, err %v", swapInfo.Bind, err)
+	}
+
+	meta["delivered_amount"] = map[string]interface{}{"currency": "USD", "issuer": sender, "value": "1"}
+	if _, err = verify(token); err != tokens.ErrTxWithWrongValue {
+		t.Fatalf("verify issued currency payment should fail, err %v", err)
+	}
+
+	meta["TransactionResult"] = "tecPATH_PARTIAL"
+	if _, err = verify(token); err != tokens.ErrTxWithWrongReceipt {
+		t.Fatalf("verify failed payment should fail, err %v", err)
+	}
+
+	txResult["validated"] = false
+	if _, err = verify(token); err != tokens.ErrTxNotStable {
+		t.Fatalf("verify unvalidated payment should fail, err %v", err)
+	}
+}
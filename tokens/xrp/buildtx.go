@@ -0,0 +1,199 @@
+package xrp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	// max fee of swap tx, open ledger fee may be very high when the ledger is busy
+	maxFee uint64 = 100000 // 0.1 XRP
+)
+
+var (
+	errEmptyIdentifier        = errors.New("build swaptx without identifier")
+	errNoSenderSpecified      = errors.New("build swaptx without specify sender")
+	errNonzeroValueSpecified  = errors.New("build swap tx with non-zero value")
+	errInvalidReceiverAddress = errors.New("invalid receiver address")
+	errNoPublicKey            = errors.New("no dcrm public key or private key")
+)
+
+// BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	err = b.checkBuildTxArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	token := b.GetTokenConfig(args.PairID)
+	if token == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	from, err := DecodeAddress(args.From)
+	if err != nil {
+		return nil, errNoSenderSpecified
+	}
+	receiver, err := DecodeAddress(args.Bind)
+	if err != nil {
+		log.Warn("swapout to wrong address", "receiver", args.Bind)
+		return nil, errInvalidReceiverAddress
+	}
+
+	swapValue := tokens.CalcSwappedValue(args.PairID, args.OriginValue, false)
+	if !swapValue.IsUint64() || swapValue.Uint64() > maxDrops {
+		return nil, tokens.ErrWrongSwapValue
+	}
+	args.SwapValue = swapValue // swap value
+	args.To = args.Bind        // to
+	args.Value = swapValue     // value
+
+	extra, err := b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := b.getSenderPublicKey(token)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := &Payment{
+		Account:       from,
+		Destination:   receiver,
+		Amount:        swapValue.Uint64(),
+		Fee:           *extra.Fee,
+		Sequence:      *extra.Sequence,
+		Flags:         tfFullyCanonicalSig,
+		Memos:         [][]byte{[]byte(tokens.UnlockMemoPrefix + args.SwapID)},
+		SigningPubKey: pubKey,
+	}
+
+	err = b.checkBalance(args.From, args.Bind, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Info("build raw tx", "pairID", args.PairID, "identifier", args.Identifier,
+		"swapID", args.SwapID, "swapType", args.SwapType,
+		"bind", args.Bind, "originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"from", args.From, "to", args.To, "value", args.Value,
+		"sequence", tx.Sequence, "fee", tx.Fee, "msghash", tx.SignHash().String())
+
+	return tx, nil
+}
+
+func (b *Bridge) checkBuildTxArgs(args *tokens.BuildTxArgs) error {
+	if args.Identifier == "" {
+		return errEmptyIdentifier
+	}
+	if args.From == "" {
+		return errNoSenderSpecified
+	}
+	if args.Value != nil && args.Value.Sign() != 0 {
+		return errNonzeroValueSpecified
+	}
+
+	switch args.SwapType {
+	case tokens.SwapoutType:
+		if !b.IsSrc {
+			return tokens.ErrBuildSwapTxInWrongEndpoint
+		}
+	case tokens.SwapinType:
+		return tokens.ErrBuildSwapTxInWrongEndpoint
+	default:
+		return tokens.ErrUnknownSwapType
+	}
+
+	return nil
+}
+
+// setDefaults sequence and fee are set if not specified,
+// and then recorded in extra args to rebuild the same tx.
+func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (extra *tokens.XrpExtraArgs, err error) {
+	if args.Extra == nil || args.Extra.XrpExtra == nil {
+		extra = &tokens.XrpExtraArgs{}
+		args.Extra = &tokens.AllExtras{XrpExtra: extra}
+	} else {
+		extra = args.Extra.XrpExtra
+	}
+	if extra.Sequence == nil {
+		info, err := b.GetAccountInfo(args.From)
+		if err != nil {
+			return nil, err
+		}
+		nonce := b.AdjustNonce(args.PairID, uint64(info.AccountData.Sequence))
+		if nonce > math.MaxUint32 {
+			return nil, fmt.Errorf("sequence %v overflow", nonce)
+		}
+		sequence := uint32(nonce)
+		extra.Sequence = &sequence
+	}
+	if extra.Fee == nil {
+		fee, err := b.GetFee()
+		if err != nil {
+			return nil, err
+		}
+		if fee > maxFee {
+			log.Warn("open ledger fee is too high, use max fee", "fee", fee, "maxFee", maxFee)
+			fee = maxFee
+		}
+		extra.Fee = &fee
+	}
+	return extra, nil
+}
+
+// getSenderPublicKey get compressed public key of dcrm address,
+// from dcrm public key or private key (if sign with private key)
+func (b *Bridge) getSenderPublicKey(token *tokens.TokenConfig) ([]byte, error) {
+	if token.DcrmPubkey != "" {
+		pubKey, err := crypto.UnmarshalPubkey(common.FromHex(token.DcrmPubkey))
+		if err != nil {
+			return nil, fmt.Errorf("wrong dcrm public key: %w", err)
+		}
+		return crypto.CompressPubkey(pubKey), nil
+	}
+	if privKey := token.GetDcrmAddressPrivateKey(); privKey != nil {
+		return crypto.CompressPubkey(&privKey.PublicKey), nil
+	}
+	return nil, errNoPublicKey
+}
+
+// checkBalance sender should keep the account reserve after payment,
+// and payment to a nonexistent account should fund at least base reserve,
+// otherwise the tx fails with 'tec' code (fee and sequence are consumed).
+func (b *Bridge) checkBalance(sender, receiver string, tx *Payment) error {
+	state, err := b.GetServerState()
+	if err != nil {
+		return err
+	}
+	ledger := state.State.ValidatedLedger
+
+	info, err := b.GetAccountInfo(sender)
+	if err != nil {
+		log.Warn("get account info error", "account", sender, "err", err)
+		return err
+	}
+	balance, err := parseUint(info.AccountData.Balance)
+	if err != nil {
+		return err
+	}
+	reserve := ledger.ReserveBase + uint64(info.AccountData.OwnerCount)*ledger.ReserveInc
+	if need := tx.Amount + tx.Fee + reserve; balance < need {
+		return fmt.Errorf("not enough XRP balance. %v < %v (reserve %v)", balance, need, reserve)
+	}
+
+	_, err = b.GetAccountInfo(receiver)
+	switch {
+	case errors.Is(err, errAccountNotFound):
+		if tx.Amount < ledger.ReserveBase {
+			return fmt.Errorf("payment %v is less than base reserve %v to create account %v", tx.Amount, ledger.ReserveBase, receiver)
+		}
+	case err != nil:
+		return err
+	}
+	return nil
+}
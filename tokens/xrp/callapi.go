@@ -0,0 +1,287 @@
+package xrp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const rpcTimeout = 10 // seconds
+
+var (
+	errEmptyURLs       = errors.New("empty URLs")
+	errAccountNotFound = errors.New("account not found")
+)
+
+// callRPC call rippled json-rpc method, params is one json object
+// (request is in the rippled format, without 'jsonrpc' and 'id' fields,
+// errors are returned in result with status 'error').
+func callRPC(result rpcResult, url, method string, params interface{}) error {
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	reqBody := map[string]interface{}{
+		"method": method,
+		"params": []interface{}{params},
+	}
+	resp, err := client.HTTPPost(url, reqBody, nil, nil, rpcTimeout)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	const maxReadContentLength int64 = 1024 * 1024 * 10 // 10M
+	body, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxReadContentLength))
+	if err != nil {
+		return fmt.Errorf("read body error: %w", err)
+	}
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("wrong response status %v. message: %v", resp.StatusCode, string(body))
+	}
+	var rpcResp struct {
+		Result json.RawMessage `json:"result"`
+	}
+	err = json.Unmarshal(body, &rpcResp)
+	if err == nil {
+		err = json.Unmarshal(rpcResp.Result, result)
+	}
+	if err != nil {
+		return fmt.Errorf("unmarshal result error: %w", err)
+	}
+	return result.err()
+}
+
+// callRPCOfGateway call rpc of gateway urls until success
+func (b *Bridge) callRPCOfGateway(result rpcResult, method string, params interface{}) (err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return errEmptyURLs
+	}
+	for _, url := range urls {
+		err = callRPC(result, url, method, params)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// GetServerState call 'server_state'
+func (b *Bridge) GetServerState() (*ServerStateResult, error) {
+	var result ServerStateResult
+	err := b.callRPCOfGateway(&result, "server_state", nil)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetLatestBlockNumberOf get latest validated ledger index
+func (b *Bridge) GetLatestBlockNumberOf(url string) (uint64, error) {
+	var result LedgerResult
+	err := callRPC(&result, url, "ledger", map[string]interface{}{"ledger_index": "validated"})
+	if err != nil {
+		return 0, err
+	}
+	return result.LedgerIndex, nil
+}
+
+// GetLatestBlockNumber get max latest validated ledger index of gateways
+func (b *Bridge) GetLatestBlockNumber() (maxHeight uint64, err error) {
+	urls := b.GatewayConfig.APIAddress
+	if len(urls) == 0 {
+		return 0, errEmptyURLs
+	}
+	for _, url := range urls {
+		height, errf := b.GetLatestBlockNumberOf(url)
+		if errf != nil {
+			err = errf
+			continue
+		}
+		if height > maxHeight {
+			maxHeight = height
+		}
+	}
+	if maxHeight > 0 {
+		tokens.CmpAndSetLatestBlockHeight(maxHeight, b.IsSrcEndpoint())
+		return maxHeight, nil
+	}
+	return 0, err
+}
+
+// GetBlockByNumber get validated ledger with expanded txs
+func (b *Bridge) GetBlockByNumber(number uint64) (*LedgerResult, error) {
+	var result LedgerResult
+	params := map[string]interface{}{
+		"ledger_index": number,
+		"transactions": true,
+		"expand":       true,
+	}
+	err := b.callRPCOfGateway(&result, "ledger", params)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Validated || result.LedgerIndex != number {
+		return nil, fmt.Errorf("validated ledger %v not found", number)
+	}
+	return &result, nil
+}
+
+// GetBlockHash get ledger hash
+func (b *Bridge) GetBlockHash(number uint64) (string, error) {
+	var result LedgerResult
+	err := b.callRPCOfGateway(&result, "ledger", map[string]interface{}{"ledger_index": number})
+	if err != nil {
+		return "", err
+	}
+	return result.LedgerHash, nil
+}
+
+// GetTransactionByHash call 'tx'
+func (b *Bridge) GetTransactionByHash(txHash string) (*TxResult, error) {
+	var result TxResult
+	err := b.callRPCOfGateway(&result, "tx", map[string]interface{}{"transaction": strings.ToUpper(txHash)})
+	if err != nil {
+		if result.Error == "txnNotFound" {
+			return nil, tokens.ErrTxNotFound
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetTransactionReceipt get tx result in form of ethereum receipt,
+// status is 1 if tx is validated with result 'tesSUCCESS'.
+func (b *Bridge) GetTransactionReceipt(txHash string) (*types.RPCTxReceipt, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if !tx.Validated {
+		return nil, tokens.ErrTxNotStable
+	}
+	return toReceipt(tx), nil
+}
+
+func toReceipt(tx *TxResult) *types.RPCTxReceipt {
+	status := hexutil.Uint64(0)
+	if tx.IsSuccess() {
+		status = 1
+	}
+	txHash := common.HexToHash(tx.Hash)
+	blockNumber := (*hexutil.Big)(new(big.Int).SetUint64(tx.LedgerIndex))
+	return &types.RPCTxReceipt{
+		TxHash:      &txHash,
+		BlockNumber: blockNumber,
+		Status:      &status,
+	}
+}
+
+// GetAccountInfo call 'account_info' of current ledger
+func (b *Bridge) GetAccountInfo(address string) (*AccountInfoResult, error) {
+	var result AccountInfoResult
+	params := map[string]interface{}{
+		"account":      address,
+		"ledger_index": "current",
+	}
+	err := b.callRPCOfGateway(&result, "account_info", params)
+	if err != nil {
+		if result.Error == "actNotFound" {
+			return nil, errAccountNotFound
+		}
+		return nil, err
+	}
+	return &result, nil
+}
+
+// GetBalance get XRP balance in drops
+func (b *Bridge) GetBalance(account string) (*big.Int, error) {
+	info, err := b.GetAccountInfo(account)
+	if err != nil {
+		return nil, err
+	}
+	balance, ok := new(big.Int).SetString(info.AccountData.Balance, 10)
+	if !ok {
+		return nil, fmt.Errorf("wrong balance %v", info.AccountData.Balance)
+	}
+	return balance, nil
+}
+
+// GetTokenBalance not supported (only XRP is supported)
+func (b *Bridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
+	return nil, fmt.Errorf("[%v] can not get token balance of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
+}
+
+// GetTokenSupply not supported (only XRP is supported)
+func (b *Bridge) GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error) {
+	return nil, fmt.Errorf("[%v] can not get token supply of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
+}
+
+// GetFee call 'fee', get open ledger fee (at least base fee) in drops
+func (b *Bridge) GetFee() (uint64, error) {
+	var result FeeResult
+	err := b.callRPCOfGateway(&result, "fee", nil)
+	if err != nil {
+		return 0, err
+	}
+	baseFee, err := parseUint(result.Drops.BaseFee)
+	if err != nil {
+		return 0, err
+	}
+	openLedgerFee, err := parseUint(result.Drops.OpenLedgerFee)
+	if err != nil {
+		return 0, err
+	}
+	if openLedgerFee < baseFee {
+		return baseFee, nil
+	}
+	return openLedgerFee, nil
+}
+
+// SubmitTransaction call 'submit' of all gateway urls
+func (b *Bridge) SubmitTransaction(tx *Payment) (txHash string, err error) {
+	params := map[string]interface{}{
+		"tx_blob": strings.ToUpper(hex.EncodeToString(tx.Marshal())),
+	}
+	gateway := b.GatewayConfig
+	urls := make([]string, 0, len(gateway.APIAddress)+len(gateway.APIAddressExt))
+	urls = append(urls, gateway.APIAddress...)
+	urls = append(urls, gateway.APIAddressExt...)
+	if len(urls) == 0 {
+		return "", errEmptyURLs
+	}
+	success := false
+	for _, url := range urls {
+		var result SubmitResult
+		errf := callRPC(&result, url, "submit", params)
+		switch {
+		case errf != nil:
+			err = errf
+		case !result.IsSubmitted():
+			err = fmt.Errorf("submit tx failed, %v: %v", result.EngineResult, result.EngineResultMessage)
+		default:
+			txHash = result.TxJSON.Hash
+			success = true
+		}
+		if err != nil {
+			log.Trace("submit tx error", "url", url, "err", err)
+		}
+	}
+	if success {
+		return txHash, nil
+	}
+	return "", err
+}
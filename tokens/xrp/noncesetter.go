@@ -0,0 +1,20 @@
+package xrp
+
+// GetTxBlockInfo impl NonceSetter interface
+func (b *Bridge) GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil || !tx.Validated {
+		return 0, 0
+	}
+	return tx.LedgerIndex, tx.BlockTime()
+}
+
+// GetPoolNonce impl NonceSetter interface, get account sequence
+// of current (open) ledger, height is ignored
+func (b *Bridge) GetPoolNonce(address, height string) (uint64, error) {
+	info, err := b.GetAccountInfo(address)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(info.AccountData.Sequence), nil
+}
@@ -0,0 +1,182 @@
+package xrp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+)
+
+var (
+	quickSyncFinish  bool
+	quickSyncWorkers = uint64(4)
+
+	maxScanHeight          = uint64(100)
+	retryIntervalInScanJob = 3 * time.Second
+	restIntervalInScanJob  = 3 * time.Second
+)
+
+func (b *Bridge) getStartAndLatestHeight() (start, latest uint64) {
+	startHeight := tools.GetLatestScanHeight(b.IsSrc)
+
+	chainCfg := b.GetChainConfig()
+	confirmations := *chainCfg.Confirmations
+	initialHeight := *chainCfg.InitialHeight
+
+	latest = tools.LoopGetLatestBlockNumber(b)
+
+	switch {
+	case startHeight != 0:
+		start = startHeight
+	case initialHeight != 0:
+		start = initialHeight
+	default:
+		if latest > confirmations {
+			start = latest - confirmations
+		}
+	}
+	if start < initialHeight {
+		start = initialHeight
+	}
+	if start+maxScanHeight < latest {
+		start = latest - maxScanHeight
+	}
+	return start, latest
+}
+
+// StartChainTransactionScanJob scan job
+func (b *Bridge) StartChainTransactionScanJob() {
+	chainName := b.ChainConfig.BlockChain
+	log.Infof("[scanchain] start %v scan chain job", chainName)
+
+	start, latest := b.getStartAndLatestHeight()
+	_ = tools.UpdateLatestScanInfo(b.IsSrc, start)
+	log.Infof("[scanchain] start %v scan chain loop from %v latest=%v", chainName, start, latest)
+
+	if latest > start {
+		go b.quickSync(context.Background(), nil, start, latest+1)
+	} else {
+		quickSyncFinish = true
+	}
+
+	stable := latest
+	errorSubject := fmt.Sprintf("[scanchain] get %v block failed", chainName)
+	scanSubject := fmt.Sprintf("[scanchain] scanned %v block", chainName)
+
+	scannedBlocks := tools.NewCachedScannedBlocks(67)
+	var quickSyncCtx context.Context
+	var quickSyncCancel context.CancelFunc
+	for {
+		latest = tools.LoopGetLatestBlockNumber(b)
+		if stable+maxScanHeight < latest {
+			if quickSyncCancel != nil {
+				select {
+				case <-quickSyncCtx.Done():
+				default:
+					log.Warn("cancel quick sync range", "stable", stable, "latest", latest)
+					quickSyncCancel()
+				}
+			}
+			quickSyncCtx, quickSyncCancel = context.WithCancel(context.Background())
+			go b.quickSync(quickSyncCtx, quickSyncCancel, stable+1, latest)
+			stable = latest
+		}
+		for h := stable; h <= latest; {
+			block, err := b.GetBlockByNumber(h)
+			if err != nil {
+				log.Error(errorSubject, "height", h, "err", err)
+				time.Sleep(retryIntervalInScanJob)
+				continue
+			}
+			blockHash := block.LedgerHash
+			if scannedBlocks.IsBlockScanned(blockHash) {
+				h++
+				continue
+			}
+			b.processBlock(block)
+			scannedBlocks.CacheScannedBlock(blockHash, h)
+			log.Info(scanSubject, "blockHash", blockHash, "height", h, "txs", len(block.Ledger.Transactions))
+			h++
+		}
+		stable = latest
+		if quickSyncFinish {
+			_ = tools.UpdateLatestScanInfo(b.IsSrc, stable)
+		}
+		time.Sleep(restIntervalInScanJob)
+	}
+}
+
+func (b *Bridge) quickSync(ctx context.Context, cancel context.CancelFunc, start, end uint64) {
+	chainName := b.ChainConfig.BlockChain
+	log.Printf("[scanchain] begin %v syncRange job. start=%v end=%v", chainName, start, end)
+	count := end - start
+	workers := quickSyncWorkers
+	if count < 10 {
+		workers = 1
+	}
+	step := count / workers
+	wg := new(sync.WaitGroup)
+	wg.Add(int(workers))
+	for i := uint64(0); i < workers; i++ {
+		wstt := start + i*step
+		wend := start + (i+1)*step
+		if i+1 == workers {
+			wend = end
+		}
+		go b.quickSyncRange(ctx, i+1, wstt, wend, wg)
+	}
+	wg.Wait()
+	if cancel != nil {
+		cancel()
+	} else {
+		quickSyncFinish = true
+	}
+	log.Printf("[scanchain] finish %v syncRange job. start=%v end=%v", chainName, start, end)
+}
+
+func (b *Bridge) quickSyncRange(ctx context.Context, idx, start, end uint64, wg *sync.WaitGroup) {
+	defer wg.Done()
+	chainName := b.ChainConfig.BlockChain
+	log.Printf("[scanchain] id=%v begin %v syncRange start=%v end=%v", idx, chainName, start, end)
+
+QUICK_SYNC_LOOP:
+	for h := start; h < end; {
+		select {
+		case <-ctx.Done():
+			break QUICK_SYNC_LOOP
+		default:
+		}
+		block, err := b.GetBlockByNumber(h)
+		if err != nil {
+			log.Errorf("[scanchain] id=%v get %v block failed at height %v. err=%v", idx, chainName, h, err)
+			time.Sleep(retryIntervalInScanJob)
+			continue
+		}
+		b.processBlock(block)
+		log.Tracef("[scanchain] id=%v scanned %v block, height=%v hash=%v txs=%v", idx, chainName, h, block.LedgerHash, len(block.Ledger.Transactions))
+		h++
+	}
+
+	log.Printf("[scanchain] id=%v finish %v syncRange start=%v end=%v", idx, chainName, start, end)
+}
+
+// processBlock process payment txs to deposit address in expanded ledger
+func (b *Bridge) processBlock(block *LedgerResult) {
+	for _, tx := range block.Ledger.Transactions {
+		if tx.TransactionType != txTypePaymentName {
+			continue
+		}
+		if _, pairIDs := tokens.FindTokenConfig(tx.Destination, b.IsSrc); len(pairIDs) != 0 {
+			tools.ProcessSwapin(tx.Hash, b.verifySwapinTx)
+		}
+	}
+}
+
+// StartPoolTransactionScanJob scan pool job, swapin is verified in validated ledger only
+func (b *Bridge) StartPoolTransactionScanJob() {
+	log.Warnf("[scanpool] %v does not support scan tx pool", b.ChainConfig.BlockChain)
+}
@@ -0,0 +1,96 @@
+package xrp
+
+import (
+	"encoding/binary"
+)
+
+// type codes of XRP Ledger binary format
+const (
+	typeUInt16    = 1
+	typeUInt32    = 2
+	typeAmount    = 6
+	typeBlob      = 7
+	typeAccountID = 8
+	typeSTObject  = 14
+	typeSTArray   = 15
+)
+
+// field ids (type code, field code) used by payment,
+// fields must be serialized in the order of type code and then field code.
+var (
+	fieldTransactionType = fieldID{typeUInt16, 2}
+	fieldFlags           = fieldID{typeUInt32, 2}
+	fieldSequence        = fieldID{typeUInt32, 4}
+	fieldDestinationTag  = fieldID{typeUInt32, 14}
+	fieldAmount          = fieldID{typeAmount, 1}
+	fieldFee             = fieldID{typeAmount, 8}
+	fieldSigningPubKey   = fieldID{typeBlob, 3}
+	fieldTxnSignature    = fieldID{typeBlob, 4}
+	fieldMemoData        = fieldID{typeBlob, 13}
+	fieldAccount         = fieldID{typeAccountID, 1}
+	fieldDestination     = fieldID{typeAccountID, 3}
+	fieldMemo            = fieldID{typeSTObject, 10}
+	fieldMemos           = fieldID{typeSTArray, 9}
+
+	objectEndMarker byte = 0xE1
+	arrayEndMarker  byte = 0xF1
+)
+
+type fieldID struct {
+	typeCode  byte
+	fieldCode byte
+}
+
+func appendFieldID(buf []byte, id fieldID) []byte {
+	switch {
+	case id.typeCode < 16 && id.fieldCode < 16:
+		return append(buf, id.typeCode<<4|id.fieldCode)
+	case id.typeCode < 16:
+		return append(buf, id.typeCode<<4, id.fieldCode)
+	case id.fieldCode < 16:
+		return append(buf, id.fieldCode, id.typeCode)
+	default:
+		return append(buf, 0, id.typeCode, id.fieldCode)
+	}
+}
+
+func appendUInt16(buf []byte, id fieldID, v uint16) []byte {
+	buf = appendFieldID(buf, id)
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUInt32(buf []byte, id fieldID, v uint32) []byte {
+	buf = appendFieldID(buf, id)
+	var data [4]byte
+	binary.BigEndian.PutUint32(data[:], v)
+	return append(buf, data[:]...)
+}
+
+// appendXrpAmount native amount, bit 63 is 0 (not issued currency), bit 62 is 1 (positive)
+func appendXrpAmount(buf []byte, id fieldID, drops uint64) []byte {
+	buf = appendFieldID(buf, id)
+	var data [8]byte
+	binary.BigEndian.PutUint64(data[:], drops|0x4000000000000000)
+	return append(buf, data[:]...)
+}
+
+func appendBlob(buf []byte, id fieldID, data []byte) []byte {
+	buf = appendFieldID(buf, id)
+	buf = appendLengthPrefix(buf, len(data))
+	return append(buf, data...)
+}
+
+func appendAccountID(buf []byte, id fieldID, accountID AccountID) []byte {
+	buf = appendFieldID(buf, id)
+	buf = appendLengthPrefix(buf, accountIDLength)
+	return append(buf, accountID[:]...)
+}
+
+// appendLengthPrefix variable length prefix (length is less than 12481)
+func appendLengthPrefix(buf []byte, length int) []byte {
+	if length <= 192 {
+		return append(buf, byte(length))
+	}
+	length -= 193
+	return append(buf, byte(193+length>>8), byte(length))
+}
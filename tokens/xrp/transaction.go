@@ -0,0 +1,117 @@
+package xrp
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+)
+
+const (
+	txTypePayment = 0
+
+	// tfFullyCanonicalSig require fully canonical signature
+	tfFullyCanonicalSig uint32 = 0x80000000
+
+	// max amount of XRP in drops (100 billion XRP)
+	maxDrops uint64 = 100000000000000000
+)
+
+// hash prefixes of XRP Ledger
+var (
+	prefixTxSign = []byte{'S', 'T', 'X', 0}
+	prefixTxID   = []byte{'T', 'X', 'N', 0}
+)
+
+// Payment XRP payment transaction
+type Payment struct {
+	Account        AccountID
+	Destination    AccountID
+	DestinationTag *uint32
+	Amount         uint64 // drops
+	Fee            uint64 // drops
+	Sequence       uint32
+	Flags          uint32
+	Memos          [][]byte // memo data
+	SigningPubKey  []byte   // compressed secp256k1 public key
+	TxnSignature   []byte   // DER encoded signature
+}
+
+// serialize binary format of payment (fields are sorted by type and field code)
+func (tx *Payment) serialize(withSignature bool) (buf []byte) {
+	buf = appendUInt16(buf, fieldTransactionType, txTypePayment)
+	buf = appendUInt32(buf, fieldFlags, tx.Flags)
+	buf = appendUInt32(buf, fieldSequence, tx.Sequence)
+	if tx.DestinationTag != nil {
+		buf = appendUInt32(buf, fieldDestinationTag, *tx.DestinationTag)
+	}
+	buf = appendXrpAmount(buf, fieldAmount, tx.Amount)
+	buf = appendXrpAmount(buf, fieldFee, tx.Fee)
+	buf = appendBlob(buf, fieldSigningPubKey, tx.SigningPubKey)
+	if withSignature {
+		buf = appendBlob(buf, fieldTxnSignature, tx.TxnSignature)
+	}
+	buf = appendAccountID(buf, fieldAccount, tx.Account)
+	buf = appendAccountID(buf, fieldDestination, tx.Destination)
+	if len(tx.Memos) > 0 {
+		buf = appendFieldID(buf, fieldMemos)
+		for _, memo := range tx.Memos {
+			buf = appendFieldID(buf, fieldMemo)
+			buf = appendBlob(buf, fieldMemoData, memo)
+			buf = append(buf, objectEndMarker)
+		}
+		buf = append(buf, arrayEndMarker)
+	}
+	return buf
+}
+
+// SignHash message hash to sign, sha512 half of signing data
+func (tx *Payment) SignHash() common.Hash {
+	return sha512Half(prefixTxSign, tx.serialize(false))
+}
+
+// Marshal signed tx blob for submitting
+func (tx *Payment) Marshal() []byte {
+	return tx.serialize(true)
+}
+
+// Hash tx hash, upper case hex of sha512 half of signed tx blob
+func (tx *Payment) Hash() string {
+	hash := sha512Half(prefixTxID, tx.Marshal())
+	return strings.ToUpper(hex.EncodeToString(hash[:]))
+}
+
+func sha512Half(prefix, data []byte) (hash common.Hash) {
+	hasher := sha512.New()
+	_, _ = hasher.Write(prefix)
+	_, _ = hasher.Write(data)
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
+
+// derEncodeSignature DER encoding of signature (r, s)
+func derEncodeSignature(r, s *big.Int) []byte {
+	rBytes := derInteger(r)
+	sBytes := derInteger(s)
+	sig := make([]byte, 0, 6+len(rBytes)+len(sBytes))
+	sig = append(sig, 0x30, byte(4+len(rBytes)+len(sBytes)))
+	sig = append(sig, 0x02, byte(len(rBytes)))
+	sig = append(sig, rBytes...)
+	sig = append(sig, 0x02, byte(len(sBytes)))
+	sig = append(sig, sBytes...)
+	return sig
+}
+
+// derInteger minimal big endian bytes, prefixed with zero if high bit is set
+func derInteger(v *big.Int) []byte {
+	data := v.Bytes()
+	if len(data) == 0 {
+		return []byte{0}
+	}
+	if data[0]&0x80 != 0 {
+		data = append([]byte{0}, data...)
+	}
+	return data
+}
@@ -0,0 +1,192 @@
+package xrp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+)
+
+const (
+	// seconds of ripple epoch (2000-01-01T00:00:00Z) since unix epoch
+	rippleEpoch = 946684800
+
+	txResultSuccess = "tesSUCCESS"
+)
+
+var (
+	errNotXrpAmount         = errors.New("not XRP amount")
+	errUnavailableDelivered = errors.New("delivered amount is unavailable")
+)
+
+// rpcStatus status of rippled json-rpc result
+type rpcStatus struct {
+	Status       string `json:"status"`
+	Error        string `json:"error"`
+	ErrorMessage string `json:"error_message"`
+}
+
+func (s *rpcStatus) err() error {
+	if s.Status == "error" || s.Error != "" {
+		return fmt.Errorf("rpc error %v: %v", s.Error, s.ErrorMessage)
+	}
+	return nil
+}
+
+type rpcResult interface {
+	err() error
+}
+
+// ServerStateResult result of 'server_state'
+type ServerStateResult struct {
+	rpcStatus
+	State struct {
+		BuildVersion    string  `json:"build_version"`
+		NetworkID       *uint32 `json:"network_id"`
+		ValidatedLedger struct {
+			Seq         uint64 `json:"seq"`
+			BaseFee     uint64 `json:"base_fee"`
+			ReserveBase uint64 `json:"reserve_base"`
+			ReserveInc  uint64 `json:"reserve_inc"`
+		} `json:"validated_ledger"`
+	} `json:"state"`
+}
+
+// GetNetworkID network id (mainnet does not report it)
+func (r *ServerStateResult) GetNetworkID() uint32 {
+	if r.State.NetworkID == nil {
+		return 0
+	}
+	return *r.State.NetworkID
+}
+
+// LedgerResult result of 'ledger'
+type LedgerResult struct {
+	rpcStatus
+	LedgerHash  string `json:"ledger_hash"`
+	LedgerIndex uint64 `json:"ledger_index"`
+	Validated   bool   `json:"validated"`
+	Ledger      struct {
+		CloseTime    uint64      `json:"close_time"` // ripple epoch
+		Transactions []*TxResult `json:"transactions"`
+	} `json:"ledger"`
+}
+
+// TxMeta metadata of tx
+type TxMeta struct {
+	TransactionResult string          `json:"TransactionResult"`
+	DeliveredAmount   json.RawMessage `json:"delivered_amount"`
+}
+
+// TxResult result of 'tx' (or expanded tx in 'ledger')
+type TxResult struct {
+	rpcStatus
+	TransactionType string          `json:"TransactionType"`
+	Account         string          `json:"Account"`
+	Destination     string          `json:"Destination"`
+	DestinationTag  *uint32         `json:"DestinationTag"`
+	Amount          json.RawMessage `json:"Amount"`
+	Fee             string          `json:"Fee"`
+	Flags           uint32          `json:"Flags"`
+	Sequence        uint32          `json:"Sequence"`
+	Hash            string          `json:"hash"`
+	LedgerIndex     uint64          `json:"ledger_index"`
+	Date            uint64          `json:"date"` // ripple epoch
+	Meta            *TxMeta         `json:"meta"`
+	MetaData        *TxMeta         `json:"metaData"` // in 'ledger' result
+	Validated       bool            `json:"validated"`
+}
+
+// GetMeta get tx metadata
+func (r *TxResult) GetMeta() *TxMeta {
+	if r.Meta != nil {
+		return r.Meta
+	}
+	return r.MetaData
+}
+
+// IsSuccess tx is validated and executed successfully
+func (r *TxResult) IsSuccess() bool {
+	meta := r.GetMeta()
+	return r.Validated && meta != nil && meta.TransactionResult == txResultSuccess
+}
+
+// BlockTime close time of ledger in unix seconds
+func (r *TxResult) BlockTime() uint64 {
+	if r.Date == 0 {
+		return 0
+	}
+	return r.Date + rippleEpoch
+}
+
+// GetDeliveredDrops get delivered XRP in drops, partial payment may deliver
+// less than 'Amount', so 'delivered_amount' of metadata must be used.
+func (r *TxResult) GetDeliveredDrops() (*big.Int, error) {
+	meta := r.GetMeta()
+	if meta == nil || len(meta.DeliveredAmount) == 0 {
+		return nil, errUnavailableDelivered
+	}
+	return parseDrops(meta.DeliveredAmount)
+}
+
+// AccountInfoResult result of 'account_info'
+type AccountInfoResult struct {
+	rpcStatus
+	AccountData struct {
+		Account    string `json:"Account"`
+		Balance    string `json:"Balance"` // drops
+		Sequence   uint32 `json:"Sequence"`
+		OwnerCount uint32 `json:"OwnerCount"`
+	} `json:"account_data"`
+}
+
+// FeeResult result of 'fee'
+type FeeResult struct {
+	rpcStatus
+	Drops struct {
+		BaseFee       string `json:"base_fee"`
+		MinimumFee    string `json:"minimum_fee"`
+		OpenLedgerFee string `json:"open_ledger_fee"`
+	} `json:"drops"`
+}
+
+// SubmitResult result of 'submit'
+type SubmitResult struct {
+	rpcStatus
+	EngineResult        string `json:"engine_result"`
+	EngineResultMessage string `json:"engine_result_message"`
+	Accepted            bool   `json:"accepted"`
+	TxJSON              struct {
+		Hash string `json:"hash"`
+	} `json:"tx_json"`
+}
+
+// IsSubmitted tx is applied or queued
+func (r *SubmitResult) IsSubmitted() bool {
+	return r.Accepted || r.EngineResult == txResultSuccess || r.EngineResult == "terQUEUED"
+}
+
+// parseDrops XRP amount is drops in string, issued currency amount is an object
+func parseDrops(amount json.RawMessage) (*big.Int, error) {
+	var drops string
+	if err := json.Unmarshal(amount, &drops); err != nil {
+		return nil, errNotXrpAmount
+	}
+	if drops == "unavailable" {
+		return nil, errUnavailableDelivered
+	}
+	value, err := parseUint(drops)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(value), nil
+}
+
+func parseUint(s string) (uint64, error) {
+	value, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("wrong number %v", s)
+	}
+	return value, nil
+}
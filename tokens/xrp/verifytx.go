@@ -0,0 +1,231 @@
+package xrp
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const txTypePaymentName = "Payment"
+
+// GetTransaction impl
+func (b *Bridge) GetTransaction(txHash string) (interface{}, error) {
+	return b.GetTransactionByHash(txHash)
+}
+
+// GetTransactionStatus impl
+func (b *Bridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
+	var txStatus tokens.TxStatus
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Trace("GetTransactionByHash fail", "hash", txHash, "err", err)
+		return &txStatus
+	}
+	if !tx.Validated {
+		return &txStatus
+	}
+	txStatus.BlockHeight = tx.LedgerIndex
+	txStatus.BlockTime = tx.BlockTime()
+	if blockHash, err := b.GetBlockHash(txStatus.BlockHeight); err == nil {
+		txStatus.BlockHash = blockHash
+	}
+	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > txStatus.BlockHeight {
+		txStatus.Confirmations = latest - txStatus.BlockHeight
+	}
+	txStatus.Receipt = toReceipt(tx)
+	return &txStatus
+}
+
+// VerifyMsgHash verify msg hash
+func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) error {
+	tx, ok := rawTx.(*Payment)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	if len(msgHashes) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
+	msgHash := msgHashes[0]
+	sigHash := tx.SignHash()
+	if sigHash.String() != msgHash {
+		log.Trace("message hash mismatch", "want", msgHash, "have", sigHash.String())
+		return tokens.ErrMsgHashMismatch
+	}
+	return nil
+}
+
+// VerifyTransaction impl
+func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	if !b.IsSrc {
+		return nil, tokens.ErrBridgeDestinationNotSupported
+	}
+	return b.verifySwapinTxWithPairID(pairID, txHash, allowUnstable)
+}
+
+func (b *Bridge) verifySwapinTxWithPairID(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	swapInfo := &tokens.TxSwapInfo{}
+	swapInfo.PairID = pairID // PairID
+	swapInfo.Hash = txHash   // Hash
+
+	token := b.GetTokenConfig(pairID)
+	if token == nil {
+		return swapInfo, tokens.ErrUnknownPairID
+	}
+
+	if token.IsSwapDisabled() {
+		return swapInfo, tokens.ErrSwapIsClosed
+	}
+
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		return swapInfo, tokens.ErrTxNotFound
+	}
+
+	if !allowUnstable {
+		_, err = b.getStableReceipt(swapInfo)
+		if err != nil {
+			return swapInfo, err
+		}
+	}
+
+	err = b.verifyPaymentTx(swapInfo, tx, token)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	err = b.checkSwapinInfo(swapInfo)
+	if err != nil {
+		return swapInfo, err
+	}
+
+	if !allowUnstable {
+		log.Info("verify swapin stable pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+	}
+	return swapInfo, nil
+}
+
+// verifyPaymentTx verify XRP payment to deposit address, the bind address
+// is registered with the destination tag, and the swap value is the
+// delivered amount (partial payment may deliver less than 'Amount').
+func (b *Bridge) verifyPaymentTx(swapInfo *tokens.TxSwapInfo, tx *TxResult, token *tokens.TokenConfig) error {
+	if tx.TransactionType != txTypePaymentName || tx.Destination != token.DepositAddress {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	if !tx.Validated {
+		return tokens.ErrTxNotStable
+	}
+	if !tx.IsSuccess() {
+		return tokens.ErrTxWithWrongReceipt
+	}
+	value, err := tx.GetDeliveredDrops()
+	if err != nil {
+		log.Warn("get delivered amount failed", "txid", swapInfo.Hash, "err", err)
+		return tokens.ErrTxWithWrongValue
+	}
+
+	var bind string
+	if tx.DestinationTag != nil {
+		bind = tools.GetDestinationTagBindAddress(*tx.DestinationTag)
+	}
+
+	swapInfo.TxTo = token.DepositAddress // TxTo
+	swapInfo.To = token.DepositAddress   // To
+	swapInfo.From = tx.Account           // From
+	swapInfo.Bind = bind                 // Bind
+	swapInfo.Value = value               // Value
+	swapInfo.Height = tx.LedgerIndex     // Height
+	swapInfo.Timestamp = tx.BlockTime()  // Timestamp
+	return nil
+}
+
+// verifySwapinTx verify swapin (in scan job)
+func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		log.Debug(b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		addSwapInfoConsiderError(nil, tokens.ErrTxNotFound, &swapInfos, &errs)
+		return swapInfos, errs
+	}
+	tokenCfgs, pairIDs := tokens.FindTokenConfig(tx.Destination, true)
+	if len(pairIDs) == 0 {
+		addSwapInfoConsiderError(nil, tokens.ErrTxWithWrongReceiver, &swapInfos, &errs)
+		return swapInfos, errs
+	}
+
+	for i, pairID := range pairIDs {
+		token := tokenCfgs[i]
+
+		swapInfo := &tokens.TxSwapInfo{}
+		swapInfo.Hash = txHash   // Hash
+		swapInfo.PairID = pairID // PairID
+
+		err = b.verifyPaymentTx(swapInfo, tx, token)
+		if err != nil {
+			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+			continue
+		}
+
+		if !allowUnstable {
+			_, err = b.getStableReceipt(swapInfo)
+			if err != nil {
+				addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+				continue
+			}
+		}
+
+		err = b.checkSwapinInfo(swapInfo)
+		addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+
+		if !allowUnstable && err == nil {
+			log.Debug("verify swapin stable pass", "pairID", swapInfo.PairID, "from", swapInfo.From, "to", swapInfo.To, "bind", swapInfo.Bind, "value", swapInfo.Value, "txid", txHash, "height", swapInfo.Height, "timestamp", swapInfo.Timestamp)
+		}
+	}
+
+	return swapInfos, errs
+}
+
+func addSwapInfoConsiderError(swapInfo *tokens.TxSwapInfo, err error, swapInfos *[]*tokens.TxSwapInfo, errs *[]error) {
+	if !tokens.ShouldRegisterSwapForError(err) {
+		return
+	}
+	*swapInfos = append(*swapInfos, swapInfo)
+	*errs = append(*errs, err)
+}
+
+func (b *Bridge) getStableReceipt(swapInfo *tokens.TxSwapInfo) (*types.RPCTxReceipt, error) {
+	txStatus := b.GetTransactionStatus(swapInfo.Hash)
+	swapInfo.Height = txStatus.BlockHeight  // Height
+	swapInfo.Timestamp = txStatus.BlockTime // Timestamp
+	receipt, ok := txStatus.Receipt.(*types.RPCTxReceipt)
+	if !ok || receipt == nil {
+		return nil, tokens.ErrTxNotStable
+	}
+	if *receipt.Status != 1 {
+		return nil, tokens.ErrTxWithWrongReceipt
+	}
+	if txStatus.BlockHeight == 0 ||
+		txStatus.Confirmations < *b.GetChainConfig().Confirmations {
+		return nil, tokens.ErrTxNotStable
+	}
+	return receipt, nil
+}
+
+func (b *Bridge) checkSwapinInfo(swapInfo *tokens.TxSwapInfo) error {
+	if swapInfo.From == swapInfo.To {
+		return tokens.ErrTxWithWrongSender
+	}
+	if !tokens.CheckSwapValue(swapInfo.PairID, swapInfo.Value, b.IsSrc) {
+		return tokens.ErrTxWithWrongValue
+	}
+	if !tokens.DstBridge.IsValidAddress(swapInfo.Bind) {
+		log.Warn("wrong bind address in swapin", "bind", swapInfo.Bind)
+		return tokens.ErrTxWithWrongMemo
+	}
+	if params.MustRegisterAccount() && !tools.IsAddressRegistered(swapInfo.Bind) {
+		return tokens.ErrTxSenderNotRegistered
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/rlp"
+	"golang.org/x/crypto/sha3"
+)
+
+// transaction types (EIP-2718)
+const (
+	LegacyTxType     = 0x00
+	DynamicFeeTxType = 0x02
+)
+
+// typed transaction errors
+var (
+	ErrTxTypeNotSupported = errors.New("transaction type not supported")
+	errEmptyTypedTx       = errors.New("empty typed transaction bytes")
+)
+
+// AccessList is an EIP-2930 access list.
+type AccessList []AccessTuple
+
+// AccessTuple is the element type of an access list.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// dynamicFeeTxData is the rlp payload of EIP-1559 transaction
+type dynamicFeeTxData struct {
+	ChainID    *big.Int
+	Nonce      uint64
+	GasTipCap  *big.Int
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *common.Address `rlp:"nil"` // nil means contract creation
+	Value      *big.Int
+	Data       []byte
+	AccessList AccessList
+
+	// Signature values
+	V *big.Int
+	R *big.Int
+	S *big.Int
+}
+
+// NewDynamicFeeTransaction new EIP-1559 tx
+func NewDynamicFeeTransaction(chainID *big.Int, nonce uint64, to common.Address, amount *big.Int, gasLimit uint64, gasTipCap, gasFeeCap *big.Int, data []byte) *Transaction {
+	tx := newTransaction(nonce, &to, amount, gasLimit, nil, data)
+	tx.data.Type = DynamicFeeTxType
+	tx.data.Price = nil
+	tx.data.ChainID = new(big.Int)
+	tx.data.GasTipCap = new(big.Int)
+	tx.data.GasFeeCap = new(big.Int)
+	if chainID != nil {
+		tx.data.ChainID.Set(chainID)
+	}
+	if gasTipCap != nil {
+		tx.data.GasTipCap.Set(gasTipCap)
+	}
+	if gasFeeCap != nil {
+		tx.data.GasFeeCap.Set(gasFeeCap)
+	}
+	return tx
+}
+
+func (tx *Transaction) dynamicFeeTxData() *dynamicFeeTxData {
+	return &dynamicFeeTxData{
+		ChainID:    tx.data.ChainID,
+		Nonce:      tx.data.AccountNonce,
+		GasTipCap:  tx.data.GasTipCap,
+		GasFeeCap:  tx.data.GasFeeCap,
+		Gas:        tx.data.GasLimit,
+		To:         tx.data.Recipient,
+		Value:      tx.data.Amount,
+		Data:       tx.data.Payload,
+		AccessList: tx.data.AccessList,
+		V:          tx.data.V,
+		R:          tx.data.R,
+		S:          tx.data.S,
+	}
+}
+
+// encodeTyped returns type || rlp(payload) of typed tx
+func (tx *Transaction) encodeTyped() ([]byte, error) {
+	if tx.Type() != DynamicFeeTxType {
+		return nil, ErrTxTypeNotSupported
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(tx.Type())
+	if err := rlp.Encode(&buf, tx.dynamicFeeTxData()); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeTyped decodes type || rlp(payload) of typed tx
+func (tx *Transaction) decodeTyped(b []byte) error {
+	if len(b) == 0 {
+		return errEmptyTypedTx
+	}
+	if b[0] != DynamicFeeTxType {
+		return ErrTxTypeNotSupported
+	}
+	var d dynamicFeeTxData
+	if err := rlp.DecodeBytes(b[1:], &d); err != nil {
+		return err
+	}
+	*tx = Transaction{data: txdata{
+		Type:         DynamicFeeTxType,
+		ChainID:      d.ChainID,
+		AccountNonce: d.Nonce,
+		GasTipCap:    d.GasTipCap,
+		GasFeeCap:    d.GasFeeCap,
+		GasLimit:     d.Gas,
+		Recipient:    d.To,
+		Amount:       d.Value,
+		Payload:      d.Data,
+		AccessList:   d.AccessList,
+		V:            d.V,
+		R:            d.R,
+		S:            d.S,
+	}}
+	tx.size.Store(StorageSize(len(b)))
+	return nil
+}
+
+// MarshalBinary returns the canonical encoding of the transaction,
+// which is used by eth_sendRawTransaction.
+// legacy tx is rlp encoded, typed tx is encoded as type || rlp(payload).
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	if tx.Type() == LegacyTxType {
+		return rlp.EncodeToBytes(&tx.data)
+	}
+	return tx.encodeTyped()
+}
+
+// UnmarshalBinary decodes the canonical encoding of transaction.
+func (tx *Transaction) UnmarshalBinary(b []byte) error {
+	if len(b) > 0 && b[0] > 0x7f {
+		var data txdata
+		if err := rlp.DecodeBytes(b, &data); err != nil {
+			return err
+		}
+		*tx = Transaction{data: data}
+		tx.size.Store(StorageSize(len(b)))
+		return nil
+	}
+	return tx.decodeTyped(b)
+}
+
+func prefixedRlpHash(prefix byte, x interface{}) (h common.Hash) {
+	hw := sha3.NewLegacyKeccak256()
+	_, _ = hw.Write([]byte{prefix})
+	_ = rlp.Encode(hw, x)
+	hw.Sum(h[:0])
+	return h
+}
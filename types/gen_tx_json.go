@@ -8,30 +8,43 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
-	"github.com/anyswap/CrossChain-Bridge/tools/rlp"
 )
 
 // MarshalJSON marshals as JSON.
 func (t *txdata) MarshalJSON() ([]byte, error) {
 	type txdata struct {
+		Type         hexutil.Uint64  `json:"type"`
+		ChainID      *hexutil.Big    `json:"chainId,omitempty"`
 		AccountNonce hexutil.Uint64  `json:"nonce"    gencodec:"required"`
-		Price        *hexutil.Big    `json:"gasPrice" gencodec:"required"`
+		Price        *hexutil.Big    `json:"gasPrice,omitempty"`
+		GasTipCap    *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+		GasFeeCap    *hexutil.Big    `json:"maxFeePerGas,omitempty"`
 		GasLimit     hexutil.Uint64  `json:"gas"      gencodec:"required"`
 		Recipient    *common.Address `json:"to"       rlp:"nil"`
 		Amount       *hexutil.Big    `json:"value"    gencodec:"required"`
 		Payload      hexutil.Bytes   `json:"input"    gencodec:"required"`
+		AccessList   *AccessList     `json:"accessList,omitempty"`
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
 	}
 	var enc txdata
+	enc.Type = hexutil.Uint64(t.Type)
 	enc.AccountNonce = hexutil.Uint64(t.AccountNonce)
-	enc.Price = (*hexutil.Big)(t.Price)
 	enc.GasLimit = hexutil.Uint64(t.GasLimit)
 	enc.Recipient = t.Recipient
 	enc.Amount = (*hexutil.Big)(t.Amount)
 	enc.Payload = t.Payload
+	if t.Type == LegacyTxType {
+		enc.Price = (*hexutil.Big)(t.Price)
+	} else {
+		accessList := t.AccessList
+		enc.ChainID = (*hexutil.Big)(t.ChainID)
+		enc.GasTipCap = (*hexutil.Big)(t.GasTipCap)
+		enc.GasFeeCap = (*hexutil.Big)(t.GasFeeCap)
+		enc.AccessList = &accessList
+	}
 	enc.V = (*hexutil.Big)(t.V)
 	enc.R = (*hexutil.Big)(t.R)
 	enc.S = (*hexutil.Big)(t.S)
@@ -40,14 +53,20 @@ func (t *txdata) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON unmarshals from JSON.
+// nolint:gocyclo // allow long required fields checking
 func (t *txdata) UnmarshalJSON(input []byte) error {
 	type txdata struct {
+		Type         *hexutil.Uint64 `json:"type"`
+		ChainID      *hexutil.Big    `json:"chainId"`
 		AccountNonce *hexutil.Uint64 `json:"nonce"    gencodec:"required"`
-		Price        *hexutil.Big    `json:"gasPrice" gencodec:"required"`
+		Price        *hexutil.Big    `json:"gasPrice"`
+		GasTipCap    *hexutil.Big    `json:"maxPriorityFeePerGas"`
+		GasFeeCap    *hexutil.Big    `json:"maxFeePerGas"`
 		GasLimit     *hexutil.Uint64 `json:"gas"      gencodec:"required"`
 		Recipient    *common.Address `json:"to"       rlp:"nil"`
 		Amount       *hexutil.Big    `json:"value"    gencodec:"required"`
 		Payload      *hexutil.Bytes  `json:"input"    gencodec:"required"`
+		AccessList   *AccessList     `json:"accessList"`
 		V            *hexutil.Big    `json:"v" gencodec:"required"`
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
@@ -57,14 +76,38 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 	if err := json.Unmarshal(input, &dec); err != nil {
 		return err
 	}
+	if dec.Type != nil {
+		t.Type = uint8(*dec.Type)
+	}
+	switch t.Type {
+	case LegacyTxType:
+		if dec.Price == nil {
+			return errors.New("missing required field 'gasPrice' for txdata")
+		}
+		t.Price = (*big.Int)(dec.Price)
+	case DynamicFeeTxType:
+		if dec.ChainID == nil {
+			return errors.New("missing required field 'chainId' for txdata")
+		}
+		t.ChainID = (*big.Int)(dec.ChainID)
+		if dec.GasTipCap == nil {
+			return errors.New("missing required field 'maxPriorityFeePerGas' for txdata")
+		}
+		t.GasTipCap = (*big.Int)(dec.GasTipCap)
+		if dec.GasFeeCap == nil {
+			return errors.New("missing required field 'maxFeePerGas' for txdata")
+		}
+		t.GasFeeCap = (*big.Int)(dec.GasFeeCap)
+		if dec.AccessList != nil {
+			t.AccessList = *dec.AccessList
+		}
+	default:
+		return ErrTxTypeNotSupported
+	}
 	if dec.AccountNonce == nil {
 		return errors.New("missing required field 'nonce' for txdata")
 	}
 	t.AccountNonce = uint64(*dec.AccountNonce)
-	if dec.Price == nil {
-		return errors.New("missing required field 'gasPrice' for txdata")
-	}
-	t.Price = (*big.Int)(dec.Price)
 	if dec.GasLimit == nil {
 		return errors.New("missing required field 'gas' for txdata")
 	}
@@ -106,12 +149,12 @@ func (tx *Transaction) PrintPretty() {
 
 // PrintRaw print raw encoded (hex string)
 func (tx *Transaction) PrintRaw() {
-	bs, _ := rlp.EncodeToBytes(tx)
+	bs, _ := tx.MarshalBinary()
 	fmt.Println(hexutil.Bytes(bs))
 }
 
 // RawStr return raw encoded (hex string)
 func (tx *Transaction) RawStr() string {
-	bs, _ := rlp.EncodeToBytes(tx)
+	bs, _ := tx.MarshalBinary()
 	return string(bs)
 }
@@ -18,6 +18,7 @@ type RPCBaseBlock struct {
 	GasLimit   *hexutil.Uint64 `json:"gasLimit"`
 	GasUsed    *hexutil.Uint64 `json:"gasUsed"`
 	Time       *hexutil.Big    `json:"timestamp"`
+	BaseFee    *hexutil.Big    `json:"baseFeePerGas,omitempty"`
 }
 
 // RPCBlock struct
@@ -42,6 +43,7 @@ type RPCBlock struct {
 	TotalDifficulty *hexutil.Big    `json:"totalDifficulty"`
 	Transactions    []*common.Hash  `json:"transactions"`
 	Uncles          []*common.Hash  `json:"uncles"`
+	BaseFee         *hexutil.Big    `json:"baseFeePerGas,omitempty"`
 }
 
 // RPCTransaction struct
@@ -53,6 +55,9 @@ type RPCTransaction struct {
 	From             *common.Address `json:"from,omitempty"`
 	AccountNonce     interface{}     `json:"nonce"` // unexpect RSK has leading zero (eg. 0x01)
 	Price            *hexutil.Big    `json:"gasPrice"`
+	GasTipCap        *hexutil.Big    `json:"maxPriorityFeePerGas,omitempty"`
+	GasFeeCap        *hexutil.Big    `json:"maxFeePerGas,omitempty"`
+	Type             *hexutil.Uint64 `json:"type,omitempty"`
 	GasLimit         *hexutil.Uint64 `json:"gas"`
 	Recipient        *common.Address `json:"to"`
 	Amount           *hexutil.Big    `json:"value"`
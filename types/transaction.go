@@ -22,13 +22,20 @@ type Transaction struct {
 	from atomic.Value
 }
 
+// txdata is rlp encoded as legacy tx,
+// fields only used by typed tx are encoded in their own payload.
 type txdata struct {
+	Type         uint8           `json:"type"     rlp:"-"`
+	ChainID      *big.Int        `json:"chainId"  rlp:"-"` // typed tx only
 	AccountNonce uint64          `json:"nonce"    gencodec:"required"`
-	Price        *big.Int        `json:"gasPrice" gencodec:"required"`
+	Price        *big.Int        `json:"gasPrice" gencodec:"required"` // legacy tx only
+	GasTipCap    *big.Int        `json:"maxPriorityFeePerGas" rlp:"-"` // dynamic fee tx only
+	GasFeeCap    *big.Int        `json:"maxFeePerGas"         rlp:"-"` // dynamic fee tx only
 	GasLimit     uint64          `json:"gas"      gencodec:"required"`
 	Recipient    *common.Address `json:"to"       rlp:"nil"` // nil means contract creation
 	Amount       *big.Int        `json:"value"    gencodec:"required"`
 	Payload      []byte          `json:"input"    gencodec:"required"`
+	AccessList   AccessList      `json:"accessList" rlp:"-"` // typed tx only
 
 	// Signature values
 	V *big.Int `json:"v" gencodec:"required"`
@@ -74,13 +81,24 @@ func newTransaction(nonce uint64, to *common.Address, amount *big.Int, gasLimit
 	return &Transaction{data: d}
 }
 
+// Type returns the transaction type (EIP-2718)
+func (tx *Transaction) Type() uint8 {
+	return tx.data.Type
+}
+
 // ChainID returns which chain id this transaction was signed for (if at all)
 func (tx *Transaction) ChainID() *big.Int {
+	if tx.Type() != LegacyTxType {
+		return new(big.Int).Set(tx.data.ChainID)
+	}
 	return deriveChainID(tx.data.V)
 }
 
 // Protected returns whether the transaction is protected from replay protection.
 func (tx *Transaction) Protected() bool {
+	if tx.Type() != LegacyTxType {
+		return true
+	}
 	return isProtectedV(tx.data.V)
 }
 
@@ -94,14 +112,32 @@ func isProtectedV(rsvV *big.Int) bool {
 }
 
 // EncodeRLP implements rlp.Encoder
+// typed tx is encoded as rlp string of its binary encoding (EIP-2718)
 func (tx *Transaction) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, &tx.data)
+	if tx.Type() == LegacyTxType {
+		return rlp.Encode(w, &tx.data)
+	}
+	bs, err := tx.encodeTyped()
+	if err != nil {
+		return err
+	}
+	return rlp.Encode(w, bs)
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Transaction) DecodeRLP(s *rlp.Stream) error {
-	_, size, _ := s.Kind()
-	err := s.Decode(&tx.data)
+	kind, size, err := s.Kind()
+	if err != nil {
+		return err
+	}
+	if kind != rlp.List {
+		bs, err := s.Bytes()
+		if err != nil {
+			return err
+		}
+		return tx.decodeTyped(bs)
+	}
+	err = s.Decode(&tx.data)
 	if err == nil {
 		tx.size.Store(StorageSize(rlp.ListSize(size)))
 	}
@@ -133,12 +169,39 @@ func (tx *Transaction) Data() []byte { return common.CopyBytes(tx.data.Payload)
 // Gas tx gas
 func (tx *Transaction) Gas() uint64 { return tx.data.GasLimit }
 
-// GasPrice tx gas price
-func (tx *Transaction) GasPrice() *big.Int { return new(big.Int).Set(tx.data.Price) }
+// GasPrice tx gas price (fee cap of dynamic fee tx)
+func (tx *Transaction) GasPrice() *big.Int {
+	if tx.Type() == DynamicFeeTxType {
+		return new(big.Int).Set(tx.data.GasFeeCap)
+	}
+	return new(big.Int).Set(tx.data.Price)
+}
 
-// SetGasPrice tx gas price
+// SetGasPrice tx gas price (legacy tx only)
 func (tx *Transaction) SetGasPrice(gasPrice *big.Int) { tx.data.Price.Set(gasPrice) }
 
+// GasTipCap tx max priority fee per gas (gas price of legacy tx)
+func (tx *Transaction) GasTipCap() *big.Int {
+	if tx.Type() == DynamicFeeTxType {
+		return new(big.Int).Set(tx.data.GasTipCap)
+	}
+	return new(big.Int).Set(tx.data.Price)
+}
+
+// GasFeeCap tx max fee per gas (gas price of legacy tx)
+func (tx *Transaction) GasFeeCap() *big.Int {
+	return tx.GasPrice()
+}
+
+// SetGasFeeCaps set tx max priority fee and max fee per gas (dynamic fee tx only)
+func (tx *Transaction) SetGasFeeCaps(gasTipCap, gasFeeCap *big.Int) {
+	tx.data.GasTipCap.Set(gasTipCap)
+	tx.data.GasFeeCap.Set(gasFeeCap)
+}
+
+// AccessList tx access list
+func (tx *Transaction) AccessList() AccessList { return tx.data.AccessList }
+
 // Value tx value
 func (tx *Transaction) Value() *big.Int { return new(big.Int).Set(tx.data.Amount) }
 
@@ -165,13 +228,18 @@ func rlpHash(x interface{}) (h common.Hash) {
 	return h
 }
 
-// Hash hashes the RLP encoding of tx.
+// Hash hashes the RLP encoding of tx (type || rlp(payload) of typed tx).
 // It uniquely identifies the transaction.
 func (tx *Transaction) Hash() common.Hash {
 	if hash := tx.hash.Load(); hash != nil {
 		return hash.(common.Hash)
 	}
-	v := rlpHash(tx)
+	var v common.Hash
+	if tx.Type() == LegacyTxType {
+		v = rlpHash(tx)
+	} else {
+		v = prefixedRlpHash(tx.Type(), tx.dynamicFeeTxData())
+	}
 	tx.hash.Store(v)
 	return v
 }
@@ -190,7 +258,12 @@ func (tx *Transaction) Size() StorageSize {
 		return size.(StorageSize)
 	}
 	c := writeCounter(0)
-	_ = rlp.Encode(&c, &tx.data)
+	if tx.Type() == LegacyTxType {
+		_ = rlp.Encode(&c, &tx.data)
+	} else {
+		bs, _ := tx.encodeTyped()
+		c = writeCounter(len(bs))
+	}
 	tx.size.Store(StorageSize(c))
 	return StorageSize(c)
 }
@@ -209,7 +282,7 @@ func (tx *Transaction) WithSignature(signer Signer, sig []byte) (*Transaction, e
 
 // Cost returns amount + gasprice * gaslimit.
 func (tx *Transaction) Cost() *big.Int {
-	total := new(big.Int).Mul(tx.data.Price, new(big.Int).SetUint64(tx.data.GasLimit))
+	total := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.data.GasLimit))
 	total.Add(total, tx.data.Amount)
 	return total
 }
@@ -27,6 +27,8 @@ type sigCache struct {
 func MakeSigner(signType string, chainID *big.Int) Signer {
 	var signer Signer
 	switch signType {
+	case "London":
+		signer = NewLondonSigner(chainID)
 	case "EIP155":
 		signer = NewEIP155Signer(chainID)
 	case "Homestead":
@@ -89,6 +91,71 @@ type Signer interface {
 	Equal(Signer) bool
 }
 
+// LondonSigner implements Signer using the London rules,
+// it accepts EIP-1559 dynamic fee txs and EIP155 legacy txs.
+type LondonSigner struct{ EIP155Signer }
+
+// NewLondonSigner new LondonSigner
+func NewLondonSigner(chainID *big.Int) LondonSigner {
+	return LondonSigner{NewEIP155Signer(chainID)}
+}
+
+// Equal compare signer
+func (s LondonSigner) Equal(s2 Signer) bool {
+	london, ok := s2.(LondonSigner)
+	return ok && london.chainID.Cmp(s.chainID) == 0
+}
+
+// Sender get sender
+func (s LondonSigner) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != DynamicFeeTxType {
+		return s.EIP155Signer.Sender(tx)
+	}
+	if tx.ChainID().Cmp(s.chainID) != 0 {
+		return common.Address{}, ErrInvalidChainID
+	}
+	// dynamic fee txs use 0 and 1 as their recovery id,
+	// add 27 to become equivalent to unprotected Homestead signatures.
+	V := new(big.Int).Add(tx.data.V, big.NewInt(27))
+	return recoverPlain(s.Hash(tx), tx.data.R, tx.data.S, V, true)
+}
+
+// SignatureValues returns signature values. This signature
+// needs to be in the [R || S || V] format where V is 0 or 1.
+func (s LondonSigner) SignatureValues(tx *Transaction, sig []byte) (rsvR, rsvS, rsvV *big.Int, err error) {
+	if tx.Type() != DynamicFeeTxType {
+		return s.EIP155Signer.SignatureValues(tx, sig)
+	}
+	if tx.data.ChainID.Sign() != 0 && tx.data.ChainID.Cmp(s.chainID) != 0 {
+		return nil, nil, nil, ErrInvalidChainID
+	}
+	rsvR, rsvS, _, err = HomesteadSigner{}.SignatureValues(tx, sig)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	rsvV = big.NewInt(int64(sig[64]))
+	return rsvR, rsvS, rsvV, nil
+}
+
+// Hash returns the hash to be signed by the sender.
+// It does not uniquely identify the transaction.
+func (s LondonSigner) Hash(tx *Transaction) common.Hash {
+	if tx.Type() != DynamicFeeTxType {
+		return s.EIP155Signer.Hash(tx)
+	}
+	return prefixedRlpHash(tx.Type(), []interface{}{
+		s.chainID,
+		tx.data.AccountNonce,
+		tx.data.GasTipCap,
+		tx.data.GasFeeCap,
+		tx.data.GasLimit,
+		tx.data.Recipient,
+		tx.data.Amount,
+		tx.data.Payload,
+		tx.data.AccessList,
+	})
+}
+
 // EIP155Signer implements Signer using the EIP155 rules.
 type EIP155Signer struct {
 	chainID, chainIDMul *big.Int
@@ -115,6 +182,9 @@ var big8 = big.NewInt(8)
 
 // Sender get sender
 func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
+	if tx.Type() != LegacyTxType {
+		return common.Address{}, ErrTxTypeNotSupported
+	}
 	if !tx.Protected() {
 		return HomesteadSigner{}.Sender(tx)
 	}
@@ -129,6 +199,9 @@ func (s EIP155Signer) Sender(tx *Transaction) (common.Address, error) {
 // SignatureValues returns signature values. This signature
 // needs to be in the [R || S || V] format where V is 0 or 1.
 func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (rsvR, rsvS, rsvV *big.Int, err error) {
+	if tx.Type() != LegacyTxType {
+		return nil, nil, nil, ErrTxTypeNotSupported
+	}
 	rsvR, rsvS, rsvV, err = HomesteadSigner{}.SignatureValues(tx, sig)
 	if err != nil {
 		return nil, nil, nil, err
@@ -0,0 +1,152 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/rlp"
+)
+
+var (
+	testChainID = big.NewInt(4)
+	testTo      = common.HexToAddress("0x0000000000000000000000000000000000000001")
+)
+
+func signTestTx(t *testing.T, tx *Transaction) (*Transaction, common.Address) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := MakeSigner("London", testChainID)
+	signedTx, err := SignTx(tx, signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender, err := Sender(signer, signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := crypto.PubkeyToAddress(key.PublicKey); sender != want {
+		t.Fatalf("sender mismatch, have %v want %v", sender.String(), want.String())
+	}
+	return signedTx, sender
+}
+
+func TestDynamicFeeTxSignAndEncode(t *testing.T) {
+	tx := NewDynamicFeeTransaction(testChainID, 7, testTo, big.NewInt(1e18), 21000, big.NewInt(2e9), big.NewInt(100e9), []byte{0x01})
+	signedTx, sender := signTestTx(t, tx)
+
+	enc, err := signedTx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc[0] != DynamicFeeTxType {
+		t.Fatalf("wrong typed tx prefix %x", enc[0])
+	}
+	if signedTx.Hash() != crypto.Keccak256Hash(enc) {
+		t.Fatal("tx hash is not keccak of canonical encoding")
+	}
+
+	var decTx Transaction
+	if err = decTx.UnmarshalBinary(enc); err != nil {
+		t.Fatal(err)
+	}
+	if decTx.Hash() != signedTx.Hash() {
+		t.Fatal("hash mismatch after binary roundtrip")
+	}
+	if decTx.GasTipCap().Cmp(big.NewInt(2e9)) != 0 || decTx.GasFeeCap().Cmp(big.NewInt(100e9)) != 0 {
+		t.Fatal("fee caps mismatch after binary roundtrip")
+	}
+	if from, errf := Sender(MakeSigner("London", testChainID), &decTx); errf != nil || from != sender {
+		t.Fatalf("sender mismatch after binary roundtrip, err %v", errf)
+	}
+
+	rlpEnc, err := rlp.EncodeToBytes(signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rlpTx Transaction
+	if err = rlp.DecodeBytes(rlpEnc, &rlpTx); err != nil {
+		t.Fatal(err)
+	}
+	if rlpTx.Hash() != signedTx.Hash() {
+		t.Fatal("hash mismatch after rlp roundtrip")
+	}
+
+	jsonEnc, err := json.Marshal(signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var jsonTx Transaction
+	if err = json.Unmarshal(jsonEnc, &jsonTx); err != nil {
+		t.Fatal(err)
+	}
+	if jsonTx.Hash() != signedTx.Hash() {
+		t.Fatal("hash mismatch after json roundtrip")
+	}
+}
+
+func TestLegacyTxWithLondonSigner(t *testing.T) {
+	tx := NewTransaction(7, testTo, big.NewInt(1e18), 21000, big.NewInt(10e9), nil)
+	signedTx, sender := signTestTx(t, tx)
+
+	if signedTx.Type() != LegacyTxType || !signedTx.Protected() {
+		t.Fatal("legacy tx should be eip155 protected")
+	}
+	from, err := Sender(MakeSigner("EIP155", testChainID), signedTx)
+	if err != nil || from != sender {
+		t.Fatalf("eip155 signer recover sender mismatch, err %v", err)
+	}
+
+	enc, err := signedTx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rlpEnc, _ := rlp.EncodeToBytes(signedTx)
+	if !bytes.Equal(enc, rlpEnc) {
+		t.Fatal("legacy tx binary encoding should be rlp")
+	}
+}
+
+// dynamic fee tx of the eip1559 block encoding test of go-ethereum (core/types/block_test.go),
+// hash and sender are computed by go-ethereum v1.10.8
+func TestDynamicFeeTxKnownAnswer(t *testing.T) {
+	raw := common.FromHex("0x02f8a0018080843b9aca008301e24194095e7baea6a6c7c4c2dfeb977efac326af552d878080f838f7940000000000000000000000000000000000000001e1a0000000000000000000000000000000000000000000000000000000000000000080a0fe38ca4e44a30002ac54af7cf922a6ac2ba11b7d22f548e8ecb3f51f41cb31b0a06de6a5cbae13c0c856e33acf021b51819636cfc009d39eafb9f606d546e305a8")
+	wantHash := common.HexToHash("0xc5a8f6026a3554e9731e6ad1c17a7450b8fe2d048cd755752cc985a89a2e125c")
+	wantSender := common.HexToAddress("0xa8E20d02Fb65adAa95f9279B325D8092724C81ee")
+
+	var tx Transaction
+	if err := tx.UnmarshalBinary(raw); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Type() != DynamicFeeTxType || tx.ChainID().Cmp(big.NewInt(1)) != 0 || tx.Nonce() != 0 || tx.Gas() != 123457 {
+		t.Fatalf("wrong tx fields, type %v chainID %v nonce %v gas %v", tx.Type(), tx.ChainID(), tx.Nonce(), tx.Gas())
+	}
+	if tx.GasTipCap().Sign() != 0 || tx.GasFeeCap().Cmp(big.NewInt(1e9)) != 0 {
+		t.Fatalf("wrong fee caps, tip %v fee %v", tx.GasTipCap(), tx.GasFeeCap())
+	}
+	if to := tx.To(); to == nil || *to != common.HexToAddress("0x095e7baea6a6c7c4c2dfeb977efac326af552d87") {
+		t.Fatalf("wrong receiver %v", to)
+	}
+	if al := tx.AccessList(); len(al) != 1 || al[0].Address != common.HexToAddress("0x0000000000000000000000000000000000000001") || len(al[0].StorageKeys) != 1 {
+		t.Fatalf("wrong access list %+v", al)
+	}
+	if tx.Hash() != wantHash {
+		t.Fatalf("tx hash mismatch, have %v want %v", tx.Hash().String(), wantHash.String())
+	}
+	sender, err := Sender(MakeSigner("London", big.NewInt(1)), &tx)
+	if err != nil || sender != wantSender {
+		t.Fatalf("sender mismatch, have %v want %v, err %v", sender.String(), wantSender.String(), err)
+	}
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(enc, raw) {
+		t.Fatalf("encoding mismatch\nhave %x\nwant %x", enc, raw)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -241,6 +242,10 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 		return tokens.ErrUnknownPairID
 	}
 
+	if len(args.Batch) != 0 {
+		return rebuildAndVerifyBatchMsgHash(msgHash, args, srcBridge, dstBridge, tokenCfg)
+	}
+
 	swapInfo, err := verifySwapTransaction(srcBridge, args.PairID, args.SwapID, args.Bind, args.TxType)
 	if err != nil {
 		logWorkerError("accept", "verifySignInfo failed", err, "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "swaptype", args.SwapType)
@@ -267,6 +272,56 @@ func rebuildAndVerifyMsgHash(keyID string, msgHash []string, args *tokens.BuildT
 	return nil
 }
 
+// rebuildAndVerifyBatchMsgHash verify every swap of the batch, rebuild the
+// batch tx with the verified values, and check the batch items of the sign
+// request are paid by the rebuilt tx as claimed
+func rebuildAndVerifyBatchMsgHash(msgHash []string, args *tokens.BuildTxArgs, srcBridge, dstBridge tokens.CrossChainBridge, tokenCfg *tokens.TokenConfig) error {
+	builder, ok := dstBridge.(tokens.BatchTxBuilder)
+	if !ok {
+		return errBatchTxNotSupported
+	}
+	if args.SwapType != tokens.SwapoutType {
+		return tokens.ErrSwapTypeNotSupported
+	}
+
+	buildTxArgs := &tokens.BuildTxArgs{
+		SwapInfo: args.SwapInfo,
+		From:     tokenCfg.DcrmAddress,
+		Extra:    args.Extra,
+	}
+	exist := make(map[string]struct{}, len(args.Batch))
+	for _, item := range args.Batch {
+		if !strings.EqualFold(item.PairID, args.PairID) || item.SwapType != args.SwapType {
+			return fmt.Errorf("batch swap %v mismatch, pairID %v, swapType %v", item.SwapID, item.PairID, item.SwapType.String())
+		}
+		key := getSwapCacheKey(false, item.SwapID, item.Bind)
+		if _, dup := exist[key]; dup {
+			return fmt.Errorf("batch swap %v is duplicated", item.SwapID)
+		}
+		exist[key] = struct{}{}
+
+		swapInfo, err := verifySwapTransaction(srcBridge, item.PairID, item.SwapID, item.Bind, item.TxType)
+		if err != nil {
+			logWorkerError("accept", "verify batch swap failed", err, "pairID", item.PairID, "txid", item.SwapID, "bind", item.Bind, "batchID", args.SwapID)
+			return err
+		}
+		buildTxArgs.Batch = append(buildTxArgs.Batch, &tokens.BatchSwapArgs{
+			SwapInfo:    item.SwapInfo,
+			OriginValue: swapInfo.Value,
+		})
+	}
+
+	rawTx, err := builder.BuildBatchRawTransaction(buildTxArgs)
+	if err != nil {
+		return err
+	}
+	err = dstBridge.VerifyMsgHash(rawTx, msgHash)
+	if err != nil {
+		return err
+	}
+	return builder.VerifyBatchRawTransaction(rawTx, args)
+}
+
 func saveAcceptRecord(bridge tokens.CrossChainBridge, keyID string, args *tokens.BuildTxArgs, rawTx interface{}) {
 	impl, ok := bridge.(interface {
 		GetSignedTxHashOfKeyID(keyID, pairID string, rawTx interface{}) (txHash string, err error)
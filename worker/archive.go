@@ -0,0 +1,60 @@
+package worker
+
+import (
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+var archiveBatchSize = 1000
+
+// StartArchiveJob archive stable swaps job
+func StartArchiveJob() {
+	archiveCfg := params.GetArchiveConfig()
+	if archiveCfg == nil || !archiveCfg.Enable {
+		return
+	}
+	logWorker("archive", "start archive stable swaps job", "archiveAfter", archiveCfg.GetArchiveAfter(), "interval", archiveCfg.GetInterval())
+	for loop := 1; ; loop++ {
+		if utils.IsCleanuping() {
+			logWorker("archive", "stop archive stable swaps job")
+			return
+		}
+		before := time.Now().Add(-archiveCfg.GetArchiveAfter()).Unix()
+		swapins, swapouts, err := ArchiveStableSwaps(before)
+		if err != nil {
+			logWorkerError("archive", "archive stable swaps failed", err, "loop", loop, "before", before, "swapins", swapins, "swapouts", swapouts)
+		} else {
+			logWorker("archive", "finish archive stable swaps", "loop", loop, "before", before, "swapins", swapins, "swapouts", swapouts)
+		}
+		time.Sleep(archiveCfg.GetInterval())
+	}
+}
+
+// ArchiveStableSwaps archive stable swaps whose result timestamp is before `before`,
+// returns the count of archived swapins and swapouts
+func ArchiveStableSwaps(before int64) (swapins, swapouts int, err error) {
+	var exportDir string
+	if archiveCfg := params.GetArchiveConfig(); archiveCfg != nil {
+		exportDir = archiveCfg.ExportDir
+	}
+	swapins, err = archiveStableSwaps(true, before, exportDir)
+	if err != nil {
+		return swapins, swapouts, err
+	}
+	swapouts, err = archiveStableSwaps(false, before, exportDir)
+	return swapins, swapouts, err
+}
+
+func archiveStableSwaps(isSwapin bool, before int64, exportDir string) (total int, err error) {
+	for !utils.IsCleanuping() {
+		count, err := mongodb.ArchiveStableSwaps(isSwapin, before, archiveBatchSize, exportDir)
+		total += count
+		if err != nil || count < archiveBatchSize {
+			return total, err
+		}
+	}
+	return total, nil
+}
@@ -0,0 +1,345 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	// key is batch swapout task queue name of pair
+	batchSwapoutQueues = make(map[string]struct{})
+
+	errBatchTxNotSupported = errors.New("bridge does not support batch tx")
+)
+
+// batchSwapError the swap of index in batch failed, it should be dropped
+// and the batch retried with the rest of the swaps
+type batchSwapError struct {
+	index int
+	err   error
+}
+
+func (e *batchSwapError) Error() string {
+	return fmt.Sprintf("batch swap %v failed: %v", e.index, e.err)
+}
+
+func (e *batchSwapError) Unwrap() error {
+	return e.err
+}
+
+// isBatchSwapoutPair swapouts of pair are paid by batch tx
+func isBatchSwapoutPair(pairID string) bool {
+	batchCfg := params.GetBatchSwapoutConfig()
+	if batchCfg == nil || !batchCfg.IsBatchPair(pairID) {
+		return false
+	}
+	_, ok := tokens.GetCrossChainBridge(true).(tokens.BatchTxBuilder)
+	return ok
+}
+
+func getBatchSwapoutQueue(pairID string) string {
+	return strings.ToLower("batchswapout:" + pairID)
+}
+
+func addBatchSwapoutJob(pairID string) {
+	if !isBatchSwapoutPair(pairID) {
+		return
+	}
+	queue := getBatchSwapoutQueue(pairID)
+	if _, exist := batchSwapoutQueues[queue]; !exist {
+		batchSwapoutQueues[queue] = struct{}{}
+		utils.TopWaitGroup.Add(1)
+		go processBatchSwapoutTasks(queue, strings.ToLower(pairID))
+	}
+}
+
+// processBatchSwapoutTasks collect pending swapouts of pair in every window
+// and pay them with one batch tx
+func processBatchSwapoutTasks(queue, pairID string) {
+	defer utils.TopWaitGroup.Done()
+	batchCfg := params.GetBatchSwapoutConfig()
+	window := batchCfg.GetWindow()
+	maxBatchSize := batchCfg.GetMaxBatchSize()
+	logWorker("batchswap", "start batch swapout job", "pairID", pairID, "window", window, "maxBatchSize", maxBatchSize)
+	for {
+		select {
+		case <-utils.CleanupChan:
+			logWorker("batchswap", "stop batch swapout job", "pairID", pairID)
+			return
+		case <-time.After(window):
+		}
+		for IsLeader() && !utils.IsCleanuping() {
+			tasks := leaseBatchSwapoutTasks(queue, maxBatchSize)
+			if len(tasks) == 0 {
+				break
+			}
+			handleBatchSwapoutTasks(pairID, tasks)
+			if len(tasks) < maxBatchSize {
+				break
+			}
+		}
+	}
+}
+
+func leaseBatchSwapoutTasks(queue string, maxBatchSize int) (tasks []*mongodb.MgoSwapTask) {
+	for len(tasks) < maxBatchSize {
+		task, err := mongodb.LeaseSwapTask(queue, swapTaskVisibilityTimeout)
+		if err != nil {
+			if !errors.Is(err, mongodb.ErrItemNotFound) {
+				logWorkerError("batchswap", "lease swap task failed", err, "queue", queue)
+			}
+			break
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks
+}
+
+func handleBatchSwapoutTasks(pairID string, tasks []*mongodb.MgoSwapTask) {
+	tokenCfg := tokens.GetCrossChainBridge(true).GetTokenConfig(pairID)
+	swaps := make([]*tokens.BuildTxArgs, 0, len(tasks))
+	batchTasks := make([]*mongodb.MgoSwapTask, 0, len(tasks))
+	cacheKeys := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		var args tokens.BuildTxArgs
+		err := json.Unmarshal([]byte(task.Args), &args)
+		if err != nil {
+			logWorkerError("batchswap", "wrong swap task args", err, "key", task.Key)
+			_ = mongodb.MarkSwapTaskDead(task, err)
+			continue
+		}
+		if tokenCfg == nil || !strings.EqualFold(args.From, tokenCfg.DcrmAddress) ||
+			!strings.EqualFold(args.PairID, pairID) || args.SwapType != tokens.SwapoutType {
+			logWorkerWarn("batchswap", "ignore swap task as mismatch reason", "pairID", pairID, "args", args)
+			_ = mongodb.MarkSwapTaskDead(task, errors.New("swap task mismatch"))
+			continue
+		}
+		err = checkBatchSwap(&args)
+		if err == nil {
+			cacheKey := getSwapCacheKey(false, args.SwapID, args.Bind)
+			err = checkAndUpdateProcessSwapTaskCache(cacheKey)
+			if err == nil {
+				cacheKeys = append(cacheKeys, cacheKey)
+			}
+		}
+		switch {
+		case err == nil:
+			swaps = append(swaps, &args)
+			batchTasks = append(batchTasks, task)
+		case errors.Is(err, errAlreadySwapped):
+			_ = mongodb.AckSwapTask(task)
+		default:
+			logWorkerError("batchswap", "check swap failed", err, "pairID", pairID, "txid", args.SwapID, "bind", args.Bind, "attempts", task.Attempts)
+			_ = mongodb.NackSwapTask(task, swapTaskRetryDelay, err)
+		}
+	}
+	for len(swaps) > 0 {
+		isCommitted, err := doBatchSwapout(pairID, tokenCfg.DcrmAddress, swaps)
+		var swapErr *batchSwapError
+		if !isCommitted && errors.As(err, &swapErr) {
+			// drop the failed swap and retry the batch with the rest
+			i := swapErr.index
+			task, swap := batchTasks[i], swaps[i]
+			if errors.Is(swapErr.err, errAlreadySwapped) {
+				_ = mongodb.AckSwapTask(task)
+			} else {
+				logWorkerError("batchswap", "drop swap from batch", swapErr.err, "pairID", pairID, "txid", swap.SwapID, "bind", swap.Bind, "attempts", task.Attempts)
+				cachedSwapTasks.Remove(cacheKeys[i])
+				_ = mongodb.NackSwapTask(task, swapTaskRetryDelay, swapErr.err)
+			}
+			swaps = append(swaps[:i], swaps[i+1:]...)
+			batchTasks = append(batchTasks[:i], batchTasks[i+1:]...)
+			cacheKeys = append(cacheKeys[:i], cacheKeys[i+1:]...)
+			continue
+		}
+		if !isCommitted {
+			for _, cacheKey := range cacheKeys {
+				cachedSwapTasks.Remove(cacheKey)
+			}
+		}
+		for _, task := range batchTasks {
+			if err == nil {
+				_ = mongodb.AckSwapTask(task)
+			} else {
+				_ = mongodb.NackSwapTask(task, swapTaskRetryDelay, err)
+			}
+		}
+		return
+	}
+}
+
+func checkBatchSwap(args *tokens.BuildTxArgs) error {
+	res, err := mongodb.FindSwapResult(false, args.SwapID, args.PairID, args.Bind)
+	if err != nil {
+		return err
+	}
+	return preventReswap(res, false)
+}
+
+func doBatchSwapout(pairID, dcrmAddress string, swaps []*tokens.BuildTxArgs) (isCommitted bool, err error) {
+	resBridge := tokens.GetCrossChainBridge(true)
+	builder, ok := resBridge.(tokens.BatchTxBuilder)
+	if !ok {
+		return false, errBatchTxNotSupported
+	}
+
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			Identifier: params.GetIdentifier(),
+			PairID:     pairID,
+			SwapID:     swaps[0].SwapID, // batch id
+			SwapType:   tokens.SwapoutType,
+		},
+		From: dcrmAddress,
+	}
+	for i, swap := range swaps {
+		if !resBridge.IsValidAddress(swap.Bind) {
+			return false, &batchSwapError{index: i, err: tokens.ErrTxWithWrongMemo}
+		}
+		if tokens.CalcSwappedValue(pairID, swap.OriginValue, false).Sign() <= 0 {
+			return false, &batchSwapError{index: i, err: tokens.ErrTxWithWrongValue}
+		}
+		args.Batch = append(args.Batch, &tokens.BatchSwapArgs{
+			SwapInfo:    swap.SwapInfo,
+			OriginValue: swap.OriginValue,
+		})
+	}
+	batchID := args.SwapID
+	logWorker("batchswap", "start to process", "pairID", pairID, "batchID", batchID, "count", len(args.Batch))
+
+	rawTx, err := builder.BuildBatchRawTransaction(args)
+	if err != nil {
+		logWorkerError("batchswap", "build batch tx failed", err, "pairID", pairID, "batchID", batchID)
+		return false, err
+	}
+
+	var signedTx interface{}
+	var signTxHash string
+
+	// write sign intents of every swap ahead, finish them after sending (or abort them)
+	intents := make([]*mongodb.MgoSignIntent, 0, len(args.Batch))
+	intentStatus := mongodb.SignIntentAborted
+	defer func() {
+		var memo string
+		if err != nil {
+			memo = err.Error()
+		}
+		for _, intent := range intents {
+			_ = mongodb.UpdateSignIntentStatus(intent.Key, intentStatus, signTxHash, memo)
+		}
+	}()
+	for i, item := range args.Batch {
+		itemArgs := &tokens.BuildTxArgs{
+			SwapInfo: item.SwapInfo,
+			From:     args.From,
+			Extra:    args.Extra,
+		}
+		intent := newSignIntent(itemArgs, false, item.SwapValue.String())
+		err = mongodb.OpenSignIntent(intent)
+		if err != nil {
+			return false, &batchSwapError{index: i, err: err}
+		}
+		intents = append(intents, intent)
+	}
+
+	tokenCfg := resBridge.GetTokenConfig(pairID)
+	for i := 1; i <= 3; i++ { // with retry
+		if tokenCfg.GetDcrmAddressPrivateKey() != nil {
+			signedTx, signTxHash, err = resBridge.SignTransaction(rawTx, pairID)
+		} else {
+			signedTx, signTxHash, err = resBridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+		}
+		if err == nil {
+			break
+		}
+		logWorkerError("batchswap", "sign batch tx failed", err, "pairID", pairID, "batchID", batchID, "signCount", i)
+		restInJob(retrySignInterval)
+	}
+	if err != nil {
+		return false, err
+	}
+	for _, intent := range intents {
+		_ = mongodb.UpdateSignIntentStatus(intent.Key, mongodb.SignIntentSigned, signTxHash, "")
+	}
+
+	// recheck leadership and reswap of every swap before update db,
+	// the batch tx can not be sent if any swap of it is already swapped
+	if !IsLeader() {
+		return false, errNotLeader
+	}
+	for i, swap := range swaps {
+		err = checkBatchSwap(swap)
+		if err != nil {
+			return false, &batchSwapError{index: i, err: err}
+		}
+	}
+
+	// update database before sending transaction
+	err = commitBatchSwapTx(args, signTxHash)
+	if err != nil {
+		return false, err
+	}
+	intentStatus = mongodb.SignIntentCommitted
+	for _, intent := range intents {
+		_ = mongodb.UpdateSignIntentStatus(intent.Key, intentStatus, signTxHash, "")
+	}
+
+	first := args.Batch[0]
+	txHash, err := sendSignedTransaction(resBridge, signedTx, first.SwapID, pairID, first.Bind, false)
+	intentStatus = mongodb.SignIntentFinalized
+	if txHash != "" {
+		for _, item := range args.Batch[1:] {
+			addSwapHistory(false, item.SwapID, item.Bind, txHash)
+			_ = mongodb.AddSwapHistory(false, item.SwapID, item.Bind, txHash)
+		}
+	}
+	if err == nil {
+		logWorker("batchswap", "send batch tx success", "pairID", pairID, "batchID", batchID, "count", len(args.Batch), "txHash", txHash)
+		if txHash != signTxHash {
+			logWorkerError("batchswap", "send batch tx success but with different hash", errSendTxWithDiffHash, "pairID", pairID, "batchID", batchID, "txHash", txHash, "signTxHash", signTxHash)
+			for _, item := range args.Batch {
+				_ = replaceSwapResult(item.SwapID, pairID, item.Bind, txHash, item.SwapValue.String(), false)
+			}
+		}
+	}
+	return true, err
+}
+
+// commitBatchSwapTx commit swap results, swap statuses and swap histories
+// of all the swaps paid by the signed batch tx in one database transaction
+func commitBatchSwapTx(args *tokens.BuildTxArgs, swapTx string) error {
+	commits := make([]*mongodb.SwapTxCommit, 0, len(args.Batch))
+	for _, item := range args.Batch {
+		commits = append(commits, &mongodb.SwapTxCommit{
+			IsSwapin: false,
+			TxID:     item.SwapID,
+			PairID:   item.PairID,
+			Bind:     item.Bind,
+			ResultItems: &mongodb.SwapResultUpdateItems{
+				SwapTx:    swapTx,
+				SwapValue: item.SwapValue.String(),
+				SwapMemo:  item.Memo,
+				Status:    mongodb.MatchTxNotStable,
+				Timestamp: now(),
+			},
+			SwapStatus: mongodb.TxProcessed,
+			History:    mongodb.NewSwapHistory(false, item.SwapID, item.Bind, swapTx),
+			Actor:      "swap",
+		})
+	}
+	err := mongodb.CommitBatchSwapTx(commits)
+	if err != nil {
+		logWorkerError("update", "commitBatchSwapTx", err, "pairID", args.PairID, "batchID", args.SwapID, "swaptx", swapTx, "count", len(commits))
+	} else {
+		logWorker("update", "commitBatchSwapTx", "pairID", args.PairID, "batchID", args.SwapID, "swaptx", swapTx, "count", len(commits))
+	}
+	return err
+}
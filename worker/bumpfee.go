@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var errCannotBumpFee = errors.New("swap can not be bumped fee")
+
+func getFeeBumper(isSrc bool) tokens.FeeBumper {
+	bridge := tokens.GetCrossChainBridge(isSrc)
+	if bridge == nil {
+		return nil
+	}
+	feeBumper, _ := bridge.(tokens.FeeBumper)
+	return feeBumper
+}
+
+// getCPFPSwapTx replace always updates swaptx to the latest,
+// so the latest old swaptx different from swaptx is a cpfp child of it
+func getCPFPSwapTx(res *mongodb.MgoSwapResult) string {
+	if len(res.OldSwapTxs) == 0 {
+		return ""
+	}
+	latest := res.OldSwapTxs[len(res.OldSwapTxs)-1]
+	if latest == res.SwapTx {
+		return ""
+	}
+	return latest
+}
+
+// bumpSwapFee speed up unconfirmed swap tx of utxo chain by RBF or CPFP
+func bumpSwapFee(txid, pairID, bind string, isSwapin bool) (txHash string, err error) {
+	swap, res, err := verifyReplaceSwap(txid, pairID, bind, isSwapin)
+	if err != nil {
+		return "", err
+	}
+
+	swapInfo, err := reverifySwapToReplace(swap, res, isSwapin)
+	if err != nil {
+		return "", err
+	}
+
+	bridge := tokens.GetCrossChainBridge(!isSwapin)
+	tokenCfg := bridge.GetTokenConfig(pairID)
+	swapType := getSwapType(isSwapin)
+
+	extra, err := getFeeBumper(!isSwapin).GetFeeBumpExtra(pairID, res.SwapTx, getCPFPSwapTx(res))
+	if err != nil {
+		logWorkerError("bumpSwapFee", "get fee bump extra failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin, "swaptx", res.SwapTx)
+		return "", err
+	}
+	isChildTx := extra.ParentTx != ""
+
+	replaceNum := uint64(len(res.OldSwapTxs))
+	if replaceNum == 0 {
+		replaceNum++
+	}
+
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			Identifier: params.GetIdentifier(),
+			PairID:     pairID,
+			SwapID:     txid,
+			SwapType:   swapType,
+			TxType:     tokens.SwapTxType(swap.TxType),
+			Bind:       bind,
+		},
+		From:        tokenCfg.DcrmAddress,
+		OriginValue: swapInfo.Value,
+		ReplaceNum:  replaceNum,
+		Extra: &tokens.AllExtras{
+			BtcExtra: extra,
+		},
+	}
+	rawTx, err := bridge.BuildRawTransaction(args)
+	if err != nil {
+		logWorkerError("bumpSwapFee", "build tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin, "parentTx", extra.ParentTx, "replaceTx", extra.ReplaceTx)
+		return "", errBuildTxFailed
+	}
+	var signedTx interface{}
+	var signTxHash string
+	if tokenCfg.GetDcrmAddressPrivateKey() != nil {
+		signedTx, signTxHash, err = bridge.SignTransaction(rawTx, pairID)
+	} else {
+		signedTx, signTxHash, err = bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+	}
+	if err != nil {
+		logWorkerError("bumpSwapFee", "sign tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		return "", errSignTxFailed
+	}
+
+	// the payment output is unchanged, and cpfp child pays nothing to bind
+	err = addOldSwapTx(txid, pairID, bind, signTxHash, res.SwapValue, isSwapin, isChildTx)
+	if err != nil {
+		return "", errUpdateOldTxsFailed
+	}
+	txHash, err = sendSignedTransaction(bridge, signedTx, txid, pairID, bind, isSwapin)
+	if err == nil && txHash != signTxHash {
+		logWorkerError("bumpSwapFee", "send tx success but with different hash", errSendTxWithDiffHash, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "txHash", txHash, "signTxHash", signTxHash)
+		_ = addOldSwapTx(txid, pairID, bind, txHash, res.SwapValue, isSwapin, isChildTx)
+	}
+	if err == nil {
+		logWorker("bumpSwapFee", "bump swap fee success", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "txHash", txHash, "isCPFP", isChildTx, "relayFeePerKb", *extra.RelayFeePerKb)
+	}
+	return txHash, err
+}
@@ -72,6 +72,7 @@ func updateSwapResult(txid, pairID, bind string, mtx *MatchTx) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
 		Status:    mongodb.KeepStatus,
 		Timestamp: now(),
+		Actor:     "swap",
 	}
 	if mtx.SwapHeight == 0 {
 		updates.SwapValue = mtx.SwapValue
@@ -114,6 +115,47 @@ func updateSwapResult(txid, pairID, bind string, mtx *MatchTx) (err error) {
 	return err
 }
 
+// commitSwapTx commit swap result, swap status, swap history and swap nonce
+// of the signed swap tx in one database transaction
+func commitSwapTx(bridge tokens.CrossChainBridge, args *tokens.BuildTxArgs, mtx *MatchTx) (err error) {
+	txid := args.SwapID
+	pairID := args.PairID
+	bind := args.Bind
+	isSwapin := mtx.SwapType == tokens.SwapinType
+	commit := &mongodb.SwapTxCommit{
+		IsSwapin: isSwapin,
+		TxID:     txid,
+		PairID:   pairID,
+		Bind:     bind,
+		ResultItems: &mongodb.SwapResultUpdateItems{
+			SwapTx:    mtx.SwapTx,
+			SwapValue: mtx.SwapValue,
+			SwapNonce: mtx.SwapNonce,
+			Status:    mongodb.MatchTxNotStable,
+			Timestamp: now(),
+		},
+		SwapStatus: mongodb.TxProcessed,
+		History:    mongodb.NewSwapHistory(isSwapin, txid, bind, mtx.SwapTx),
+		Actor:      "swap",
+	}
+	if _, ok := bridge.(tokens.NonceSetter); ok {
+		commit.SwapNonce = mongodb.NewLatestSwapNonce(args.From, isSwapin, mtx.SwapNonce+1)
+	}
+	err = mongodb.CommitSwapTx(commit)
+	if err != nil {
+		logWorkerError("update", "commitSwapTx", err,
+			"txid", txid, "pairID", pairID, "bind", bind,
+			"swaptx", mtx.SwapTx, "swapvalue", mtx.SwapValue,
+			"swaptype", mtx.SwapType, "swapnonce", mtx.SwapNonce)
+	} else {
+		logWorker("update", "commitSwapTx",
+			"txid", txid, "pairID", pairID, "bind", bind,
+			"swaptx", mtx.SwapTx, "swapvalue", mtx.SwapValue,
+			"swaptype", mtx.SwapType, "swapnonce", mtx.SwapNonce)
+	}
+	return err
+}
+
 func updateSwapResultHeight(swap *mongodb.MgoSwapResult, blockHeight, blockTime uint64, updateSwapTx bool) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
 		Status:    mongodb.KeepStatus,
@@ -168,6 +210,7 @@ func updateSwapResultTx(txid, pairID, bind, swapTx, swapValue string, isSwapin b
 		SwapTx:    swapTx,
 		SwapValue: swapValue,
 		Timestamp: now(),
+		Actor:     "stable",
 	}
 	if isSwapin {
 		err = mongodb.UpdateSwapinResult(txid, pairID, bind, updates)
@@ -210,7 +253,7 @@ func markSwapResultStable(txid, pairID, bind string, isSwapin bool) (err error)
 	status := mongodb.MatchTxStable
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo, "stable")
 	if err != nil {
 		logWorkerError("stable", "markSwapResultStable", err, "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin)
 	} else {
@@ -223,7 +266,7 @@ func markSwapResultFailed(txid, pairID, bind string, isSwapin bool) (err error)
 	status := mongodb.MatchTxFailed
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo, "stable")
 	if err != nil {
 		logWorkerError("stable", "markSwapResultFailed", err, "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin)
 	} else {
@@ -0,0 +1,108 @@
+package worker
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var errSwapTxNotExpired = errors.New("swaptx is not expired")
+
+func getTxExpirer(isSrc bool) tokens.TxExpirer {
+	bridge := tokens.GetCrossChainBridge(isSrc)
+	if bridge == nil {
+		return nil
+	}
+	txExpirer, _ := bridge.(tokens.TxExpirer)
+	return txExpirer
+}
+
+// checkIfSwapTxExpired swap result timestamp is updated after building swaptx,
+// so it's the time after which the latest swaptx is built
+func checkIfSwapTxExpired(txExpirer tokens.TxExpirer, res *mongodb.MgoSwapResult) error {
+	if res.SwapTx == "" {
+		return errSwapTxNotExpired
+	}
+	expired, err := txExpirer.IsTxExpired(res.SwapTx, res.Timestamp)
+	if err != nil {
+		return err
+	}
+	if !expired {
+		return errSwapTxNotExpired
+	}
+	return nil
+}
+
+// rebuildExpiredSwapTx rebuild swap tx which is dropped after expiration,
+// the new tx refers to the latest block and has a new expiration
+func rebuildExpiredSwapTx(txid, pairID, bind string, isSwapin bool) (txHash string, err error) {
+	swap, res, err := verifyReplaceSwap(txid, pairID, bind, isSwapin)
+	if err != nil {
+		return "", err
+	}
+
+	swapInfo, err := reverifySwapToReplace(swap, res, isSwapin)
+	if err != nil {
+		return "", err
+	}
+
+	bridge := tokens.GetCrossChainBridge(!isSwapin)
+	tokenCfg := bridge.GetTokenConfig(pairID)
+	swapType := getSwapType(isSwapin)
+
+	replaceNum := uint64(len(res.OldSwapTxs))
+	if replaceNum == 0 {
+		replaceNum++
+	}
+
+	// empty extra args, reference block and expiration are set to the latest
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			Identifier: params.GetIdentifier(),
+			PairID:     pairID,
+			SwapID:     txid,
+			SwapType:   swapType,
+			TxType:     tokens.SwapTxType(swap.TxType),
+			Bind:       bind,
+		},
+		From:        tokenCfg.DcrmAddress,
+		OriginValue: swapInfo.Value,
+		ReplaceNum:  replaceNum,
+	}
+	rawTx, err := bridge.BuildRawTransaction(args)
+	if err != nil {
+		logWorkerError("rebuildExpiredSwapTx", "build tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		return "", errBuildTxFailed
+	}
+	var signedTx interface{}
+	var signTxHash string
+	if tokenCfg.GetDcrmAddressPrivateKey() != nil {
+		signedTx, signTxHash, err = bridge.SignTransaction(rawTx, pairID)
+	} else {
+		signedTx, signTxHash, err = bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+	}
+	if err != nil {
+		logWorkerError("rebuildExpiredSwapTx", "sign tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		return "", errSignTxFailed
+	}
+
+	swapValue := ""
+	if args.SwapValue != nil {
+		swapValue = args.SwapValue.String()
+	}
+	err = replaceSwapResult(txid, pairID, bind, signTxHash, swapValue, isSwapin)
+	if err != nil {
+		return "", errUpdateOldTxsFailed
+	}
+	txHash, err = sendSignedTransaction(bridge, signedTx, txid, pairID, bind, isSwapin)
+	if err == nil && txHash != signTxHash {
+		logWorkerError("rebuildExpiredSwapTx", "send tx success but with different hash", errSendTxWithDiffHash, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "txHash", txHash, "signTxHash", signTxHash)
+		_ = replaceSwapResult(txid, pairID, bind, txHash, swapValue, isSwapin)
+	}
+	if err == nil {
+		logWorker("rebuildExpiredSwapTx", "rebuild expired swap tx success", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "txHash", txHash, "expiredTx", res.SwapTx)
+	}
+	return txHash, err
+}
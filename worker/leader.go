@@ -0,0 +1,91 @@
+package worker
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+)
+
+var (
+	// local deadline (unix milliseconds) before which we are surely the leader
+	leaderDeadline int64
+
+	errNotLeader = errors.New("swap server is not the leader")
+)
+
+// IsLeader is this swap server the leader (always true if HA is disabled)
+func IsLeader() bool {
+	if !params.IsHAEnabled() {
+		return true
+	}
+	return atomic.LoadInt64(&leaderDeadline) > common.NowMilli()
+}
+
+func getLeaderLeaseName() string {
+	return "leader:" + params.GetIdentifier()
+}
+
+// StartLeaderElectionJob compete for the leader lease, call onElected once
+// when this replica becomes the leader. the leader exits when it lost the
+// lease, as the running jobs can not be stopped safely.
+func StartLeaderElectionJob(onElected func()) {
+	haCfg := params.GetHAConfig()
+	leaseName := getLeaderLeaseName()
+	nodeID := haCfg.GetNodeID()
+	leaseTime := haCfg.GetLeaseTime()
+	renewInterval := haCfg.GetRenewInterval()
+	logWorker("leader", "start leader election job", "lease", leaseName, "node", nodeID, "leaseTime", leaseTime, "renewInterval", renewInterval)
+
+	var elected bool
+	var term uint64
+	for {
+		if utils.IsCleanuping() {
+			if elected {
+				atomic.StoreInt64(&leaderDeadline, 0)
+				_ = mongodb.ReleaseLeaderLease(leaseName, nodeID)
+			}
+			logWorker("leader", "stop leader election job")
+			return
+		}
+		start := common.NowMilli()
+		lease, err := mongodb.AcquireLeaderLease(leaseName, nodeID, leaseTime)
+		switch {
+		case err == nil && (!elected || lease.Term == term):
+			// stop being the leader one renew interval before the lease expires,
+			// leave time for the clock drift between replicas
+			atomic.StoreInt64(&leaderDeadline, start+(leaseTime-renewInterval).Milliseconds())
+			if !elected {
+				elected = true
+				term = lease.Term
+				logWorker("leader", "elected as leader", "lease", leaseName, "node", nodeID, "term", term)
+				onElected()
+			}
+		case elected && (err == nil || errors.Is(err, mongodb.ErrLeaseIsHeld) || !IsLeader()):
+			atomic.StoreInt64(&leaderDeadline, 0)
+			logWorkerError("leader", "lost leader lease, shutdown to avoid double swapping", err, "lease", leaseName, "node", nodeID, "term", term)
+			shutdownOnLostLeader()
+			return
+		case err != nil && !errors.Is(err, mongodb.ErrLeaseIsHeld):
+			logWorkerError("leader", "acquire leader lease failed", err, "lease", leaseName, "node", nodeID)
+		}
+		time.Sleep(renewInterval)
+	}
+}
+
+func shutdownOnLostLeader() {
+	process, err := os.FindProcess(os.Getpid())
+	if err == nil {
+		err = process.Signal(syscall.SIGTERM)
+	}
+	if err != nil {
+		log.Fatal("shutdown on lost leader failed", "err", err)
+	}
+}
@@ -116,7 +116,7 @@ func processPassBigValSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	}
 
 	if isSwapin {
-		return mongodb.PassSwapinBigValue(txid, pairID, bind)
+		return mongodb.PassSwapinBigValue(txid, pairID, bind, "passbigvalue")
 	}
-	return mongodb.PassSwapoutBigValue(txid, pairID, bind)
+	return mongodb.PassSwapoutBigValue(txid, pairID, bind, "passbigvalue")
 }
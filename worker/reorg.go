@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	errTxReorged         = errors.New("tx is reorged")
+	errNoBlockHashGetter = errors.New("bridge can not get block hash")
+)
+
+type blockHashGetter interface {
+	GetBlockHash(height uint64) (string, error)
+}
+
+// ReorgAlert alert posted to webhook when reorg is detected
+type ReorgAlert struct {
+	Identifier   string `json:"identifier"`
+	PairID       string `json:"pairid"`
+	TxID         string `json:"txid"`
+	Bind         string `json:"bind"`
+	IsSwapin     bool   `json:"isswapin"`
+	IsSwapTx     bool   `json:"isswaptx"`
+	TxHash       string `json:"txhash"`
+	Height       uint64 `json:"height"`
+	OldBlockHash string `json:"oldblockhash"`
+	NewBlockHash string `json:"newblockhash"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+// StartReorgMonitorJob watch block hashes of swap source txs and swap txs,
+// move swaps with reorged txs to status TxReorged and halt their pairs.
+func StartReorgMonitorJob() {
+	if !params.IsReorgMonitorEnabled() {
+		return
+	}
+	cfg := params.GetReorgMonitorConfig()
+	logWorker("reorg", "start reorg monitor job", "interval", cfg.GetCheckInterval(), "watchTime", cfg.GetWatchTime())
+	for {
+		if utils.IsCleanuping() {
+			logWorker("reorg", "stop reorg monitor job")
+			return
+		}
+		haltReorgedPairs()
+		checkTxBlockHashes(cfg)
+		restInJob(cfg.GetCheckInterval())
+	}
+}
+
+// txs on source chain are source txs of swapins and swap txs of swapouts
+func isTxOnSrcChain(isSwapin, isSwapTx bool) bool {
+	return isSwapin != isSwapTx
+}
+
+func getBlockHash(isSrc bool, height uint64) (string, error) {
+	getter, ok := tokens.GetCrossChainBridge(isSrc).(blockHashGetter)
+	if !ok {
+		return "", errNoBlockHashGetter
+	}
+	return getter.GetBlockHash(height)
+}
+
+// recordTxBlockHash record block hash of swap source tx or swap tx once if reorg monitor is enabled
+func recordTxBlockHash(isSwapin bool, txid, pairID, bind string, isSwapTx bool, txHash string, height uint64, blockHash string) error {
+	if !params.IsReorgMonitorEnabled() || mongodb.IsTxBlockHashRecorded(isSwapin, txid, pairID, bind, isSwapTx) {
+		return nil
+	}
+	return addTxBlockHash(isSwapin, txid, pairID, bind, isSwapTx, txHash, height, blockHash)
+}
+
+// recordSrcTxBlockHash record block hash of swap source tx once if reorg monitor is enabled,
+// the block hash is from tx status, so that a reorg before recording is not hidden.
+func recordSrcTxBlockHash(bridge tokens.CrossChainBridge, isSwapin bool, txid, pairID, bind string) error {
+	if !params.IsReorgMonitorEnabled() || mongodb.IsTxBlockHashRecorded(isSwapin, txid, pairID, bind, false) {
+		return nil
+	}
+	txStatus := bridge.GetTransactionStatus(txid)
+	if txStatus == nil || txStatus.BlockHeight == 0 {
+		return fmt.Errorf("get block of tx %v failed", txid)
+	}
+	return addTxBlockHash(isSwapin, txid, pairID, bind, false, txid, txStatus.BlockHeight, txStatus.BlockHash)
+}
+
+func addTxBlockHash(isSwapin bool, txid, pairID, bind string, isSwapTx bool, txHash string, height uint64, blockHash string) (err error) {
+	if height == 0 {
+		return nil
+	}
+	if blockHash == "" {
+		blockHash, err = getBlockHash(isTxOnSrcChain(isSwapin, isSwapTx), height)
+		if errors.Is(err, errNoBlockHashGetter) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("get block hash of height %v failed, %w", height, err)
+		}
+	}
+	return mongodb.AddTxBlockHash(isSwapin, txid, pairID, bind, isSwapTx, txHash, height, blockHash)
+}
+
+func checkTxBlockHashes(cfg *params.ReorgMonitorConfig) {
+	since := common.NowMilli() - cfg.GetWatchTime().Milliseconds()
+	if err := mongodb.PruneTxBlockHashes(since); err != nil {
+		logWorkerError("reorg", "prune tx block hashes error", err)
+	}
+	items, err := mongodb.FindTxBlockHashesToWatch(since)
+	if err != nil {
+		logWorkerError("reorg", "find tx block hashes error", err)
+		return
+	}
+	// cache block hashes in one round, key is chain and height
+	cache := make(map[string]string)
+	for _, item := range items {
+		if utils.IsCleanuping() {
+			return
+		}
+		isSrc := isTxOnSrcChain(item.IsSwapin, item.IsSwapTx)
+		cacheKey := fmt.Sprintf("%v:%v", isSrc, item.Height)
+		hash, exist := cache[cacheKey]
+		if !exist {
+			hash, err = getBlockHash(isSrc, item.Height)
+			if err != nil {
+				logWorkerTrace("reorg", "get block hash failed", "isSrc", isSrc, "height", item.Height, "err", err)
+				continue
+			}
+			cache[cacheKey] = hash
+		}
+		if strings.EqualFold(hash, item.BlockHash) {
+			continue
+		}
+		// query again in case of cached or unsynced result
+		hash, err = getBlockHash(isSrc, item.Height)
+		if err != nil || strings.EqualFold(hash, item.BlockHash) {
+			continue
+		}
+		cache[cacheKey] = hash
+		handleTxReorged(item, hash)
+	}
+}
+
+func handleTxReorged(item *mongodb.MgoTxBlockHash, newBlockHash string) {
+	txKind := "source tx"
+	if item.IsSwapTx {
+		txKind = "swap tx"
+	}
+	memo := fmt.Sprintf("%v %v is reorged at height %v, block hash %v is replaced by %v", txKind, item.TxHash, item.Height, item.BlockHash, newBlockHash)
+	logWorkerError("reorg", "ALERT: reorg detected, halt swaps of pair", errTxReorged,
+		"pairID", item.PairID, "txid", item.TxID, "bind", item.Bind, "isSwapin", item.IsSwapin,
+		"isSwapTx", item.IsSwapTx, "txhash", item.TxHash, "height", item.Height,
+		"oldBlockHash", item.BlockHash, "newBlockHash", newBlockHash)
+	haltSwapPair(item.PairID)
+	if err := mongodb.MarkSwapReorged(item, memo); err != nil {
+		logWorkerError("reorg", "mark swap reorged failed", err, "pairID", item.PairID, "txid", item.TxID, "bind", item.Bind, "isSwapin", item.IsSwapin)
+	}
+	sendReorgAlert(&ReorgAlert{
+		Identifier:   params.GetIdentifier(),
+		PairID:       item.PairID,
+		TxID:         item.TxID,
+		Bind:         item.Bind,
+		IsSwapin:     item.IsSwapin,
+		IsSwapTx:     item.IsSwapTx,
+		TxHash:       item.TxHash,
+		Height:       item.Height,
+		OldBlockHash: item.BlockHash,
+		NewBlockHash: newBlockHash,
+		Timestamp:    now(),
+	})
+}
+
+func sendReorgAlert(alert *ReorgAlert) {
+	webhook := params.GetReorgMonitorConfig().AlertWebhook
+	if webhook == "" {
+		return
+	}
+	resp, err := client.HTTPPost(webhook, alert, nil, nil, 10)
+	if err != nil {
+		logWorkerError("reorg", "send reorg alert failed", err, "webhook", webhook)
+		return
+	}
+	_ = resp.Body.Close()
+}
+
+// haltReorgedPairs keep halting pairs which have unresolved reorged swaps
+func haltReorgedPairs() {
+	for _, isSwapin := range []bool{true, false} {
+		var swaps []*mongodb.MgoSwap
+		var err error
+		if isSwapin {
+			swaps, err = mongodb.FindSwapinsWithStatus(mongodb.TxReorged, 0)
+		} else {
+			swaps, err = mongodb.FindSwapoutsWithStatus(mongodb.TxReorged, 0)
+		}
+		if err != nil {
+			logWorkerError("reorg", "find reorged swaps error", err, "isSwapin", isSwapin)
+			continue
+		}
+		for _, swap := range swaps {
+			haltSwapPair(swap.PairID)
+		}
+	}
+}
+
+func haltSwapPair(pairID string) {
+	pairCfg := tokens.GetTokenPairConfig(pairID)
+	if pairCfg == nil {
+		return
+	}
+	if pairCfg.SrcToken.IsSwapDisabled() && pairCfg.DestToken.IsSwapDisabled() {
+		return
+	}
+	pairCfg.SrcToken.SetDisableSwap(true)
+	pairCfg.DestToken.SetDisableSwap(true)
+	logWorkerWarn("reorg", "halt swaps of pair", "pairID", pairID)
+}
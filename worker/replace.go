@@ -19,18 +19,19 @@ var (
 
 // StartReplaceJob replace job
 func StartReplaceJob() {
-	if tokens.DstNonceSetter != nil {
+	if tokens.DstNonceSetter != nil || getFeeBumper(false) != nil || getTxExpirer(false) != nil {
 		go startReplaceSwapinJob()
 	}
 
-	if tokens.SrcNonceSetter != nil {
+	if tokens.SrcNonceSetter != nil || getFeeBumper(true) != nil || getTxExpirer(true) != nil {
 		go startReplaceSwapoutJob()
 	}
 }
 
 func startReplaceSwapinJob() {
 	logWorker("replace", "start replace swapin job")
-	if !tokens.DstBridge.GetChainConfig().EnableReplaceSwap {
+	// rebuilding expired swap tx is always enabled as it can not be double paid
+	if !tokens.DstBridge.GetChainConfig().EnableReplaceSwap && getTxExpirer(false) == nil {
 		logWorker("replace", "stop replace swapin job as disabled")
 		return
 	}
@@ -53,7 +54,8 @@ func startReplaceSwapinJob() {
 
 func startReplaceSwapoutJob() {
 	logWorker("replace", "start replace swapout job")
-	if !tokens.SrcBridge.GetChainConfig().EnableReplaceSwap {
+	// rebuilding expired swap tx is always enabled as it can not be double paid
+	if !tokens.SrcBridge.GetChainConfig().EnableReplaceSwap && getTxExpirer(true) == nil {
 		logWorker("replace", "stop replace swapout job as disabled")
 		return
 	}
@@ -99,7 +101,9 @@ func getReplaceConfigs(isSwapin bool) (waitTimeToReplace int64, maxReplaceCount
 }
 
 func processReplaceSwap(swap *mongodb.MgoSwapResult, isSwapin bool) {
-	if swap.SwapNonce == 0 || swap.SwapHeight != 0 {
+	isFeeBump := getFeeBumper(!isSwapin) != nil
+	txExpirer := getTxExpirer(!isSwapin)
+	if swap.SwapHeight != 0 || (swap.SwapNonce == 0 && !isFeeBump && txExpirer == nil) {
 		return
 	}
 	switch swap.Status {
@@ -121,11 +125,20 @@ func processReplaceSwap(swap *mongodb.MgoSwapResult, isSwapin bool) {
 	if getSepTimeInFind(waitTimeToReplace) < swap.Timestamp {
 		return
 	}
-	bridge := tokens.GetCrossChainBridge(!isSwapin)
-	err := checkIfSwapNonceHasPassed(bridge, swap, true)
-	if err != nil {
+	if txExpirer != nil {
+		// keep timestamp which is the bound of expiration of swaptx
+		if checkIfSwapTxExpired(txExpirer, swap) == nil {
+			dispatchReplaceTask(swap)
+		}
 		return
 	}
+	if !isFeeBump {
+		bridge := tokens.GetCrossChainBridge(!isSwapin)
+		err := checkIfSwapNonceHasPassed(bridge, swap, true)
+		if err != nil {
+			return
+		}
+	}
 	_ = updateSwapTimestamp(swap.TxID, swap.PairID, swap.Bind, isSwapin)
 	dispatchReplaceTask(swap)
 }
@@ -164,12 +177,14 @@ func processReplaceSwapTask(swapChan <-chan *mongodb.MgoSwapResult) {
 }
 
 func doReplaceSwap(swap *mongodb.MgoSwapResult) {
-	if swap.SwapNonce == 0 || swap.SwapHeight != 0 {
+	isSwapin := tokens.SwapType(swap.SwapType) == tokens.SwapinType
+	isFeeBump := getFeeBumper(!isSwapin) != nil
+	isTxExpirable := getTxExpirer(!isSwapin) != nil
+	if swap.SwapHeight != 0 || (swap.SwapNonce == 0 && !isFeeBump && !isTxExpirable) {
 		return
 	}
-	isSwapin := tokens.SwapType(swap.SwapType) == tokens.SwapinType
 	nonceSetter := tokens.GetNonceSetter(!isSwapin)
-	if nonceSetter == nil {
+	if nonceSetter == nil && !isFeeBump && !isTxExpirable {
 		logWorkerWarn("replace", "not nonce support chain", "isSwapin", isSwapin)
 		return
 	}
@@ -189,15 +204,19 @@ func doReplaceSwap(swap *mongodb.MgoSwapResult) {
 	}
 }
 
-func isTransactionOnChain(bridge tokens.NonceSetter, txHash string) bool {
+func isTransactionOnChain(bridge tokens.CrossChainBridge, txHash string) bool {
 	if txHash == "" {
 		return false
 	}
-	blockHeight, _ := bridge.GetTxBlockInfo(txHash)
-	return blockHeight > 0
+	if nonceSetter, ok := bridge.(tokens.NonceSetter); ok {
+		blockHeight, _ := nonceSetter.GetTxBlockInfo(txHash)
+		return blockHeight > 0
+	}
+	txStatus := bridge.GetTransactionStatus(txHash)
+	return txStatus != nil && txStatus.BlockHeight > 0
 }
 
-func isSwapResultTxOnChain(bridge tokens.NonceSetter, res *mongodb.MgoSwapResult) bool {
+func isSwapResultTxOnChain(bridge tokens.CrossChainBridge, res *mongodb.MgoSwapResult) bool {
 	if isTransactionOnChain(bridge, res.SwapTx) {
 		return true
 	}
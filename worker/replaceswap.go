@@ -51,17 +51,33 @@ func verifyReplaceSwap(txid, pairID, bind string, isSwapin bool) (*mongodb.MgoSw
 		return nil, nil, errSwapTxWithHeight
 	}
 	bridge := tokens.GetCrossChainBridge(!isSwapin)
-	nonceSetter, ok := bridge.(tokens.NonceSetter)
-	if !ok {
-		return nil, nil, errNotNonceSupport
+	isFeeBump := getFeeBumper(!isSwapin) != nil
+	txExpirer := getTxExpirer(!isSwapin)
+	if !isFeeBump && txExpirer == nil {
+		if _, ok := bridge.(tokens.NonceSetter); !ok {
+			return nil, nil, errNotNonceSupport
+		}
 	}
-	if isSwapResultTxOnChain(nonceSetter, res) {
+	if isSwapResultTxOnChain(bridge, res) {
 		return nil, nil, errSwapTxIsOnChain
 	}
 
-	err = checkIfSwapNonceHasPassed(bridge, res, true)
-	if err != nil {
-		return nil, nil, err
+	switch {
+	case isFeeBump:
+		// swaps paid by batch tx are not bumped one by one
+		if res.SwapTx == "" || res.SwapMemo != "" {
+			return nil, nil, errCannotBumpFee
+		}
+	case txExpirer != nil:
+		err = checkIfSwapTxExpired(txExpirer, res)
+		if err != nil {
+			return nil, nil, err
+		}
+	default:
+		err = checkIfSwapNonceHasPassed(bridge, res, true)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	err = preventReplaceswapByHistory(res, isSwapin)
@@ -112,7 +128,31 @@ func checkIfSwapNonceHasPassed(bridge tokens.CrossChainBridge, res *mongodb.MgoS
 	return nil
 }
 
+func reverifySwapToReplace(swap *mongodb.MgoSwap, res *mongodb.MgoSwapResult, isSwapin bool) (*tokens.TxSwapInfo, error) {
+	srcBridge := tokens.GetCrossChainBridge(isSwapin)
+	swapInfo, err := verifySwapTransaction(srcBridge, res.PairID, res.TxID, res.Bind, tokens.SwapTxType(swap.TxType))
+	if err != nil {
+		return nil, fmt.Errorf("[replace] reverify swap failed, %w", err)
+	}
+	if swapInfo.Value.String() != res.Value {
+		return nil, fmt.Errorf("[replace] reverify swap value mismatch, in db %v != %v", res.Value, swapInfo.Value)
+	}
+	if !strings.EqualFold(swapInfo.Bind, res.Bind) {
+		return nil, fmt.Errorf("[replace] reverify swap bind address mismatch, in db %v != %v", res.Bind, swapInfo.Bind)
+	}
+	return swapInfo, nil
+}
+
 func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash string, err error) {
+	if !IsLeader() {
+		return "", errNotLeader
+	}
+	if getFeeBumper(!isSwapin) != nil {
+		return bumpSwapFee(txid, pairID, bind, isSwapin)
+	}
+	if getTxExpirer(!isSwapin) != nil {
+		return rebuildExpiredSwapTx(txid, pairID, bind, isSwapin)
+	}
 	var gasPrice *big.Int
 	if gasPriceStr != "" {
 		var ok bool
@@ -127,16 +167,9 @@ func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash
 		return "", err
 	}
 
-	srcBridge := tokens.GetCrossChainBridge(isSwapin)
-	swapInfo, err := verifySwapTransaction(srcBridge, pairID, txid, bind, tokens.SwapTxType(swap.TxType))
+	swapInfo, err := reverifySwapToReplace(swap, res, isSwapin)
 	if err != nil {
-		return "", fmt.Errorf("[replace] reverify swap failed, %w", err)
-	}
-	if swapInfo.Value.String() != res.Value {
-		return "", fmt.Errorf("[replace] reverify swap value mismatch, in db %v != %v", res.Value, swapInfo.Value)
-	}
-	if !strings.EqualFold(swapInfo.Bind, bind) {
-		return "", fmt.Errorf("[replace] reverify swap bind address mismatch, in db %v != %v", bind, swapInfo.Bind)
+		return "", err
 	}
 
 	bridge := tokens.GetCrossChainBridge(!isSwapin)
@@ -169,6 +202,17 @@ func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash
 			},
 		},
 	}
+	if replacer, ok := bridge.(tokens.DynamicFeeTxReplacer); ok && gasPrice == nil {
+		gasTipCap, gasFeeCap, errf := replacer.GetReplaceGasFeeCaps(args, res.SwapTx)
+		if errf != nil {
+			logWorkerError("replaceSwap", "get replace gas fee caps failed", errf, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+			return "", errf
+		}
+		if gasTipCap != nil && gasFeeCap != nil {
+			args.Extra.EthExtra.GasTipCap = gasTipCap
+			args.Extra.EthExtra.GasFeeCap = gasFeeCap
+		}
+	}
 	rawTx, err := bridge.BuildRawTransaction(args)
 	if err != nil {
 		logWorkerError("replaceSwap", "build tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
@@ -203,6 +247,11 @@ func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash
 }
 
 func replaceSwapResult(txid, pairID, bind, txHash, swapValue string, isSwapin bool) (err error) {
+	return addOldSwapTx(txid, pairID, bind, txHash, swapValue, isSwapin, false)
+}
+
+// addOldSwapTx add txHash to old swaptxs, and replace swaptx with it unless it is a cpfp child
+func addOldSwapTx(txid, pairID, bind, txHash, swapValue string, isSwapin, isChildTx bool) (err error) {
 	updateOldSwapTxsLock.Lock()
 	defer updateOldSwapTxsLock.Unlock()
 
@@ -233,8 +282,12 @@ func replaceSwapResult(txid, pairID, bind, txHash, swapValue string, isSwapin bo
 			oldSwapVals = []string{res.SwapValue, swapValue}
 		}
 	}
+	swapTx := txHash
+	if isChildTx {
+		swapTx = res.SwapTx
+	}
 	swapType := tokens.SwapType(res.SwapType).String()
-	err = updateOldSwapTxs(txid, pairID, bind, txHash, oldSwapTxs, oldSwapVals, isSwapin)
+	err = updateOldSwapTxs(txid, pairID, bind, swapTx, oldSwapTxs, oldSwapVals, isSwapin)
 	if err != nil {
 		logWorkerError("replace", "replaceSwapResult", err, "txid", txid, "pairID", pairID, "bind", bind, "swaptx", txHash, "swapType", swapType, "nonce", res.SwapNonce, "swapValue", swapValue)
 	} else {
@@ -249,12 +302,8 @@ func preventReplaceswapByHistory(res *mongodb.MgoSwapResult, isSwapin bool) erro
 		return nil
 	}
 	resBridge := tokens.GetCrossChainBridge(!isSwapin)
-	nonceSetter, ok := resBridge.(tokens.NonceSetter)
-	if !ok {
-		return errNotNonceSupport
-	}
 	for _, swaphist := range swapHistories {
-		if isTransactionOnChain(nonceSetter, swaphist.SwapTx) {
+		if isTransactionOnChain(resBridge, swaphist.SwapTx) {
 			logWorkerError("[replace]", "forbid replace by history", errSwapTxIsOnChain,
 				"isSwapin", isSwapin, "txid", res.TxID, "bind", res.Bind, "swaptx", swaphist.SwapTx)
 			return errSwapTxIsOnChain
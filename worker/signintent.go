@@ -0,0 +1,148 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var retryRecoverSignIntentsInterval = 10 * time.Second
+
+func newSignIntent(args *tokens.BuildTxArgs, isSwapin bool, swapValue string) *mongodb.MgoSignIntent {
+	intent := &mongodb.MgoSignIntent{
+		IsSwapin:  isSwapin,
+		TxID:      args.SwapID,
+		PairID:    args.PairID,
+		Bind:      args.Bind,
+		From:      args.From,
+		Nonce:     args.GetTxNonce(),
+		SwapValue: swapValue,
+	}
+	if args.Extra != nil && args.Extra.BtcExtra != nil {
+		for _, outpoint := range args.Extra.BtcExtra.PreviousOutPoints {
+			intent.Utxos = append(intent.Utxos, fmt.Sprintf("%v:%v", outpoint.Hash, outpoint.Index))
+		}
+	}
+	return intent
+}
+
+// recordSignRequest record dcrm sign request of swap to its sign intent
+func recordSignRequest(keyID string, msgHash, msgContext []string) {
+	if len(msgContext) == 0 {
+		return
+	}
+	var args tokens.BuildTxArgs
+	if err := json.Unmarshal([]byte(msgContext[0]), &args); err != nil {
+		return
+	}
+	switch args.SwapType {
+	case tokens.SwapinType, tokens.SwapoutType:
+	default:
+		return // not swap tx, eg. aggregate tx
+	}
+	isSwapin := args.SwapType == tokens.SwapinType
+	swaps := []tokens.SwapInfo{args.SwapInfo}
+	if len(args.Batch) != 0 {
+		swaps = swaps[:0]
+		for _, item := range args.Batch {
+			swaps = append(swaps, item.SwapInfo)
+		}
+	}
+	for _, swap := range swaps {
+		key := mongodb.GetSwapTaskKey(isSwapin, swap.SwapID, swap.PairID, swap.Bind)
+		err := mongodb.AddSignIntentRequest(key, keyID, msgHash)
+		if err != nil && !errors.Is(err, mongodb.ErrItemNotFound) {
+			logWorkerError("recover", "record sign request failed", err, "key", key, "keyID", keyID)
+		}
+	}
+}
+
+// recoverSignIntents reconcile unfinished sign intents (of crashed process)
+// against database and chain state, retry until success.
+// it must be called before any new swap is signed.
+func recoverSignIntents() {
+	dcrm.SetSignRequestHook(recordSignRequest)
+	for !utils.IsCleanuping() {
+		intents, err := mongodb.FindUnfinishedSignIntents()
+		if err == nil {
+			logWorker("recover", "find unfinished sign intents", "count", len(intents))
+			for _, intent := range intents {
+				err = recoverSignIntent(intent)
+				if err != nil {
+					logWorkerError("recover", "recover sign intent failed", err, "key", intent.Key)
+					break
+				}
+			}
+		}
+		if err == nil {
+			return
+		}
+		time.Sleep(retryRecoverSignIntentsInterval)
+	}
+}
+
+func recoverSignIntent(intent *mongodb.MgoSignIntent) (err error) {
+	var status, memo string
+	res, err := mongodb.FindSwapResult(intent.IsSwapin, intent.TxID, intent.PairID, intent.Bind)
+	switch {
+	case errors.Is(err, mongodb.ErrItemNotFound):
+		status, memo = mongodb.SignIntentAborted, "recovered: swap result not found"
+	case err != nil:
+		return err
+	case intent.SignedTxHash != "" && isSwapTxOfResult(res, intent.SignedTxHash):
+		// committed swap tx is resent or replaced by the stable and replace jobs
+		status, memo = mongodb.SignIntentFinalized, "recovered: swap tx is committed"
+	case res.SwapTx != "" || len(res.OldSwapTxs) > 0:
+		status, memo = mongodb.SignIntentAborted, "recovered: swap is committed with other tx"
+	case intent.SignedTxHash != "":
+		resBridge := tokens.GetCrossChainBridge(!intent.IsSwapin)
+		if _, errt := resBridge.GetTransaction(intent.SignedTxHash); errt != nil {
+			// never committed nor sent, the nonce or utxos are not consumed
+			status, memo = mongodb.SignIntentAborted, "recovered: signed tx is not committed nor sent"
+			break
+		}
+		// should not happen as we always commit before sending
+		err = commitSwapTx(resBridge, &tokens.BuildTxArgs{
+			SwapInfo: tokens.SwapInfo{
+				PairID: intent.PairID,
+				SwapID: intent.TxID,
+				Bind:   intent.Bind,
+			},
+			From: intent.From,
+		}, &MatchTx{
+			SwapTx:    intent.SignedTxHash,
+			SwapValue: intent.SwapValue,
+			SwapType:  getSwapType(intent.IsSwapin),
+			SwapNonce: intent.Nonce,
+		})
+		if err != nil {
+			return err
+		}
+		status, memo = mongodb.SignIntentFinalized, "recovered: signed tx is found on chain"
+	default:
+		// not signed yet, dcrm sign result (if any) can not be sent without the raw tx
+		status, memo = mongodb.SignIntentAborted, "recovered: not signed"
+	}
+	logWorker("recover", "recover sign intent", "key", intent.Key, "oldStatus", intent.Status, "status", status, "memo", memo,
+		"nonce", intent.Nonce, "utxos", intent.Utxos, "keyIDs", intent.KeyIDs, "signedTxHash", intent.SignedTxHash)
+	return mongodb.UpdateSignIntentStatus(intent.Key, status, "", memo)
+}
+
+func isSwapTxOfResult(res *mongodb.MgoSwapResult, swapTx string) bool {
+	if strings.EqualFold(res.SwapTx, swapTx) {
+		return true
+	}
+	for _, oldSwapTx := range res.OldSwapTxs {
+		if strings.EqualFold(oldSwapTx, swapTx) {
+			return true
+		}
+	}
+	return false
+}
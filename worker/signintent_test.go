@@ -0,0 +1,100 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestRecoverSignIntents(t *testing.T) {
+	srcBridge, dstBridge, cleanup := newTestWorker(t)
+	defer cleanup()
+
+	tests := []struct {
+		txid        string
+		hasResult   bool
+		committedTx string
+		signedTx    string
+		onChain     bool
+		wantStatus  string
+		wantSwapTx  string
+	}{
+		// swap result not found
+		{txid: "0x01", wantStatus: mongodb.SignIntentAborted},
+		// not signed
+		{txid: "0x02", hasResult: true, wantStatus: mongodb.SignIntentAborted},
+		// signed tx is not committed nor sent
+		{txid: "0x03", hasResult: true, signedTx: "0xa3", wantStatus: mongodb.SignIntentAborted},
+		// signed tx is not committed but on chain
+		{txid: "0x04", hasResult: true, signedTx: "0xa4", onChain: true, wantStatus: mongodb.SignIntentFinalized, wantSwapTx: "0xa4"},
+		// signed tx is committed
+		{txid: "0x05", hasResult: true, committedTx: "0xa5", signedTx: "0xa5", wantStatus: mongodb.SignIntentFinalized, wantSwapTx: "0xa5"},
+		// swap is committed with other tx
+		{txid: "0x06", hasResult: true, committedTx: "0xb6", signedTx: "0xa6", wantStatus: mongodb.SignIntentAborted, wantSwapTx: "0xb6"},
+	}
+
+	for _, test := range tests {
+		if test.hasResult {
+			addTestSwapin(t, srcBridge, test.txid)
+		}
+		if test.committedTx != "" {
+			args := &tokens.BuildTxArgs{
+				SwapInfo: tokens.SwapInfo{PairID: testPairID, SwapID: test.txid, Bind: testBindAddress},
+				From:     testDstDcrmAddress,
+			}
+			mtx := &MatchTx{SwapTx: test.committedTx, SwapValue: "1000", SwapType: tokens.SwapinType}
+			if err := commitSwapTx(dstBridge, args, mtx); err != nil {
+				t.Fatalf("commit swap tx of %v failed: %v", test.txid, err)
+			}
+		}
+		if test.onChain {
+			dstBridge.txs[test.signedTx] = "signedtx:" + test.txid
+		}
+		intent := &mongodb.MgoSignIntent{
+			IsSwapin:  true,
+			TxID:      test.txid,
+			PairID:    testPairID,
+			Bind:      testBindAddress,
+			From:      testDstDcrmAddress,
+			SwapValue: "1000",
+		}
+		if err := mongodb.OpenSignIntent(intent); err != nil {
+			t.Fatalf("open sign intent of %v failed: %v", test.txid, err)
+		}
+		if test.signedTx != "" {
+			status := mongodb.SignIntentSigned
+			if test.signedTx == test.committedTx {
+				status = mongodb.SignIntentCommitted
+			}
+			if err := mongodb.UpdateSignIntentStatus(intent.Key, status, test.signedTx, ""); err != nil {
+				t.Fatalf("update sign intent of %v failed: %v", test.txid, err)
+			}
+		}
+	}
+
+	recoverSignIntents()
+
+	if intents, err := mongodb.FindUnfinishedSignIntents(); err != nil || len(intents) != 0 {
+		t.Fatalf("find unfinished sign intents, want 0, have %v, err %v", len(intents), err)
+	}
+	for _, test := range tests {
+		intent, err := mongodb.FindSignIntent(mongodb.GetSwapTaskKey(true, test.txid, testPairID, testBindAddress))
+		if err != nil {
+			t.Fatalf("find sign intent of %v failed: %v", test.txid, err)
+		}
+		if intent.Status != test.wantStatus {
+			t.Errorf("recover sign intent of %v, want %v, have %v (%v)", test.txid, test.wantStatus, intent.Status, intent.Memo)
+		}
+		if !test.hasResult {
+			continue
+		}
+		res := findTestSwapinResult(t, test.txid)
+		if res.SwapTx != test.wantSwapTx {
+			t.Errorf("swap tx of %v, want %v, have %v", test.txid, test.wantSwapTx, res.SwapTx)
+		}
+		if test.wantSwapTx != "" && res.Status != mongodb.MatchTxNotStable {
+			t.Errorf("swap result status of %v, want %v, have %v", test.txid, mongodb.MatchTxNotStable, res.Status)
+		}
+	}
+}
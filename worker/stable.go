@@ -138,6 +138,10 @@ func processSwapStable(swap *mongodb.MgoSwapResult, isSwapin bool) (err error) {
 			logWorkerWarn("[stable]", "mark swap result failed with confirms", "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind, "isSwapin", isSwapin, "swaptime", swap.Timestamp, "nowtime", now(), "confirmations", txStatus.Confirmations)
 			return markSwapResultFailed(swap.TxID, swap.PairID, swap.Bind, isSwapin)
 		}
+		err = recordTxBlockHash(isSwapin, swap.TxID, swap.PairID, swap.Bind, true, swap.SwapTx, txStatus.BlockHeight, txStatus.BlockHash)
+		if err != nil {
+			return err
+		}
 		return markSwapResultStable(swap.TxID, swap.PairID, swap.Bind, isSwapin)
 	}
 
@@ -2,10 +2,12 @@ package worker
 
 import (
 	"container/ring"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
@@ -23,9 +25,14 @@ var (
 	cachedSwapTasks    = mapset.NewSet()
 	maxCachedSwapTasks = 1000
 
-	swapChanSize       = 10
-	swapinTaskChanMap  = make(map[string]chan *tokens.BuildTxArgs)
-	swapoutTaskChanMap = make(map[string]chan *tokens.BuildTxArgs)
+	swapChanSize = 10
+
+	// key is swap task queue name, value is notify channel of new task
+	swapTaskQueues = make(map[string]chan struct{})
+
+	swapTaskVisibilityTimeout = 10 * time.Minute
+	swapTaskRetryDelay        = time.Minute
+	swapTaskPollInterval      = 3 * time.Second
 
 	errAlreadySwapped     = errors.New("already swapped")
 	errDBError            = errors.New("database error")
@@ -34,6 +41,8 @@ var (
 
 // StartSwapJob swap job
 func StartSwapJob() {
+	recoverSignIntents()
+
 	swapinNonces, swapoutNonces := mongodb.LoadAllSwapNonces()
 	if tokens.DstNonceSetter != nil {
 		tokens.DstNonceSetter.InitNonces(swapinNonces)
@@ -51,17 +60,24 @@ func StartSwapJob() {
 
 // AddSwapJob add swap job
 func AddSwapJob(pairCfg *tokens.TokenPairConfig) {
-	swapinDcrmAddr := strings.ToLower(pairCfg.DestToken.DcrmAddress)
-	if _, exist := swapinTaskChanMap[swapinDcrmAddr]; !exist {
-		swapinTaskChanMap[swapinDcrmAddr] = make(chan *tokens.BuildTxArgs, swapChanSize)
-		utils.TopWaitGroup.Add(1)
-		go processSwapTask(swapinTaskChanMap[swapinDcrmAddr], swapinDcrmAddr, true)
-	}
-	swapoutDcrmAddr := strings.ToLower(pairCfg.SrcToken.DcrmAddress)
-	if _, exist := swapoutTaskChanMap[swapoutDcrmAddr]; !exist {
-		swapoutTaskChanMap[swapoutDcrmAddr] = make(chan *tokens.BuildTxArgs, swapChanSize)
+	addSwapTaskQueue(pairCfg.DestToken.DcrmAddress, true)
+	addSwapTaskQueue(pairCfg.SrcToken.DcrmAddress, false)
+	addBatchSwapoutJob(pairCfg.PairID)
+}
+
+// getSwapTaskQueue swap task queue name of dcrm address
+func getSwapTaskQueue(dcrmAddress string, isSwapin bool) string {
+	return strings.ToLower(getSwapType(isSwapin).String() + ":" + dcrmAddress)
+}
+
+func addSwapTaskQueue(dcrmAddress string, isSwapin bool) {
+	dcrmAddress = strings.ToLower(dcrmAddress)
+	queue := getSwapTaskQueue(dcrmAddress, isSwapin)
+	if _, exist := swapTaskQueues[queue]; !exist {
+		notifyChan := make(chan struct{}, 1)
+		swapTaskQueues[queue] = notifyChan
 		utils.TopWaitGroup.Add(1)
-		go processSwapTask(swapoutTaskChanMap[swapoutDcrmAddr], swapoutDcrmAddr, false)
+		go processSwapTask(queue, notifyChan, dcrmAddress, isSwapin)
 	}
 }
 
@@ -110,7 +126,8 @@ func processSwapins(status mongodb.SwapStatus) {
 			errors.Is(err, errDBError),
 			errors.Is(err, tokens.ErrUnknownPairID),
 			errors.Is(err, tokens.ErrAddressIsInBlacklist),
-			errors.Is(err, tokens.ErrSwapIsClosed):
+			errors.Is(err, tokens.ErrSwapIsClosed),
+			errors.Is(err, errExceedsRateLimit):
 		default:
 			logWorkerError("swapin", "process swapin swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
 		}
@@ -137,7 +154,8 @@ func processSwapouts(status mongodb.SwapStatus) {
 			errors.Is(err, errDBError),
 			errors.Is(err, tokens.ErrUnknownPairID),
 			errors.Is(err, tokens.ErrAddressIsInBlacklist),
-			errors.Is(err, tokens.ErrSwapIsClosed):
+			errors.Is(err, tokens.ErrSwapIsClosed),
+			errors.Is(err, errExceedsRateLimit):
 		default:
 			logWorkerError("swapout", "process swapout swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
 		}
@@ -217,6 +235,16 @@ func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 		return fmt.Errorf("[doSwap] reverify swap bind address mismatch, in db %v != %v", bind, swapInfo.Bind)
 	}
 
+	err = recordSrcTxBlockHash(srcBridge, isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+
+	err = checkSwapVelocity(res, isSwapin, swapInfo.Value)
+	if err != nil {
+		return err
+	}
+
 	swapType := getSwapType(isSwapin)
 	args := &tokens.BuildTxArgs{
 		SwapInfo: tokens.SwapInfo{
@@ -244,7 +272,7 @@ func checkSwapResult(res *mongodb.MgoSwapResult, isSwapin bool) (dcrmAddress str
 		logWorkerTrace("swap", "swap is not configed", "pairID", pairID, "isSwapin", isSwapin)
 		return "", tokens.ErrUnknownPairID
 	}
-	if fromTokenCfg.DisableSwap {
+	if fromTokenCfg.IsSwapDisabled() {
 		logWorkerTrace("swap", "swap is disabled", "pairID", pairID, "isSwapin", isSwapin)
 		return "", tokens.ErrSwapIsClosed
 	}
@@ -255,7 +283,7 @@ func checkSwapResult(res *mongodb.MgoSwapResult, isSwapin bool) (dcrmAddress str
 	if isBlacked {
 		logWorkerTrace("swap", "address is in blacklist", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		err = tokens.ErrAddressIsInBlacklist
-		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error())
+		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error(), "swap")
 		return "", err
 	}
 
@@ -264,7 +292,7 @@ func checkSwapResult(res *mongodb.MgoSwapResult, isSwapin bool) (dcrmAddress str
 
 func preventReswap(res *mongodb.MgoSwapResult, isSwapin bool) error {
 	if res.SwapNonce > 0 || res.SwapTx != "" || res.SwapHeight != 0 || len(res.OldSwapTxs) > 0 {
-		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxProcessed, now(), "")
+		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxProcessed, now(), "", "swap")
 		return errAlreadySwapped
 	}
 	switch res.Status {
@@ -272,7 +300,7 @@ func preventReswap(res *mongodb.MgoSwapResult, isSwapin bool) error {
 		mongodb.TxWithWrongMemo,
 		mongodb.BindAddrIsContract,
 		mongodb.TxWithWrongValue:
-		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, res.Status, now(), "")
+		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, res.Status, now(), "", "swap")
 		return fmt.Errorf("forbid doswap for swap with status %v", res.Status.String())
 	default:
 	}
@@ -281,7 +309,7 @@ func preventReswap(res *mongodb.MgoSwapResult, isSwapin bool) error {
 		if history != nil {
 			logWorkerError("[doSwap]", "forbid reswap by cache", errAlreadySwapped,
 				"isSwapin", history.isSwapin, "txid", history.txid, "bind", history.bind, "swaptx", history.matchTx)
-			_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxProcessed, now(), "")
+			_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxProcessed, now(), "", "swap")
 			return errAlreadySwapped
 		}
 	}
@@ -315,54 +343,111 @@ func preventReswapByHistory(res *mongodb.MgoSwapResult, isSwapin bool) error {
 	if alreadySwapped {
 		logWorkerError("[doSwap]", "forbid reswap by history", errAlreadySwapped,
 			"isSwapin", isSwapin, "txid", res.TxID, "bind", res.Bind, "history", swapHistories)
-		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxProcessed, now(), "")
+		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxProcessed, now(), "", "swap")
 		return errAlreadySwapped
 	}
 	return nil
 }
 
 func dispatchSwapTask(args *tokens.BuildTxArgs) error {
-	from := strings.ToLower(args.From)
+	var isSwapin bool
 	switch args.SwapType {
 	case tokens.SwapinType:
-		swapChan, exist := swapinTaskChanMap[from]
-		if !exist {
-			return fmt.Errorf("no swapin task channel for dcrm address '%v'", args.From)
-		}
-		swapChan <- args
+		isSwapin = true
 	case tokens.SwapoutType:
-		swapChan, exist := swapoutTaskChanMap[from]
-		if !exist {
-			return fmt.Errorf("no swapout task channel for dcrm address '%v'", args.From)
-		}
-		swapChan <- args
+		isSwapin = false
 	default:
 		return fmt.Errorf("wrong swap type '%v'", args.SwapType.String())
 	}
+	queue := getSwapTaskQueue(args.From, isSwapin)
+	notifyChan, exist := swapTaskQueues[queue]
+	if !isSwapin && isBatchSwapoutPair(args.PairID) {
+		// batch swapouts are collected by window, no need to notify
+		queue = getBatchSwapoutQueue(args.PairID)
+		_, exist = batchSwapoutQueues[queue]
+		notifyChan = nil
+	}
+	if !exist {
+		return fmt.Errorf("no %v task queue for dcrm address '%v'", args.SwapType.String(), args.From)
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	task := &mongodb.MgoSwapTask{
+		Queue:    queue,
+		IsSwapin: isSwapin,
+		TxID:     args.SwapID,
+		PairID:   args.PairID,
+		Bind:     args.Bind,
+		Args:     string(data),
+	}
+	err = mongodb.AddSwapTask(task)
+	if errors.Is(err, mongodb.ErrItemIsDup) {
+		logWorkerTrace("doSwap", "swap task is already queued", "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "swapType", args.SwapType.String())
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	select {
+	case notifyChan <- struct{}{}:
+	default:
+	}
 	logWorker("doSwap", "dispatch swap task", "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "swapType", args.SwapType.String(), "value", args.OriginValue)
 	return nil
 }
 
-func processSwapTask(swapChan <-chan *tokens.BuildTxArgs, dcrmAddress string, isSwapin bool) {
+// processSwapTask consume durable swap tasks of queue. tasks are leased with
+// visibility timeout, so tasks of crashed process will be processed again.
+func processSwapTask(queue string, notifyChan <-chan struct{}, dcrmAddress string, isSwapin bool) {
 	defer utils.TopWaitGroup.Done()
 	for {
-		select {
-		case <-utils.CleanupChan:
-			logWorker("doSwap", "stop process swap task", "isSwapin", isSwapin, "dcrmAddress", dcrmAddress)
-			return
-		case args := <-swapChan:
-			if !strings.EqualFold(args.From, dcrmAddress) || args.SwapType != getSwapType(isSwapin) {
-				logWorkerWarn("doSwap", "ignore swap task as mismatch reason", "isSwapin", isSwapin, "dcrmAddress", dcrmAddress, "args", args)
+		if IsLeader() {
+			task, err := mongodb.LeaseSwapTask(queue, swapTaskVisibilityTimeout)
+			if err == nil {
+				handleSwapTask(task, dcrmAddress, isSwapin)
+				if utils.IsCleanuping() {
+					logWorker("doSwap", "stop process swap task", "queue", queue)
+					return
+				}
 				continue
 			}
-			err := doSwap(args)
-			switch {
-			case err == nil,
-				errors.Is(err, errAlreadySwapped):
-			default:
-				logWorkerError("doSwap", "process failed", err, "pairID", args.PairID, "txid", args.SwapID, "swapType", args.SwapType.String(), "value", args.OriginValue)
+			if !errors.Is(err, mongodb.ErrItemNotFound) {
+				logWorkerError("doSwap", "lease swap task failed", err, "queue", queue)
 			}
 		}
+		select {
+		case <-utils.CleanupChan:
+			logWorker("doSwap", "stop process swap task", "queue", queue)
+			return
+		case <-notifyChan:
+		case <-time.After(swapTaskPollInterval):
+		}
+	}
+}
+
+func handleSwapTask(task *mongodb.MgoSwapTask, dcrmAddress string, isSwapin bool) {
+	var args tokens.BuildTxArgs
+	err := json.Unmarshal([]byte(task.Args), &args)
+	if err != nil {
+		logWorkerError("doSwap", "wrong swap task args", err, "key", task.Key)
+		_ = mongodb.MarkSwapTaskDead(task, err)
+		return
+	}
+	if !strings.EqualFold(args.From, dcrmAddress) || args.SwapType != getSwapType(isSwapin) {
+		logWorkerWarn("doSwap", "ignore swap task as mismatch reason", "isSwapin", isSwapin, "dcrmAddress", dcrmAddress, "args", args)
+		_ = mongodb.MarkSwapTaskDead(task, errors.New("swap task mismatch"))
+		return
+	}
+	err = doSwap(&args)
+	switch {
+	case err == nil,
+		errors.Is(err, errAlreadySwapped):
+		_ = mongodb.AckSwapTask(task)
+	default:
+		logWorkerError("doSwap", "process failed", err, "pairID", args.PairID, "txid", args.SwapID, "swapType", args.SwapType.String(), "value", args.OriginValue, "attempts", task.Attempts)
+		_ = mongodb.NackSwapTask(task, swapTaskRetryDelay, err)
 	}
 }
 
@@ -413,9 +498,31 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	}
 
 	swapNonce := args.GetTxNonce()
+	var swapValue string
+	if args.SwapValue != nil {
+		swapValue = args.SwapValue.String()
+	} else {
+		swapValue = tokens.CalcSwappedValue(pairID, args.OriginValue, isSwapin).String()
+	}
 
 	var signedTx interface{}
 	var signTxHash string
+
+	// write sign intent ahead, finish it after sending (or abort it)
+	intent := newSignIntent(args, isSwapin, swapValue)
+	err = mongodb.OpenSignIntent(intent)
+	if err != nil {
+		return err
+	}
+	intentStatus := mongodb.SignIntentAborted
+	defer func() {
+		var memo string
+		if err != nil {
+			memo = err.Error()
+		}
+		_ = mongodb.UpdateSignIntentStatus(intent.Key, intentStatus, signTxHash, memo)
+	}()
+
 	tokenCfg := resBridge.GetTokenConfig(pairID)
 	for i := 1; i <= 3; i++ { // with retry
 		if tokenCfg.GetDcrmAddressPrivateKey() != nil {
@@ -432,8 +539,12 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	if err != nil {
 		return err
 	}
+	_ = mongodb.UpdateSignIntentStatus(intent.Key, mongodb.SignIntentSigned, signTxHash, "")
 
-	// recheck reswap before update db
+	// recheck leadership and reswap before update db
+	if !IsLeader() {
+		return errNotLeader
+	}
 	res, err := mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -446,28 +557,21 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	// update database before sending transaction
 	matchTx := &MatchTx{
 		SwapTx:    signTxHash,
+		SwapValue: swapValue,
 		SwapType:  swapType,
 		SwapNonce: swapNonce,
 	}
-	if args.SwapValue != nil {
-		matchTx.SwapValue = args.SwapValue.String()
-	} else {
-		matchTx.SwapValue = tokens.CalcSwappedValue(pairID, args.OriginValue, isSwapin).String()
-	}
-	err = updateSwapResult(txid, pairID, bind, matchTx)
+	err = commitSwapTx(resBridge, args, matchTx)
 	if err != nil {
-		logWorkerError("doSwap", "update swap result failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		logWorkerError("doSwap", "commit swap tx failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		return err
 	}
 	isCachedSwapProcessed = true
-
-	err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxProcessed, now(), "")
-	if err != nil {
-		logWorkerError("doSwap", "update swap status failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
-		return err
-	}
+	intentStatus = mongodb.SignIntentCommitted
+	_ = mongodb.UpdateSignIntentStatus(intent.Key, intentStatus, signTxHash, "")
 
 	txHash, err := sendSignedTransaction(resBridge, signedTx, txid, pairID, bind, isSwapin)
+	intentStatus = mongodb.SignIntentFinalized
 	if err == nil {
 		logWorker("doSwap", "send tx success", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "swapNonce", swapNonce, "txHash", txHash)
 		if txHash != signTxHash {
@@ -0,0 +1,287 @@
+package worker
+
+import (
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	testPairID         = "testpair"
+	testSrcDcrmAddress = "0x1111111111111111111111111111111111111111"
+	testDstDcrmAddress = "0x2222222222222222222222222222222222222222"
+	testBindAddress    = "0x3333333333333333333333333333333333333333"
+	testFromAddress    = "0x4444444444444444444444444444444444444444"
+)
+
+// testBridge fake bridge, calling methods which are not faked panics
+type testBridge struct {
+	tokens.CrossChainBridge
+
+	isSrc        bool
+	chainCfg     *tokens.ChainConfig
+	swapInfos    map[string]*tokens.TxSwapInfo
+	txStatuses   map[string]*tokens.TxStatus
+	txs          map[string]interface{}
+	signedTxHash string
+	sentTxs      []interface{}
+}
+
+func newTestBridge(isSrc bool) *testBridge {
+	confirmations := uint64(3)
+	return &testBridge{
+		isSrc:      isSrc,
+		chainCfg:   &tokens.ChainConfig{Confirmations: &confirmations},
+		swapInfos:  make(map[string]*tokens.TxSwapInfo),
+		txStatuses: make(map[string]*tokens.TxStatus),
+		txs:        make(map[string]interface{}),
+	}
+}
+
+func (b *testBridge) GetChainConfig() *tokens.ChainConfig {
+	return b.chainCfg
+}
+
+func (b *testBridge) GetTokenConfig(pairID string) *tokens.TokenConfig {
+	return tokens.GetTokenConfig(pairID, b.isSrc)
+}
+
+func (b *testBridge) GetTransaction(txHash string) (interface{}, error) {
+	if tx, exist := b.txs[txHash]; exist {
+		return tx, nil
+	}
+	return nil, tokens.ErrTxNotFound
+}
+
+func (b *testBridge) GetTransactionStatus(txHash string) *tokens.TxStatus {
+	return b.txStatuses[txHash]
+}
+
+func (b *testBridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
+	if swapInfo, exist := b.swapInfos[txHash]; exist {
+		return swapInfo, nil
+	}
+	return nil, tokens.ErrTxNotFound
+}
+
+func (b *testBridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	return "rawtx:" + args.SwapID, nil
+}
+
+func (b *testBridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	return rawTx, b.signedTxHash, nil
+}
+
+func (b *testBridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	b.sentTxs = append(b.sentTxs, signedTx)
+	b.txs[b.signedTxHash] = signedTx
+	return b.signedTxHash, nil
+}
+
+// newTestWorker set config, token pair, bridges and leveldb store for worker tests
+func newTestWorker(t *testing.T) (srcBridge, dstBridge *testBridge, cleanup func()) {
+	dir, err := ioutil.TempDir("", "workerstore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := mongodb.NewLvlStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldStore := mongodb.GetStore()
+	mongodb.SetStore(s)
+
+	oldConfig := params.GetConfig()
+	params.SetConfig(&params.ServerConfig{
+		Identifier:   "testworker",
+		ReorgMonitor: &params.ReorgMonitorConfig{Enable: true},
+	})
+
+	noFee := 0.0
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		testPairID: {
+			PairID:    testPairID,
+			SrcToken:  &tokens.TokenConfig{DcrmAddress: testSrcDcrmAddress, SwapFeeRate: &noFee},
+			DestToken: &tokens.TokenConfig{DcrmAddress: testDstDcrmAddress, SwapFeeRate: &noFee},
+		},
+	}, false)
+
+	oldSrcBridge, oldDstBridge := tokens.SrcBridge, tokens.DstBridge
+	srcBridge, dstBridge = newTestBridge(true), newTestBridge(false)
+	tokens.SrcBridge, tokens.DstBridge = srcBridge, dstBridge
+
+	return srcBridge, dstBridge, func() {
+		tokens.SrcBridge, tokens.DstBridge = oldSrcBridge, oldDstBridge
+		tokens.SetTokenPairsConfig(oldPairsConfig, false)
+		params.SetConfig(oldConfig)
+		if oldStore != nil {
+			mongodb.SetStore(oldStore)
+		}
+		_ = s.Close()
+		_ = os.RemoveAll(dir)
+	}
+}
+
+// addTestSwapin add verified swapin and its initial swap result
+func addTestSwapin(t *testing.T, srcBridge *testBridge, txid string) (*mongodb.MgoSwap, *tokens.TxSwapInfo) {
+	swapInfo := &tokens.TxSwapInfo{
+		PairID: testPairID,
+		Hash:   txid,
+		Height: 10,
+		From:   testFromAddress,
+		To:     testSrcDcrmAddress,
+		Bind:   testBindAddress,
+		Value:  big.NewInt(1000),
+	}
+	srcBridge.swapInfos[txid] = swapInfo
+	srcBridge.txStatuses[txid] = &tokens.TxStatus{BlockHeight: 10, BlockHash: "0xb10c10", Confirmations: 5}
+
+	swap := &mongodb.MgoSwap{TxID: txid, PairID: testPairID, Bind: testBindAddress, Status: mongodb.TxNotSwapped}
+	if err := mongodb.AddSwapin(swap); err != nil {
+		t.Fatalf("add swapin failed: %v", err)
+	}
+	if err := addInitialSwapResult(swapInfo, mongodb.MatchTxEmpty, true); err != nil {
+		t.Fatalf("add swapin result failed: %v", err)
+	}
+	return swap, swapInfo
+}
+
+func findTestSwapinResult(t *testing.T, txid string) *mongodb.MgoSwapResult {
+	res, err := mongodb.FindSwapResult(true, txid, testPairID, testBindAddress)
+	if err != nil {
+		t.Fatalf("find swapin result failed: %v", err)
+	}
+	return res
+}
+
+func TestSwapinToStable(t *testing.T) {
+	srcBridge, dstBridge, cleanup := newTestWorker(t)
+	defer cleanup()
+
+	txid := "0xaaaa000000000000000000000000000000000000000000000000000000000001"
+	swapTx := "0xbbbb000000000000000000000000000000000000000000000000000000000001"
+	dstBridge.signedTxHash = swapTx
+	swap, _ := addTestSwapin(t, srcBridge, txid)
+	defer DeleteCachedSwap(true, txid, testBindAddress)
+
+	queue := getSwapTaskQueue(testDstDcrmAddress, true)
+	notifyChan := make(chan struct{}, 1)
+	swapTaskQueues[queue] = notifyChan
+	defer delete(swapTaskQueues, queue)
+
+	// dispatch swap task, and dispatch again before it is processed
+	for i := 0; i < 2; i++ {
+		if err := processSwap(swap, true); err != nil {
+			t.Fatalf("process swap failed: %v", err)
+		}
+	}
+	if len(notifyChan) != 1 {
+		t.Fatalf("task queue is not notified")
+	}
+	tasks, err := mongodb.FindSwapTasks(queue, "")
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("find swap tasks, want 1, have %v, err %v", len(tasks), err)
+	}
+	if !mongodb.IsTxBlockHashRecorded(true, txid, testPairID, testBindAddress, false) {
+		t.Fatalf("block hash of swapin tx is not recorded")
+	}
+
+	task, err := mongodb.LeaseSwapTask(queue, time.Minute)
+	if err != nil {
+		t.Fatalf("lease swap task failed: %v", err)
+	}
+	handleSwapTask(task, testDstDcrmAddress, true)
+
+	if tasks, _ = mongodb.FindSwapTasks(queue, ""); len(tasks) != 0 {
+		t.Fatalf("swap task is not acked, have %v tasks", len(tasks))
+	}
+	if len(dstBridge.sentTxs) != 1 || dstBridge.sentTxs[0] != "rawtx:"+txid {
+		t.Fatalf("unexpected sent txs %v", dstBridge.sentTxs)
+	}
+	res := findTestSwapinResult(t, txid)
+	if res.SwapTx != swapTx || res.SwapValue != "1000" || res.Status != mongodb.MatchTxNotStable {
+		t.Fatalf("unexpected swap result %+v", res)
+	}
+	if swap, err = mongodb.FindSwap(true, txid, testPairID, testBindAddress); err != nil || swap.Status != mongodb.TxProcessed {
+		t.Fatalf("unexpected swap %+v, err %v", swap, err)
+	}
+	intent, err := mongodb.FindSignIntent(mongodb.GetSwapTaskKey(true, txid, testPairID, testBindAddress))
+	if err != nil || intent.Status != mongodb.SignIntentFinalized || intent.SignedTxHash != swapTx {
+		t.Fatalf("unexpected sign intent %+v, err %v", intent, err)
+	}
+	if err = processSwap(swap, true); !errors.Is(err, errAlreadySwapped) {
+		t.Fatalf("reswap, want %v, have %v", errAlreadySwapped, err)
+	}
+
+	// swap tx is not on chain
+	if err = processSwapStable(res, true); err != nil {
+		t.Fatalf("process swap stable failed: %v", err)
+	}
+	if res = findTestSwapinResult(t, txid); res.SwapHeight != 0 || res.Status != mongodb.MatchTxNotStable {
+		t.Fatalf("unexpected swap result %+v", res)
+	}
+
+	// swap tx is on chain but not stable
+	swapTxStatus := &tokens.TxStatus{BlockHeight: 20, BlockHash: "0xb10c20", BlockTime: 1600000000, Confirmations: 1}
+	dstBridge.txStatuses[swapTx] = swapTxStatus
+	for i := 0; i < 2; i++ {
+		if err = processSwapStable(res, true); err != nil {
+			t.Fatalf("process swap stable failed: %v", err)
+		}
+		if res = findTestSwapinResult(t, txid); res.SwapHeight != 20 || res.SwapTime != 1600000000 || res.Status != mongodb.MatchTxNotStable {
+			t.Fatalf("unexpected swap result %+v", res)
+		}
+	}
+
+	// swap tx is stable
+	swapTxStatus.Confirmations = *dstBridge.chainCfg.Confirmations
+	if err = processSwapStable(res, true); err != nil {
+		t.Fatalf("process swap stable failed: %v", err)
+	}
+	if res = findTestSwapinResult(t, txid); res.Status != mongodb.MatchTxStable {
+		t.Fatalf("swap result is not stable, status %v", res.Status)
+	}
+	if !mongodb.IsTxBlockHashRecorded(true, txid, testPairID, testBindAddress, true) {
+		t.Fatalf("block hash of swap tx is not recorded")
+	}
+}
+
+func TestSwapTaskMismatch(t *testing.T) {
+	srcBridge, dstBridge, cleanup := newTestWorker(t)
+	defer cleanup()
+
+	txid := "0xaaaa000000000000000000000000000000000000000000000000000000000002"
+	swap, _ := addTestSwapin(t, srcBridge, txid)
+
+	queue := getSwapTaskQueue(testDstDcrmAddress, true)
+	swapTaskQueues[queue] = make(chan struct{}, 1)
+	defer delete(swapTaskQueues, queue)
+
+	if err := processSwap(swap, true); err != nil {
+		t.Fatalf("process swap failed: %v", err)
+	}
+	task, err := mongodb.LeaseSwapTask(queue, time.Minute)
+	if err != nil {
+		t.Fatalf("lease swap task failed: %v", err)
+	}
+	handleSwapTask(task, testSrcDcrmAddress, true)
+
+	tasks, err := mongodb.FindSwapTasks(queue, mongodb.SwapTaskDead)
+	if err != nil || len(tasks) != 1 {
+		t.Fatalf("find dead swap tasks, want 1, have %v, err %v", len(tasks), err)
+	}
+	if len(dstBridge.sentTxs) != 0 {
+		t.Fatalf("mismatch swap task is processed")
+	}
+	if res := findTestSwapinResult(t, txid); res.SwapTx != "" || res.Status != mongodb.MatchTxEmpty {
+		t.Fatalf("unexpected swap result %+v", res)
+	}
+}
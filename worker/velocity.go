@@ -0,0 +1,129 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	errExceedsRateLimit = errors.New("swap exceeds rate limit")
+
+	restIntervalInPassRateLimitJob = 60 * time.Second
+	pruneSwapVelocitiesInterval    = time.Hour
+)
+
+// StartPassRateLimitJob release rate limited swaps when the rolling windows have room
+func StartPassRateLimitJob() {
+	logWorker("passratelimit", "start pass rate limit job")
+	var lastPruneTime time.Time
+	for {
+		if time.Since(lastPruneTime) > pruneSwapVelocitiesInterval {
+			if err := mongodb.PruneSwapVelocities(); err != nil {
+				logWorkerError("passratelimit", "prune swap velocities error", err)
+			} else {
+				lastPruneTime = time.Now()
+			}
+		}
+		for _, isSwapin := range []bool{true, false} {
+			if utils.IsCleanuping() {
+				logWorker("passratelimit", "stop pass rate limit job")
+				return
+			}
+			processPassRateLimitSwaps(isSwapin)
+		}
+		restInJob(restIntervalInPassRateLimitJob)
+	}
+}
+
+func findRateLimitSwaps(isSwapin bool) ([]*mongodb.MgoSwap, error) {
+	status := mongodb.TxExceedsRateLimit
+	septime := getSepTimeInFind(maxPassBigValueLifetime)
+	if isSwapin {
+		return mongodb.FindSwapinsWithStatus(status, septime)
+	}
+	return mongodb.FindSwapoutsWithStatus(status, septime)
+}
+
+func processPassRateLimitSwaps(isSwapin bool) {
+	res, err := findRateLimitSwaps(isSwapin)
+	if err != nil {
+		logWorkerError("passratelimit", "find rate limit swaps error", err, "isSwapin", isSwapin)
+		return
+	}
+	for _, swap := range res {
+		if utils.IsCleanuping() {
+			return
+		}
+		err = processPassRateLimitSwap(swap, isSwapin)
+		switch {
+		case err == nil,
+			errors.Is(err, errExceedsRateLimit),
+			errors.Is(err, tokens.ErrUnknownPairID):
+		default:
+			logWorkerError("passratelimit", "process pass rate limit swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind, "isSwapin", isSwapin)
+		}
+	}
+}
+
+func processPassRateLimitSwap(swap *mongodb.MgoSwap, isSwapin bool) error {
+	if tokens.GetTokenConfig(swap.PairID, isSwapin) == nil {
+		return tokens.ErrUnknownPairID
+	}
+	res, err := mongodb.FindSwapResult(isSwapin, swap.TxID, swap.PairID, swap.Bind)
+	if err != nil {
+		return err
+	}
+	value, ok := new(big.Int).SetString(res.Value, 10)
+	if !ok {
+		return fmt.Errorf("wrong swap value %v", res.Value)
+	}
+	checker := newSwapVelocityChecker(swap.PairID, isSwapin, value)
+	err = mongodb.PassSwapRateLimit(isSwapin, swap.TxID, swap.PairID, swap.Bind, "passratelimit", checker)
+	if err == nil {
+		logWorker("passratelimit", "pass rate limit swap success", "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind, "isSwapin", isSwapin, "value", value)
+	}
+	return err
+}
+
+// newSwapVelocityChecker returns nil if no velocity limit is configed
+func newSwapVelocityChecker(pairID string, isSwapin bool, value *big.Int) func(*mongodb.SwapVelocityUsage) error {
+	pairHourlyLimit, pairDailyLimit, bindDailyLimit := tokens.GetVelocityLimits(pairID, isSwapin)
+	if pairHourlyLimit == nil && pairDailyLimit == nil && bindDailyLimit == nil {
+		return nil
+	}
+	exceeds := func(usage, limit *big.Int) bool {
+		return limit != nil && new(big.Int).Add(usage, value).Cmp(limit) > 0
+	}
+	return func(usage *mongodb.SwapVelocityUsage) error {
+		switch {
+		case exceeds(usage.PairHourly, pairHourlyLimit):
+			return fmt.Errorf("%w: pair hourly usage %v + value %v > limit %v", errExceedsRateLimit, usage.PairHourly, value, pairHourlyLimit)
+		case exceeds(usage.PairDaily, pairDailyLimit):
+			return fmt.Errorf("%w: pair daily usage %v + value %v > limit %v", errExceedsRateLimit, usage.PairDaily, value, pairDailyLimit)
+		case exceeds(usage.BindDaily, bindDailyLimit):
+			return fmt.Errorf("%w: bind daily usage %v + value %v > limit %v", errExceedsRateLimit, usage.BindDaily, value, bindDailyLimit)
+		}
+		return nil
+	}
+}
+
+// checkSwapVelocity count swap value in velocity limits,
+// park the swap with status TxExceedsRateLimit if it exceeds.
+func checkSwapVelocity(res *mongodb.MgoSwapResult, isSwapin bool, value *big.Int) error {
+	checker := newSwapVelocityChecker(res.PairID, isSwapin, value)
+	if checker == nil {
+		return nil
+	}
+	err := mongodb.AddSwapVelocity(isSwapin, res.TxID, res.PairID, res.Bind, value.String(), "swap", checker)
+	if errors.Is(err, errExceedsRateLimit) {
+		logWorkerWarn("swap", "swap exceeds rate limit", "pairID", res.PairID, "txid", res.TxID, "bind", res.Bind, "isSwapin", isSwapin, "err", err)
+		_ = mongodb.UpdateSwapStatus(isSwapin, res.TxID, res.PairID, res.Bind, mongodb.TxExceedsRateLimit, now(), err.Error(), "swap")
+	}
+	return err
+}
@@ -129,7 +129,7 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 		logWorkerTrace("swap", "swap is not configed", "pairID", pairID, "isSwapin", isSwapin)
 		return tokens.ErrUnknownPairID
 	}
-	if fromTokenCfg.DisableSwap {
+	if fromTokenCfg.IsSwapDisabled() {
 		logWorkerTrace("swap", "swap is disabled", "pairID", pairID, "isSwapin", isSwapin)
 		return tokens.ErrSwapIsClosed
 	}
@@ -142,7 +142,7 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	if swapInfo.Height != 0 &&
 		swapInfo.Height < *bridge.GetChainConfig().InitialHeight {
 		err = tokens.ErrTxBeforeInitialHeight
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error(), "verify")
 	}
 	isBlacked, errf := isInBlacklist(swapInfo)
 	if errf != nil {
@@ -150,7 +150,7 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	}
 	if isBlacked {
 		err = tokens.ErrAddressIsInBlacklist
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error(), "verify")
 	}
 	return updateSwapStatus(pairID, txid, bind, swapInfo, isSwapin, err)
 }
@@ -170,28 +170,28 @@ func updateSwapStatus(pairID, txid, bind string, swapInfo *tokens.TxSwapInfo, is
 			status = mongodb.TxWithBigValue
 			resultStatus = mongodb.TxWithBigValue
 		}
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now(), "")
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now(), "", "verify")
 	case errors.Is(err, tokens.ErrTxWithWrongMemo):
 		resultStatus = mongodb.TxWithWrongMemo
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongMemo, now(), err.Error())
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongMemo, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrBindAddrIsContract):
 		resultStatus = mongodb.BindAddrIsContract
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.BindAddrIsContract, now(), err.Error())
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.BindAddrIsContract, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrTxWithWrongValue):
 		resultStatus = mongodb.TxWithWrongValue
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongValue, now(), err.Error())
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongValue, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrTxSenderNotRegistered):
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSenderNotRegistered, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSenderNotRegistered, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrTxWithWrongSender):
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongSender, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongSender, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrTxIncompatible):
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxIncompatible, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxIncompatible, now(), err.Error(), "verify")
 	case errors.Is(err, tokens.ErrTxWithWrongReceipt),
 		errors.Is(err, tokens.ErrBindAddressMismatch):
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error(), "verify")
 	default:
 		logWorkerWarn("verify", "maybe not considered tx verify error", "err", err)
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error(), "verify")
 	}
 
 	if err != nil {
@@ -3,6 +3,7 @@ package worker
 import (
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
 )
@@ -20,19 +21,35 @@ func StartWork(isServer bool) {
 	client.InitHTTPClient()
 	bridge.InitCrossChainBridge(isServer)
 
-	go StartScanJob(isServer)
-	time.Sleep(interval)
+	if !isServer {
+		go StartScanJob(isServer)
+		time.Sleep(interval)
 
-	go StartUpdateLatestBlockHeightJob()
-	time.Sleep(interval)
+		go StartUpdateLatestBlockHeightJob()
+		time.Sleep(interval)
 
-	if !isServer {
 		StartAcceptSignJob()
 		time.Sleep(interval)
 		AddTokenPairDynamically()
 		return
 	}
 
+	go StartUpdateLatestBlockHeightJob()
+	time.Sleep(interval)
+
+	// standby replicas only serve the api
+	if params.IsHAEnabled() {
+		go StartLeaderElectionJob(startServerJobs)
+		return
+	}
+	startServerJobs()
+}
+
+// startServerJobs start jobs of the single (or leader) swap server
+func startServerJobs() {
+	go StartScanJob(true)
+	time.Sleep(interval)
+
 	StartSwapJob()
 	time.Sleep(interval)
 
@@ -48,5 +65,14 @@ func StartWork(isServer bool) {
 	go StartPassBigValueJob()
 	time.Sleep(interval)
 
+	go StartPassRateLimitJob()
+	time.Sleep(interval)
+
+	go StartReorgMonitorJob()
+	time.Sleep(interval)
+
 	go StartAggregateJob()
+	time.Sleep(interval)
+
+	go StartArchiveJob()
 }